@@ -0,0 +1,10 @@
+-- +up
+-- +begin
+ALTER TABLE `profits` ADD COLUMN `fee_in_quote` DECIMAL(16, 8) NOT NULL DEFAULT 0.00000000;
+-- +end
+
+-- +down
+
+-- +begin
+ALTER TABLE `profits` DROP COLUMN `fee_in_quote`;
+-- +end
@@ -0,0 +1,26 @@
+-- +up
+-- +begin
+CREATE TABLE `funding_fees`
+(
+    `gid`      BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `exchange` VARCHAR(24)     NOT NULL DEFAULT '',
+    `symbol`   VARCHAR(32)     NOT NULL,
+
+    -- asset is the settlement currency of the funding payment
+    `asset`    VARCHAR(10)     NOT NULL,
+
+    -- amount is positive when the funding is received, negative when it is paid
+    `amount`   DECIMAL(16, 8)  NOT NULL,
+    `txn_id`   VARCHAR(64)     NOT NULL,
+    `time`     DATETIME(3)     NOT NULL,
+
+    PRIMARY KEY (`gid`),
+    UNIQUE KEY `txn_id` (`exchange`, `txn_id`)
+);
+-- +end
+
+-- +down
+
+-- +begin
+DROP TABLE IF EXISTS `funding_fees`;
+-- +end
@@ -0,0 +1,26 @@
+-- +up
+-- +begin
+ALTER TABLE `positions`
+    ADD COLUMN `price`          DECIMAL(16, 8) NOT NULL DEFAULT 0.00000000,
+    ADD COLUMN `quantity`       DECIMAL(16, 8) NOT NULL DEFAULT 0.00000000,
+    ADD COLUMN `quote_quantity` DECIMAL(16, 8) NOT NULL DEFAULT 0.00000000,
+    ADD COLUMN `fee_in_quote`   DECIMAL(16, 8) NOT NULL DEFAULT 0.00000000;
+-- +end
+
+-- +begin
+CREATE INDEX `positions_strategy_instance_id` ON `positions` (`strategy_instance_id`, `symbol`, `traded_at`);
+-- +end
+
+-- +down
+
+-- +begin
+DROP INDEX `positions_strategy_instance_id` ON `positions`;
+-- +end
+
+-- +begin
+ALTER TABLE `positions`
+    DROP COLUMN `price`,
+    DROP COLUMN `quantity`,
+    DROP COLUMN `quote_quantity`,
+    DROP COLUMN `fee_in_quote`;
+-- +end
@@ -0,0 +1,23 @@
+-- +up
+-- +begin
+CREATE TABLE `kline_gaps`
+(
+    `gid`        BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `exchange`   VARCHAR(24)     NOT NULL,
+    `symbol`     VARCHAR(20)     NOT NULL,
+    `interval`   VARCHAR(3)      NOT NULL,
+
+    -- the kline range [start_time, end_time) that the exchange has no data
+    `start_time` DATETIME(3)     NOT NULL,
+    `end_time`   DATETIME(3)     NOT NULL,
+
+    PRIMARY KEY (`gid`),
+    UNIQUE KEY `kline_gaps_start_time` (`exchange`, `symbol`, `interval`, `start_time`)
+);
+-- +end
+
+-- +down
+
+-- +begin
+DROP TABLE IF EXISTS `kline_gaps`;
+-- +end
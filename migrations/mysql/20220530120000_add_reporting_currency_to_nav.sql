@@ -0,0 +1,16 @@
+-- +up
+-- +begin
+ALTER TABLE `nav_history_details`
+    ADD COLUMN `reporting_currency`              VARCHAR(10)    NOT NULL DEFAULT '',
+    ADD COLUMN `net_asset_in_reporting_currency` DECIMAL(32, 8) NOT NULL DEFAULT 0.00000000
+;
+-- +end
+
+-- +down
+
+-- +begin
+ALTER TABLE `nav_history_details`
+    DROP COLUMN `reporting_currency`,
+    DROP COLUMN `net_asset_in_reporting_currency`
+;
+-- +end
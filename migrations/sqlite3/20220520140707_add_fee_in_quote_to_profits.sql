@@ -0,0 +1,6 @@
+-- +up
+ALTER TABLE `profits` ADD COLUMN `fee_in_quote` DECIMAL DEFAULT 0.00000000 NOT NULL;
+
+-- +down
+-- we can not rollback alter table change in sqlite
+SELECT 1;
@@ -0,0 +1,31 @@
+-- +up
+-- +begin
+CREATE TABLE `funding_fees`
+(
+    `gid`      INTEGER PRIMARY KEY AUTOINCREMENT,
+    `exchange` VARCHAR(24)    NOT NULL DEFAULT '',
+    `symbol`   VARCHAR(32)    NOT NULL,
+
+    -- asset is the settlement currency of the funding payment
+    `asset`    VARCHAR(10)    NOT NULL,
+
+    -- amount is positive when the funding is received, negative when it is paid
+    `amount`   DECIMAL(16, 8) NOT NULL,
+    `txn_id`   VARCHAR(64)    NOT NULL,
+    `time`     DATETIME(3)    NOT NULL
+);
+-- +end
+
+-- +begin
+CREATE UNIQUE INDEX `funding_fees_txn_id` ON `funding_fees` (`exchange`, `txn_id`);
+-- +end
+
+-- +down
+
+-- +begin
+DROP INDEX IF EXISTS `funding_fees_txn_id`;
+-- +end
+
+-- +begin
+DROP TABLE IF EXISTS `funding_fees`;
+-- +end
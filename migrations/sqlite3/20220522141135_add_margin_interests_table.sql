@@ -0,0 +1,31 @@
+-- +up
+-- +begin
+CREATE TABLE `margin_interests`
+(
+    `gid`             INTEGER PRIMARY KEY AUTOINCREMENT,
+    `exchange`        VARCHAR(24)     NOT NULL DEFAULT '',
+    `asset`           VARCHAR(10)     NOT NULL,
+
+    -- isolated_symbol is empty for the cross margin account
+    `isolated_symbol` VARCHAR(32)     NOT NULL DEFAULT '',
+
+    `principal`       DECIMAL(16, 8)  NOT NULL,
+    `interest`        DECIMAL(20, 16) NOT NULL,
+    `interest_rate`   DECIMAL(20, 16) NOT NULL,
+    `time`            DATETIME(3)     NOT NULL
+);
+-- +end
+
+-- +begin
+CREATE UNIQUE INDEX `margin_interests_asset_time` ON `margin_interests` (`exchange`, `asset`, `isolated_symbol`, `time`);
+-- +end
+
+-- +down
+
+-- +begin
+DROP INDEX IF EXISTS `margin_interests_asset_time`;
+-- +end
+
+-- +begin
+DROP TABLE IF EXISTS `margin_interests`;
+-- +end
@@ -0,0 +1,26 @@
+-- +up
+-- +begin
+ALTER TABLE `positions` ADD COLUMN `price` DECIMAL DEFAULT 0.00000000 NOT NULL;
+-- +end
+
+-- +begin
+ALTER TABLE `positions` ADD COLUMN `quantity` DECIMAL DEFAULT 0.00000000 NOT NULL;
+-- +end
+
+-- +begin
+ALTER TABLE `positions` ADD COLUMN `quote_quantity` DECIMAL DEFAULT 0.00000000 NOT NULL;
+-- +end
+
+-- +begin
+ALTER TABLE `positions` ADD COLUMN `fee_in_quote` DECIMAL DEFAULT 0.00000000 NOT NULL;
+-- +end
+
+-- +begin
+CREATE INDEX `positions_strategy_instance_id` ON `positions` (`strategy_instance_id`, `symbol`, `traded_at`);
+-- +end
+
+-- +down
+-- we can not rollback alter table change in sqlite
+-- +begin
+DROP INDEX IF EXISTS `positions_strategy_instance_id`;
+-- +end
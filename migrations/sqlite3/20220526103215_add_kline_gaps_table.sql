@@ -0,0 +1,28 @@
+-- +up
+-- +begin
+CREATE TABLE `kline_gaps`
+(
+    `gid`        INTEGER PRIMARY KEY AUTOINCREMENT,
+    `exchange`   VARCHAR(24) NOT NULL,
+    `symbol`     VARCHAR(20) NOT NULL,
+    `interval`   VARCHAR(3)  NOT NULL,
+
+    -- the kline range [start_time, end_time) that the exchange has no data
+    `start_time` DATETIME(3) NOT NULL,
+    `end_time`   DATETIME(3) NOT NULL
+);
+-- +end
+
+-- +begin
+CREATE UNIQUE INDEX `kline_gaps_start_time` ON `kline_gaps` (`exchange`, `symbol`, `interval`, `start_time`);
+-- +end
+
+-- +down
+
+-- +begin
+DROP INDEX IF EXISTS `kline_gaps_start_time`;
+-- +end
+
+-- +begin
+DROP TABLE IF EXISTS `kline_gaps`;
+-- +end
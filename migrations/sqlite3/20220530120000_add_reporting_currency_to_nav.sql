@@ -0,0 +1,7 @@
+-- +up
+ALTER TABLE `nav_history_details` ADD COLUMN `reporting_currency` VARCHAR(10) DEFAULT '' NOT NULL;
+ALTER TABLE `nav_history_details` ADD COLUMN `net_asset_in_reporting_currency` DECIMAL DEFAULT 0.00000000 NOT NULL;
+
+-- +down
+-- we can not rollback alter table change in sqlite
+SELECT 1;
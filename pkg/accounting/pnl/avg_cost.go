@@ -12,6 +12,15 @@ import (
 type AverageCostCalculator struct {
 	TradingFeeCurrency string
 	Market             types.Market
+
+	// PriceLookup converts the fees paid in other currencies into the quote currency at the trade time,
+	// the fees are approximated by the fee rates if it's not set.
+	PriceLookup types.PriceLookup
+
+	// FundingFees and Interests are the funding payments and the margin interests,
+	// the records attributed to the symbol are added into the net profit.
+	FundingFees []types.FundingFee
+	Interests   []types.MarginInterest
 }
 
 func (c *AverageCostCalculator) Calculate(symbol string, trades []types.Trade, currentPrice fixedpoint.Value) *AverageCostPnlReport {
@@ -41,6 +50,10 @@ func (c *AverageCostCalculator) Calculate(symbol string, trades []types.Trade, c
 		TakerFeeRate: fixedpoint.NewFromFloat(0.075 * 0.01),
 	})
 
+	if c.PriceLookup != nil {
+		position.SetPriceLookup(c.PriceLookup)
+	}
+
 	// TODO: configure the exchange fee rate here later
 	// position.SetExchangeFeeRate()
 	var totalProfit fixedpoint.Value
@@ -79,6 +92,8 @@ func (c *AverageCostCalculator) Calculate(symbol string, trades []types.Trade, c
 		tradeIDs[trade.ID] = trade
 	}
 
+	fundingFee, interest := addCarryCosts(position, c.FundingFees, c.Interests)
+
 	unrealizedProfit := currentPrice.Sub(position.AverageCost).
 		Mul(position.GetBase())
 
@@ -94,10 +109,41 @@ func (c *AverageCostCalculator) Calculate(symbol string, trades []types.Trade, c
 
 		Stock:            position.GetBase(),
 		Profit:           totalProfit,
-		NetProfit:        totalNetProfit,
+		NetProfit:        totalNetProfit.Add(fundingFee).Sub(interest),
 		UnrealizedProfit: unrealizedProfit,
 		AverageCost:      position.AverageCost,
 		FeeInUSD:         totalProfit.Sub(totalNetProfit),
+		FundingFee:       fundingFee,
+		Interest:         interest,
 		CurrencyFees:     currencyFees,
 	}
 }
+
+// addCarryCosts adds the funding fees and the margin interests attributed to the position,
+// the total funding fee and the total interest in the quote currency are returned.
+func addCarryCosts(position *types.Position, fees []types.FundingFee, interests []types.MarginInterest) (fundingFee, interest fixedpoint.Value) {
+	for _, fee := range fees {
+		if fee.Symbol != position.Symbol {
+			continue
+		}
+
+		amount, ok := position.AddFundingFee(fee)
+		if !ok {
+			log.Warnf("can not convert the funding fee into %s: %s", position.QuoteCurrency, fee.String())
+			continue
+		}
+
+		fundingFee = fundingFee.Add(amount)
+	}
+
+	for _, record := range interests {
+		amount, ok := position.AddInterest(record)
+		if !ok {
+			continue
+		}
+
+		interest = interest.Add(amount)
+	}
+
+	return fundingFee, interest
+}
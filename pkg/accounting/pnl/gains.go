@@ -0,0 +1,363 @@
+package pnl
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type GainType string
+
+const (
+	// GainTypeDisposal is the gain of selling (or spending) an asset
+	GainTypeDisposal GainType = "disposal"
+
+	// GainTypeIncome is the income of the rewards, the market value at the receiving time is the gain
+	GainTypeIncome GainType = "income"
+)
+
+// GainRecord is a row of the realized gains export, all the values are in the reporting currency.
+// A disposal that closes several lots is split into several records, one for each lot.
+type GainRecord struct {
+	Type        GainType         `json:"type"`
+	Description string           `json:"description"`
+	Asset       string           `json:"asset"`
+	Quantity    fixedpoint.Value `json:"quantity"`
+
+	// DateAcquired is zero if the cost basis of the disposed quantity is unknown
+	DateAcquired time.Time `json:"dateAcquired"`
+	DateSold     time.Time `json:"dateSold"`
+
+	Proceeds  fixedpoint.Value `json:"proceeds"`
+	CostBasis fixedpoint.Value `json:"costBasis"`
+	Gain      fixedpoint.Value `json:"gain"`
+}
+
+// gainLot is an acquired lot of an asset, the total cost is kept to avoid the rounding errors of the unit price
+type gainLot struct {
+	Time     time.Time
+	Quantity fixedpoint.Value
+	Cost     fixedpoint.Value
+}
+
+// gainEvent is a trade, a deposit, a withdrawal or a reward to be applied in the time order
+type gainEvent struct {
+	time  time.Time
+	apply func() error
+}
+
+// GainsCalculator calculates the realized gains of the spot trades per disposal in the reporting currency.
+//
+// Every asset except the reporting currency is tracked by lots:
+//   - a buy trade acquires the base asset and disposes the quote asset, a sell trade does the opposite
+//   - a trading fee is a disposal of the fee asset at its market value, and the value is added to the cost basis (buy)
+//     or deducted from the proceeds (sell). A fee in the base currency of a buy trade reduces the acquired quantity instead.
+//   - a reward is recorded as income at its market value, and it's acquired with the same cost basis
+//   - a deposit is acquired at its market value, since the original cost basis is not known by the exchange
+//   - a withdrawal removes the lots without realizing a gain, the withdrawal fee is a disposal at its market value
+//
+// The margin and the futures trades are skipped.
+type GainsCalculator struct {
+	ReportingCurrency string
+	Method            CostBasisMethod
+	Markets           types.MarketMap
+	Prices            types.PriceLookup
+
+	lots    map[string][]gainLot
+	records []GainRecord
+}
+
+// Calculate replays all the records in the time order and returns the gain records
+func (c *GainsCalculator) Calculate(trades []types.Trade, deposits []types.Deposit, withdraws []types.Withdraw, rewards []types.Reward) ([]GainRecord, error) {
+	c.lots = make(map[string][]gainLot)
+	c.records = nil
+
+	var events []gainEvent
+	for _, trade := range trades {
+		trade := trade
+		if trade.IsMargin || trade.IsFutures {
+			continue
+		}
+
+		events = append(events, gainEvent{time: trade.Time.Time(), apply: func() error { return c.addTrade(trade) }})
+	}
+
+	for _, deposit := range deposits {
+		deposit := deposit
+		events = append(events, gainEvent{time: deposit.Time.Time(), apply: func() error { return c.addDeposit(deposit) }})
+	}
+
+	for _, withdraw := range withdraws {
+		withdraw := withdraw
+		events = append(events, gainEvent{time: withdraw.ApplyTime.Time(), apply: func() error { return c.addWithdraw(withdraw) }})
+	}
+
+	for _, reward := range rewards {
+		reward := reward
+		events = append(events, gainEvent{time: reward.CreatedAt.Time(), apply: func() error { return c.addReward(reward) }})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].time.Before(events[j].time)
+	})
+
+	for _, event := range events {
+		if err := event.apply(); err != nil {
+			return c.records, err
+		}
+	}
+
+	return c.records, nil
+}
+
+// OpenLots returns the remaining lots of the asset after Calculate
+func (c *GainsCalculator) OpenLots(asset string) (lots []Lot) {
+	for _, lot := range c.lots[asset] {
+		lots = append(lots, Lot{
+			Side:     types.SideTypeBuy,
+			Time:     lot.Time,
+			Price:    lot.Cost.Div(lot.Quantity),
+			Quantity: lot.Quantity,
+		})
+	}
+
+	return lots
+}
+
+// value returns the value of the quantity in the reporting currency
+func (c *GainsCalculator) value(asset string, quantity fixedpoint.Value, t time.Time) (fixedpoint.Value, error) {
+	if asset == c.ReportingCurrency || quantity.IsZero() {
+		return quantity, nil
+	}
+
+	price, err := c.Prices.QueryPrice(asset, c.ReportingCurrency, t)
+	if err != nil {
+		return fixedpoint.Zero, errors.Wrapf(err, "can not find the %s price in %s at %s", asset, c.ReportingCurrency, t)
+	}
+
+	return price.Mul(quantity), nil
+}
+
+func (c *GainsCalculator) acquire(asset string, quantity, cost fixedpoint.Value, t time.Time) {
+	if asset == c.ReportingCurrency || quantity.Sign() <= 0 {
+		return
+	}
+
+	c.lots[asset] = append(c.lots[asset], gainLot{
+		Time:     t,
+		Quantity: quantity,
+		Cost:     cost,
+	})
+}
+
+// dispose closes the lots of the asset, the proceeds are allocated to the closed lots by quantity.
+// If record is false, the lots are removed without realizing the gains, e.g., a withdrawal.
+func (c *GainsCalculator) dispose(description, asset string, quantity, proceeds fixedpoint.Value, t time.Time, record bool) {
+	if asset == c.ReportingCurrency || quantity.Sign() <= 0 {
+		return
+	}
+
+	lots := c.lots[asset]
+	for quantity.Sign() > 0 && len(lots) > 0 {
+		candidates := make([]Lot, len(lots))
+		for i, lot := range lots {
+			candidates[i] = Lot{Side: types.SideTypeBuy, Time: lot.Time, Price: lot.Cost.Div(lot.Quantity), Quantity: lot.Quantity}
+		}
+
+		idx := selectLot(c.Method, candidates)
+		lot := &lots[idx]
+
+		q := fixedpoint.Min(lot.Quantity, quantity)
+		cost := lot.Cost
+		if q.Compare(lot.Quantity) < 0 {
+			cost = lot.Cost.Mul(q).Div(lot.Quantity)
+		}
+
+		p := proceeds
+		if q.Compare(quantity) < 0 {
+			p = proceeds.Mul(q).Div(quantity)
+		}
+
+		if record {
+			c.addDisposal(description, asset, q, lot.Time, t, p, cost)
+		}
+
+		lot.Quantity = lot.Quantity.Sub(q)
+		lot.Cost = lot.Cost.Sub(cost)
+		quantity = quantity.Sub(q)
+		proceeds = proceeds.Sub(p)
+		if lot.Quantity.IsZero() {
+			lots = append(lots[:idx], lots[idx+1:]...)
+		}
+	}
+
+	c.lots[asset] = lots
+
+	if quantity.Sign() > 0 {
+		log.Warnf("%s: disposed %s %s more than the acquired quantity, the cost basis of the remaining quantity is unknown", description, quantity.String(), asset)
+		if record {
+			c.addDisposal(description, asset, quantity, time.Time{}, t, proceeds, fixedpoint.Zero)
+		}
+	}
+}
+
+func (c *GainsCalculator) addDisposal(description, asset string, quantity fixedpoint.Value, acquired, sold time.Time, proceeds, cost fixedpoint.Value) {
+	c.records = append(c.records, GainRecord{
+		Type:         GainTypeDisposal,
+		Description:  description,
+		Asset:        asset,
+		Quantity:     quantity,
+		DateAcquired: acquired,
+		DateSold:     sold,
+		Proceeds:     proceeds,
+		CostBasis:    cost,
+		Gain:         proceeds.Sub(cost),
+	})
+}
+
+func (c *GainsCalculator) addTrade(trade types.Trade) error {
+	market, ok := c.Markets[trade.Symbol]
+	if !ok {
+		return fmt.Errorf("market %s not found", trade.Symbol)
+	}
+
+	t := trade.Time.Time()
+	quoteQuantity := trade.QuoteQuantity
+	if quoteQuantity.IsZero() {
+		quoteQuantity = trade.Price.Mul(trade.Quantity)
+	}
+
+	value, err := c.value(market.QuoteCurrency, quoteQuantity, t)
+	if err != nil {
+		return err
+	}
+
+	feeValue, err := c.value(trade.FeeCurrency, trade.Fee, t)
+	if err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("%s %s %s", trade.Side, trade.Quantity.String(), trade.Symbol)
+
+	switch trade.Side {
+	case types.SideTypeBuy:
+		c.dispose(description, market.QuoteCurrency, quoteQuantity, value, t, true)
+
+		if trade.FeeCurrency == market.BaseCurrency {
+			c.acquire(market.BaseCurrency, trade.Quantity.Sub(trade.Fee), value, t)
+		} else {
+			c.dispose(description+" fee", trade.FeeCurrency, trade.Fee, feeValue, t, true)
+			c.acquire(market.BaseCurrency, trade.Quantity, value.Add(feeValue), t)
+		}
+
+	case types.SideTypeSell:
+		c.dispose(description, market.BaseCurrency, trade.Quantity, value.Sub(feeValue), t, true)
+		c.acquire(market.QuoteCurrency, quoteQuantity, value, t)
+		c.dispose(description+" fee", trade.FeeCurrency, trade.Fee, feeValue, t, true)
+
+	default:
+		return fmt.Errorf("unexpected trade side %q of trade %d", trade.Side, trade.ID)
+	}
+
+	return nil
+}
+
+func (c *GainsCalculator) addDeposit(deposit types.Deposit) error {
+	t := deposit.Time.Time()
+	value, err := c.value(deposit.Asset, deposit.Amount, t)
+	if err != nil {
+		return err
+	}
+
+	c.acquire(deposit.Asset, deposit.Amount, value, t)
+	return nil
+}
+
+func (c *GainsCalculator) addWithdraw(withdraw types.Withdraw) error {
+	t := withdraw.ApplyTime.Time()
+	c.dispose("withdraw", withdraw.Asset, withdraw.Amount, fixedpoint.Zero, t, false)
+
+	if withdraw.TransactionFee.Sign() > 0 {
+		feeCurrency := withdraw.TransactionFeeCurrency
+		if feeCurrency == "" {
+			feeCurrency = withdraw.Asset
+		}
+
+		feeValue, err := c.value(feeCurrency, withdraw.TransactionFee, t)
+		if err != nil {
+			return err
+		}
+
+		c.dispose("withdraw fee", feeCurrency, withdraw.TransactionFee, feeValue, t, true)
+	}
+
+	return nil
+}
+
+func (c *GainsCalculator) addReward(reward types.Reward) error {
+	t := reward.CreatedAt.Time()
+	value, err := c.value(reward.Currency, reward.Quantity, t)
+	if err != nil {
+		return err
+	}
+
+	c.records = append(c.records, GainRecord{
+		Type:         GainTypeIncome,
+		Description:  string(reward.Type) + " reward",
+		Asset:        reward.Currency,
+		Quantity:     reward.Quantity,
+		DateAcquired: t,
+		DateSold:     t,
+		Proceeds:     value,
+		CostBasis:    fixedpoint.Zero,
+		Gain:         value,
+	})
+
+	c.acquire(reward.Currency, reward.Quantity, value, t)
+	return nil
+}
+
+const gainsDateLayout = "2006-01-02"
+
+// WriteGainsCSV writes the gain records in the layout of the capital gains forms:
+// description, date acquired, date sold, proceeds, cost basis and gain.
+func WriteGainsCSV(w io.Writer, records []GainRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"Type", "Description", "Asset", "Quantity", "Date Acquired", "Date Sold", "Proceeds", "Cost Basis", "Gain",
+	}); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		dateAcquired := ""
+		if !record.DateAcquired.IsZero() {
+			dateAcquired = record.DateAcquired.UTC().Format(gainsDateLayout)
+		}
+
+		if err := writer.Write([]string{
+			string(record.Type),
+			record.Description,
+			record.Asset,
+			record.Quantity.String(),
+			dateAcquired,
+			record.DateSold.UTC().Format(gainsDateLayout),
+			record.Proceeds.String(),
+			record.CostBasis.String(),
+			record.Gain.String(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,211 @@
+package pnl
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type pricePoint struct {
+	time  time.Time
+	price fixedpoint.Value
+}
+
+// staticPrices returns the last price before the given time
+type staticPrices map[string][]pricePoint
+
+func (p staticPrices) QueryPrice(currency, reportingCurrency string, t time.Time) (fixedpoint.Value, error) {
+	price := fixedpoint.Zero
+	found := false
+	for _, point := range p[currency+reportingCurrency] {
+		if point.time.After(t) {
+			break
+		}
+
+		price = point.price
+		found = true
+	}
+
+	if !found {
+		return fixedpoint.Zero, fmt.Errorf("price of %s not found", currency)
+	}
+
+	return price, nil
+}
+
+func TestGainsCalculator_Calculate(t *testing.T) {
+	t0 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return t0.AddDate(0, 0, n) }
+
+	markets := types.MarketMap{
+		"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+	}
+
+	prices := staticPrices{
+		"BTCUSDT": {{day(1), fixedpoint.NewFromFloat(10000)}, {day(3), fixedpoint.NewFromFloat(20000)}, {day(4), fixedpoint.NewFromFloat(30000)}},
+		"BNBUSDT": {{day(2), fixedpoint.NewFromFloat(100)}, {day(3), fixedpoint.NewFromFloat(200)}},
+	}
+
+	trades := []types.Trade{
+		{
+			ID: 1, Symbol: "BTCUSDT", Side: types.SideTypeBuy, Time: types.Time(day(1)),
+			Price: fixedpoint.NewFromFloat(10000), Quantity: fixedpoint.NewFromFloat(1), QuoteQuantity: fixedpoint.NewFromFloat(10000),
+			Fee: fixedpoint.NewFromFloat(0.001), FeeCurrency: "BTC",
+		},
+		{
+			ID: 2, Symbol: "BTCUSDT", Side: types.SideTypeBuy, Time: types.Time(day(3)),
+			Price: fixedpoint.NewFromFloat(20000), Quantity: fixedpoint.NewFromFloat(1), QuoteQuantity: fixedpoint.NewFromFloat(20000),
+			Fee: fixedpoint.NewFromFloat(1), FeeCurrency: "BNB",
+		},
+		{
+			ID: 3, Symbol: "BTCUSDT", Side: types.SideTypeSell, Time: types.Time(day(4)),
+			Price: fixedpoint.NewFromFloat(30000), Quantity: fixedpoint.NewFromFloat(1.5), QuoteQuantity: fixedpoint.NewFromFloat(45000),
+			Fee: fixedpoint.NewFromFloat(45), FeeCurrency: "USDT",
+		},
+		{
+			ID: 4, Symbol: "BTCUSDT", Side: types.SideTypeSell, Time: types.Time(day(4)),
+			Price: fixedpoint.NewFromFloat(30000), Quantity: fixedpoint.NewFromFloat(0.1), QuoteQuantity: fixedpoint.NewFromFloat(3000),
+			IsFutures: true,
+		},
+	}
+
+	rewards := []types.Reward{
+		{Type: types.RewardCommission, Currency: "BNB", Quantity: fixedpoint.NewFromFloat(10), CreatedAt: types.Time(day(2))},
+	}
+
+	withdraws := []types.Withdraw{
+		{
+			Asset: "BTC", Amount: fixedpoint.NewFromFloat(0.4), ApplyTime: types.Time(day(5)),
+			TransactionFee: fixedpoint.NewFromFloat(0.0005), TransactionFeeCurrency: "BTC",
+		},
+	}
+
+	t.Run("fifo", func(t *testing.T) {
+		calculator := &GainsCalculator{
+			ReportingCurrency: "USDT",
+			Method:            CostBasisFIFO,
+			Markets:           markets,
+			Prices:            prices,
+		}
+
+		records, err := calculator.Calculate(trades, nil, withdraws, rewards)
+		assert.NoError(t, err)
+		if !assert.Len(t, records, 5) {
+			return
+		}
+
+		// reward income
+		assert.Equal(t, GainTypeIncome, records[0].Type)
+		assert.Equal(t, "BNB", records[0].Asset)
+		assert.Equal(t, "1000", records[0].Gain.String())
+
+		// the BNB fee is a disposal at its market value
+		assert.Equal(t, GainTypeDisposal, records[1].Type)
+		assert.Equal(t, "BNB", records[1].Asset)
+		assert.Equal(t, "200", records[1].Proceeds.String())
+		assert.Equal(t, "100", records[1].CostBasis.String())
+
+		// the first lot is reduced by the BTC fee
+		assert.Equal(t, "0.999", records[2].Quantity.String())
+		assert.Equal(t, day(1), records[2].DateAcquired)
+		assert.Equal(t, day(4), records[2].DateSold)
+		assert.Equal(t, "29940.03", records[2].Proceeds.String())
+		assert.Equal(t, "10000", records[2].CostBasis.String())
+
+		// the BNB fee is added to the cost basis of the second lot
+		assert.Equal(t, "0.501", records[3].Quantity.String())
+		assert.Equal(t, "15014.97", records[3].Proceeds.String())
+		assert.Equal(t, "10120.2", records[3].CostBasis.String())
+		assert.Equal(t, "4894.77", records[3].Gain.String())
+
+		// the withdrawal removes 0.4 BTC without gain, the withdrawal fee is a disposal
+		assert.Equal(t, "withdraw fee", records[4].Description)
+		assert.Equal(t, "15", records[4].Proceeds.String())
+		assert.InDelta(t, 10.1, records[4].CostBasis.Float64(), 1e-8)
+
+		lots := calculator.OpenLots("BTC")
+		if assert.Len(t, lots, 1) {
+			assert.InDelta(t, 0.0985, lots[0].Quantity.Float64(), 1e-8)
+		}
+	})
+
+	t.Run("hifo", func(t *testing.T) {
+		calculator := &GainsCalculator{
+			ReportingCurrency: "USDT",
+			Method:            CostBasisHighestCost,
+			Markets:           markets,
+			Prices:            prices,
+		}
+
+		records, err := calculator.Calculate(trades, nil, nil, nil)
+		assert.NoError(t, err)
+		if !assert.Len(t, records, 3) {
+			return
+		}
+
+		// without the reward, the BNB fee is disposed with an unknown cost basis
+		assert.True(t, records[0].DateAcquired.IsZero())
+		assert.Equal(t, "200", records[0].Gain.String())
+
+		assert.Equal(t, day(3), records[1].DateAcquired)
+		assert.Equal(t, "1", records[1].Quantity.String())
+		assert.Equal(t, "29970", records[1].Proceeds.String())
+		assert.Equal(t, "20200", records[1].CostBasis.String())
+
+		assert.Equal(t, day(1), records[2].DateAcquired)
+		assert.Equal(t, "0.5", records[2].Quantity.String())
+		assert.InDelta(t, 5005.005005, records[2].CostBasis.Float64(), 1e-6)
+	})
+
+	t.Run("missing price", func(t *testing.T) {
+		calculator := &GainsCalculator{
+			ReportingCurrency: "USD",
+			Method:            CostBasisFIFO,
+			Markets:           markets,
+			Prices:            prices,
+		}
+
+		_, err := calculator.Calculate(trades, nil, nil, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestWriteGainsCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteGainsCSV(buf, []GainRecord{
+		{
+			Type:         GainTypeDisposal,
+			Description:  "sell 1 BTCUSDT",
+			Asset:        "BTC",
+			Quantity:     fixedpoint.NewFromFloat(1),
+			DateAcquired: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			DateSold:     time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			Proceeds:     fixedpoint.NewFromFloat(30000),
+			CostBasis:    fixedpoint.NewFromFloat(10000),
+			Gain:         fixedpoint.NewFromFloat(20000),
+		},
+		{
+			Type:     GainTypeDisposal,
+			Asset:    "BNB",
+			Quantity: fixedpoint.NewFromFloat(1),
+			DateSold: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			Proceeds: fixedpoint.NewFromFloat(200),
+			Gain:     fixedpoint.NewFromFloat(200),
+		},
+	})
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, "Type,Description,Asset,Quantity,Date Acquired,Date Sold,Proceeds,Cost Basis,Gain", lines[0])
+		assert.Equal(t, "disposal,sell 1 BTCUSDT,BTC,1,2021-01-01,2021-03-01,30000,10000,20000", lines[1])
+		assert.Equal(t, "disposal,,BNB,1,,2021-03-01,200,0,200", lines[2])
+	}
+}
@@ -0,0 +1,278 @@
+package pnl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// CostBasisMethod is the method for selecting the lots to be closed
+type CostBasisMethod string
+
+const (
+	CostBasisAverage CostBasisMethod = "average"
+	CostBasisFIFO    CostBasisMethod = "fifo"
+	CostBasisLIFO    CostBasisMethod = "lifo"
+
+	// CostBasisHighestCost closes the long lot with the highest price (or the short lot with the lowest price) first,
+	// which realizes the least gain.
+	CostBasisHighestCost CostBasisMethod = "hifo"
+)
+
+func ParseCostBasisMethod(s string) (CostBasisMethod, error) {
+	switch m := CostBasisMethod(strings.ToLower(s)); m {
+	case "", CostBasisAverage:
+		return CostBasisAverage, nil
+	case CostBasisFIFO, CostBasisLIFO, CostBasisHighestCost:
+		return m, nil
+	}
+
+	return "", fmt.Errorf("unsupported cost basis method %q, valid methods are: average, fifo, lifo, hifo", s)
+}
+
+// Lot is an open tax lot, a long lot is opened by a buy trade and a short lot is opened by a sell trade.
+type Lot struct {
+	TradeID  uint64           `json:"tradeID"`
+	Side     types.SideType   `json:"side"`
+	Time     time.Time        `json:"time"`
+	Price    fixedpoint.Value `json:"price"`
+	Quantity fixedpoint.Value `json:"quantity"`
+
+	// FeePerUnit is the quote currency fee allocated to each unit of the lot
+	FeePerUnit fixedpoint.Value `json:"feePerUnit"`
+}
+
+// RealizedGain is the realized gain record of a closed (or partially closed) lot
+type RealizedGain struct {
+	Symbol   string           `json:"symbol"`
+	Side     types.SideType   `json:"side"`
+	Quantity fixedpoint.Value `json:"quantity"`
+
+	OpenTradeID  uint64    `json:"openTradeID"`
+	CloseTradeID uint64    `json:"closeTradeID"`
+	OpenTime     time.Time `json:"openTime"`
+	CloseTime    time.Time `json:"closeTime"`
+
+	OpenPrice  fixedpoint.Value `json:"openPrice"`
+	ClosePrice fixedpoint.Value `json:"closePrice"`
+
+	// Cost is the quote amount spent on the lot, including the quote currency fees
+	Cost fixedpoint.Value `json:"cost"`
+
+	// Proceeds is the quote amount received from the lot, excluding the quote currency fees
+	Proceeds fixedpoint.Value `json:"proceeds"`
+
+	Gain fixedpoint.Value `json:"gain"`
+
+	HoldingPeriod time.Duration `json:"holdingPeriod"`
+}
+
+// LotCostCalculator calculates the realized gains by tax lots.
+// Like the AverageCostCalculator, the fee in the base currency reduces the trade quantity,
+// and the fee in the quote currency is added to the cost (or deducted from the proceeds).
+// The fees in other currencies are converted into the quote currency if PriceLookup is set,
+// otherwise they are collected in the report only.
+type LotCostCalculator struct {
+	TradingFeeCurrency string
+	Market             types.Market
+	Method             CostBasisMethod
+	PriceLookup        types.PriceLookup
+
+	// FundingFees and Interests are the funding payments and the margin interests,
+	// the records attributed to the symbol are added into the net profit.
+	FundingFees []types.FundingFee
+	Interests   []types.MarginInterest
+}
+
+// selectLot returns the index of the lot to be closed
+func selectLot(method CostBasisMethod, lots []Lot) int {
+	switch method {
+	case CostBasisLIFO:
+		return len(lots) - 1
+
+	case CostBasisHighestCost:
+		idx := 0
+		for i, lot := range lots {
+			switch lot.Side {
+			case types.SideTypeBuy:
+				if lot.Price.Compare(lots[idx].Price) > 0 {
+					idx = i
+				}
+			case types.SideTypeSell:
+				if lot.Price.Compare(lots[idx].Price) < 0 {
+					idx = i
+				}
+			}
+		}
+		return idx
+	}
+
+	return 0
+}
+
+func (c *LotCostCalculator) Calculate(symbol string, trades []types.Trade, currentPrice fixedpoint.Value) *LotCostPnlReport {
+	report := &LotCostPnlReport{
+		Symbol:       symbol,
+		Market:       c.Market,
+		Method:       c.Method,
+		LastPrice:    currentPrice,
+		CurrencyFees: map[string]fixedpoint.Value{},
+	}
+
+	if len(trades) == 0 {
+		return report
+	}
+
+	report.StartTime = time.Time(trades[0].Time)
+
+	var lots []Lot
+	var tradeIDs = map[uint64]struct{}{}
+
+	for _, trade := range trades {
+		if _, exists := tradeIDs[trade.ID]; exists {
+			log.Warnf("duplicated trade: %+v", trade)
+			continue
+		}
+
+		if trade.Symbol != symbol {
+			continue
+		}
+
+		tradeIDs[trade.ID] = struct{}{}
+		report.NumTrades++
+		report.CurrencyFees[trade.FeeCurrency] = report.CurrencyFees[trade.FeeCurrency].Add(trade.Fee)
+
+		quantity := trade.Quantity
+		quoteFee := fixedpoint.Zero
+		switch trade.FeeCurrency {
+		case c.Market.BaseCurrency:
+			quantity = quantity.Sub(trade.Fee)
+		case c.Market.QuoteCurrency:
+			quoteFee = trade.Fee
+		default:
+			if c.PriceLookup != nil && !trade.Fee.IsZero() {
+				price, err := c.PriceLookup.QueryPrice(trade.FeeCurrency, c.Market.QuoteCurrency, time.Time(trade.Time))
+				if err != nil {
+					log.WithError(err).Warnf("can not convert the %s fee of trade %d", trade.FeeCurrency, trade.ID)
+				} else {
+					quoteFee = trade.Fee.Mul(price)
+				}
+			}
+		}
+
+		if quantity.Sign() <= 0 {
+			continue
+		}
+
+		switch trade.Side {
+		case types.SideTypeBuy:
+			report.BuyVolume = report.BuyVolume.Add(trade.Quantity)
+		case types.SideTypeSell:
+			report.SellVolume = report.SellVolume.Add(trade.Quantity)
+		}
+
+		feePerUnit := quoteFee.Div(quantity)
+		tradeTime := time.Time(trade.Time)
+
+		// close the lots of the opposite side
+		for quantity.Sign() > 0 {
+			var opposite []int
+			for i, lot := range lots {
+				if lot.Side != trade.Side {
+					opposite = append(opposite, i)
+				}
+			}
+
+			if len(opposite) == 0 {
+				break
+			}
+
+			candidates := make([]Lot, len(opposite))
+			for i, idx := range opposite {
+				candidates[i] = lots[idx]
+			}
+
+			idx := opposite[selectLot(c.Method, candidates)]
+			lot := &lots[idx]
+
+			q := fixedpoint.Min(lot.Quantity, quantity)
+			gain := RealizedGain{
+				Symbol:        symbol,
+				Side:          lot.Side,
+				Quantity:      q,
+				OpenTradeID:   lot.TradeID,
+				CloseTradeID:  trade.ID,
+				OpenTime:      lot.Time,
+				CloseTime:     tradeTime,
+				OpenPrice:     lot.Price,
+				ClosePrice:    trade.Price,
+				HoldingPeriod: tradeTime.Sub(lot.Time),
+			}
+
+			if lot.Side == types.SideTypeBuy {
+				// long lot: bought at the open price and sold at the close price
+				gain.Cost = lot.Price.Add(lot.FeePerUnit).Mul(q)
+				gain.Proceeds = trade.Price.Sub(feePerUnit).Mul(q)
+			} else {
+				// short lot: sold at the open price and bought back at the close price
+				gain.Cost = trade.Price.Add(feePerUnit).Mul(q)
+				gain.Proceeds = lot.Price.Sub(lot.FeePerUnit).Mul(q)
+			}
+
+			gain.Gain = gain.Proceeds.Sub(gain.Cost)
+			report.Realized = append(report.Realized, gain)
+			report.Profit = report.Profit.Add(gain.Gain)
+
+			lot.Quantity = lot.Quantity.Sub(q)
+			quantity = quantity.Sub(q)
+
+			if lot.Quantity.IsZero() {
+				lots = append(lots[:idx], lots[idx+1:]...)
+			}
+		}
+
+		// open a new lot with the remaining quantity
+		if quantity.Sign() > 0 {
+			lots = append(lots, Lot{
+				TradeID:    trade.ID,
+				Side:       trade.Side,
+				Time:       tradeTime,
+				Price:      trade.Price,
+				Quantity:   quantity,
+				FeePerUnit: feePerUnit,
+			})
+		}
+	}
+
+	position := types.NewPositionFromMarket(c.Market)
+	if c.PriceLookup != nil {
+		position.SetPriceLookup(c.PriceLookup)
+	}
+
+	report.FundingFee, report.Interest = addCarryCosts(position, c.FundingFees, c.Interests)
+	report.NetProfit = report.Profit.Add(report.FundingFee).Sub(report.Interest)
+
+	report.OpenLots = lots
+	for _, lot := range lots {
+		switch lot.Side {
+		case types.SideTypeBuy:
+			report.Stock = report.Stock.Add(lot.Quantity)
+			if currentPrice.Sign() > 0 {
+				report.UnrealizedProfit = report.UnrealizedProfit.Add(currentPrice.Sub(lot.Price.Add(lot.FeePerUnit)).Mul(lot.Quantity))
+			}
+
+		case types.SideTypeSell:
+			report.Stock = report.Stock.Sub(lot.Quantity)
+			if currentPrice.Sign() > 0 {
+				report.UnrealizedProfit = report.UnrealizedProfit.Add(lot.Price.Sub(lot.FeePerUnit).Sub(currentPrice).Mul(lot.Quantity))
+			}
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,149 @@
+package pnl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestLotCostCalculator_Calculate(t *testing.T) {
+	market := types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+	startTime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newTrade := func(id uint64, side types.SideType, price, quantity float64, days int) types.Trade {
+		p := fixedpoint.NewFromFloat(price)
+		q := fixedpoint.NewFromFloat(quantity)
+		return types.Trade{
+			ID:            id,
+			Symbol:        "BTCUSDT",
+			Side:          side,
+			Price:         p,
+			Quantity:      q,
+			QuoteQuantity: p.Mul(q),
+			FeeCurrency:   "BNB",
+			Time:          types.Time(startTime.AddDate(0, 0, days)),
+		}
+	}
+
+	trades := []types.Trade{
+		newTrade(1, types.SideTypeBuy, 100, 1, 0),
+		newTrade(2, types.SideTypeBuy, 300, 1, 1),
+		newTrade(3, types.SideTypeBuy, 200, 1, 2),
+		newTrade(4, types.SideTypeSell, 250, 1.5, 10),
+	}
+
+	tests := []struct {
+		method     CostBasisMethod
+		profit     string
+		unrealized string
+		openTrades []uint64
+	}{
+		// 1 @ 100 + 0.5 @ 300
+		{method: CostBasisFIFO, profit: "125", unrealized: "25", openTrades: []uint64{2, 3}},
+		// 1 @ 200 + 0.5 @ 300
+		{method: CostBasisLIFO, profit: "25", unrealized: "125", openTrades: []uint64{1, 2}},
+		// 1 @ 300 + 0.5 @ 200
+		{method: CostBasisHighestCost, profit: "-25", unrealized: "175", openTrades: []uint64{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			calculator := &LotCostCalculator{Market: market, Method: tt.method}
+			report := calculator.Calculate("BTCUSDT", trades, fixedpoint.NewFromInt(250))
+
+			assert.Equal(t, 4, report.NumTrades)
+			assert.Equal(t, tt.profit, report.Profit.String())
+			assert.Equal(t, tt.unrealized, report.UnrealizedProfit.String())
+			assert.Equal(t, "1.5", report.Stock.String())
+			assert.Len(t, report.Realized, 2)
+
+			var openTrades []uint64
+			for _, lot := range report.OpenLots {
+				openTrades = append(openTrades, lot.TradeID)
+			}
+			assert.ElementsMatch(t, tt.openTrades, openTrades)
+		})
+	}
+
+	report := (&LotCostCalculator{Market: market, Method: CostBasisFIFO}).Calculate("BTCUSDT", trades, fixedpoint.Zero)
+	assert.Equal(t, 10*24*time.Hour, report.Realized[0].HoldingPeriod)
+	assert.Equal(t, uint64(1), report.Realized[0].OpenTradeID)
+	assert.Equal(t, uint64(4), report.Realized[0].CloseTradeID)
+}
+
+func TestLotCostCalculator_ShortLotAndFees(t *testing.T) {
+	market := types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+	trades := []types.Trade{
+		{ID: 1, Symbol: "BTCUSDT", Side: types.SideTypeSell, Price: fixedpoint.NewFromInt(200), Quantity: fixedpoint.One, Fee: fixedpoint.NewFromInt(2), FeeCurrency: "USDT"},
+		{ID: 2, Symbol: "BTCUSDT", Side: types.SideTypeBuy, Price: fixedpoint.NewFromInt(150), Quantity: fixedpoint.NewFromInt(2), Fee: fixedpoint.NewFromInt(1), FeeCurrency: "BTC"},
+	}
+
+	report := (&LotCostCalculator{Market: market, Method: CostBasisFIFO}).Calculate("BTCUSDT", trades, fixedpoint.NewFromInt(150))
+
+	// the short lot: sold 1 @ 200 with 2 USDT fee, covered @ 150
+	if assert.Len(t, report.Realized, 1) {
+		assert.Equal(t, types.SideTypeSell, report.Realized[0].Side)
+		assert.Equal(t, "48", report.Realized[0].Gain.String())
+	}
+
+	// the base fee is deducted from the buy quantity, nothing left for a new lot
+	assert.Len(t, report.OpenLots, 0)
+	assert.Equal(t, "2", report.CurrencyFees["USDT"].String())
+	assert.Equal(t, "1", report.CurrencyFees["BTC"].String())
+}
+
+func TestLotCostCalculator_FundingFeeAndInterest(t *testing.T) {
+	market := types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+	trades := []types.Trade{
+		{ID: 1, Symbol: "BTCUSDT", Side: types.SideTypeBuy, Price: fixedpoint.NewFromInt(100), Quantity: fixedpoint.One, FeeCurrency: "USDT"},
+		{ID: 2, Symbol: "BTCUSDT", Side: types.SideTypeSell, Price: fixedpoint.NewFromInt(150), Quantity: fixedpoint.One, FeeCurrency: "USDT"},
+	}
+
+	calculator := &LotCostCalculator{
+		Market: market,
+		Method: CostBasisFIFO,
+		FundingFees: []types.FundingFee{
+			{Symbol: "BTCUSDT", Asset: "USDT", Amount: fixedpoint.NewFromInt(-3)},
+			{Symbol: "BTCUSDT", Asset: "USDT", Amount: fixedpoint.NewFromInt(1)},
+			{Symbol: "ETHUSDT", Asset: "USDT", Amount: fixedpoint.NewFromInt(-10)},
+		},
+		Interests: []types.MarginInterest{
+			{Asset: "USDT", IsolatedSymbol: "BTCUSDT", Interest: fixedpoint.NewFromInt(4)},
+			{Asset: "ETH", IsolatedSymbol: "BTCUSDT", Interest: fixedpoint.One},
+			// the cross margin interest is not attributed to the position
+			{Asset: "USDT", Interest: fixedpoint.NewFromInt(5)},
+		},
+	}
+
+	report := calculator.Calculate("BTCUSDT", trades, fixedpoint.NewFromInt(150))
+	assert.Equal(t, "50", report.Profit.String())
+	assert.Equal(t, "-2", report.FundingFee.String())
+	assert.Equal(t, "4", report.Interest.String())
+	assert.Equal(t, "44", report.NetProfit.String())
+
+	avgReport := (&AverageCostCalculator{
+		Market:      market,
+		FundingFees: calculator.FundingFees,
+		Interests:   calculator.Interests,
+	}).Calculate("BTCUSDT", trades, fixedpoint.NewFromInt(150))
+	assert.Equal(t, "-2", avgReport.FundingFee.String())
+	assert.Equal(t, "4", avgReport.Interest.String())
+	assert.Equal(t, avgReport.Profit.Sub(avgReport.FeeInUSD).Sub(fixedpoint.NewFromInt(6)).String(), avgReport.NetProfit.String())
+}
+
+func TestParseCostBasisMethod(t *testing.T) {
+	m, err := ParseCostBasisMethod("FIFO")
+	assert.NoError(t, err)
+	assert.Equal(t, CostBasisFIFO, m)
+
+	m, err = ParseCostBasisMethod("")
+	assert.NoError(t, err)
+	assert.Equal(t, CostBasisAverage, m)
+
+	_, err = ParseCostBasisMethod("random")
+	assert.Error(t, err)
+}
@@ -2,7 +2,9 @@ package pnl
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -28,6 +30,8 @@ type AverageCostPnlReport struct {
 	BuyVolume        fixedpoint.Value            `json:"buyVolume,omitempty"`
 	SellVolume       fixedpoint.Value            `json:"sellVolume,omitempty"`
 	FeeInUSD         fixedpoint.Value            `json:"feeInUSD"`
+	FundingFee       fixedpoint.Value            `json:"fundingFee,omitempty"`
+	Interest         fixedpoint.Value            `json:"interest,omitempty"`
 	Stock            fixedpoint.Value            `json:"stock"`
 	CurrencyFees     map[string]fixedpoint.Value `json:"currencyFees"`
 }
@@ -55,6 +59,17 @@ func (report AverageCostPnlReport) Print() {
 		color.Red("PROFIT: %s", types.USD.FormatMoney(report.Profit))
 	}
 
+	if !report.FundingFee.IsZero() || !report.Interest.IsZero() {
+		color.Green("FUNDING FEE: %s", types.USD.FormatMoney(report.FundingFee))
+		color.Green("MARGIN INTEREST: %s", types.USD.FormatMoney(report.Interest))
+
+		if report.NetProfit.Sign() > 0 {
+			color.Green("NET PROFIT: %s", types.USD.FormatMoney(report.NetProfit))
+		} else {
+			color.Red("NET PROFIT: %s", types.USD.FormatMoney(report.NetProfit))
+		}
+	}
+
 	if report.UnrealizedProfit.Sign() > 0 {
 		color.Green("UNREALIZED PROFIT: %s", types.USD.FormatMoney(report.UnrealizedProfit))
 	} else {
@@ -90,3 +105,109 @@ func (report AverageCostPnlReport) SlackAttachment() slack.Attachment {
 		FooterIcon: "",
 	}
 }
+
+type LotCostPnlReport struct {
+	LastPrice fixedpoint.Value `json:"lastPrice"`
+	StartTime time.Time        `json:"startTime"`
+	Symbol    string           `json:"symbol"`
+	Market    types.Market     `json:"market"`
+	Method    CostBasisMethod  `json:"method"`
+
+	NumTrades        int                         `json:"numTrades"`
+	Profit           fixedpoint.Value            `json:"profit"`
+	NetProfit        fixedpoint.Value            `json:"netProfit"`
+	UnrealizedProfit fixedpoint.Value            `json:"unrealizedProfit"`
+	FundingFee       fixedpoint.Value            `json:"fundingFee,omitempty"`
+	Interest         fixedpoint.Value            `json:"interest,omitempty"`
+	BuyVolume        fixedpoint.Value            `json:"buyVolume,omitempty"`
+	SellVolume       fixedpoint.Value            `json:"sellVolume,omitempty"`
+	Stock            fixedpoint.Value            `json:"stock"`
+	CurrencyFees     map[string]fixedpoint.Value `json:"currencyFees"`
+
+	Realized []RealizedGain `json:"realized"`
+	OpenLots []Lot          `json:"openLots"`
+}
+
+func (report *LotCostPnlReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(report, "", "  ")
+}
+
+func (report LotCostPnlReport) Print() {
+	color.Green("TRADES SINCE: %v", report.StartTime)
+	color.Green("COST BASIS METHOD: %s", report.Method)
+	color.Green("NUMBER OF TRADES: %d", report.NumTrades)
+	color.Green("TOTAL BUY VOLUME: %v", report.BuyVolume)
+	color.Green("TOTAL SELL VOLUME: %v", report.SellVolume)
+
+	color.Green("CURRENT PRICE: %s", types.USD.FormatMoney(report.LastPrice))
+	color.Green("CURRENCY FEES:")
+	for currency, fee := range report.CurrencyFees {
+		color.Green(" - %s: %s", currency, fee.String())
+	}
+
+	color.Green("REALIZED GAINS:")
+	for _, gain := range report.Realized {
+		line := fmt.Sprintf(" - %s %s lot #%d -> #%d, %s @ %s -> %s, held %s, gain %s",
+			gain.Side, gain.Symbol, gain.OpenTradeID, gain.CloseTradeID,
+			gain.Quantity.String(), gain.OpenPrice.String(), gain.ClosePrice.String(),
+			gain.HoldingPeriod.Round(time.Second), types.USD.FormatMoney(gain.Gain))
+
+		if gain.Gain.Sign() >= 0 {
+			color.Green("%s", line)
+		} else {
+			color.Red("%s", line)
+		}
+	}
+
+	color.Green("OPEN LOTS:")
+	for _, lot := range report.OpenLots {
+		color.Green(" - %s #%d %s @ %s since %s", lot.Side, lot.TradeID, lot.Quantity.String(), lot.Price.String(), lot.Time)
+	}
+
+	if report.Profit.Sign() > 0 {
+		color.Green("PROFIT: %s", types.USD.FormatMoney(report.Profit))
+	} else {
+		color.Red("PROFIT: %s", types.USD.FormatMoney(report.Profit))
+	}
+
+	if !report.FundingFee.IsZero() || !report.Interest.IsZero() {
+		color.Green("FUNDING FEE: %s", types.USD.FormatMoney(report.FundingFee))
+		color.Green("MARGIN INTEREST: %s", types.USD.FormatMoney(report.Interest))
+
+		if report.NetProfit.Sign() > 0 {
+			color.Green("NET PROFIT: %s", types.USD.FormatMoney(report.NetProfit))
+		} else {
+			color.Red("NET PROFIT: %s", types.USD.FormatMoney(report.NetProfit))
+		}
+	}
+
+	if report.UnrealizedProfit.Sign() > 0 {
+		color.Green("UNREALIZED PROFIT: %s", types.USD.FormatMoney(report.UnrealizedProfit))
+	} else {
+		color.Red("UNREALIZED PROFIT: %s", types.USD.FormatMoney(report.UnrealizedProfit))
+	}
+}
+
+func (report LotCostPnlReport) SlackAttachment() slack.Attachment {
+	var color = slackstyle.Red
+
+	if report.UnrealizedProfit.Sign() > 0 {
+		color = slackstyle.Green
+	}
+
+	return slack.Attachment{
+		Title: report.Symbol + " Profit and Loss report (" + strings.ToUpper(string(report.Method)) + ")",
+		Text:  "Profit " + types.USD.FormatMoney(report.Profit),
+		Color: color,
+		Fields: []slack.AttachmentField{
+			{Title: "Profit", Value: types.USD.FormatMoney(report.Profit)},
+			{Title: "Unrealized Profit", Value: types.USD.FormatMoney(report.UnrealizedProfit)},
+			{Title: "Current Price", Value: report.Market.FormatPrice(report.LastPrice), Short: true},
+			{Title: "Open Lots", Value: strconv.Itoa(len(report.OpenLots)), Short: true},
+			{Title: "Stock", Value: report.Stock.String(), Short: true},
+			{Title: "Number of Trades", Value: strconv.Itoa(report.NumTrades), Short: true},
+		},
+		Footer:     report.StartTime.Format(time.RFC822),
+		FooterIcon: "",
+	}
+}
@@ -64,6 +64,10 @@ type Exchange struct {
 	matchingBooks      map[string]*SimplePriceMatching
 	matchingBooksMutex sync.Mutex
 
+	// marketTradeSymbols are the symbols that subscribe the market trades,
+	// the market trades are synthesized from the 1m klines
+	marketTradeSymbols map[string]struct{}
+
 	markets types.MarketMap
 }
 
@@ -339,6 +343,12 @@ func (e *Exchange) SubscribeMarketData(extraIntervals ...types.Interval) (chan t
 		case types.KLineChannel:
 			loadedIntervals[types.Interval(sub.Options.Interval)] = struct{}{}
 
+		case types.MarketTradeChannel:
+			if e.marketTradeSymbols == nil {
+				e.marketTradeSymbols = make(map[string]struct{})
+			}
+			e.marketTradeSymbols[sub.Symbol] = struct{}{}
+
 		default:
 			return nil, fmt.Errorf("stream channel %s is not supported in backtest", sub.Channel)
 		}
@@ -356,13 +366,18 @@ func (e *Exchange) SubscribeMarketData(extraIntervals ...types.Interval) (chan t
 
 	log.Infof("using symbols: %v and intervals: %v for back-testing", symbols, intervals)
 	log.Infof("querying klines from database...")
+	feeder, err := e.newKLineGapFeeder(symbols, intervals)
+	if err != nil {
+		return nil, err
+	}
+
 	klineC, errC := e.srv.QueryKLinesCh(e.startTime, e.endTime, e, symbols, intervals)
 	go func() {
 		if err := <-errC; err != nil {
 			log.WithError(err).Error("backtest data feed error")
 		}
 	}()
-	return klineC, nil
+	return feeder.Feed(klineC), nil
 }
 
 func (e *Exchange) ConsumeKLine(k types.KLine) {
@@ -375,6 +390,12 @@ func (e *Exchange) ConsumeKLine(k types.KLine) {
 
 		// here we generate trades and order updates
 		matching.processKLine(k)
+
+		if _, ok := e.marketTradeSymbols[k.Symbol]; ok {
+			for _, trade := range synthesizeMarketTrades(k) {
+				e.marketDataStream.EmitMarketTrade(trade)
+			}
+		}
 	}
 
 	e.marketDataStream.EmitKLineClosed(k)
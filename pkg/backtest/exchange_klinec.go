@@ -10,3 +10,37 @@ type ExchangeDataSource struct {
 	Exchange *Exchange
 	Session  *bbgo.ExchangeSession
 }
+
+// MergeExchangeDataSources reads the klines of the data sources in the order of the end time,
+// so that the klines of the different sessions are consumed at the same pace.
+// The klines with the same end time are read in the order of the sources.
+// It returns when all the sources are closed.
+func MergeExchangeDataSources(sources []ExchangeDataSource, handler func(k types.KLine, source *ExchangeDataSource)) {
+	heads := make([]*types.KLine, len(sources))
+	next := func(i int) {
+		heads[i] = nil
+		if k, more := <-sources[i].C; more {
+			heads[i] = &k
+		}
+	}
+
+	for i := range sources {
+		next(i)
+	}
+
+	for {
+		earliest := -1
+		for i, k := range heads {
+			if k != nil && (earliest == -1 || k.EndTime.Before(heads[earliest].EndTime.Time())) {
+				earliest = i
+			}
+		}
+
+		if earliest == -1 {
+			return
+		}
+
+		handler(*heads[earliest], &sources[earliest])
+		next(earliest)
+	}
+}
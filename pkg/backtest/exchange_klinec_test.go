@@ -0,0 +1,50 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestMergeExchangeDataSources(t *testing.T) {
+	startTime := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	newSource := func(symbol string, interval types.Interval, count int) ExchangeDataSource {
+		c := make(chan types.KLine, count)
+		for i := 0; i < count; i++ {
+			kStartTime := startTime.Add(time.Duration(i) * interval.Duration())
+			c <- types.KLine{
+				Symbol:    symbol,
+				Interval:  interval,
+				StartTime: types.Time(kStartTime),
+				EndTime:   types.Time(kStartTime.Add(interval.Duration() - time.Millisecond)),
+			}
+		}
+		close(c)
+		return ExchangeDataSource{C: c}
+	}
+
+	sources := []ExchangeDataSource{
+		newSource("BTCUSDT", types.Interval5m, 2),
+		newSource("ETHUSDT", types.Interval1m, 6),
+	}
+
+	var symbols []string
+	var lastEndTime time.Time
+	MergeExchangeDataSources(sources, func(k types.KLine, source *ExchangeDataSource) {
+		assert.Equal(t, k.Symbol == "BTCUSDT", source == &sources[0])
+		assert.False(t, k.EndTime.Before(lastEndTime), "klines must be in the order of the end time")
+		lastEndTime = k.EndTime.Time()
+		symbols = append(symbols, k.Symbol)
+	})
+
+	// the klines with the same end time are read in the order of the sources,
+	// and the remaining klines are read after the other source is closed
+	assert.Equal(t, []string{
+		"ETHUSDT", "ETHUSDT", "ETHUSDT", "ETHUSDT",
+		"BTCUSDT", "ETHUSDT",
+		"ETHUSDT", "BTCUSDT",
+	}, symbols)
+}
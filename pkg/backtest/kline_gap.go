@@ -0,0 +1,149 @@
+package backtest
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// kLineGapFeeder feeds the klines and handles the gaps between them,
+// the gaps that are marked as no data by the kline repair are skipped or forward-filled by the policy,
+// the other gaps are reported since the kline data should be repaired.
+type kLineGapFeeder struct {
+	policy bbgo.MissingKLinePolicy
+
+	// markedGaps are the marked no data ranges by symbol and interval
+	markedGaps map[string][]service.KLineGap
+}
+
+func kLineGapKey(symbol string, interval types.Interval) string {
+	return symbol + "." + interval.String()
+}
+
+func (f *kLineGapFeeder) isMarked(symbol string, interval types.Interval, gap service.KLineGap) bool {
+	for _, m := range f.markedGaps[kLineGapKey(symbol, interval)] {
+		if !m.Since.After(gap.Since) && !m.Until.Before(gap.Until) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextFiller returns the flat kline after the previous kline with the close price of the previous kline,
+// if the next kline is in a marked gap
+func (f *kLineGapFeeder) nextFiller(prev types.KLine) (types.KLine, bool) {
+	duration := prev.Interval.Duration()
+	t := prev.StartTime.Time().Add(duration)
+	if !f.isMarked(prev.Symbol, prev.Interval, service.KLineGap{Since: t, Until: t.Add(duration)}) {
+		return types.KLine{}, false
+	}
+
+	return types.KLine{
+		Exchange:  prev.Exchange,
+		Symbol:    prev.Symbol,
+		Interval:  prev.Interval,
+		StartTime: types.Time(t),
+		EndTime:   types.Time(t.Add(duration - time.Millisecond)),
+		Open:      prev.Close,
+		High:      prev.Close,
+		Low:       prev.Close,
+		Close:     prev.Close,
+		Closed:    true,
+	}, true
+}
+
+// fillUntil returns the forward-filled klines of all the symbols and intervals that end at or before the given time,
+// ordered by the end time, and updates the last klines.
+// The klines of the source are ordered by the end time across all the symbols and intervals,
+// so the fillers are generated before the real kline that ends after them to keep the time in order.
+func (f *kLineGapFeeder) fillUntil(lastKLines map[string]types.KLine, until time.Time) (fillers []types.KLine) {
+	for key, prev := range lastKLines {
+		for {
+			fk, ok := f.nextFiller(prev)
+			if !ok || fk.EndTime.After(until) {
+				break
+			}
+
+			fillers = append(fillers, fk)
+			prev = fk
+		}
+
+		lastKLines[key] = prev
+	}
+
+	sort.Slice(fillers, func(i, j int) bool {
+		if !fillers[i].EndTime.Time().Equal(fillers[j].EndTime.Time()) {
+			return fillers[i].EndTime.Before(fillers[j].EndTime.Time())
+		}
+
+		return kLineGapKey(fillers[i].Symbol, fillers[i].Interval) < kLineGapKey(fillers[j].Symbol, fillers[j].Interval)
+	})
+	return fillers
+}
+
+func (f *kLineGapFeeder) Feed(klineC chan types.KLine) chan types.KLine {
+	c := make(chan types.KLine, 500)
+
+	go func() {
+		defer close(c)
+
+		lastKLines := map[string]types.KLine{}
+		for k := range klineC {
+			if f.policy == bbgo.MissingKLineForwardFill {
+				for _, fk := range f.fillUntil(lastKLines, k.EndTime.Time()) {
+					c <- fk
+				}
+			}
+
+			key := kLineGapKey(k.Symbol, k.Interval)
+			if prev, ok := lastKLines[key]; ok {
+				expected := prev.StartTime.Time().Add(k.Interval.Duration())
+				if k.StartTime.After(expected) {
+					gap := service.KLineGap{Since: expected, Until: k.StartTime.Time()}
+					if !f.isMarked(k.Symbol, k.Interval, gap) {
+						log.Warnf("found unrepaired %s %s kline gap %s, please run backtest with --repair", k.Symbol, k.Interval, gap)
+					} else {
+						log.Infof("skipping %s %s kline gap %s that the exchange has no data", k.Symbol, k.Interval, gap)
+					}
+				}
+			}
+
+			lastKLines[key] = k
+			c <- k
+		}
+
+		// the marked gaps at the end of the data
+		if f.policy == bbgo.MissingKLineForwardFill {
+			for _, fk := range f.fillUntil(lastKLines, time.Unix(math.MaxInt32, 0)) {
+				c <- fk
+			}
+		}
+	}()
+
+	return c
+}
+
+func (e *Exchange) newKLineGapFeeder(symbols []string, intervals []types.Interval) (*kLineGapFeeder, error) {
+	feeder := &kLineGapFeeder{
+		policy:     e.config.MissingKLines,
+		markedGaps: make(map[string][]service.KLineGap),
+	}
+
+	for _, symbol := range symbols {
+		for _, interval := range intervals {
+			gaps, err := e.srv.QueryKLineGaps(context.Background(), e.Name(), symbol, interval, e.startTime, e.endTime)
+			if err != nil {
+				return nil, err
+			}
+
+			feeder.markedGaps[kLineGapKey(symbol, interval)] = gaps
+		}
+	}
+
+	return feeder, nil
+}
@@ -0,0 +1,126 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestKLine(interval types.Interval, start time.Time, price int64) types.KLine {
+	return types.KLine{
+		Symbol:    "BTCUSDT",
+		Interval:  interval,
+		StartTime: types.Time(start),
+		EndTime:   types.Time(start.Add(interval.Duration() - time.Millisecond)),
+		Close:     fixedpoint.NewFromInt(price),
+	}
+}
+
+func feedKLines(feeder *kLineGapFeeder, klines ...types.KLine) (fed []types.KLine) {
+	in := make(chan types.KLine, len(klines))
+	for _, k := range klines {
+		in <- k
+	}
+	close(in)
+
+	for k := range feeder.Feed(in) {
+		fed = append(fed, k)
+	}
+	return fed
+}
+
+func feedTestKLines(feeder *kLineGapFeeder, startTime time.Time, minutes ...int) (klines []types.KLine) {
+	for _, m := range minutes {
+		klines = append(klines, newTestKLine(types.Interval1m, startTime.Add(time.Duration(m)*time.Minute), int64(100+m)))
+	}
+	return feedKLines(feeder, klines...)
+}
+
+func TestKLineGapFeeder(t *testing.T) {
+	startTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	markedGaps := map[string][]service.KLineGap{
+		kLineGapKey("BTCUSDT", types.Interval1m): {
+			{Since: startTime.Add(2 * time.Minute), Until: startTime.Add(4 * time.Minute)},
+		},
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		feeder := &kLineGapFeeder{policy: bbgo.MissingKLineSkip, markedGaps: markedGaps}
+		klines := feedTestKLines(feeder, startTime, 0, 1, 4, 5)
+		assert.Len(t, klines, 4)
+	})
+
+	t.Run("forward fill", func(t *testing.T) {
+		feeder := &kLineGapFeeder{policy: bbgo.MissingKLineForwardFill, markedGaps: markedGaps}
+		klines := feedTestKLines(feeder, startTime, 0, 1, 4, 5)
+		if assert.Len(t, klines, 6) {
+			assert.Equal(t, startTime.Add(2*time.Minute), klines[2].StartTime.Time())
+			assert.Equal(t, "101", klines[2].Close.String())
+			assert.Equal(t, "101", klines[3].High.String())
+			assert.True(t, klines[3].Volume.IsZero())
+			assert.Equal(t, "104", klines[4].Close.String())
+		}
+	})
+
+	t.Run("unmarked gaps are not filled", func(t *testing.T) {
+		feeder := &kLineGapFeeder{policy: bbgo.MissingKLineForwardFill, markedGaps: markedGaps}
+		klines := feedTestKLines(feeder, startTime, 4, 5, 8)
+		assert.Len(t, klines, 3)
+	})
+}
+
+func TestKLineGapFeeder_MultipleIntervals(t *testing.T) {
+	startTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	markedGaps := map[string][]service.KLineGap{
+		kLineGapKey("BTCUSDT", types.Interval1m): {
+			{Since: startTime.Add(5 * time.Minute), Until: startTime.Add(8 * time.Minute)},
+		},
+		kLineGapKey("BTCUSDT", types.Interval5m): {
+			{Since: startTime.Add(5 * time.Minute), Until: startTime.Add(15 * time.Minute)},
+		},
+	}
+
+	// the source is ordered by the end time across the intervals
+	var in []types.KLine
+	for m := 0; m < 15; m++ {
+		if m >= 5 && m < 8 {
+			continue
+		}
+
+		in = append(in, newTestKLine(types.Interval1m, startTime.Add(time.Duration(m)*time.Minute), int64(100+m)))
+		if m == 4 {
+			in = append(in, newTestKLine(types.Interval5m, startTime, 200))
+		}
+	}
+
+	feeder := &kLineGapFeeder{policy: bbgo.MissingKLineForwardFill, markedGaps: markedGaps}
+	klines := feedKLines(feeder, in...)
+
+	// 15 1m klines and 3 5m klines
+	if !assert.Len(t, klines, 18) {
+		return
+	}
+
+	for i := 1; i < len(klines); i++ {
+		assert.False(t, klines[i].EndTime.Before(klines[i-1].EndTime.Time()), "kline %d %s ends before the previous kline %s", i, klines[i].String(), klines[i-1].String())
+	}
+
+	var filled5m []types.KLine
+	for _, k := range klines {
+		if k.Interval == types.Interval5m {
+			filled5m = append(filled5m, k)
+		}
+	}
+
+	if assert.Len(t, filled5m, 3) {
+		assert.Equal(t, startTime.Add(5*time.Minute), filled5m[1].StartTime.Time())
+		assert.Equal(t, "200", filled5m[1].Close.String())
+		assert.Equal(t, startTime.Add(10*time.Minute), filled5m[2].StartTime.Time())
+	}
+}
@@ -0,0 +1,45 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var four = fixedpoint.NewFromInt(4)
+
+// synthesizeMarketTrades synthesizes the market trades of a kline since we don't have the market trades in the backtest.
+// The price goes through open, low, high and close for the bullish kline, and open, high, low and close for the bearish kline,
+// the volume is split equally to the 4 trades.
+func synthesizeMarketTrades(k types.KLine) (trades []types.Trade) {
+	prices := []fixedpoint.Value{k.Open, k.Low, k.High, k.Close}
+	if k.Close.Compare(k.Open) < 0 {
+		prices = []fixedpoint.Value{k.Open, k.High, k.Low, k.Close}
+	}
+
+	quantity := k.Volume.Div(four)
+	startTime := k.StartTime.Time()
+	duration := k.EndTime.Time().Sub(startTime)
+	side := types.SideTypeBuy
+	for i, price := range prices {
+		if i > 0 && price.Compare(prices[i-1]) < 0 {
+			side = types.SideTypeSell
+		} else if i > 0 && price.Compare(prices[i-1]) > 0 {
+			side = types.SideTypeBuy
+		}
+
+		trades = append(trades, types.Trade{
+			Exchange:      k.Exchange,
+			Symbol:        k.Symbol,
+			Side:          side,
+			Price:         price,
+			Quantity:      quantity,
+			QuoteQuantity: quantity.Mul(price),
+			IsBuyer:       side == types.SideTypeBuy,
+			Time:          types.Time(startTime.Add(duration * time.Duration(i) / time.Duration(len(prices)-1))),
+		})
+	}
+
+	return trades
+}
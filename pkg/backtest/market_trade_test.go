@@ -0,0 +1,52 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestSynthesizeMarketTrades(t *testing.T) {
+	startTime := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	k := types.KLine{
+		Symbol:    "BTCUSDT",
+		Interval:  types.Interval1m,
+		StartTime: types.Time(startTime),
+		EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+		Open:      fixedpoint.NewFromInt(100),
+		High:      fixedpoint.NewFromInt(110),
+		Low:       fixedpoint.NewFromInt(95),
+		Close:     fixedpoint.NewFromInt(105),
+		Volume:    fixedpoint.NewFromInt(8),
+	}
+
+	trades := synthesizeMarketTrades(k)
+	if assert.Len(t, trades, 4) {
+		var prices []float64
+		var sides []types.SideType
+		for i, trade := range trades {
+			prices = append(prices, trade.Price.Float64())
+			sides = append(sides, trade.Side)
+			assert.Equal(t, 2.0, trade.Quantity.Float64())
+			if i > 0 {
+				assert.True(t, trade.Time.After(trades[i-1].Time.Time()))
+			}
+		}
+
+		assert.Equal(t, []float64{100, 95, 110, 105}, prices)
+		assert.Equal(t, []types.SideType{types.SideTypeBuy, types.SideTypeSell, types.SideTypeBuy, types.SideTypeSell}, sides)
+		assert.Equal(t, k.EndTime, trades[3].Time)
+	}
+
+	// the bearish kline goes through the high first
+	k.Close = fixedpoint.NewFromInt(97)
+	trades = synthesizeMarketTrades(k)
+	if assert.Len(t, trades, 4) {
+		assert.Equal(t, 110.0, trades[1].Price.Float64())
+		assert.Equal(t, 95.0, trades[2].Price.Float64())
+	}
+}
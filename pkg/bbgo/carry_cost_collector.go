@@ -0,0 +1,99 @@
+package bbgo
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultCarryCostSyncInterval = time.Hour
+
+// CarryCostCollector adds the funding fees of the futures session and the interest of the isolated margin session
+// into the position and the profit stats of a strategy instance.
+// The records are queried from the session exchange since the latest record added into the position,
+// so the position should be persisted with the strategy to avoid adding the records again after restarting.
+type CarryCostCollector struct {
+	Session     *ExchangeSession
+	Position    *types.Position
+	ProfitStats *types.ProfitStats
+
+	// Since is the start time of the first query when no record is added into the position yet
+	Since time.Time
+}
+
+func NewCarryCostCollector(session *ExchangeSession, position *types.Position, profitStats *types.ProfitStats) *CarryCostCollector {
+	return &CarryCostCollector{
+		Session:     session,
+		Position:    position,
+		ProfitStats: profitStats,
+		Since:       time.Now(),
+	}
+}
+
+func (c *CarryCostCollector) since(last types.Time) time.Time {
+	if t := last.Time(); !t.IsZero() {
+		return t.Add(time.Millisecond)
+	}
+
+	return c.Since
+}
+
+// Sync queries the new funding fees and interests and adds them into the position and the profit stats
+func (c *CarryCostCollector) Sync(ctx context.Context) error {
+	now := time.Now()
+	symbol := c.Position.Symbol
+
+	if service, ok := c.Session.Exchange.(types.ExchangeFundingFeeService); ok && c.Session.Futures {
+		fees, err := service.QueryFundingFeeHistory(ctx, symbol, c.since(c.Position.FundingFeeTime), now)
+		if err != nil {
+			return err
+		}
+
+		for _, fee := range fees {
+			if amount, ok := c.Position.AddFundingFee(fee); ok && c.ProfitStats != nil {
+				c.ProfitStats.AddFundingFee(amount)
+			}
+		}
+	}
+
+	// only the interest of the isolated margin account is attributed to the position
+	if service, ok := c.Session.Exchange.(types.MarginInterestHistoryService); ok && c.Session.IsolatedMargin && c.Session.IsolatedMarginSymbol == symbol {
+		interests, err := service.QueryInterestHistory(ctx, "", c.since(c.Position.InterestTime), now)
+		if err != nil {
+			return err
+		}
+
+		for _, interest := range interests {
+			if amount, ok := c.Position.AddInterest(interest); ok && c.ProfitStats != nil {
+				c.ProfitStats.AddInterest(amount)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Run syncs the funding fees and the interests periodically until the context is canceled, the interval defaults to 1h
+func (c *CarryCostCollector) Run(ctx context.Context, interval time.Duration) {
+	if interval == 0 {
+		interval = defaultCarryCostSyncInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := c.Sync(ctx); err != nil {
+				log.WithError(err).Errorf("can not sync the funding fees and the interests of %s", c.Position.Symbol)
+			}
+		}
+	}
+}
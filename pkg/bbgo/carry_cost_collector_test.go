@@ -0,0 +1,78 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type carryCostTestExchange struct {
+	types.Exchange
+
+	fundingFees []types.FundingFee
+	interests   []types.MarginInterest
+}
+
+func (e *carryCostTestExchange) QueryFundingFeeHistory(ctx context.Context, symbol string, since, until time.Time) (fees []types.FundingFee, err error) {
+	for _, fee := range e.fundingFees {
+		if fee.Symbol == symbol && !fee.Time.Before(since) && !fee.Time.After(until) {
+			fees = append(fees, fee)
+		}
+	}
+
+	return fees, nil
+}
+
+func (e *carryCostTestExchange) QueryInterestHistory(ctx context.Context, asset string, since, until time.Time) (interests []types.MarginInterest, err error) {
+	for _, interest := range e.interests {
+		if !interest.Time.Before(since) && !interest.Time.After(until) {
+			interests = append(interests, interest)
+		}
+	}
+
+	return interests, nil
+}
+
+func TestCarryCostCollector_Sync(t *testing.T) {
+	t0 := time.Now().Add(-time.Hour)
+	ex := &carryCostTestExchange{
+		fundingFees: []types.FundingFee{
+			{Symbol: "BTCUSDT", Asset: "USDT", Amount: fixedpoint.NewFromInt(-2), Time: types.Time(t0)},
+			{Symbol: "ETHUSDT", Asset: "USDT", Amount: fixedpoint.NewFromInt(-5), Time: types.Time(t0)},
+		},
+		interests: []types.MarginInterest{
+			{Asset: "USDT", IsolatedSymbol: "BTCUSDT", Interest: fixedpoint.One, Time: types.Time(t0)},
+		},
+	}
+
+	market := types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+	position := types.NewPositionFromMarket(market)
+	profitStats := types.NewProfitStats(market)
+
+	collector := NewCarryCostCollector(&ExchangeSession{Exchange: ex}, position, profitStats)
+	collector.Since = t0.Add(-time.Minute)
+
+	// the session is neither futures nor isolated margin
+	assert.NoError(t, collector.Sync(context.Background()))
+	assert.True(t, position.AccumulatedFundingFee.IsZero())
+	assert.True(t, position.AccumulatedInterest.IsZero())
+
+	collector.Session = &ExchangeSession{Exchange: ex, Futures: true, IsolatedMargin: true, IsolatedMarginSymbol: "BTCUSDT"}
+	assert.NoError(t, collector.Sync(context.Background()))
+	assert.Equal(t, "-2", position.AccumulatedFundingFee.String())
+	assert.Equal(t, "1", position.AccumulatedInterest.String())
+	assert.Equal(t, "-3", profitStats.AccumulatedNetProfit.String())
+	assert.Equal(t, t0.UnixMilli(), position.FundingFeeTime.UnixMilli())
+
+	// the records are queried since the latest added record
+	ex.fundingFees = append(ex.fundingFees, types.FundingFee{Symbol: "BTCUSDT", Asset: "USDT", Amount: fixedpoint.One, Time: types.Time(t0.Add(time.Minute))})
+	assert.NoError(t, collector.Sync(context.Background()))
+	assert.Equal(t, "-1", position.AccumulatedFundingFee.String())
+	assert.Equal(t, "1", position.AccumulatedInterest.String())
+	assert.Equal(t, "-2", profitStats.AccumulatedNetProfit.String())
+}
@@ -0,0 +1,359 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var ErrCircuitBreakerTripped = errors.New("circuit breaker is tripped, new orders are refused until it's reset")
+
+const defaultCircuitBreakerCheckInterval = time.Minute
+
+// CircuitBreakerLimits defines the loss limits in the quote currency.
+// A zero value disables the check.
+type CircuitBreakerLimits struct {
+	// MaxDailyLoss is the max loss (realized + unrealized) since the start of the current UTC day
+	MaxDailyLoss fixedpoint.Value `json:"maxDailyLoss,omitempty" yaml:"maxDailyLoss,omitempty"`
+
+	// MaxDrawdown is the max peak-to-trough loss since the circuit breaker is started or reset
+	MaxDrawdown fixedpoint.Value `json:"maxDrawdown,omitempty" yaml:"maxDrawdown,omitempty"`
+}
+
+// pnlTracker tracks the pnl of a scope (account, session or strategy) for the limit checks
+type pnlTracker struct {
+	dayStart fixedpoint.Value
+	peak     fixedpoint.Value
+}
+
+// update updates the tracker with the current pnl and returns the breach reason if the limits are reached
+func (t *pnlTracker) update(pnl fixedpoint.Value, limits *CircuitBreakerLimits) (string, bool) {
+	if pnl.Compare(t.peak) > 0 {
+		t.peak = pnl
+	}
+
+	if limits.MaxDailyLoss.Sign() > 0 {
+		loss := t.dayStart.Sub(pnl)
+		if loss.Compare(limits.MaxDailyLoss) >= 0 {
+			return fmt.Sprintf("daily loss %s reached the limit %s", loss.String(), limits.MaxDailyLoss.String()), true
+		}
+	}
+
+	if limits.MaxDrawdown.Sign() > 0 {
+		drawdown := t.peak.Sub(pnl)
+		if drawdown.Compare(limits.MaxDrawdown) >= 0 {
+			return fmt.Sprintf("drawdown %s reached the limit %s", drawdown.String(), limits.MaxDrawdown.String()), true
+		}
+	}
+
+	return "", false
+}
+
+// pnlScope is the pnl of an account, a session or a strategy instance with the limits applied to it
+type pnlScope struct {
+	key    string
+	pnl    fixedpoint.Value
+	limits *CircuitBreakerLimits
+}
+
+// CircuitBreaker is the account-level kill switch.
+// It tracks the realized and the unrealized pnl of the positions of every session and every strategy that implements PositionReader.
+// When one of the limits is reached, it emergency stops all the strategies, cancels all the open orders of the session symbols
+// (including the orders that are not submitted by bbgo), and refuses the new orders submitted through the session order executors
+// until it's reset manually.
+type CircuitBreaker struct {
+	// CircuitBreakerLimits are the limits of the whole account, which sums the pnl of all sessions
+	CircuitBreakerLimits `yaml:",inline"`
+
+	// BySession defines the limits of the session pnl, key: session name
+	BySession map[string]*CircuitBreakerLimits `json:"bySession,omitempty" yaml:"bySession,omitempty"`
+
+	// ByStrategy defines the limits of the strategy pnl, key: session name, strategy id and position symbol, e.g. binance.bollmaker.ethusdt,
+	// the key is case-insensitive. The instances of the same strategy and symbol on a session share the same limits.
+	ByStrategy map[string]*CircuitBreakerLimits `json:"byStrategy,omitempty" yaml:"byStrategy,omitempty"`
+
+	// CheckInterval is the interval of the pnl check, defaults to 1m
+	CheckInterval time.Duration `json:"checkInterval,omitempty" yaml:"checkInterval,omitempty"`
+
+	// Notifiability is used for sending the notifications, it's injected by the trader
+	Notifiability *Notifiability `json:"-" yaml:"-"`
+
+	mu sync.Mutex
+
+	tripped bool
+	reason  string
+
+	// breached is set when a limit is breached, the breaker is tripped after the strategies are emergency stopped,
+	// so the position close orders of the strategies are not refused
+	breached bool
+
+	// day is the start time of the current UTC day
+	day      time.Time
+	trackers map[string]*pnlTracker
+}
+
+// Tripped returns true if the circuit breaker is tripped
+func (b *CircuitBreaker) Tripped() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tripped
+}
+
+// Reason returns the reason of the last trip
+func (b *CircuitBreaker) Reason() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reason
+}
+
+// Reset closes the circuit breaker, the daily start pnl and the peak pnl are re-initialized on the next check.
+func (b *CircuitBreaker) Reset() {
+	b.mu.Lock()
+	b.tripped = false
+	b.breached = false
+	b.reason = ""
+	b.trackers = nil
+	b.mu.Unlock()
+
+	metricsCircuitBreakerTripped.Set(0)
+	log.Infof("circuit breaker is reset")
+
+	if b.Notifiability != nil {
+		b.Notifiability.Notify(":white_check_mark: circuit breaker is reset, new orders are allowed")
+	}
+}
+
+// evaluate updates the trackers with the pnl of the scopes and returns the reason if one of the limits is reached
+func (b *CircuitBreaker) evaluate(now time.Time, scopes []pnlScope) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tripped || b.breached {
+		return b.reason, false
+	}
+
+	day := now.UTC().Truncate(24 * time.Hour)
+	if b.trackers == nil {
+		b.trackers = make(map[string]*pnlTracker)
+	}
+
+	// reset the daily start pnl when a new day starts
+	if !day.Equal(b.day) {
+		b.day = day
+		for _, scope := range scopes {
+			if tracker, ok := b.trackers[scope.key]; ok {
+				tracker.dayStart = scope.pnl
+			}
+		}
+	}
+
+	for _, scope := range scopes {
+		tracker, ok := b.trackers[scope.key]
+		if !ok {
+			tracker = &pnlTracker{dayStart: scope.pnl, peak: scope.pnl}
+			b.trackers[scope.key] = tracker
+		}
+
+		if scope.limits == nil {
+			continue
+		}
+
+		if reason, breached := tracker.update(scope.pnl, scope.limits); breached {
+			b.breached = true
+			b.reason = scope.key + ": " + reason
+			return b.reason, true
+		}
+	}
+
+	return "", false
+}
+
+// positionPnL returns the accumulated realized profit plus the unrealized profit calculated from the last price
+func positionPnL(position *types.Position, lastPrice fixedpoint.Value) fixedpoint.Value {
+	position.Lock()
+	defer position.Unlock()
+
+	pnl := position.AccumulatedProfit
+	if !position.Base.IsZero() && lastPrice.Sign() > 0 {
+		pnl = pnl.Add(lastPrice.Sub(position.AverageCost).Mul(position.Base))
+	}
+
+	return pnl
+}
+
+// strategyKey returns the key of the strategy limits, which is in lower case
+func strategyKey(sessionName, strategyID, symbol string) string {
+	return strings.ToLower(sessionName + "." + strategyID + "." + symbol)
+}
+
+// strategyLimits returns the limits of the strategy key, the keys of ByStrategy are compared case-insensitively
+func (b *CircuitBreaker) strategyLimits(key string) *CircuitBreakerLimits {
+	if limits, ok := b.ByStrategy[key]; ok {
+		return limits
+	}
+
+	for k, limits := range b.ByStrategy {
+		if strings.ToLower(k) == key {
+			return limits
+		}
+	}
+
+	return nil
+}
+
+// warnUnknownStrategies logs the ByStrategy keys that don't match any strategy position
+func (b *CircuitBreaker) warnUnknownStrategies(scopes []pnlScope) {
+	keys := make(map[string]struct{})
+	for _, scope := range scopes {
+		keys[scope.key] = struct{}{}
+	}
+
+	for k := range b.ByStrategy {
+		if _, ok := keys["strategy:"+strings.ToLower(k)]; !ok {
+			log.Warnf("circuit breaker: byStrategy key %q doesn't match any strategy position, the key format is <session>.<strategy id>.<symbol>, e.g. binance.bollmaker.ethusdt", k)
+		}
+	}
+}
+
+func (b *CircuitBreaker) collectScopes(trader *Trader) (scopes []pnlScope) {
+	account := fixedpoint.Zero
+	for sessionName, session := range trader.environment.Sessions() {
+		sessionPnL := fixedpoint.Zero
+		for symbol, position := range session.Positions() {
+			lastPrice, _ := session.LastPrice(symbol)
+			sessionPnL = sessionPnL.Add(positionPnL(position, lastPrice))
+		}
+
+		account = account.Add(sessionPnL)
+		scopes = append(scopes, pnlScope{
+			key:    "session:" + sessionName,
+			pnl:    sessionPnL,
+			limits: b.BySession[sessionName],
+		})
+	}
+
+	scopes = append(scopes, pnlScope{key: "account", pnl: account, limits: &b.CircuitBreakerLimits})
+
+	for sessionName, strategies := range trader.exchangeStrategies {
+		session := trader.environment.sessions[sessionName]
+		for _, strategy := range strategies {
+			reader, ok := strategy.(PositionReader)
+			if !ok {
+				continue
+			}
+
+			position := reader.CurrentPosition()
+			if position == nil {
+				continue
+			}
+
+			key := strategyKey(sessionName, strategy.ID(), position.Symbol)
+			lastPrice, _ := session.LastPrice(position.Symbol)
+			scopes = append(scopes, pnlScope{
+				key:    "strategy:" + key,
+				pnl:    positionPnL(position, lastPrice),
+				limits: b.strategyLimits(key),
+			})
+		}
+	}
+
+	return scopes
+}
+
+// Run checks the pnl periodically until the context is canceled
+func (b *CircuitBreaker) Run(ctx context.Context, trader *Trader) {
+	interval := b.CheckInterval
+	if interval == 0 {
+		interval = defaultCircuitBreakerCheckInterval
+	}
+
+	b.warnUnknownStrategies(b.collectScopes(trader))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if reason, tripped := b.evaluate(time.Now(), b.collectScopes(trader)); tripped {
+				b.trip(ctx, trader, reason)
+			}
+		}
+	}
+}
+
+// trip emergency stops all the strategies and cancels all the open orders of the session symbols.
+// The new orders are refused after the strategies are emergency stopped, hence the strategies can close their positions.
+func (b *CircuitBreaker) trip(ctx context.Context, trader *Trader, reason string) {
+	log.Errorf("circuit breaker tripped: %s", reason)
+	metricsCircuitBreakerTripped.Set(1)
+
+	if b.Notifiability != nil {
+		b.Notifiability.Notify(":rotating_light: circuit breaker tripped: %s, stopping all strategies and canceling all open orders", reason)
+	}
+
+	var strategies []interface{}
+	for _, ss := range trader.exchangeStrategies {
+		for _, s := range ss {
+			strategies = append(strategies, s)
+		}
+	}
+
+	for _, s := range trader.crossExchangeStrategies {
+		strategies = append(strategies, s)
+	}
+
+	for _, s := range strategies {
+		if stopper, ok := s.(EmergencyStopper); ok {
+			if err := stopper.EmergencyStop(); err != nil {
+				log.WithError(err).Errorf("circuit breaker: can not emergency stop strategy %T", s)
+			}
+		}
+	}
+
+	b.mu.Lock()
+	b.tripped = true
+	b.breached = false
+	b.mu.Unlock()
+
+	for sessionName, session := range trader.environment.Sessions() {
+		for symbol, store := range session.OrderStores() {
+			openOrders, err := session.Exchange.QueryOpenOrders(ctx, symbol)
+			if err != nil {
+				// fall back to the open orders submitted by bbgo
+				log.WithError(err).Errorf("circuit breaker: can not query the open orders of %s %s, canceling the orders in the order store", sessionName, symbol)
+				openOrders = storeOpenOrders(store)
+			}
+
+			if len(openOrders) == 0 {
+				continue
+			}
+
+			if err := session.Exchange.CancelOrders(ctx, openOrders...); err != nil {
+				log.WithError(err).Errorf("circuit breaker: can not cancel the open orders of %s %s", sessionName, symbol)
+			}
+		}
+	}
+}
+
+func storeOpenOrders(store *OrderStore) (orders []types.Order) {
+	for _, o := range store.Orders() {
+		switch o.Status {
+		case types.OrderStatusNew, types.OrderStatusPartiallyFilled:
+			orders = append(orders, o)
+		}
+	}
+
+	return orders
+}
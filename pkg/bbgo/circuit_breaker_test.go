@@ -0,0 +1,262 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestCircuitBreaker_evaluate(t *testing.T) {
+	breaker := &CircuitBreaker{
+		CircuitBreakerLimits: CircuitBreakerLimits{
+			MaxDailyLoss: fixedpoint.NewFromInt(100),
+			MaxDrawdown:  fixedpoint.NewFromInt(150),
+		},
+	}
+
+	scopes := func(pnl float64) []pnlScope {
+		return []pnlScope{
+			{key: "session:binance", pnl: fixedpoint.NewFromFloat(pnl)},
+			{key: "account", pnl: fixedpoint.NewFromFloat(pnl), limits: &breaker.CircuitBreakerLimits},
+		}
+	}
+
+	day1 := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	_, tripped := breaker.evaluate(day1, scopes(0))
+	assert.False(t, tripped)
+
+	// peak
+	_, tripped = breaker.evaluate(day1.Add(time.Hour), scopes(80))
+	assert.False(t, tripped)
+
+	// the daily loss is 60, the drawdown is 140
+	_, tripped = breaker.evaluate(day2, scopes(-60))
+	assert.False(t, tripped)
+
+	// the daily loss is 90 (since -60), the drawdown is 230
+	reason, tripped := breaker.evaluate(day2.Add(time.Hour), scopes(-150))
+	assert.True(t, tripped)
+	assert.Contains(t, reason, "account: drawdown")
+
+	// the breaker is tripped by trip after the strategies are emergency stopped
+	assert.False(t, breaker.Tripped())
+	_, tripped = breaker.evaluate(day2.Add(90*time.Minute), scopes(-150))
+	assert.False(t, tripped)
+
+	breaker.Reset()
+	assert.False(t, breaker.Tripped())
+
+	_, tripped = breaker.evaluate(day2.Add(2*time.Hour), scopes(-150))
+	assert.False(t, tripped)
+
+	reason, tripped = breaker.evaluate(day2.Add(3*time.Hour), scopes(-250))
+	assert.True(t, tripped)
+	assert.Contains(t, reason, "account: daily loss 100")
+}
+
+func TestPositionPnL(t *testing.T) {
+	position := types.NewPosition("BTCUSDT", "BTC", "USDT")
+	position.AddTrade(types.Trade{
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		Price:         fixedpoint.NewFromInt(1000),
+		Quantity:      fixedpoint.NewFromInt(2),
+		QuoteQuantity: fixedpoint.NewFromInt(2000),
+	})
+	position.AddTrade(types.Trade{
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeSell,
+		Price:         fixedpoint.NewFromInt(1100),
+		Quantity:      fixedpoint.One,
+		QuoteQuantity: fixedpoint.NewFromInt(1100),
+	})
+
+	// realized 100, unrealized (900 - 1000) * 1
+	assert.Equal(t, "0", positionPnL(position, fixedpoint.NewFromInt(900)).String())
+	assert.Equal(t, "200", positionPnL(position, fixedpoint.NewFromInt(1100)).String())
+	assert.Equal(t, "100", positionPnL(position, fixedpoint.Zero).String())
+}
+
+type circuitBreakerTestStrategy struct {
+	Position *types.Position
+}
+
+func (s *circuitBreakerTestStrategy) ID() string { return "buyandhold" }
+
+func (s *circuitBreakerTestStrategy) Run(ctx context.Context, orderExecutor OrderExecutor, session *ExchangeSession) error {
+	return nil
+}
+
+func (s *circuitBreakerTestStrategy) CurrentPosition() *types.Position { return s.Position }
+
+type circuitBreakerTestExchange struct {
+	types.Exchange
+
+	openOrders      []types.Order
+	canceledOrders  []types.Order
+	submittedOrders []types.SubmitOrder
+}
+
+func (e *circuitBreakerTestExchange) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder) (createdOrders types.OrderSlice, err error) {
+	e.submittedOrders = append(e.submittedOrders, orders...)
+	for _, o := range orders {
+		createdOrders = append(createdOrders, types.Order{SubmitOrder: o, Status: types.OrderStatusNew})
+	}
+	return createdOrders, nil
+}
+
+func (e *circuitBreakerTestExchange) QueryOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return e.openOrders, nil
+}
+
+func (e *circuitBreakerTestExchange) CancelOrders(ctx context.Context, orders ...types.Order) error {
+	e.canceledOrders = append(e.canceledOrders, orders...)
+	return nil
+}
+
+func TestCircuitBreaker_ByStrategy(t *testing.T) {
+	config, err := Load("testdata/order_executor.yaml", false)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	breaker := config.RiskControls.CircuitBreaker
+	if !assert.NotNil(t, breaker) {
+		return
+	}
+
+	ex := &circuitBreakerTestExchange{
+		openOrders: []types.Order{
+			// the order that is not submitted by bbgo
+			{SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT", Side: types.SideTypeBuy}, OrderID: 1, Status: types.OrderStatusNew},
+		},
+	}
+
+	environ := NewEnvironment()
+	environ.AddExchangeSession("binance", &ExchangeSession{
+		Name:        "binance",
+		Exchange:    ex,
+		positions:   map[string]*types.Position{},
+		lastPrices:  map[string]fixedpoint.Value{"BTCUSDT": fixedpoint.NewFromInt(20000)},
+		orderStores: map[string]*OrderStore{"BTCUSDT": NewOrderStore("BTCUSDT")},
+	})
+
+	strategy := &circuitBreakerTestStrategy{Position: types.NewPosition("BTCUSDT", "BTC", "USDT")}
+	strategy.Position.AddTrade(types.Trade{
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		Price:         fixedpoint.NewFromInt(20000),
+		Quantity:      fixedpoint.NewFromFloat(0.1),
+		QuoteQuantity: fixedpoint.NewFromInt(2000),
+	})
+
+	trader := NewTrader(environ)
+	trader.exchangeStrategies["binance"] = []SingleExchangeStrategy{strategy}
+
+	scopes := breaker.collectScopes(trader)
+	if assert.Len(t, scopes, 3) {
+		assert.Equal(t, "strategy:binance.buyandhold.btcusdt", scopes[2].key)
+		assert.NotNil(t, scopes[2].limits)
+	}
+
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	_, tripped := breaker.evaluate(now, scopes)
+	assert.False(t, tripped)
+
+	// the strategy loses 100 USDT, which reaches the strategy daily loss limit but not the account limit
+	environ.sessions["binance"].lastPrices["BTCUSDT"] = fixedpoint.NewFromInt(19000)
+	reason, tripped := breaker.evaluate(now.Add(time.Hour), breaker.collectScopes(trader))
+	assert.True(t, tripped)
+	assert.Contains(t, reason, "strategy:binance.buyandhold.btcusdt: daily loss 100")
+
+	breaker.trip(context.Background(), trader, reason)
+	assert.True(t, breaker.Tripped())
+	if assert.Len(t, ex.canceledOrders, 1) {
+		assert.Equal(t, uint64(1), ex.canceledOrders[0].OrderID)
+	}
+}
+
+// circuitBreakerStopStrategy closes its position through the session order executor when it's emergency stopped
+type circuitBreakerStopStrategy struct {
+	circuitBreakerTestStrategy
+
+	session *ExchangeSession
+	err     error
+}
+
+func (s *circuitBreakerStopStrategy) EmergencyStop() error {
+	_, s.err = s.session.OrderExecutor.SubmitOrders(context.Background(), types.SubmitOrder{
+		Symbol:   s.Position.Symbol,
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Quantity: s.Position.GetBase(),
+	})
+	return s.err
+}
+
+func TestCircuitBreaker_TripClosesPosition(t *testing.T) {
+	breaker := &CircuitBreaker{
+		ByStrategy: map[string]*CircuitBreakerLimits{
+			"binance.buyandhold.btcusdt": {MaxDailyLoss: fixedpoint.NewFromInt(50)},
+		},
+	}
+
+	ex := &circuitBreakerTestExchange{}
+	session := &ExchangeSession{
+		Name:        "binance",
+		Exchange:    ex,
+		positions:   map[string]*types.Position{},
+		markets:     map[string]types.Market{"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}},
+		lastPrices:  map[string]fixedpoint.Value{"BTCUSDT": fixedpoint.NewFromInt(20000)},
+		orderStores: map[string]*OrderStore{"BTCUSDT": NewOrderStore("BTCUSDT")},
+	}
+	executor := &ExchangeOrderExecutor{Session: session}
+	executor.SetCircuitBreaker(breaker)
+	session.OrderExecutor = executor
+
+	environ := NewEnvironment()
+	environ.AddExchangeSession("binance", session)
+
+	strategy := &circuitBreakerStopStrategy{session: session}
+	strategy.Position = types.NewPosition("BTCUSDT", "BTC", "USDT")
+	strategy.Position.AddTrade(types.Trade{
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		Price:         fixedpoint.NewFromInt(20000),
+		Quantity:      fixedpoint.NewFromFloat(0.1),
+		QuoteQuantity: fixedpoint.NewFromInt(2000),
+	})
+
+	trader := NewTrader(environ)
+	trader.exchangeStrategies["binance"] = []SingleExchangeStrategy{strategy}
+
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	_, tripped := breaker.evaluate(now, breaker.collectScopes(trader))
+	assert.False(t, tripped)
+
+	session.lastPrices["BTCUSDT"] = fixedpoint.NewFromInt(19000)
+	reason, tripped := breaker.evaluate(now.Add(time.Hour), breaker.collectScopes(trader))
+	if !assert.True(t, tripped) {
+		return
+	}
+
+	breaker.trip(context.Background(), trader, reason)
+
+	// the close order is submitted, the orders after the trip are refused
+	assert.NoError(t, strategy.err)
+	if assert.Len(t, ex.submittedOrders, 1) {
+		assert.Equal(t, types.SideTypeSell, ex.submittedOrders[0].Side)
+		assert.Equal(t, "0.1", ex.submittedOrders[0].Quantity.String())
+	}
+
+	_, err := executor.SubmitOrders(context.Background(), types.SubmitOrder{Symbol: "BTCUSDT", Side: types.SideTypeBuy, Type: types.OrderTypeMarket, Quantity: fixedpoint.One})
+	assert.ErrorIs(t, err, ErrCircuitBreakerTripped)
+	assert.Len(t, ex.submittedOrders, 1)
+}
@@ -27,6 +27,9 @@ type PnLReporterConfig struct {
 	AverageCostBySymbols datatype.StringSlice `json:"averageCostBySymbols" yaml:"averageCostBySymbols"`
 	Of                   datatype.StringSlice `json:"of" yaml:"of"`
 	When                 datatype.StringSlice `json:"when" yaml:"when"`
+
+	// Method is the cost basis method: average (default), fifo, lifo or hifo
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
 }
 
 // ExchangeStrategyMount wraps the SingleExchangeStrategy with the ExchangeSession name for mounting
@@ -111,8 +114,19 @@ type Backtest struct {
 	Accounts map[string]BacktestAccount `json:"accounts" yaml:"accounts"`
 	Symbols  []string                   `json:"symbols" yaml:"symbols"`
 	Sessions []string                   `json:"sessions" yaml:"sessions"`
+
+	// MissingKLines is how the kline ranges that the exchange has no data are fed, the ranges are marked by the kline repair.
+	// "skip" (the default) jumps over the ranges, "forwardFill" feeds flat klines with the previous close price.
+	MissingKLines MissingKLinePolicy `json:"missingKLines,omitempty" yaml:"missingKLines,omitempty"`
 }
 
+type MissingKLinePolicy string
+
+const (
+	MissingKLineSkip        MissingKLinePolicy = "skip"
+	MissingKLineForwardFill MissingKLinePolicy = "forwardFill"
+)
+
 func (b *Backtest) GetAccount(n string) BacktestAccount {
 	accountConfig, ok := b.Accounts[n]
 	if ok {
@@ -208,6 +222,12 @@ type SyncConfig struct {
 	// RewardHistory for syncing reward history
 	RewardHistory bool `json:"rewardHistory" yaml:"rewardHistory"`
 
+	// FundingFeeHistory for syncing the funding payments of the futures positions
+	FundingFeeHistory bool `json:"fundingFeeHistory" yaml:"fundingFeeHistory"`
+
+	// MarginInterestHistory for syncing the interest charged for the borrowed margin assets
+	MarginInterestHistory bool `json:"marginInterestHistory" yaml:"marginInterestHistory"`
+
 	// Since is the date where you want to start syncing data
 	Since *types.LooseFormatTime `json:"since,omitempty"`
 
@@ -237,6 +257,10 @@ type Config struct {
 
 	RiskControls *RiskControls `json:"riskControls,omitempty" yaml:"riskControls,omitempty"`
 
+	// ReportingCurrency is the currency that the balances, the net asset values and the profits are reported in,
+	// e.g. USD, TWD or BTC. Defaults to USD.
+	ReportingCurrency string `json:"reportingCurrency,omitempty" yaml:"reportingCurrency,omitempty"`
+
 	ExchangeStrategies      []ExchangeStrategyMount `json:"-" yaml:"-"`
 	CrossExchangeStrategies []CrossExchangeStrategy `json:"-" yaml:"-"`
 
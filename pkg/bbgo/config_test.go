@@ -4,6 +4,7 @@ import (
 	"context"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
@@ -178,6 +179,28 @@ func TestLoadConfig(t *testing.T) {
 				executorConf, ok := conf.OrderExecutor.BySymbol["BTCUSDT"]
 				assert.True(t, ok)
 				assert.NotNil(t, executorConf)
+
+				preTrade := riskControls.PreTrade
+				if assert.NotNil(t, preTrade) {
+					assert.Equal(t, "1000", preTrade.MaxOrderNotional.String())
+					assert.Equal(t, 20, preTrade.MaxOpenOrders)
+					assert.Equal(t, "0.05", preTrade.PriceBand.String())
+					assert.Equal(t, 60, preTrade.MaxOrdersPerMinute)
+
+					limits := preTrade.limits("BTCUSDT")
+					assert.Equal(t, "0.5", limits.MaxOrderQuantity.String())
+					assert.Equal(t, "1", limits.MaxNetPosition.String())
+				}
+
+				breaker := riskControls.CircuitBreaker
+				if assert.NotNil(t, breaker) {
+					assert.Equal(t, "500", breaker.MaxDailyLoss.String())
+					assert.Equal(t, "1000", breaker.MaxDrawdown.String())
+					assert.Equal(t, 30*time.Second, breaker.CheckInterval)
+					if assert.NotNil(t, breaker.ByStrategy["binance.buyandhold.btcusdt"]) {
+						assert.Equal(t, "100", breaker.ByStrategy["binance.buyandhold.btcusdt"].MaxDailyLoss.String())
+					}
+				}
 			},
 		},
 		{
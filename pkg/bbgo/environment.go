@@ -81,8 +81,11 @@ type Environment struct {
 	PositionService          *service.PositionService
 	BacktestService          *service.BacktestService
 	RewardService            *service.RewardService
+	FundingFeeService        *service.FundingFeeService
+	MarginInterestService    *service.MarginInterestService
 	SyncService              *service.SyncService
 	AccountService           *service.AccountService
+	NavService               *service.NavService
 
 	// startTime is the time of start point (which is used in the backtest)
 	startTime time.Time
@@ -95,6 +98,9 @@ type Environment struct {
 	syncStatus      SyncStatus
 	syncConfig      *SyncConfig
 
+	// reportingCurrency is the currency that the assets and the profits are converted into
+	reportingCurrency string
+
 	sessions map[string]*ExchangeSession
 }
 
@@ -152,6 +158,10 @@ func (environ *Environment) ConfigureDatabase(ctx context.Context) error {
 
 		return environ.ConfigureDatabaseDriver(ctx, "mysql", dsn)
 
+	} else if dsn, ok := os.LookupEnv("POSTGRES_URL"); ok {
+
+		return environ.ConfigureDatabaseDriver(ctx, "postgres", dsn)
+
 	}
 
 	return nil
@@ -173,7 +183,10 @@ func (environ *Environment) ConfigureDatabaseDriver(ctx context.Context, driver
 	environ.OrderService = &service.OrderService{DB: db}
 	environ.TradeService = &service.TradeService{DB: db}
 	environ.RewardService = &service.RewardService{DB: db}
+	environ.FundingFeeService = &service.FundingFeeService{DB: db}
+	environ.MarginInterestService = &service.MarginInterestService{DB: db}
 	environ.AccountService = &service.AccountService{DB: db}
+	environ.NavService = &service.NavService{DB: db}
 	environ.ProfitService = &service.ProfitService{DB: db}
 	environ.PositionService = &service.PositionService{DB: db}
 
@@ -183,6 +196,9 @@ func (environ *Environment) ConfigureDatabaseDriver(ctx context.Context, driver
 		RewardService:   environ.RewardService,
 		WithdrawService: &service.WithdrawService{DB: db},
 		DepositService:  &service.DepositService{DB: db},
+
+		FundingFeeService:     environ.FundingFeeService,
+		MarginInterestService: environ.MarginInterestService,
 	}
 
 	return nil
@@ -204,6 +220,8 @@ func (environ *Environment) AddExchange(name string, exchange types.Exchange) (s
 }
 
 func (environ *Environment) ConfigureExchangeSessions(userConfig *Config) error {
+	environ.SetReportingCurrency(userConfig.ReportingCurrency)
+
 	// if sessions are not defined, we detect the sessions automatically
 	if len(userConfig.Sessions) == 0 {
 		return environ.AddExchangesByViperKeys()
@@ -539,6 +557,11 @@ func (environ *Environment) Connect(ctx context.Context) error {
 		} else {
 			// add the subscribe requests to the stream
 			for _, s := range session.Subscriptions {
+				// the derived klines are built by the market data store from the source subscription
+				if s.Channel == types.KLineChannel && types.Interval(s.Options.Interval).IsDerived() {
+					continue
+				}
+
 				logger.Infof("subscribing %s %s %v", s.Symbol, s.Channel, s.Options)
 				session.MarketDataStream.Subscribe(s.Channel, s.Symbol, s.Options)
 			}
@@ -616,6 +639,18 @@ func (environ *Environment) Sync(ctx context.Context, userConfig ...*Config) err
 					return err
 				}
 			}
+
+			if userConfig[0].Sync.FundingFeeHistory {
+				if err := environ.SyncService.SyncFundingFeeHistory(ctx, session.Exchange); err != nil {
+					return err
+				}
+			}
+
+			if userConfig[0].Sync.MarginInterestHistory {
+				if err := environ.SyncService.SyncMarginInterestHistory(ctx, session.Exchange); err != nil {
+					return err
+				}
+			}
 		}
 
 		return nil
@@ -648,6 +683,18 @@ func (environ *Environment) Sync(ctx context.Context, userConfig ...*Config) err
 				return err
 			}
 		}
+
+		if userConfig[0].Sync.FundingFeeHistory {
+			if err := environ.SyncService.SyncFundingFeeHistory(ctx, session.Exchange); err != nil {
+				return err
+			}
+		}
+
+		if userConfig[0].Sync.MarginInterestHistory {
+			if err := environ.SyncService.SyncMarginInterestHistory(ctx, session.Exchange); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -686,15 +733,15 @@ func (environ *Environment) RecordPosition(position *types.Position, trade types
 		return
 	}
 
-	if position.Strategy == "" && profit.Strategy != "" {
-		position.Strategy = profit.Strategy
-	}
+	if profit != nil {
+		if position.Strategy == "" && profit.Strategy != "" {
+			position.Strategy = profit.Strategy
+		}
 
-	if position.StrategyInstanceID == "" && profit.StrategyInstanceID != "" {
-		position.StrategyInstanceID = profit.StrategyInstanceID
-	}
+		if position.StrategyInstanceID == "" && profit.StrategyInstanceID != "" {
+			position.StrategyInstanceID = profit.StrategyInstanceID
+		}
 
-	if profit != nil {
 		if err := environ.PositionService.Insert(position, trade, profit.Profit); err != nil {
 			log.WithError(err).Errorf("can not insert position record")
 		}
@@ -0,0 +1,148 @@
+package bbgo
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultSubmitOrderAttempts = 3
+
+const defaultSubmitOrderRetryInterval = time.Second
+
+// InstanceOrderExecutor wraps the session order executor of a strategy instance.
+// It assigns the client order IDs that encode the strategy instance, so the orders can be attributed to the instance,
+// and retries the submissions failed by the network errors idempotently: before the order is re-submitted, it's queried by its client order ID
+// to check whether the previous attempt actually created the order.
+type InstanceOrderExecutor struct {
+	OrderExecutor
+
+	Session *ExchangeSession
+
+	// Attempts is the max number of the submission attempts of an order, defaults to 3
+	Attempts int
+
+	// RetryInterval is the interval between the attempts, defaults to 1s
+	RetryInterval time.Duration
+
+	generator *types.ClientOrderIDGenerator
+}
+
+func NewInstanceOrderExecutor(executor OrderExecutor, session *ExchangeSession, strategyID, instanceID string) *InstanceOrderExecutor {
+	return &InstanceOrderExecutor{
+		OrderExecutor: executor,
+		Session:       session,
+		Attempts:      defaultSubmitOrderAttempts,
+		RetryInterval: defaultSubmitOrderRetryInterval,
+		generator:     types.NewClientOrderIDGenerator(strategyID, instanceID),
+	}
+}
+
+// Tag returns the strategy instance tag encoded in the client order IDs
+func (e *InstanceOrderExecutor) Tag() string {
+	return e.generator.Tag()
+}
+
+func (e *InstanceOrderExecutor) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+	queryService, queryable := e.Session.Exchange.(types.ExchangeOrderQueryService)
+
+	var createdOrders types.OrderSlice
+	for _, order := range orders {
+		// the user-defined client order ID is kept
+		if order.ClientOrderID == "" {
+			order.ClientOrderID = e.generator.NextFor(e.Session.ExchangeName)
+		}
+
+		// the order can not be recovered without a client order ID
+		if !queryable || order.ClientOrderID == "" || order.ClientOrderID == types.NoClientOrderID {
+			retOrders, err := e.OrderExecutor.SubmitOrders(ctx, order)
+			createdOrders = append(createdOrders, retOrders...)
+			if err != nil {
+				return createdOrders, err
+			}
+
+			continue
+		}
+
+		createdOrder, err := e.submitOrderIdempotent(ctx, queryService, order)
+		if err != nil {
+			return createdOrders, err
+		}
+
+		if createdOrder != nil {
+			createdOrders = append(createdOrders, *createdOrder)
+		}
+	}
+
+	return createdOrders, nil
+}
+
+func (e *InstanceOrderExecutor) submitOrderIdempotent(ctx context.Context, queryService types.ExchangeOrderQueryService, order types.SubmitOrder) (*types.Order, error) {
+	attempts := e.Attempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, errors.Wrap(err, "context done")
+			case <-time.After(e.RetryInterval):
+			}
+
+			// the previous attempt might have created the order, e.g., the response was lost because of the timeout
+			createdOrder, queryErr := queryService.QueryOrder(ctx, types.OrderQuery{
+				Symbol:        order.Symbol,
+				ClientOrderID: order.ClientOrderID,
+			})
+			if queryErr == nil && createdOrder != nil {
+				log.Infof("order %s is recovered by client order id %s", order.String(), order.ClientOrderID)
+				return createdOrder, nil
+			}
+		}
+
+		var retOrders types.OrderSlice
+		retOrders, err = e.OrderExecutor.SubmitOrders(ctx, order)
+		if err == nil {
+			if len(retOrders) == 0 {
+				return nil, nil
+			}
+
+			return &retOrders[0], nil
+		}
+
+		// the orders rejected by the exchange or the risk controls are not retried
+		if !isTransientSubmitError(err) {
+			return nil, err
+		}
+
+		log.WithError(err).Warnf("submit order %s failed, attempt %d/%d", order.String(), i+1, attempts)
+	}
+
+	return nil, err
+}
+
+// isTransientSubmitError returns true if the order submission failed because of the network,
+// e.g., the request timed out or the connection was reset, so the order might be created or can be submitted again.
+// The rejections of the exchange, like the insufficient balance, are returned by the api response, and they are not transient.
+func isTransientSubmitError(err error) bool {
+	if errors.Is(err, ErrPreTradeRiskRejected) || errors.Is(err, ErrCircuitBreakerTripped) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	// the errors of the http client are *url.Error, which is a net.Error
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
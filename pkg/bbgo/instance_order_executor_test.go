@@ -0,0 +1,126 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// lostResponseExchange creates the orders but drops the responses of the first failures
+type lostResponseExchange struct {
+	types.Exchange
+
+	failures int
+	orders   map[string]types.Order
+
+	// rejection is returned without creating the order
+	rejection error
+}
+
+func (e *lostResponseExchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	if o, ok := e.orders[q.ClientOrderID]; ok {
+		return &o, nil
+	}
+
+	return nil, errors.New("order not found")
+}
+
+type lostResponseOrderExecutor struct {
+	ExchangeOrderExecutor
+	exchange *lostResponseExchange
+	submits  int
+}
+
+func (e *lostResponseOrderExecutor) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+	e.submits++
+
+	if e.exchange.rejection != nil {
+		return nil, e.exchange.rejection
+	}
+
+	var createdOrders types.OrderSlice
+	for _, o := range orders {
+		if _, ok := e.exchange.orders[o.ClientOrderID]; ok {
+			return nil, errors.New("duplicate client order id")
+		}
+
+		order := types.Order{SubmitOrder: o, OrderID: uint64(len(e.exchange.orders) + 1), Status: types.OrderStatusNew}
+		e.exchange.orders[o.ClientOrderID] = order
+		createdOrders = append(createdOrders, order)
+	}
+
+	if e.exchange.failures > 0 {
+		e.exchange.failures--
+		// the http client timeout
+		return nil, &url.Error{Op: "Post", URL: "https://api.binance.com/api/v3/order", Err: context.DeadlineExceeded}
+	}
+
+	return createdOrders, nil
+}
+
+func TestInstanceOrderExecutor_SubmitOrders(t *testing.T) {
+	exchange := &lostResponseExchange{failures: 1, orders: make(map[string]types.Order)}
+	executor := &lostResponseOrderExecutor{exchange: exchange}
+	session := &ExchangeSession{ExchangeName: types.ExchangeBinance, Exchange: exchange}
+
+	instanceExecutor := NewInstanceOrderExecutor(executor, session, "test", "test:BTCUSDT")
+	instanceExecutor.RetryInterval = 0
+
+	createdOrders, err := instanceExecutor.SubmitOrders(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Quantity: fixedpoint.One,
+		Price:    fixedpoint.NewFromInt(1000),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, executor.submits)
+	assert.Len(t, exchange.orders, 1)
+	if assert.Len(t, createdOrders, 1) {
+		assert.Equal(t, uint64(1), createdOrders[0].OrderID)
+
+		store := NewOrderStore("BTCUSDT")
+		store.Add(createdOrders[0], types.Order{OrderID: 2, SubmitOrder: types.SubmitOrder{ClientOrderID: strconv.Itoa(2)}})
+		assert.Len(t, store.InstanceOrders("test", "test:BTCUSDT"), 1)
+		assert.Len(t, store.InstanceOrders("test", "test:ETHUSDT"), 0)
+	}
+}
+
+func TestInstanceOrderExecutor_SubmitOrdersRejected(t *testing.T) {
+	exchange := &lostResponseExchange{
+		orders:    make(map[string]types.Order),
+		rejection: errors.New("Account has insufficient balance for requested action"),
+	}
+	executor := &lostResponseOrderExecutor{exchange: exchange}
+	session := &ExchangeSession{ExchangeName: types.ExchangeBinance, Exchange: exchange}
+
+	instanceExecutor := NewInstanceOrderExecutor(executor, session, "test", "test:BTCUSDT")
+	instanceExecutor.RetryInterval = 0
+
+	_, err := instanceExecutor.SubmitOrders(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Quantity: fixedpoint.One,
+		Price:    fixedpoint.NewFromInt(1000),
+	})
+
+	// the rejection is returned immediately
+	assert.Error(t, err)
+	assert.Equal(t, 1, executor.submits)
+}
+
+func Test_isTransientSubmitError(t *testing.T) {
+	assert.True(t, isTransientSubmitError(&url.Error{Op: "Post", URL: "https://api.binance.com", Err: errors.New("connection reset by peer")}))
+	assert.True(t, isTransientSubmitError(context.DeadlineExceeded))
+	assert.False(t, isTransientSubmitError(errors.New("Filter failure: PRICE_FILTER")))
+	assert.False(t, isTransientSubmitError(ErrPreTradeRiskRejected))
+	assert.False(t, isTransientSubmitError(context.Canceled))
+}
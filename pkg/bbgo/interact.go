@@ -7,6 +7,9 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/interact"
@@ -115,6 +118,13 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 			message += "- " + balance.String() + "\n"
 		}
 
+		if err := it.environment.UpdateReportingPrices(context.Background(), balances.Currencies()); err != nil {
+			log.WithError(err).Error("reporting price update failed")
+		} else {
+			assets := it.environment.ConvertAssets(balances.Assets(session.LastPrices(), time.Now()))
+			message += fmt.Sprintf("Total ≈ %s\n", types.FormatMoney(it.environment.ReportingCurrency(), assets.InReportingCurrency()))
+		}
+
 		reply.Message(message)
 		return nil
 	})
@@ -386,6 +396,24 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		reply.Message(fmt.Sprintf("Strategy %s stopped and the position closed.", signature))
 		return nil
 	})
+
+	i.PrivateCommand("/resetcircuitbreaker", "Reset Circuit Breaker", func(reply interact.Reply) error {
+		if it.trader.riskControls == nil || it.trader.riskControls.CircuitBreaker == nil {
+			reply.Message("Circuit breaker is not configured")
+			return nil
+		}
+
+		breaker := it.trader.riskControls.CircuitBreaker
+		if !breaker.Tripped() {
+			reply.Message("Circuit breaker is not tripped")
+			return nil
+		}
+
+		reason := breaker.Reason()
+		breaker.Reset()
+		reply.Message(fmt.Sprintf("Circuit breaker (%s) is reset, please resume the strategies manually.", reason))
+		return nil
+	})
 }
 
 func (it *CoreInteraction) Initialize() error {
@@ -0,0 +1,237 @@
+package bbgo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var (
+	two  = fixedpoint.NewFromInt(2)
+	four = fixedpoint.NewFromInt(4)
+)
+
+// KLineTransformer transforms the closed klines of the source interval into the derived klines
+type KLineTransformer interface {
+	Transform(kline types.KLine) []types.KLine
+}
+
+// NewKLineTransformer creates the transformer of the derived interval that is built from the klines
+func NewKLineTransformer(derived types.DerivedInterval) (KLineTransformer, error) {
+	switch derived.Type {
+	case types.DerivedKLineHeikinAshi:
+		return &HeikinAshiTransformer{Interval: derived.Interval()}, nil
+
+	case types.DerivedKLineRenko:
+		return &RenkoTransformer{Interval: derived.Interval(), BoxSize: derived.Size, ATRWindow: derived.ATRWindow}, nil
+	}
+
+	return nil, fmt.Errorf("derived kline type %s can not be built from klines", derived.Type)
+}
+
+// derivedKLineEndTime returns the end time after the end time of the last derived kline,
+// the indicators skip the klines that don't end after the last updated kline,
+// so the derived klines created at the same time are given the consecutive milliseconds.
+func derivedKLineEndTime(lastEndTime, endTime time.Time) time.Time {
+	if !endTime.After(lastEndTime) {
+		return lastEndTime.Add(time.Millisecond)
+	}
+
+	return endTime
+}
+
+// HeikinAshiTransformer transforms the klines into the heikin-ashi klines
+type HeikinAshiTransformer struct {
+	Interval types.Interval
+
+	last *types.KLine
+}
+
+func (t *HeikinAshiTransformer) Transform(kline types.KLine) []types.KLine {
+	haClose := kline.Open.Add(kline.High).Add(kline.Low).Add(kline.Close).Div(four)
+
+	// the first open is the middle of the open and the close
+	haOpen := kline.Open.Add(kline.Close).Div(two)
+	if t.last != nil {
+		haOpen = t.last.Open.Add(t.last.Close).Div(two)
+	}
+
+	ha := kline
+	ha.Interval = t.Interval
+	ha.Open = haOpen
+	ha.Close = haClose
+	ha.High = fixedpoint.Max(kline.High, fixedpoint.Max(haOpen, haClose))
+	ha.Low = fixedpoint.Min(kline.Low, fixedpoint.Min(haOpen, haClose))
+	t.last = &ha
+	return []types.KLine{ha}
+}
+
+// RenkoTransformer transforms the close prices of the klines into the renko bricks.
+// A new brick is added when the close price moves a box size above the top or below the bottom of the last brick,
+// so the reversal takes two box sizes.
+// If the ATRWindow is set, the box size is the latest ATR of the klines, and no brick is added before the ATR is ready.
+type RenkoTransformer struct {
+	Interval  types.Interval
+	BoxSize   fixedpoint.Value
+	ATRWindow int
+
+	atr *indicator.ATR
+
+	// top and bottom are the boundaries of the last brick
+	top, bottom fixedpoint.Value
+
+	// the volume of the klines that haven't been added to a brick
+	volume, quoteVolume fixedpoint.Value
+	numberOfTrades      uint64
+	startTime           types.Time
+	lastEndTime         time.Time
+}
+
+func (t *RenkoTransformer) Transform(kline types.KLine) (bricks []types.KLine) {
+	boxSize := t.BoxSize
+	if t.ATRWindow > 0 {
+		if t.atr == nil {
+			t.atr = &indicator.ATR{IntervalWindow: types.IntervalWindow{Interval: kline.Interval, Window: t.ATRWindow}}
+		}
+
+		t.atr.Update(kline.High.Float64(), kline.Low.Float64(), kline.Close.Float64())
+		if t.atr.Length() < t.ATRWindow {
+			return nil
+		}
+
+		boxSize = fixedpoint.NewFromFloat(t.atr.Last())
+	}
+
+	if boxSize.Sign() <= 0 {
+		return nil
+	}
+
+	if t.startTime.Time().IsZero() {
+		t.startTime = kline.StartTime
+	}
+
+	t.volume = t.volume.Add(kline.Volume)
+	t.quoteVolume = t.quoteVolume.Add(kline.QuoteVolume)
+	t.numberOfTrades += kline.NumberOfTrades
+
+	// the first brick starts from the first close price
+	if t.top.IsZero() && t.bottom.IsZero() {
+		t.top = kline.Close
+		t.bottom = kline.Close
+		return nil
+	}
+
+	var prices [][2]fixedpoint.Value
+	for kline.Close.Compare(t.top.Add(boxSize)) >= 0 {
+		prices = append(prices, [2]fixedpoint.Value{t.top, t.top.Add(boxSize)})
+		t.bottom = t.top
+		t.top = t.top.Add(boxSize)
+	}
+
+	for kline.Close.Compare(t.bottom.Sub(boxSize)) <= 0 {
+		prices = append(prices, [2]fixedpoint.Value{t.bottom, t.bottom.Sub(boxSize)})
+		t.top = t.bottom
+		t.bottom = t.bottom.Sub(boxSize)
+	}
+
+	for i, p := range prices {
+		brick := kline
+		brick.Interval = t.Interval
+		brick.Open = p[0]
+		brick.Close = p[1]
+		brick.High = fixedpoint.Max(p[0], p[1])
+		brick.Low = fixedpoint.Min(p[0], p[1])
+		brick.StartTime = t.startTime
+		brick.EndTime = types.Time(derivedKLineEndTime(t.lastEndTime, kline.EndTime.Time().Add(-time.Duration(len(prices)-1-i)*time.Millisecond)))
+		brick.Closed = true
+
+		// the volume since the last brick goes to the first brick
+		brick.Volume = t.volume
+		brick.QuoteVolume = t.quoteVolume
+		brick.NumberOfTrades = t.numberOfTrades
+		t.volume = fixedpoint.Zero
+		t.quoteVolume = fixedpoint.Zero
+		t.numberOfTrades = 0
+		t.startTime = brick.EndTime
+
+		t.lastEndTime = brick.EndTime.Time()
+		bricks = append(bricks, brick)
+	}
+
+	if len(bricks) > 0 {
+		t.startTime = types.Time{}
+	}
+
+	return bricks
+}
+
+// TradeBarBuilder builds the range, the volume and the tick bars from the market trades
+type TradeBarBuilder struct {
+	Interval types.Interval
+	Type     types.DerivedKLineType
+	Size     fixedpoint.Value
+
+	Symbol string
+
+	current     *types.KLine
+	lastEndTime time.Time
+}
+
+func NewTradeBarBuilder(symbol string, derived types.DerivedInterval) (*TradeBarBuilder, error) {
+	switch derived.Type {
+	case types.DerivedKLineRange, types.DerivedKLineVolume, types.DerivedKLineTick:
+		return &TradeBarBuilder{Interval: derived.Interval(), Type: derived.Type, Size: derived.Size, Symbol: symbol}, nil
+	}
+
+	return nil, fmt.Errorf("derived kline type %s can not be built from trades", derived.Type)
+}
+
+// AddTrade adds the trade to the current bar, the bar is returned when it's closed
+func (b *TradeBarBuilder) AddTrade(trade types.Trade) (types.KLine, bool) {
+	if b.current == nil {
+		b.current = &types.KLine{
+			Exchange:  trade.Exchange,
+			Symbol:    b.Symbol,
+			Interval:  b.Interval,
+			StartTime: trade.Time,
+			Open:      trade.Price,
+			High:      trade.Price,
+			Low:       trade.Price,
+		}
+	}
+
+	bar := b.current
+	bar.High = fixedpoint.Max(bar.High, trade.Price)
+	bar.Low = fixedpoint.Min(bar.Low, trade.Price)
+	bar.Close = trade.Price
+	bar.Volume = bar.Volume.Add(trade.Quantity)
+	bar.QuoteVolume = bar.QuoteVolume.Add(trade.Quantity.Mul(trade.Price))
+	bar.NumberOfTrades++
+	if trade.Side == types.SideTypeBuy {
+		bar.TakerBuyBaseAssetVolume = bar.TakerBuyBaseAssetVolume.Add(trade.Quantity)
+		bar.TakerBuyQuoteAssetVolume = bar.TakerBuyQuoteAssetVolume.Add(trade.Quantity.Mul(trade.Price))
+	}
+
+	var closed bool
+	switch b.Type {
+	case types.DerivedKLineRange:
+		closed = bar.High.Sub(bar.Low).Compare(b.Size) >= 0
+	case types.DerivedKLineVolume:
+		closed = bar.Volume.Compare(b.Size) >= 0
+	case types.DerivedKLineTick:
+		closed = fixedpoint.NewFromInt(int64(bar.NumberOfTrades)).Compare(b.Size) >= 0
+	}
+
+	if !closed {
+		return types.KLine{}, false
+	}
+
+	bar.EndTime = types.Time(derivedKLineEndTime(b.lastEndTime, trade.Time.Time()))
+	bar.Closed = true
+	b.lastEndTime = bar.EndTime.Time()
+	b.current = nil
+	return *bar, true
+}
@@ -0,0 +1,204 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestKLine(i int, open, high, low, cloze float64) types.KLine {
+	startTime := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Minute)
+	return types.KLine{
+		Symbol:    "BTCUSDT",
+		Interval:  types.Interval1m,
+		StartTime: types.Time(startTime),
+		EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+		Open:      fixedpoint.NewFromFloat(open),
+		High:      fixedpoint.NewFromFloat(high),
+		Low:       fixedpoint.NewFromFloat(low),
+		Close:     fixedpoint.NewFromFloat(cloze),
+		Volume:    fixedpoint.NewFromInt(int64(i + 1)),
+		Closed:    true,
+	}
+}
+
+func newTestTrade(i int, price, quantity float64, side types.SideType) types.Trade {
+	return types.Trade{
+		Symbol:   "BTCUSDT",
+		Side:     side,
+		Price:    fixedpoint.NewFromFloat(price),
+		Quantity: fixedpoint.NewFromFloat(quantity),
+		Time:     types.Time(time.Date(2022, 6, 1, 0, 0, i, 0, time.UTC)),
+	}
+}
+
+func TestHeikinAshiTransformer(t *testing.T) {
+	transformer := &HeikinAshiTransformer{Interval: "heikinashi:1m"}
+
+	ha := transformer.Transform(newTestKLine(0, 100, 110, 90, 104))
+	if assert.Len(t, ha, 1) {
+		assert.Equal(t, types.Interval("heikinashi:1m"), ha[0].Interval)
+		assert.InDelta(t, 102.0, ha[0].Open.Float64(), 1e-9)
+		assert.InDelta(t, 101.0, ha[0].Close.Float64(), 1e-9)
+		assert.InDelta(t, 110.0, ha[0].High.Float64(), 1e-9)
+		assert.InDelta(t, 90.0, ha[0].Low.Float64(), 1e-9)
+	}
+
+	ha = transformer.Transform(newTestKLine(1, 104, 106, 100, 105))
+	if assert.Len(t, ha, 1) {
+		// open = (102 + 101) / 2, close = (104 + 106 + 100 + 105) / 4
+		assert.InDelta(t, 101.5, ha[0].Open.Float64(), 1e-9)
+		assert.InDelta(t, 103.75, ha[0].Close.Float64(), 1e-9)
+		assert.InDelta(t, 106.0, ha[0].High.Float64(), 1e-9)
+		assert.InDelta(t, 100.0, ha[0].Low.Float64(), 1e-9)
+		assert.InDelta(t, 2.0, ha[0].Volume.Float64(), 1e-9)
+	}
+}
+
+func TestRenkoTransformer(t *testing.T) {
+	transformer := &RenkoTransformer{Interval: "renko:1m:10", BoxSize: fixedpoint.NewFromInt(10)}
+
+	var bricks []types.KLine
+	for i, cloze := range []float64{100, 105, 112, 125, 131, 108, 85} {
+		bricks = append(bricks, transformer.Transform(newTestKLine(i, cloze, cloze, cloze, cloze))...)
+	}
+
+	expected := [][2]float64{{100, 110}, {110, 120}, {120, 130}, {120, 110}, {110, 100}, {100, 90}}
+	if assert.Len(t, bricks, len(expected)) {
+		for i, brick := range bricks {
+			assert.Equal(t, types.Interval("renko:1m:10"), brick.Interval)
+			assert.InDelta(t, expected[i][0], brick.Open.Float64(), 1e-9, "open of brick %d", i)
+			assert.InDelta(t, expected[i][1], brick.Close.Float64(), 1e-9, "close of brick %d", i)
+			if i > 0 {
+				assert.True(t, brick.EndTime.After(bricks[i-1].EndTime.Time()), "end time of brick %d", i)
+			}
+		}
+
+		// the volume of the klines 0 ~ 2 goes to the first brick
+		assert.InDelta(t, 6.0, bricks[0].Volume.Float64(), 1e-9)
+
+		// the bricks of the same kline end at the consecutive milliseconds
+		assert.Equal(t, time.Millisecond, bricks[5].EndTime.Time().Sub(bricks[4].EndTime.Time()))
+		assert.Equal(t, bricks[5].EndTime, newTestKLine(6, 0, 0, 0, 0).EndTime)
+	}
+}
+
+func TestRenkoTransformer_ATR(t *testing.T) {
+	transformer := &RenkoTransformer{Interval: "renko:1m:atr3", ATRWindow: 3}
+	atr := &indicator.ATR{IntervalWindow: types.IntervalWindow{Interval: types.Interval1m, Window: 3}}
+
+	var bricks []types.KLine
+	for i, cloze := range []float64{100, 100, 100, 100, 100, 140} {
+		k := newTestKLine(i, cloze, cloze+5, cloze-5, cloze)
+		atr.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+
+		b := transformer.Transform(k)
+		if atr.Length() < 3 {
+			assert.Empty(t, b)
+		}
+		bricks = append(bricks, b...)
+	}
+
+	boxSize := atr.Last()
+	if assert.Len(t, bricks, int(40/boxSize)) {
+		for _, brick := range bricks {
+			assert.InDelta(t, boxSize, brick.Close.Sub(brick.Open).Float64(), 1e-6)
+		}
+	}
+}
+
+func TestTradeBarBuilder(t *testing.T) {
+	t.Run("range", func(t *testing.T) {
+		builder, err := NewTradeBarBuilder("BTCUSDT", types.DerivedInterval{Type: types.DerivedKLineRange, Size: fixedpoint.NewFromInt(10)})
+		assert.NoError(t, err)
+
+		_, ok := builder.AddTrade(newTestTrade(0, 100, 1, types.SideTypeBuy))
+		assert.False(t, ok)
+		_, ok = builder.AddTrade(newTestTrade(1, 95, 1, types.SideTypeSell))
+		assert.False(t, ok)
+
+		bar, ok := builder.AddTrade(newTestTrade(2, 105, 2, types.SideTypeBuy))
+		if assert.True(t, ok) {
+			assert.Equal(t, types.Interval("range:10"), bar.Interval)
+			assert.InDelta(t, 100.0, bar.Open.Float64(), 1e-9)
+			assert.InDelta(t, 105.0, bar.High.Float64(), 1e-9)
+			assert.InDelta(t, 95.0, bar.Low.Float64(), 1e-9)
+			assert.InDelta(t, 105.0, bar.Close.Float64(), 1e-9)
+			assert.InDelta(t, 4.0, bar.Volume.Float64(), 1e-9)
+			assert.InDelta(t, 3.0, bar.TakerBuyBaseAssetVolume.Float64(), 1e-9)
+			assert.Equal(t, uint64(3), bar.NumberOfTrades)
+			assert.True(t, bar.Closed)
+		}
+	})
+
+	t.Run("volume", func(t *testing.T) {
+		builder, err := NewTradeBarBuilder("BTCUSDT", types.DerivedInterval{Type: types.DerivedKLineVolume, Size: fixedpoint.NewFromInt(3)})
+		assert.NoError(t, err)
+
+		var bars []types.KLine
+		for i := 0; i < 7; i++ {
+			if bar, ok := builder.AddTrade(newTestTrade(0, 100, 1, types.SideTypeBuy)); ok {
+				bars = append(bars, bar)
+			}
+		}
+
+		// the trades happen at the same time, but the bars end at the increasing times
+		if assert.Len(t, bars, 2) {
+			assert.True(t, bars[1].EndTime.After(bars[0].EndTime.Time()))
+		}
+	})
+
+	t.Run("tick", func(t *testing.T) {
+		builder, err := NewTradeBarBuilder("BTCUSDT", types.DerivedInterval{Type: types.DerivedKLineTick, Size: fixedpoint.NewFromInt(2)})
+		assert.NoError(t, err)
+
+		var bars []types.KLine
+		for i := 0; i < 5; i++ {
+			if bar, ok := builder.AddTrade(newTestTrade(i, 100+float64(i), 1, types.SideTypeBuy)); ok {
+				bars = append(bars, bar)
+			}
+		}
+
+		if assert.Len(t, bars, 2) {
+			assert.InDelta(t, 102.0, bars[1].Open.Float64(), 1e-9)
+			assert.InDelta(t, 103.0, bars[1].Close.Float64(), 1e-9)
+		}
+	})
+
+	_, err := NewTradeBarBuilder("BTCUSDT", types.DerivedInterval{Type: types.DerivedKLineHeikinAshi, Source: types.Interval1m})
+	assert.Error(t, err)
+}
+
+func TestMarketDataStore_DerivedInterval(t *testing.T) {
+	store := NewMarketDataStore("BTCUSDT")
+	assert.NoError(t, store.AddDerivedInterval("heikinashi:1m"))
+	assert.NoError(t, store.AddDerivedInterval("heikinashi:1m"))
+	assert.NoError(t, store.AddDerivedInterval("tick:2"))
+	assert.Error(t, store.AddDerivedInterval("heikinashi:2m"))
+
+	sma := &indicator.SMA{IntervalWindow: types.IntervalWindow{Interval: "heikinashi:1m", Window: 3}}
+	sma.Bind(store)
+
+	addTestKLines(store, 0, 10)
+
+	window, ok := store.KLinesOfInterval("heikinashi:1m")
+	if assert.True(t, ok) && assert.Len(t, *window, 10) {
+		last := (*window)[7:]
+		expected := (last[0].Close.Float64() + last[1].Close.Float64() + last[2].Close.Float64()) / 3
+		assert.InDelta(t, expected, sma.Last(), 1e-6)
+	}
+
+	for i := 0; i < 5; i++ {
+		store.AddTrade(newTestTrade(i, 100, 1, types.SideTypeBuy))
+	}
+
+	window, ok = store.KLinesOfInterval("tick:2")
+	if assert.True(t, ok) {
+		assert.Len(t, *window, 2)
+	}
+}
@@ -1,6 +1,13 @@
 package bbgo
 
-import "github.com/c9s/bbgo/pkg/types"
+import (
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
 
 const MaxNumOfKLines = 5_000
 const MaxNumOfKLinesTruncate = 100
@@ -13,6 +20,14 @@ type MarketDataStore struct {
 	// KLineWindows stores all loaded klines per interval
 	KLineWindows map[types.Interval]*types.KLineWindow `json:"-"`
 
+	// kLineTransformers are the transformers of the derived intervals, indexed by the source interval
+	kLineTransformers map[types.Interval][]KLineTransformer
+
+	// tradeBarBuilders are the builders of the derived intervals of the market trades
+	tradeBarBuilders []*TradeBarBuilder
+
+	derivedIntervals map[types.Interval]struct{}
+
 	kLineWindowUpdateCallbacks []func(interval types.Interval, klines types.KLineWindow)
 }
 
@@ -22,7 +37,110 @@ func NewMarketDataStore(symbol string) *MarketDataStore {
 
 		// KLineWindows stores all loaded klines per interval
 		KLineWindows: make(map[types.Interval]*types.KLineWindow, len(types.SupportedIntervals)), // 12 interval, 1m,5m,15m,30m,1h,2h,4h,6h,12h,1d,3d,1w
+
+		kLineTransformers: make(map[types.Interval][]KLineTransformer),
+		derivedIntervals:  make(map[types.Interval]struct{}),
+	}
+}
+
+// BindIndicator sends the loaded klines of the interval to the indicator, and then binds the indicator to the store.
+// The indicator restored from the persistence skips the klines before its snapshot,
+// so it's continued with only the klines after the snapshot.
+// If the loaded klines don't continue from the snapshot, the missing klines can not be calculated, an error is logged
+// so that the snapshot can be removed and the indicator can be recalculated from the loaded klines.
+func (store *MarketDataStore) BindIndicator(interval types.Interval, inc KLineWindowIndicator) {
+	if window, ok := store.KLinesOfInterval(interval); ok && len(*window) > 0 {
+		if endTime, ok := snapshotEndTime(inc, interval); ok {
+			if gap, found := findSnapshotGap(*window, interval, endTime); found {
+				log.Errorf("%s %s indicator %T snapshot ends at %s, but the loaded klines start from %s, the klines in between are skipped",
+					store.Symbol, interval, inc, endTime, gap)
+			}
+		}
+
+		inc.Bind(&kLineWindowReplayer{interval: interval, window: *window})
+	}
+
+	inc.Bind(store)
+}
+
+// snapshotEndTime returns the end time of the last kline calculated by the indicator, it's set when the indicator is restored from a snapshot.
+// The indicators keep the EndTime of the last kline, or the LastOpenTime like EWMA.
+func snapshotEndTime(inc interface{}, interval types.Interval) (time.Time, bool) {
+	rv := reflect.Indirect(reflect.ValueOf(inc))
+	if rv.Kind() != reflect.Struct {
+		return time.Time{}, false
+	}
+
+	if field := rv.FieldByName("EndTime"); field.IsValid() {
+		endTime, ok := field.Interface().(time.Time)
+		return endTime, ok && !endTime.IsZero()
+	}
+
+	if field := rv.FieldByName("LastOpenTime"); field.IsValid() {
+		openTime, ok := field.Interface().(time.Time)
+		if !ok || openTime.IsZero() {
+			return time.Time{}, false
+		}
+
+		return openTime.Add(interval.Duration() - time.Millisecond), true
 	}
+
+	return time.Time{}, false
+}
+
+// findSnapshotGap returns the start time of the first kline after the snapshot end time,
+// if the kline starts one interval or more after the snapshot end time, which means at least one kline is missing.
+// The derived intervals like renko don't have a fixed duration, so they are not checked.
+func findSnapshotGap(window types.KLineWindow, interval types.Interval, endTime time.Time) (time.Time, bool) {
+	duration := interval.Duration()
+	if duration == 0 {
+		return time.Time{}, false
+	}
+
+	for _, k := range window {
+		if !k.EndTime.After(endTime) {
+			continue
+		}
+
+		start := k.StartTime.Time()
+		return start, !start.Before(endTime.Add(duration))
+	}
+
+	return time.Time{}, false
+}
+
+// AddDerivedInterval adds the derived interval like "heikinashi:1m", "renko:1m:10" or "range:10",
+// the derived klines are added to the kline windows and emitted like the klines of the normal intervals.
+// The klines derived from the klines are built from the closed klines of the source interval,
+// and the klines derived from the market trades are built from the market trades of the bound stream.
+func (store *MarketDataStore) AddDerivedInterval(interval types.Interval) error {
+	if _, ok := store.derivedIntervals[interval]; ok {
+		return nil
+	}
+
+	derived, err := types.ParseDerivedInterval(interval)
+	if err != nil {
+		return err
+	}
+
+	if derived.FromTrades() {
+		builder, err := NewTradeBarBuilder(store.Symbol, derived)
+		if err != nil {
+			return err
+		}
+
+		store.tradeBarBuilders = append(store.tradeBarBuilders, builder)
+	} else {
+		transformer, err := NewKLineTransformer(derived)
+		if err != nil {
+			return err
+		}
+
+		store.kLineTransformers[derived.Source] = append(store.kLineTransformers[derived.Source], transformer)
+	}
+
+	store.derivedIntervals[interval] = struct{}{}
+	return nil
 }
 
 func (store *MarketDataStore) SetKLineWindows(windows map[types.Interval]*types.KLineWindow) {
@@ -37,6 +155,24 @@ func (store *MarketDataStore) KLinesOfInterval(interval types.Interval) (kLines
 
 func (store *MarketDataStore) BindStream(stream types.Stream) {
 	stream.OnKLineClosed(store.handleKLineClosed)
+	stream.OnMarketTrade(store.handleMarketTrade)
+}
+
+func (store *MarketDataStore) handleMarketTrade(trade types.Trade) {
+	if trade.Symbol != store.Symbol {
+		return
+	}
+
+	store.AddTrade(trade)
+}
+
+// AddTrade adds the market trade to the bars of the derived intervals
+func (store *MarketDataStore) AddTrade(trade types.Trade) {
+	for _, builder := range store.tradeBarBuilders {
+		if bar, ok := builder.AddTrade(trade); ok {
+			store.AddKLine(bar)
+		}
+	}
 }
 
 func (store *MarketDataStore) handleKLineClosed(kline types.KLine) {
@@ -61,4 +197,10 @@ func (store *MarketDataStore) AddKLine(kline types.KLine) {
 	}
 
 	store.EmitKLineWindowUpdate(kline.Interval, *window)
+
+	for _, transformer := range store.kLineTransformers[kline.Interval] {
+		for _, derived := range transformer.Transform(kline) {
+			store.AddKLine(derived)
+		}
+	}
 }
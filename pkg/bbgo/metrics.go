@@ -97,6 +97,26 @@ var (
 			"currency",  // for balance
 		},
 	)
+
+	metricsRiskRejectedOrders = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bbgo_risk_rejected_orders_total",
+			Help: "bbgo orders rejected by the pre-trade risk control",
+		},
+		[]string{
+			"exchange", // exchange name
+			"session",  // session name
+			"symbol",
+			"rule", // the risk rule that rejects the order
+		},
+	)
+
+	metricsCircuitBreakerTripped = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "bbgo_circuit_breaker_tripped",
+			Help: "bbgo circuit breaker status, 1 means tripped",
+		},
+	)
 )
 
 func init() {
@@ -108,5 +128,7 @@ func init() {
 		metricsTradesTotal,
 		metricsTradingVolume,
 		metricsLastUpdateTimeBalance,
+		metricsRiskRejectedOrders,
+		metricsCircuitBreakerTripped,
 	)
 }
@@ -0,0 +1,110 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// AmendOrder changes the price or the quantity of an existing order.
+// If the exchange implements types.ExchangeOrderAmendService, the native amend api is used,
+// otherwise the order is canceled and a new order with the remaining quantity is submitted (cancel-replace).
+func AmendOrder(ctx context.Context, ex types.Exchange, amend types.OrderAmend) (*types.Order, error) {
+	if service, ok := ex.(types.ExchangeOrderAmendService); ok {
+		amendedOrder, err := service.AmendOrder(ctx, amend)
+		if err == nil {
+			return amendedOrder, nil
+		}
+
+		if !errors.Is(err, types.ErrOrderAmendNotSupported) {
+			return nil, err
+		}
+
+		log.Debugf("native order amend is not supported for order %s, falling back to cancel-replace", amend.Order.String())
+	}
+
+	return cancelReplaceOrder(ctx, ex, amend)
+}
+
+// cancelReplaceOrder emulates the amend api by canceling the order and submitting a new one.
+// The new order does not keep the queue priority of the original order.
+func cancelReplaceOrder(ctx context.Context, ex types.Exchange, amend types.OrderAmend) (*types.Order, error) {
+	order := amend.Order
+
+	remaining := amend.RemainingQuantity()
+	if remaining.Sign() <= 0 {
+		return nil, fmt.Errorf("can not amend order %s, new quantity %s is not greater than the executed quantity %s",
+			order.String(), amend.NewQuantity().String(), order.ExecutedQuantity.String())
+	}
+
+	if err := ex.CancelOrders(ctx, order); err != nil {
+		return nil, errors.Wrapf(err, "cancel-replace: can not cancel order %s", order.String())
+	}
+
+	submitOrder := order.Backup()
+	submitOrder.Price = amend.NewPrice()
+	submitOrder.Quantity = remaining
+
+	createdOrders, err := ex.SubmitOrders(ctx, submitOrder)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cancel-replace: can not submit order %s", submitOrder.String())
+	}
+
+	if len(createdOrders) == 0 {
+		return nil, fmt.Errorf("cancel-replace: no order is created for %s", submitOrder.String())
+	}
+
+	return &createdOrders[0], nil
+}
+
+// OrderAmender amends the orders, ExchangeOrderExecutor implements it with the pre-trade risk checks
+type OrderAmender interface {
+	AmendOrder(ctx context.Context, amend types.OrderAmend) (*types.Order, error)
+}
+
+// AmendOrder amends the order through the session exchange, the new price and quantity are formatted by the session market.
+// The amend is refused when the circuit breaker is tripped, and the new order is checked by the pre-trade risk control
+// like the orders submitted by SubmitOrders, since both the native amend and the cancel-replace place a new order.
+func (e *ExchangeOrderExecutor) AmendOrder(ctx context.Context, amend types.OrderAmend) (*types.Order, error) {
+	if e.circuitBreaker != nil && e.circuitBreaker.Tripped() {
+		return nil, errors.Wrap(ErrCircuitBreakerTripped, e.circuitBreaker.Reason())
+	}
+
+	if market, ok := e.Session.Market(amend.Order.Symbol); ok {
+		amend.Order.Market = market
+	}
+
+	if e.preTradeRiskControl != nil {
+		newOrder := amend.Order.Backup()
+		newOrder.Price = amend.NewPrice()
+		newOrder.Quantity = amend.RemainingQuantity()
+		if err := e.preTradeRiskControl.ProcessReplaceOrder(e.Session, newOrder); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Infof("amending order: %s -> price %s quantity %s", amend.Order.String(), amend.NewPrice().String(), amend.NewQuantity().String())
+	return AmendOrder(ctx, e.Session.Exchange, amend)
+}
+
+// Amend amends the active order through the amender, usually the session order executor, and replaces it in the local active order book.
+// Exchanges like binance create a new order ID for the amended order, so the original order is removed.
+func (b *LocalActiveOrderBook) Amend(ctx context.Context, amender OrderAmender, amend types.OrderAmend) (*types.Order, error) {
+	amendedOrder, err := amender.AmendOrder(ctx, amend)
+	if err != nil {
+		return nil, err
+	}
+
+	if amendedOrder.OrderID != amend.Order.OrderID {
+		b.Remove(amend.Order)
+		b.Add(*amendedOrder)
+	} else {
+		b.Update(*amendedOrder)
+	}
+
+	return amendedOrder, nil
+}
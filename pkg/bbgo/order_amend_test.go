@@ -0,0 +1,204 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type amendTestExchange struct {
+	types.Exchange
+
+	// nativeAmend is the amended order returned by AmendOrder, AmendOrder returns ErrOrderAmendNotSupported when it's nil
+	nativeAmend *types.Order
+
+	nextOrderID     uint64
+	canceledOrders  []types.Order
+	submittedOrders []types.SubmitOrder
+}
+
+func (e *amendTestExchange) AmendOrder(ctx context.Context, amend types.OrderAmend) (*types.Order, error) {
+	if e.nativeAmend == nil {
+		return nil, fmt.Errorf("%w: classic order", types.ErrOrderAmendNotSupported)
+	}
+
+	return e.nativeAmend, nil
+}
+
+func (e *amendTestExchange) CancelOrders(ctx context.Context, orders ...types.Order) error {
+	e.canceledOrders = append(e.canceledOrders, orders...)
+	return nil
+}
+
+func (e *amendTestExchange) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+	var createdOrders types.OrderSlice
+	for _, order := range orders {
+		e.submittedOrders = append(e.submittedOrders, order)
+		e.nextOrderID++
+		createdOrders = append(createdOrders, types.Order{
+			SubmitOrder: order,
+			OrderID:     e.nextOrderID,
+			Status:      types.OrderStatusNew,
+		})
+	}
+
+	return createdOrders, nil
+}
+
+func newAmendTestOrder() types.Order {
+	return types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Quantity: fixedpoint.NewFromInt(2),
+			Price:    fixedpoint.NewFromInt(20000),
+		},
+		OrderID:          1,
+		Status:           types.OrderStatusPartiallyFilled,
+		ExecutedQuantity: fixedpoint.NewFromFloat(0.5),
+	}
+}
+
+func TestAmendOrder_FallbackToCancelReplace(t *testing.T) {
+	ex := &amendTestExchange{nextOrderID: 100}
+	order := newAmendTestOrder()
+
+	amendedOrder, err := AmendOrder(context.Background(), ex, types.OrderAmend{
+		Order:    order,
+		Price:    fixedpoint.NewFromInt(19900),
+		Quantity: fixedpoint.NewFromInt(3),
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(101), amendedOrder.OrderID)
+	}
+
+	if assert.Len(t, ex.canceledOrders, 1) {
+		assert.Equal(t, order.OrderID, ex.canceledOrders[0].OrderID)
+	}
+
+	// the new order is submitted with the remaining quantity of the new total quantity
+	if assert.Len(t, ex.submittedOrders, 1) {
+		assert.Equal(t, fixedpoint.NewFromInt(19900), ex.submittedOrders[0].Price)
+		assert.Equal(t, fixedpoint.NewFromFloat(2.5), ex.submittedOrders[0].Quantity)
+		assert.Equal(t, types.SideTypeBuy, ex.submittedOrders[0].Side)
+	}
+}
+
+func TestAmendOrder_QuantityNotGreaterThanExecuted(t *testing.T) {
+	ex := &amendTestExchange{}
+	_, err := AmendOrder(context.Background(), ex, types.OrderAmend{
+		Order:    newAmendTestOrder(),
+		Quantity: fixedpoint.NewFromFloat(0.5),
+	})
+	assert.Error(t, err)
+	assert.Empty(t, ex.canceledOrders)
+	assert.Empty(t, ex.submittedOrders)
+}
+
+func TestLocalActiveOrderBook_Amend(t *testing.T) {
+	t.Run("cancel-replace", func(t *testing.T) {
+		ex := &amendTestExchange{nextOrderID: 100}
+		order := newAmendTestOrder()
+
+		book := NewLocalActiveOrderBook("BTCUSDT")
+		book.Add(order)
+
+		executor := &ExchangeOrderExecutor{Session: &ExchangeSession{Exchange: ex}}
+		amendedOrder, err := book.Amend(context.Background(), executor, types.OrderAmend{
+			Order: order,
+			Price: fixedpoint.NewFromInt(19900),
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// the canceled order is replaced by the new order
+		assert.False(t, book.Exists(order))
+		assert.True(t, book.Exists(*amendedOrder))
+		assert.Equal(t, 1, book.NumOfOrders())
+
+		if orders := book.Bids.Orders(); assert.Len(t, orders, 1) {
+			assert.Equal(t, fixedpoint.NewFromInt(19900), orders[0].Price)
+			assert.Equal(t, fixedpoint.NewFromFloat(1.5), orders[0].Quantity)
+		}
+	})
+
+	t.Run("native", func(t *testing.T) {
+		order := newAmendTestOrder()
+		nativeAmend := order
+		nativeAmend.Price = fixedpoint.NewFromInt(19900)
+
+		ex := &amendTestExchange{nativeAmend: &nativeAmend}
+
+		book := NewLocalActiveOrderBook("BTCUSDT")
+		book.Add(order)
+
+		executor := &ExchangeOrderExecutor{Session: &ExchangeSession{Exchange: ex}}
+		_, err := book.Amend(context.Background(), executor, types.OrderAmend{
+			Order: order,
+			Price: fixedpoint.NewFromInt(19900),
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// the order keeps its order ID, so it's updated in place
+		assert.Empty(t, ex.canceledOrders)
+		assert.Empty(t, ex.submittedOrders)
+		assert.Equal(t, 1, book.NumOfOrders())
+
+		if orders := book.Bids.Orders(); assert.Len(t, orders, 1) {
+			assert.Equal(t, order.OrderID, orders[0].OrderID)
+			assert.Equal(t, fixedpoint.NewFromInt(19900), orders[0].Price)
+		}
+	})
+}
+
+func TestExchangeOrderExecutor_AmendOrder(t *testing.T) {
+	ex := &amendTestExchange{nextOrderID: 100}
+	order := newAmendTestOrder()
+
+	store := NewOrderStore("BTCUSDT")
+	store.Add(order)
+
+	session := &ExchangeSession{
+		Name:        "binance",
+		Exchange:    ex,
+		lastPrices:  map[string]fixedpoint.Value{"BTCUSDT": fixedpoint.NewFromInt(20000)},
+		orderStores: map[string]*OrderStore{"BTCUSDT": store},
+	}
+
+	executor := &ExchangeOrderExecutor{Session: session}
+	executor.SetPreTradeRiskControl(&PreTradeRiskControl{
+		PreTradeRiskLimits: PreTradeRiskLimits{
+			PriceBand:     fixedpoint.NewFromFloat(0.05),
+			MaxOpenOrders: 1,
+		},
+	})
+
+	breaker := &CircuitBreaker{}
+	executor.SetCircuitBreaker(breaker)
+
+	// the new price deviates 10% from the last price
+	_, err := executor.AmendOrder(context.Background(), types.OrderAmend{Order: order, Price: fixedpoint.NewFromInt(18000)})
+	assert.ErrorIs(t, err, ErrPreTradeRiskRejected)
+	assert.Empty(t, ex.canceledOrders)
+	assert.Empty(t, ex.submittedOrders)
+
+	// the amended order is not counted as an open order, so the max open orders is not reached
+	amendedOrder, err := executor.AmendOrder(context.Background(), types.OrderAmend{Order: order, Price: fixedpoint.NewFromInt(19900)})
+	if assert.NoError(t, err) {
+		assert.Equal(t, fixedpoint.NewFromInt(19900), amendedOrder.Price)
+	}
+
+	breaker.trip(context.Background(), NewTrader(NewEnvironment()), "test")
+	_, err = executor.AmendOrder(context.Background(), types.OrderAmend{Order: order, Price: fixedpoint.NewFromInt(19950)})
+	assert.ErrorIs(t, err, ErrCircuitBreakerTripped)
+	assert.Len(t, ex.submittedOrders, 1)
+}
@@ -44,12 +44,7 @@ func (e *ExchangeOrderExecutionRouter) SubmitOrdersTo(ctx context.Context, sessi
 		return nil, fmt.Errorf("exchange session %s not found", session)
 	}
 
-	formattedOrders, err := formatOrders(es, orders)
-	if err != nil {
-		return nil, err
-	}
-
-	return es.Exchange.SubmitOrders(ctx, formattedOrders...)
+	return es.OrderExecutor.SubmitOrders(ctx, orders...)
 }
 
 func (e *ExchangeOrderExecutionRouter) CancelOrdersTo(ctx context.Context, session string, orders ...types.Order) error {
@@ -73,6 +68,12 @@ type ExchangeOrderExecutor struct {
 
 	Session *ExchangeSession `json:"-" yaml:"-"`
 
+	// preTradeRiskControl checks the orders before they are submitted
+	preTradeRiskControl *PreTradeRiskControl
+
+	// circuitBreaker refuses the new orders when it's tripped
+	circuitBreaker *CircuitBreaker
+
 	// private trade update callbacks
 	tradeUpdateCallbacks []func(trade types.Trade)
 
@@ -92,7 +93,29 @@ func (e *ExchangeOrderExecutor) notifySubmitOrders(orders ...types.SubmitOrder)
 	}
 }
 
+// SetPreTradeRiskControl sets the risk control that checks every order submitted through this executor
+func (e *ExchangeOrderExecutor) SetPreTradeRiskControl(control *PreTradeRiskControl) {
+	e.preTradeRiskControl = control
+}
+
+// SetCircuitBreaker sets the circuit breaker that refuses the new orders when it's tripped
+func (e *ExchangeOrderExecutor) SetCircuitBreaker(breaker *CircuitBreaker) {
+	e.circuitBreaker = breaker
+}
+
 func (e *ExchangeOrderExecutor) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+	if e.circuitBreaker != nil && e.circuitBreaker.Tripped() {
+		return nil, errors.Wrap(ErrCircuitBreakerTripped, e.circuitBreaker.Reason())
+	}
+
+	if e.preTradeRiskControl != nil {
+		var riskErrs []error
+		orders, riskErrs = e.preTradeRiskControl.ProcessOrders(e.Session, orders...)
+		if len(orders) == 0 && len(riskErrs) > 0 {
+			return nil, riskErrs[0]
+		}
+	}
+
 	formattedOrders, err := formatOrders(e.Session, orders)
 	if err != nil {
 		return nil, err
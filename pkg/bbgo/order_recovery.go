@@ -0,0 +1,151 @@
+package bbgo
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ActiveOrderSnapshot is the persisted snapshot of the active orders,
+// it's saved with the strategy state so that the active orders can be recovered after restart.
+type ActiveOrderSnapshot struct {
+	Orders []types.Order `json:"orders"`
+	Time   time.Time     `json:"time"`
+}
+
+// Snapshot returns the snapshot of the current active orders
+func (b *LocalActiveOrderBook) Snapshot() *ActiveOrderSnapshot {
+	return &ActiveOrderSnapshot{
+		Orders: b.Orders(),
+		Time:   time.Now(),
+	}
+}
+
+// ActiveOrderRecovery re-attaches the resting orders of a strategy instance after restart.
+//
+// The open orders are matched by the order IDs in the persisted snapshot, or by the strategy instance tag
+// encoded in the client order ID (see InstanceOrderExecutor). The snapshot orders that are no longer open
+// are queried for their final status, the filled orders are emitted through the active order book filled callbacks.
+// At last, the trades since the snapshot time are replayed through the trade collector,
+// so that the position and the profit stats bound to the collector catch up with the missed fills.
+//
+// The callbacks of the active order book and the trade collector should be registered before Recover is called.
+type ActiveOrderRecovery struct {
+	Session *ExchangeSession
+	Symbol  string
+
+	ActiveOrderBook *LocalActiveOrderBook
+	OrderStore      *OrderStore
+	TradeCollector  *TradeCollector
+
+	// StrategyID and InstanceID are used for matching the open orders by the client order ID, optional
+	StrategyID, InstanceID string
+
+	// Snapshot is the persisted active orders, optional
+	Snapshot *ActiveOrderSnapshot
+}
+
+// Recover returns the recovered open orders
+func (r *ActiveOrderRecovery) Recover(ctx context.Context) (types.OrderSlice, error) {
+	ex := r.Session.Exchange
+
+	openOrders, err := ex.QueryOpenOrders(ctx, r.Symbol)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can not query %s open orders", r.Symbol)
+	}
+
+	snapshotOrders := make(map[uint64]types.Order)
+	since := time.Time{}
+	if r.Snapshot != nil {
+		since = r.Snapshot.Time
+		for _, o := range r.Snapshot.Orders {
+			snapshotOrders[o.OrderID] = o
+		}
+	}
+
+	tag := ""
+	if r.StrategyID != "" || r.InstanceID != "" {
+		tag = types.ClientOrderIDTag(r.StrategyID, r.InstanceID)
+	}
+
+	var recoveredOrders types.OrderSlice
+	openOrderIDs := make(map[uint64]struct{}, len(openOrders))
+	for _, o := range openOrders {
+		openOrderIDs[o.OrderID] = struct{}{}
+
+		_, matched := snapshotOrders[o.OrderID]
+		if !matched && tag != "" {
+			if info, ok := types.ParseClientOrderID(o.ClientOrderID); ok && info.Tag == tag {
+				matched = true
+			}
+		}
+
+		if !matched {
+			continue
+		}
+
+		if market, ok := r.Session.Market(o.Symbol); ok {
+			o.Market = market
+		}
+
+		recoveredOrders = append(recoveredOrders, o)
+	}
+
+	if r.OrderStore != nil {
+		r.OrderStore.Add(recoveredOrders...)
+	}
+
+	r.ActiveOrderBook.Add(recoveredOrders...)
+	log.Infof("[%s] recovered %d %s open orders", r.Session.Name, len(recoveredOrders), r.Symbol)
+
+	// the orders in the snapshot that are not open were filled or canceled when the strategy is not running
+	queryService, queryable := ex.(types.ExchangeOrderQueryService)
+	for orderID, o := range snapshotOrders {
+		if _, ok := openOrderIDs[orderID]; ok {
+			continue
+		}
+
+		// the trades of the closed orders should be replayed
+		if r.OrderStore != nil {
+			r.OrderStore.Add(o)
+		}
+
+		if !queryable {
+			continue
+		}
+
+		closedOrder, err := queryService.QueryOrder(ctx, types.OrderQuery{
+			Symbol:        o.Symbol,
+			OrderID:       strconv.FormatUint(orderID, 10),
+			ClientOrderID: o.ClientOrderID,
+		})
+		if err != nil {
+			log.WithError(err).Warnf("[%s] can not query the closed order %d", r.Session.Name, orderID)
+			continue
+		}
+
+		if r.OrderStore != nil {
+			r.OrderStore.Update(*closedOrder)
+		}
+
+		if closedOrder.Status == types.OrderStatusFilled {
+			log.Infof("[%s] order %s was filled when the strategy is not running", r.Session.Name, closedOrder.String())
+			r.ActiveOrderBook.EmitFilled(*closedOrder)
+		}
+	}
+
+	if r.TradeCollector != nil && !since.IsZero() {
+		if historyService, ok := ex.(types.ExchangeTradeHistoryService); ok {
+			if err := r.TradeCollector.Recover(ctx, historyService, r.Symbol, since); err != nil {
+				return recoveredOrders, errors.Wrapf(err, "can not replay %s trades since %s", r.Symbol, since)
+			}
+		}
+	}
+
+	return recoveredOrders, nil
+}
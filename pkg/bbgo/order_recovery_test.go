@@ -0,0 +1,126 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type recoveryTestExchange struct {
+	types.Exchange
+
+	openOrders   []types.Order
+	closedOrders map[uint64]types.Order
+	trades       []types.Trade
+}
+
+func (e *recoveryTestExchange) QueryOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return e.openOrders, nil
+}
+
+func (e *recoveryTestExchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	orderID, err := strconv.ParseUint(q.OrderID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if o, ok := e.closedOrders[orderID]; ok {
+		return &o, nil
+	}
+
+	return nil, errors.New("order not found")
+}
+
+func (e *recoveryTestExchange) QueryTrades(ctx context.Context, symbol string, options *types.TradeQueryOptions) ([]types.Trade, error) {
+	return e.trades, nil
+}
+
+func (e *recoveryTestExchange) QueryClosedOrders(ctx context.Context, symbol string, since, until time.Time, lastOrderID uint64) ([]types.Order, error) {
+	return nil, nil
+}
+
+func TestActiveOrderRecovery_Recover(t *testing.T) {
+	newOrder := func(orderID uint64, clientOrderID string, side types.SideType, price int64, status types.OrderStatus) types.Order {
+		return types.Order{
+			SubmitOrder: types.SubmitOrder{
+				ClientOrderID: clientOrderID,
+				Symbol:        "BTCUSDT",
+				Side:          side,
+				Type:          types.OrderTypeLimit,
+				Quantity:      fixedpoint.One,
+				Price:         fixedpoint.NewFromInt(price),
+			},
+			OrderID: orderID,
+			Status:  status,
+		}
+	}
+
+	generator := types.NewClientOrderIDGenerator("grid", "grid-BTCUSDT")
+
+	ex := &recoveryTestExchange{
+		openOrders: []types.Order{
+			// in the snapshot
+			newOrder(1, "", types.SideTypeSell, 1100, types.OrderStatusNew),
+			// submitted by the strategy instance after the snapshot is saved
+			newOrder(4, generator.Next(), types.SideTypeBuy, 900, types.OrderStatusNew),
+			// submitted manually
+			newOrder(5, "", types.SideTypeBuy, 800, types.OrderStatusNew),
+		},
+		closedOrders: map[uint64]types.Order{
+			2: newOrder(2, "", types.SideTypeBuy, 1000, types.OrderStatusFilled),
+			3: newOrder(3, "", types.SideTypeBuy, 950, types.OrderStatusCanceled),
+		},
+		trades: []types.Trade{
+			{ID: 1, OrderID: 2, Symbol: "BTCUSDT", Side: types.SideTypeBuy, Price: fixedpoint.NewFromInt(1000), Quantity: fixedpoint.One, QuoteQuantity: fixedpoint.NewFromInt(1000)},
+			{ID: 2, OrderID: 5, Symbol: "BTCUSDT", Side: types.SideTypeBuy, Price: fixedpoint.NewFromInt(800), Quantity: fixedpoint.One, QuoteQuantity: fixedpoint.NewFromInt(800)},
+		},
+	}
+
+	session := &ExchangeSession{Name: "binance", Exchange: ex}
+	orderStore := NewOrderStore("BTCUSDT")
+	position := types.NewPosition("BTCUSDT", "BTC", "USDT")
+	activeBook := NewLocalActiveOrderBook("BTCUSDT")
+
+	var filledOrders []types.Order
+	activeBook.OnFilled(func(o types.Order) {
+		filledOrders = append(filledOrders, o)
+	})
+
+	recovery := &ActiveOrderRecovery{
+		Session:         session,
+		Symbol:          "BTCUSDT",
+		ActiveOrderBook: activeBook,
+		OrderStore:      orderStore,
+		TradeCollector:  NewTradeCollector("BTCUSDT", position, orderStore),
+		StrategyID:      "grid",
+		InstanceID:      "grid-BTCUSDT",
+		Snapshot: &ActiveOrderSnapshot{
+			Orders: []types.Order{
+				newOrder(1, "", types.SideTypeSell, 1100, types.OrderStatusNew),
+				newOrder(2, "", types.SideTypeBuy, 1000, types.OrderStatusNew),
+				newOrder(3, "", types.SideTypeBuy, 950, types.OrderStatusNew),
+			},
+			Time: time.Now().Add(-time.Hour),
+		},
+	}
+
+	recoveredOrders, err := recovery.Recover(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, recoveredOrders, 2)
+	assert.Equal(t, 2, activeBook.NumOfOrders())
+
+	if assert.Len(t, filledOrders, 1) {
+		assert.Equal(t, uint64(2), filledOrders[0].OrderID)
+	}
+
+	// only the fill of order 2 belongs to the strategy
+	assert.Equal(t, "1", position.Base.String())
+	assert.Equal(t, "1000", position.AverageCost.String())
+}
@@ -66,6 +66,23 @@ func (s *OrderStore) Orders() (orders []types.Order) {
 	return orders
 }
 
+// InstanceOrders returns the orders submitted by the strategy instance,
+// the orders are matched by the strategy instance tag encoded in the client order ID.
+func (s *OrderStore) InstanceOrders(strategyID, instanceID string) (orders []types.Order) {
+	tag := types.ClientOrderIDTag(strategyID, instanceID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, o := range s.orders {
+		if info, ok := types.ParseClientOrderID(o.ClientOrderID); ok && info.Tag == tag {
+			orders = append(orders, o)
+		}
+	}
+
+	return orders
+}
+
 func (s *OrderStore) Exists(oID uint64) (ok bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
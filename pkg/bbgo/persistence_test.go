@@ -5,9 +5,12 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator"
 	"github.com/c9s/bbgo/pkg/service"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -94,3 +97,88 @@ func Test_storePersistenceFields(t *testing.T) {
 	}
 
 }
+
+type TestIndicatorStruct struct {
+	EWMA *indicator.EWMA `persistence:"ewma"`
+}
+
+func (t *TestIndicatorStruct) InstanceID() string {
+	return "test-indicator-struct"
+}
+
+func Test_indicatorPersistence(t *testing.T) {
+	ps := &service.JsonPersistenceService{Directory: t.TempDir()}
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 5}
+
+	var kLines []types.KLine
+	for i := 0; i < 30; i++ {
+		c := 100 + float64(i%7)
+		kLines = append(kLines, newTestKLine(i, c, c+1, c-1, c))
+	}
+
+	// the indicator is saved after the kline 19 is closed
+	store := NewMarketDataStore("BTCUSDT")
+	a := &TestIndicatorStruct{EWMA: &indicator.EWMA{IntervalWindow: iw}}
+	store.BindIndicator(iw.Interval, a.EWMA)
+	for _, k := range kLines[:20] {
+		store.AddKLine(k)
+	}
+	assert.NoError(t, storePersistenceFields(a, callID(a), ps))
+
+	// after the restart, the klines 15 ~ 24 are loaded, and the indicator is continued from the kline 20
+	b := &TestIndicatorStruct{}
+	assert.NoError(t, loadPersistenceFields(b, callID(b), ps))
+	if !assert.NotNil(t, b.EWMA) {
+		return
+	}
+
+	store = NewMarketDataStore("BTCUSDT")
+	for _, k := range kLines[15:25] {
+		store.AddKLine(k)
+	}
+	store.BindIndicator(iw.Interval, b.EWMA)
+	for _, k := range kLines[25:] {
+		store.AddKLine(k)
+	}
+
+	expected := &indicator.EWMA{IntervalWindow: iw}
+	for _, k := range kLines {
+		expected.Update(k.Close.Float64())
+	}
+
+	assert.Equal(t, expected.Length(), b.EWMA.Length())
+	assert.InDelta(t, expected.Last(), b.EWMA.Last(), 1e-9)
+	assert.InDelta(t, expected.Index(12), b.EWMA.Index(12), 1e-9)
+}
+
+func Test_indicatorPersistence_gap(t *testing.T) {
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 5}
+
+	var kLines []types.KLine
+	for i := 0; i < 30; i++ {
+		c := 100 + float64(i%7)
+		kLines = append(kLines, newTestKLine(i, c, c+1, c-1, c))
+	}
+
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+
+	// the snapshot ends at the kline 19, and the loaded klines continue from the kline 20
+	store := NewMarketDataStore("BTCUSDT")
+	for _, k := range kLines[15:25] {
+		store.AddKLine(k)
+	}
+	store.BindIndicator(iw.Interval, &indicator.SMA{IntervalWindow: iw, EndTime: kLines[19].EndTime.Time()})
+	assert.Empty(t, hook.AllEntries())
+
+	// the klines 20 ~ 24 are missing, EWMA keeps the open time of the last kline
+	store = NewMarketDataStore("BTCUSDT")
+	for _, k := range kLines[25:] {
+		store.AddKLine(k)
+	}
+	store.BindIndicator(iw.Interval, &indicator.EWMA{IntervalWindow: iw, Values: types.Float64Slice{100}, LastOpenTime: kLines[19].StartTime.Time()})
+	if entry := hook.LastEntry(); assert.NotNil(t, entry) {
+		assert.Equal(t, logrus.ErrorLevel, entry.Level)
+		assert.Contains(t, entry.Message, "the klines in between are skipped")
+	}
+}
@@ -0,0 +1,183 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultLivePriceWindow = 10 * time.Minute
+
+const tickerPriceCacheTTL = time.Minute
+
+type cachedPrice struct {
+	price     fixedpoint.Value
+	updatedAt time.Time
+}
+
+// SessionPriceLookup looks up the prices for converting the fees into the quote currency.
+// The recent prices come from the last prices of the session, and the ticker is queried if the market is not subscribed.
+// The prices of the trades older than LiveWindow come from the historical price lookup (the stored klines).
+type SessionPriceLookup struct {
+	Session *ExchangeSession
+
+	// Historical is used for the trades older than LiveWindow, optional
+	Historical types.PriceLookup
+
+	// LiveWindow is the max age of the trade that uses the live prices, defaults to 10m
+	LiveWindow time.Duration
+
+	mu           sync.Mutex
+	tickerPrices map[string]cachedPrice
+
+	// refreshing is the symbols of the ticker prices being queried in the background by the live lookup
+	refreshing map[string]struct{}
+}
+
+func (l *SessionPriceLookup) QueryPrice(currency, quoteCurrency string, t time.Time) (fixedpoint.Value, error) {
+	if currency == quoteCurrency {
+		return fixedpoint.One, nil
+	}
+
+	window := l.LiveWindow
+	if window == 0 {
+		window = defaultLivePriceWindow
+	}
+
+	if time.Since(t) > window {
+		if l.Historical == nil {
+			return fixedpoint.Zero, fmt.Errorf("historical price lookup is not configured for %s%s at %s", currency, quoteCurrency, t)
+		}
+
+		return l.Historical.QueryPrice(currency, quoteCurrency, t)
+	}
+
+	if price, ok := l.Session.LastPrice(currency + quoteCurrency); ok && price.Sign() > 0 {
+		return price, nil
+	}
+
+	if price, ok := l.Session.LastPrice(quoteCurrency + currency); ok && price.Sign() > 0 {
+		return fixedpoint.One.Div(price), nil
+	}
+
+	if price, ok := l.queryTickerPrice(currency + quoteCurrency); ok {
+		return price, nil
+	}
+
+	if price, ok := l.queryTickerPrice(quoteCurrency + currency); ok {
+		return fixedpoint.One.Div(price), nil
+	}
+
+	if l.Historical != nil {
+		return l.Historical.QueryPrice(currency, quoteCurrency, t)
+	}
+
+	return fixedpoint.Zero, fmt.Errorf("%s price of %s%s or %s%s not found", l.Session.Name, currency, quoteCurrency, quoteCurrency, currency)
+}
+
+// Live returns the price lookup for the trade processing, e.g., the fee conversion of the positions.
+// It doesn't query the exchange or the database, the prices come from the last prices of the session and the cached ticker prices,
+// the last price is used as the approximation of the price at the trade time.
+// If the price is not cached, the ticker is queried in the background for the next trades, and an error is returned,
+// so the position approximates the fee by the fee rates.
+// The historical prices are looked up by the pnl reports with the price lookup itself.
+func (l *SessionPriceLookup) Live() types.PriceLookup {
+	return &livePriceLookup{lookup: l}
+}
+
+type livePriceLookup struct {
+	lookup *SessionPriceLookup
+}
+
+func (l *livePriceLookup) QueryPrice(currency, quoteCurrency string, t time.Time) (fixedpoint.Value, error) {
+	if currency == quoteCurrency {
+		return fixedpoint.One, nil
+	}
+
+	if price, ok := l.lookup.cachedPrice(currency + quoteCurrency); ok {
+		return price, nil
+	}
+
+	if price, ok := l.lookup.cachedPrice(quoteCurrency + currency); ok {
+		return fixedpoint.One.Div(price), nil
+	}
+
+	// the market of the symbol might be inverted, e.g., USDTBNB is not a market but BNBUSDT is
+	l.lookup.refreshTickerPrice(currency + quoteCurrency)
+	l.lookup.refreshTickerPrice(quoteCurrency + currency)
+	return fixedpoint.Zero, fmt.Errorf("%s price of %s%s or %s%s is not cached", l.lookup.Session.Name, currency, quoteCurrency, quoteCurrency, currency)
+}
+
+// cachedPrice returns the last price of the session or the cached ticker price of the symbol
+func (l *SessionPriceLookup) cachedPrice(symbol string) (fixedpoint.Value, bool) {
+	if price, ok := l.Session.LastPrice(symbol); ok && price.Sign() > 0 {
+		return price, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if cached, ok := l.tickerPrices[symbol]; ok && time.Since(cached.updatedAt) < tickerPriceCacheTTL {
+		return cached.price, true
+	}
+
+	return fixedpoint.Zero, false
+}
+
+// refreshTickerPrice queries the ticker price of the symbol in the background if the market exists
+func (l *SessionPriceLookup) refreshTickerPrice(symbol string) {
+	if _, ok := l.Session.Market(symbol); !ok {
+		return
+	}
+
+	l.mu.Lock()
+	if _, ok := l.refreshing[symbol]; ok {
+		l.mu.Unlock()
+		return
+	}
+
+	if l.refreshing == nil {
+		l.refreshing = make(map[string]struct{})
+	}
+
+	l.refreshing[symbol] = struct{}{}
+	l.mu.Unlock()
+
+	go func() {
+		l.queryTickerPrice(symbol)
+
+		l.mu.Lock()
+		delete(l.refreshing, symbol)
+		l.mu.Unlock()
+	}()
+}
+
+// queryTickerPrice queries the last price of the symbol from the ticker, the price is cached for a minute
+func (l *SessionPriceLookup) queryTickerPrice(symbol string) (fixedpoint.Value, bool) {
+	if _, ok := l.Session.Market(symbol); !ok {
+		return fixedpoint.Zero, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if cached, ok := l.tickerPrices[symbol]; ok && time.Since(cached.updatedAt) < tickerPriceCacheTTL {
+		return cached.price, true
+	}
+
+	ticker, err := l.Session.Exchange.QueryTicker(context.Background(), symbol)
+	if err != nil || ticker == nil || ticker.Last.Sign() <= 0 {
+		return fixedpoint.Zero, false
+	}
+
+	if l.tickerPrices == nil {
+		l.tickerPrices = make(map[string]cachedPrice)
+	}
+
+	l.tickerPrices[symbol] = cachedPrice{price: ticker.Last, updatedAt: time.Now()}
+	return ticker.Last, true
+}
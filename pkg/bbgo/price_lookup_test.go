@@ -0,0 +1,65 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type priceLookupTestExchange struct {
+	types.Exchange
+
+	tickerC chan *types.Ticker
+}
+
+func (e *priceLookupTestExchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
+	return <-e.tickerC, nil
+}
+
+type historicalPriceLookup struct {
+	queries int
+}
+
+func (l *historicalPriceLookup) QueryPrice(currency, quoteCurrency string, t time.Time) (fixedpoint.Value, error) {
+	l.queries++
+	return fixedpoint.NewFromInt(250), nil
+}
+
+func TestSessionPriceLookup_Live(t *testing.T) {
+	ex := &priceLookupTestExchange{tickerC: make(chan *types.Ticker)}
+	session := &ExchangeSession{
+		Name:       "binance",
+		Exchange:   ex,
+		markets:    map[string]types.Market{"BNBUSDT": {Symbol: "BNBUSDT", BaseCurrency: "BNB", QuoteCurrency: "USDT"}},
+		lastPrices: map[string]fixedpoint.Value{"MAXUSDT": fixedpoint.NewFromFloat(0.5)},
+	}
+
+	historical := &historicalPriceLookup{}
+	lookup := &SessionPriceLookup{Session: session, Historical: historical}
+	live := lookup.Live()
+
+	// the last price of the session is used for the old trades too, the historical price is not looked up
+	price, err := live.QueryPrice("MAX", "USDT", time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.5", price.String())
+
+	price, err = live.QueryPrice("USDT", "MAX", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "2", price.String())
+
+	// the ticker is queried in the background, the lookup doesn't wait for it
+	_, err = live.QueryPrice("BNB", "USDT", time.Now())
+	assert.Error(t, err)
+	assert.Equal(t, 0, historical.queries)
+
+	ex.tickerC <- &types.Ticker{Last: fixedpoint.NewFromInt(300)}
+	assert.Eventually(t, func() bool {
+		price, err := live.QueryPrice("BNB", "USDT", time.Now())
+		return err == nil && price.String() == "300"
+	}, time.Second, 10*time.Millisecond)
+}
@@ -4,6 +4,9 @@ import (
 	"regexp"
 
 	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/accounting/pnl"
 )
 
 type PnLReporter interface {
@@ -46,6 +49,14 @@ type AverageCostPnLReporter struct {
 
 	Sessions []string
 	Symbols  []string
+
+	// CostBasisMethod is the cost basis method, the lot-based calculator is used if it's not the average cost method
+	CostBasisMethod pnl.CostBasisMethod
+}
+
+func (reporter *AverageCostPnLReporter) Method(method pnl.CostBasisMethod) *AverageCostPnLReporter {
+	reporter.CostBasisMethod = method
+	return reporter
 }
 
 func (reporter *AverageCostPnLReporter) Of(sessions ...string) *AverageCostPnLReporter {
@@ -61,24 +72,54 @@ func (reporter *AverageCostPnLReporter) When(specs ...string) *AverageCostPnLRep
 		}
 	}
 
+	reporter.cron.Start()
 	return reporter
 }
 
+// Run calculates the pnl of the symbols from the trades collected by the sessions and sends the reports to the notifier,
+// all the sessions are reported if the sessions are not specified.
 func (reporter *AverageCostPnLReporter) Run() {
-	// FIXME: this is causing cyclic import
-	/*
-		for _, sessionName := range reporter.Sessions {
-			session := reporter.environment.sessions[sessionName]
-			calculator := &pnl.AverageCostCalculator{
-				TradingFeeCurrency: session.Exchange.PlatformFeeCurrency(),
+	if reporter.environment == nil {
+		return
+	}
+
+	sessionNames := reporter.Sessions
+	if len(sessionNames) == 0 {
+		for name := range reporter.environment.Sessions() {
+			sessionNames = append(sessionNames, name)
+		}
+	}
+
+	for _, sessionName := range sessionNames {
+		session, ok := reporter.environment.Session(sessionName)
+		if !ok {
+			log.Warnf("pnl reporter: session %s is not found", sessionName)
+			continue
+		}
+
+		for _, symbol := range reporter.Symbols {
+			tradeSlice, ok := session.Trades[symbol]
+			if !ok {
+				log.Warnf("pnl reporter: no trades of %s are collected by session %s", symbol, sessionName)
+				continue
 			}
 
-			for _, symbol := range reporter.Symbols {
-				report := calculator.Calculate(symbol, session.Trades[symbol].Copy(), session.lastPrices[symbol])
-				report.Print()
+			trades := tradeSlice.Copy()
+			lastPrice, _ := session.LastPrice(symbol)
+			market, _ := session.Market(symbol)
+			feeCurrency := session.Exchange.PlatformFeeCurrency()
+
+			switch reporter.CostBasisMethod {
+			case "", pnl.CostBasisAverage:
+				calculator := &pnl.AverageCostCalculator{TradingFeeCurrency: feeCurrency, Market: market}
+				reporter.notifier.Notify(calculator.Calculate(symbol, trades, lastPrice))
+
+			default:
+				calculator := &pnl.LotCostCalculator{TradingFeeCurrency: feeCurrency, Market: market, Method: reporter.CostBasisMethod}
+				reporter.notifier.Notify(calculator.Calculate(symbol, trades, lastPrice))
 			}
 		}
-	*/
+	}
 }
 
 type PatternChannelRouter struct {
@@ -0,0 +1,104 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/accounting/pnl"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type reporterTestNotifier struct {
+	objects []interface{}
+}
+
+func (n *reporterTestNotifier) NotifyTo(channel string, obj interface{}, args ...interface{}) {
+	n.objects = append(n.objects, obj)
+}
+
+func (n *reporterTestNotifier) Notify(obj interface{}, args ...interface{}) {
+	n.objects = append(n.objects, obj)
+}
+
+type reporterTestExchange struct {
+	types.Exchange
+}
+
+func (e *reporterTestExchange) PlatformFeeCurrency() string { return "BNB" }
+
+func TestAverageCostPnLReporter_Run(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	trade := func(id uint64, side types.SideType, price, quantity int64, after time.Duration) types.Trade {
+		return types.Trade{
+			ID:            id,
+			Symbol:        "BTCUSDT",
+			Side:          side,
+			IsBuyer:       side == types.SideTypeBuy,
+			Price:         fixedpoint.NewFromInt(price),
+			Quantity:      fixedpoint.NewFromInt(quantity),
+			QuoteQuantity: fixedpoint.NewFromInt(price * quantity),
+			FeeCurrency:   "USDT",
+			Time:          types.Time(t0.Add(after)),
+		}
+	}
+
+	environ := NewEnvironment()
+	environ.AddExchangeSession("binance", &ExchangeSession{
+		Name:       "binance",
+		Exchange:   &reporterTestExchange{},
+		markets:    map[string]types.Market{"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}},
+		lastPrices: map[string]fixedpoint.Value{"BTCUSDT": fixedpoint.NewFromInt(130)},
+		Trades: map[string]*types.TradeSlice{
+			"BTCUSDT": {Trades: []types.Trade{
+				trade(1, types.SideTypeBuy, 100, 1, 0),
+				trade(2, types.SideTypeBuy, 120, 1, time.Hour),
+				trade(3, types.SideTypeSell, 110, 1, 2*time.Hour),
+			}},
+		},
+	})
+
+	notifier := &reporterTestNotifier{}
+	manager := NewPnLReporter(notifier)
+	manager.environment = environ
+
+	manager.AverageCostBySymbols("BTCUSDT").Method(pnl.CostBasisFIFO).Run()
+	manager.AverageCostBySymbols("BTCUSDT").Of("binance").Run()
+
+	if !assert.Len(t, notifier.objects, 2) {
+		return
+	}
+
+	// the first lot is closed by fifo
+	if report, ok := notifier.objects[0].(*pnl.LotCostPnlReport); assert.True(t, ok) {
+		assert.Equal(t, pnl.CostBasisFIFO, report.Method)
+		assert.Equal(t, "10", report.Profit.String())
+		if assert.Len(t, report.Realized, 1) {
+			assert.Equal(t, uint64(1), report.Realized[0].OpenTradeID)
+		}
+	}
+
+	if report, ok := notifier.objects[1].(*pnl.AverageCostPnlReport); assert.True(t, ok) {
+		assert.Equal(t, 3, report.NumTrades)
+	}
+}
+
+func TestTrader_Configure_PnLReporterMethod(t *testing.T) {
+	trader := NewTrader(NewEnvironment())
+	err := trader.Configure(&Config{
+		PnLReporters: []PnLReporterConfig{
+			{AverageCostBySymbols: []string{"BTCUSDT"}, When: []string{"@daily"}, Method: "newest"},
+		},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported cost basis method")
+
+	err = trader.Configure(&Config{
+		PnLReporters: []PnLReporterConfig{
+			{AverageCostBySymbols: []string{"BTCUSDT"}, When: []string{"@daily"}, Method: "lifo"},
+		},
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,114 @@
+package bbgo
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/cache"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DefaultReportingCurrency is used when the reportingCurrency is not configured
+const DefaultReportingCurrency = "USD"
+
+// SetReportingCurrency sets the currency that the balances, the net asset values and the profits are reported in
+func (environ *Environment) SetReportingCurrency(currency string) {
+	environ.reportingCurrency = strings.ToUpper(currency)
+}
+
+func (environ *Environment) ReportingCurrency() string {
+	if len(environ.reportingCurrency) == 0 {
+		return DefaultReportingCurrency
+	}
+
+	return environ.reportingCurrency
+}
+
+func (environ *Environment) sortedSessions() []*ExchangeSession {
+	var names []string
+	for name := range environ.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sessions []*ExchangeSession
+	for _, name := range names {
+		sessions = append(sessions, environ.sessions[name])
+	}
+	return sessions
+}
+
+// ConversionGraph builds the currency conversion graph from the markets and the last prices of all sessions
+func (environ *Environment) ConversionGraph() *types.ConversionGraph {
+	graph := types.NewConversionGraph()
+	for _, session := range environ.sortedSessions() {
+		graph.AddMarkets(session.Markets())
+		graph.SetPrices(session.LastPrices())
+	}
+
+	graph.AddUSDPegs()
+	return graph
+}
+
+// UpdateReportingPrices updates the last prices of the markets that are required to convert the given currencies into
+// the reporting currency, USD and BTC. The markets are routed through all sessions,
+// and the ticker of each market is queried from the first session (ordered by the session name) that has it.
+func (environ *Environment) UpdateReportingPrices(ctx context.Context, currencies []string) error {
+	sessions := environ.sortedSessions()
+
+	for _, session := range sessions {
+		if len(session.markets) > 0 {
+			continue
+		}
+
+		markets, err := cache.LoadExchangeMarketsWithCache(ctx, session.Exchange)
+		if err != nil {
+			return err
+		}
+
+		session.markets = markets
+	}
+
+	graph := environ.ConversionGraph()
+
+	symbolSet := map[string]struct{}{}
+	for _, currency := range currencies {
+		for _, target := range []string{environ.ReportingCurrency(), "USD", "BTC"} {
+			symbols, ok := graph.Symbols(currency, target)
+			if !ok {
+				continue
+			}
+
+			for _, symbol := range symbols {
+				symbolSet[symbol] = struct{}{}
+			}
+		}
+	}
+
+	for _, session := range sessions {
+		var symbols []string
+		for symbol := range symbolSet {
+			if _, ok := session.Market(symbol); ok {
+				symbols = append(symbols, symbol)
+				delete(symbolSet, symbol)
+			}
+		}
+
+		if len(symbols) == 0 {
+			continue
+		}
+
+		sort.Strings(symbols)
+		if err := session.UpdateLastPrices(ctx, symbols...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ConvertAssets converts the net assets into the reporting currency with the current last prices
+func (environ *Environment) ConvertAssets(assets types.AssetMap) types.AssetMap {
+	return assets.Convert(environ.ConversionGraph(), environ.ReportingCurrency())
+}
@@ -71,4 +71,10 @@ func groupSubmitOrdersBySymbol(orders []types.SubmitOrder) map[string][]types.Su
 
 type RiskControls struct {
 	SessionBasedRiskControl map[string]*SessionBasedRiskControl `json:"sessionBased,omitempty" yaml:"sessionBased,omitempty"`
+
+	// PreTrade is applied to the orders of all sessions before they are sent to the exchange
+	PreTrade *PreTradeRiskControl `json:"preTrade,omitempty" yaml:"preTrade,omitempty"`
+
+	// CircuitBreaker stops all strategies when the daily loss or the drawdown limit is reached
+	CircuitBreaker *CircuitBreaker `json:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty"`
 }
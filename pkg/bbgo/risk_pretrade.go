@@ -0,0 +1,316 @@
+package bbgo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var ErrPreTradeRiskRejected = errors.New("order rejected by pre-trade risk control")
+
+const (
+	RiskRuleMaxOrderNotional   = "maxOrderNotional"
+	RiskRuleMaxOrderQuantity   = "maxOrderQuantity"
+	RiskRuleMaxOpenOrders      = "maxOpenOrders"
+	RiskRuleMaxNetPosition     = "maxNetPosition"
+	RiskRulePriceBand          = "priceBand"
+	RiskRuleMaxOrdersPerMinute = "maxOrdersPerMinute"
+)
+
+// PreTradeRiskLimits defines the limits that are checked before an order is sent to the exchange.
+// A zero value disables the check.
+type PreTradeRiskLimits struct {
+	// MaxOrderNotional is the max quote amount (price * quantity) of a single order
+	MaxOrderNotional fixedpoint.Value `json:"maxOrderNotional,omitempty" yaml:"maxOrderNotional,omitempty"`
+
+	// MaxOrderQuantity is the fat-finger check of the order quantity
+	MaxOrderQuantity fixedpoint.Value `json:"maxOrderQuantity,omitempty" yaml:"maxOrderQuantity,omitempty"`
+
+	// MaxOpenOrders is the max number of the open orders of a symbol
+	MaxOpenOrders int `json:"maxOpenOrders,omitempty" yaml:"maxOpenOrders,omitempty"`
+
+	// MaxNetPosition is the max absolute base position of a symbol after the order is filled
+	MaxNetPosition fixedpoint.Value `json:"maxNetPosition,omitempty" yaml:"maxNetPosition,omitempty"`
+
+	// PriceBand is the max deviation ratio of the order price from the mid price (or the last trade price), e.g. 5%
+	PriceBand fixedpoint.Value `json:"priceBand,omitempty" yaml:"priceBand,omitempty"`
+
+	// MaxOrdersPerMinute is the max number of orders of a symbol submitted in the last minute
+	MaxOrdersPerMinute int `json:"maxOrdersPerMinute,omitempty" yaml:"maxOrdersPerMinute,omitempty"`
+}
+
+// riskCheckContext is the market and account state used for checking an order
+type riskCheckContext struct {
+	referencePrice fixedpoint.Value
+	netPosition    fixedpoint.Value
+	numOpenOrders  int
+	numOrders      int
+}
+
+// RiskRejection is the error of the rejected order, it contains the rule that rejects the order
+type RiskRejection struct {
+	Rule    string
+	Order   types.SubmitOrder
+	Message string
+}
+
+func (r *RiskRejection) Error() string {
+	return fmt.Sprintf("%s: %s, order: %s", r.Rule, r.Message, r.Order.String())
+}
+
+func (r *RiskRejection) Unwrap() error {
+	return ErrPreTradeRiskRejected
+}
+
+func (l *PreTradeRiskLimits) check(order types.SubmitOrder, c riskCheckContext) error {
+	reject := func(rule, format string, args ...interface{}) error {
+		return &RiskRejection{Rule: rule, Order: order, Message: fmt.Sprintf(format, args...)}
+	}
+
+	price := order.Price
+	if order.Type == types.OrderTypeMarket || price.IsZero() {
+		price = c.referencePrice
+	}
+
+	if l.MaxOrderQuantity.Sign() > 0 && order.Quantity.Compare(l.MaxOrderQuantity) > 0 {
+		return reject(RiskRuleMaxOrderQuantity, "quantity %s exceeds %s", order.Quantity.String(), l.MaxOrderQuantity.String())
+	}
+
+	if l.MaxOrderNotional.Sign() > 0 && price.Sign() > 0 {
+		notional := order.Quantity.Mul(price)
+		if notional.Compare(l.MaxOrderNotional) > 0 {
+			return reject(RiskRuleMaxOrderNotional, "notional %s exceeds %s", notional.String(), l.MaxOrderNotional.String())
+		}
+	}
+
+	if l.MaxOpenOrders > 0 && order.Type != types.OrderTypeMarket && c.numOpenOrders >= l.MaxOpenOrders {
+		return reject(RiskRuleMaxOpenOrders, "%d open orders reached the limit %d", c.numOpenOrders, l.MaxOpenOrders)
+	}
+
+	if l.MaxOrdersPerMinute > 0 && c.numOrders >= l.MaxOrdersPerMinute {
+		return reject(RiskRuleMaxOrdersPerMinute, "%d orders in the last minute reached the limit %d", c.numOrders, l.MaxOrdersPerMinute)
+	}
+
+	if l.MaxNetPosition.Sign() > 0 && !order.ReduceOnly && !order.ClosePosition {
+		position := c.netPosition
+		switch order.Side {
+		case types.SideTypeBuy:
+			position = position.Add(order.Quantity)
+		case types.SideTypeSell:
+			position = position.Sub(order.Quantity)
+		}
+
+		// only reject the order that increases the exposure
+		if position.Abs().Compare(l.MaxNetPosition) > 0 && position.Abs().Compare(c.netPosition.Abs()) > 0 {
+			return reject(RiskRuleMaxNetPosition, "net position %s would exceed %s", position.String(), l.MaxNetPosition.String())
+		}
+	}
+
+	if l.PriceBand.Sign() > 0 && order.Type != types.OrderTypeMarket && c.referencePrice.Sign() > 0 && order.Price.Sign() > 0 {
+		deviation := order.Price.Sub(c.referencePrice).Abs().Div(c.referencePrice)
+		if deviation.Compare(l.PriceBand) > 0 {
+			return reject(RiskRulePriceBand, "price %s deviates %s from the reference price %s, band %s",
+				order.Price.String(), deviation.FormatPercentage(2), c.referencePrice.String(), l.PriceBand.FormatPercentage(2))
+		}
+	}
+
+	return nil
+}
+
+// PreTradeRiskControl checks every order submitted through the session order executor,
+// including the position close orders of the strategies, the smart stop orders, the orders of the grpc trading service
+// and the new orders of the amended orders.
+// The orders of the submit-order command are sent to the exchange directly since the command doesn't load the risk controls.
+// The default limits are applied to all symbols, the limits defined in BySymbol override them field by field,
+// so a symbol only needs to define the limits that differ from the default limits.
+type PreTradeRiskControl struct {
+	PreTradeRiskLimits `yaml:",inline"`
+
+	BySymbol map[string]*PreTradeRiskLimits `json:"bySymbol,omitempty" yaml:"bySymbol,omitempty"`
+
+	// Notifiability is used for sending the rejection notification, it's injected by the trader
+	Notifiability *Notifiability `json:"-" yaml:"-"`
+
+	mu sync.Mutex
+
+	// orderTimes stores the submission time of the recent orders, key: session name + symbol
+	orderTimes map[string][]time.Time
+}
+
+// merge returns the limits with the zero fields filled by the fields of the default limits
+func (l PreTradeRiskLimits) merge(defaults PreTradeRiskLimits) PreTradeRiskLimits {
+	if l.MaxOrderNotional.IsZero() {
+		l.MaxOrderNotional = defaults.MaxOrderNotional
+	}
+
+	if l.MaxOrderQuantity.IsZero() {
+		l.MaxOrderQuantity = defaults.MaxOrderQuantity
+	}
+
+	if l.MaxOpenOrders == 0 {
+		l.MaxOpenOrders = defaults.MaxOpenOrders
+	}
+
+	if l.MaxNetPosition.IsZero() {
+		l.MaxNetPosition = defaults.MaxNetPosition
+	}
+
+	if l.PriceBand.IsZero() {
+		l.PriceBand = defaults.PriceBand
+	}
+
+	if l.MaxOrdersPerMinute == 0 {
+		l.MaxOrdersPerMinute = defaults.MaxOrdersPerMinute
+	}
+
+	return l
+}
+
+// limits returns the limits of the symbol, the fields that are not set in BySymbol are inherited from the default limits
+func (c *PreTradeRiskControl) limits(symbol string) *PreTradeRiskLimits {
+	if l, ok := c.BySymbol[symbol]; ok && l != nil {
+		merged := l.merge(c.PreTradeRiskLimits)
+		return &merged
+	}
+
+	return &c.PreTradeRiskLimits
+}
+
+// recentOrders returns the number of the orders submitted in the last minute and drops the expired records.
+func (c *PreTradeRiskControl) recentOrders(key string, now time.Time) int {
+	since := now.Add(-time.Minute)
+	times := c.orderTimes[key]
+
+	i := 0
+	for ; i < len(times); i++ {
+		if times[i].After(since) {
+			break
+		}
+	}
+
+	times = times[i:]
+	c.orderTimes[key] = times
+	return len(times)
+}
+
+func (c *PreTradeRiskControl) referencePrice(session *ExchangeSession, symbol string) fixedpoint.Value {
+	if book, ok := session.OrderBook(symbol); ok {
+		if bid, ask, ok := book.BestBidAndAsk(); ok {
+			return bid.Price.Add(ask.Price).Div(fixedpoint.NewFromInt(2))
+		}
+	}
+
+	if price, ok := session.LastPrice(symbol); ok {
+		return price
+	}
+
+	return fixedpoint.Zero
+}
+
+func countOpenOrders(store *OrderStore) int {
+	return len(storeOpenOrders(store))
+}
+
+// ProcessOrders checks the orders with the configured limits,
+// the rejected orders are dropped and reported through the notification system and the metrics.
+func (c *PreTradeRiskControl) ProcessOrders(session *ExchangeSession, orders ...types.SubmitOrder) (outOrders []types.SubmitOrder, errs []error) {
+	return c.processOrders(session, false, orders...)
+}
+
+// ProcessReplaceOrder checks the new order of an amended order with the configured limits,
+// the amended order is replaced by the new order, so it's not counted as an open order.
+func (c *PreTradeRiskControl) ProcessReplaceOrder(session *ExchangeSession, order types.SubmitOrder) error {
+	if _, errs := c.processOrders(session, true, order); len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+func (c *PreTradeRiskControl) processOrders(session *ExchangeSession, replace bool, orders ...types.SubmitOrder) (outOrders []types.SubmitOrder, errs []error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.orderTimes == nil {
+		c.orderTimes = make(map[string][]time.Time)
+	}
+
+	now := time.Now()
+	openOrders := make(map[string]int)
+	netPositions := make(map[string]fixedpoint.Value)
+
+	for _, order := range orders {
+		symbol := order.Symbol
+		key := session.Name + ":" + symbol
+
+		if _, ok := openOrders[symbol]; !ok {
+			if store, ok := session.OrderStore(symbol); ok {
+				openOrders[symbol] = countOpenOrders(store)
+			} else {
+				openOrders[symbol] = 0
+			}
+
+			if replace && openOrders[symbol] > 0 {
+				openOrders[symbol]--
+			}
+
+			if position, ok := session.Positions()[symbol]; ok {
+				netPositions[symbol] = position.GetBase()
+			}
+		}
+
+		err := c.limits(symbol).check(order, riskCheckContext{
+			referencePrice: c.referencePrice(session, symbol),
+			netPosition:    netPositions[symbol],
+			numOpenOrders:  openOrders[symbol],
+			numOrders:      c.recentOrders(key, now),
+		})
+
+		if err != nil {
+			c.reject(session, err)
+			errs = append(errs, err)
+			continue
+		}
+
+		// count the accepted order for the rest orders in the same batch
+		if order.Type != types.OrderTypeMarket {
+			openOrders[symbol]++
+		}
+
+		switch order.Side {
+		case types.SideTypeBuy:
+			netPositions[symbol] = netPositions[symbol].Add(order.Quantity)
+		case types.SideTypeSell:
+			netPositions[symbol] = netPositions[symbol].Sub(order.Quantity)
+		}
+
+		c.orderTimes[key] = append(c.orderTimes[key], now)
+		outOrders = append(outOrders, order)
+	}
+
+	return outOrders, errs
+}
+
+func (c *PreTradeRiskControl) reject(session *ExchangeSession, err error) {
+	log.WithError(err).Warnf("[%s] pre-trade risk control rejected the order", session.Name)
+
+	var rejection *RiskRejection
+	if errors.As(err, &rejection) {
+		metricsRiskRejectedOrders.With(map[string]string{
+			"exchange": session.ExchangeName.String(),
+			"session":  session.Name,
+			"symbol":   rejection.Order.Symbol,
+			"rule":     rejection.Rule,
+		}).Inc()
+	}
+
+	if c.Notifiability != nil {
+		c.Notifiability.Notify(":no_entry: [%s] order rejected by risk control: %s", session.Name, err.Error())
+	}
+}
@@ -0,0 +1,139 @@
+package bbgo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestPreTradeRiskLimits_check(t *testing.T) {
+	limits := PreTradeRiskLimits{
+		MaxOrderNotional:   fixedpoint.NewFromInt(1000),
+		MaxOrderQuantity:   fixedpoint.NewFromFloat(0.5),
+		MaxOpenOrders:      2,
+		MaxNetPosition:     fixedpoint.One,
+		PriceBand:          fixedpoint.NewFromFloat(0.05),
+		MaxOrdersPerMinute: 10,
+	}
+
+	ctx := riskCheckContext{
+		referencePrice: fixedpoint.NewFromInt(1000),
+		netPosition:    fixedpoint.NewFromFloat(0.8),
+	}
+
+	newOrder := func(side types.SideType, quantity, price float64) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     side,
+			Type:     types.OrderTypeLimit,
+			Quantity: fixedpoint.NewFromFloat(quantity),
+			Price:    fixedpoint.NewFromFloat(price),
+		}
+	}
+
+	tests := []struct {
+		name  string
+		order types.SubmitOrder
+		ctx   riskCheckContext
+		rule  string
+	}{
+		{name: "accepted", order: newOrder(types.SideTypeSell, 0.1, 1010), ctx: ctx},
+		{name: "fat finger", order: newOrder(types.SideTypeSell, 0.6, 1000), ctx: ctx, rule: RiskRuleMaxOrderQuantity},
+		{name: "notional", order: newOrder(types.SideTypeSell, 0.5, 2100), ctx: ctx, rule: RiskRuleMaxOrderNotional},
+		{name: "net position", order: newOrder(types.SideTypeBuy, 0.3, 1000), ctx: ctx, rule: RiskRuleMaxNetPosition},
+		{name: "reduce position", order: newOrder(types.SideTypeSell, 0.3, 1000), ctx: ctx},
+		{name: "price band", order: newOrder(types.SideTypeSell, 0.1, 900), ctx: ctx, rule: RiskRulePriceBand},
+		{
+			name:  "open orders",
+			order: newOrder(types.SideTypeSell, 0.1, 1000),
+			ctx:   riskCheckContext{referencePrice: ctx.referencePrice, numOpenOrders: 2},
+			rule:  RiskRuleMaxOpenOrders,
+		},
+		{
+			name:  "order rate",
+			order: newOrder(types.SideTypeSell, 0.1, 1000),
+			ctx:   riskCheckContext{referencePrice: ctx.referencePrice, numOrders: 10},
+			rule:  RiskRuleMaxOrdersPerMinute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.check(tt.order, tt.ctx)
+			if tt.rule == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var rejection *RiskRejection
+			if assert.True(t, errors.As(err, &rejection)) {
+				assert.Equal(t, tt.rule, rejection.Rule)
+			}
+			assert.True(t, errors.Is(err, ErrPreTradeRiskRejected))
+		})
+	}
+}
+
+func TestPreTradeRiskControl_recentOrders(t *testing.T) {
+	control := &PreTradeRiskControl{orderTimes: make(map[string][]time.Time)}
+	now := time.Now()
+	control.orderTimes["max:BTCUSDT"] = []time.Time{
+		now.Add(-2 * time.Minute),
+		now.Add(-30 * time.Second),
+		now.Add(-time.Second),
+	}
+
+	assert.Equal(t, 2, control.recentOrders("max:BTCUSDT", now))
+	assert.Len(t, control.orderTimes["max:BTCUSDT"], 2)
+	assert.Equal(t, 0, control.recentOrders("max:ETHUSDT", now))
+}
+
+func TestPreTradeRiskControl_limits(t *testing.T) {
+	control := &PreTradeRiskControl{
+		PreTradeRiskLimits: PreTradeRiskLimits{
+			MaxOrderNotional:   fixedpoint.NewFromInt(1000),
+			MaxOpenOrders:      20,
+			PriceBand:          fixedpoint.NewFromFloat(0.05),
+			MaxOrdersPerMinute: 60,
+		},
+		BySymbol: map[string]*PreTradeRiskLimits{
+			"BTCUSDT": {
+				MaxOrderNotional: fixedpoint.NewFromInt(5000),
+				MaxOrderQuantity: fixedpoint.NewFromFloat(0.5),
+			},
+		},
+	}
+
+	// the overridden limits
+	limits := control.limits("BTCUSDT")
+	assert.Equal(t, fixedpoint.NewFromInt(5000), limits.MaxOrderNotional)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.5), limits.MaxOrderQuantity)
+
+	// the inherited limits
+	assert.Equal(t, 20, limits.MaxOpenOrders)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.05), limits.PriceBand)
+	assert.Equal(t, 60, limits.MaxOrdersPerMinute)
+	assert.True(t, limits.MaxNetPosition.IsZero())
+
+	// the default limits are not changed by the symbol limits
+	assert.Equal(t, fixedpoint.NewFromInt(1000), control.limits("ETHUSDT").MaxOrderNotional)
+	assert.True(t, control.BySymbol["BTCUSDT"].PriceBand.IsZero())
+
+	err := limits.check(types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Quantity: fixedpoint.NewFromFloat(0.1),
+		Price:    fixedpoint.NewFromInt(20000),
+	}, riskCheckContext{referencePrice: fixedpoint.NewFromInt(22000)})
+
+	var rejection *RiskRejection
+	if assert.True(t, errors.As(err, &rejection)) {
+		assert.Equal(t, RiskRulePriceBand, rejection.Rule)
+	}
+}
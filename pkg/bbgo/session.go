@@ -17,146 +17,11 @@ import (
 
 	"github.com/c9s/bbgo/pkg/cmd/cmdutil"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
-	"github.com/c9s/bbgo/pkg/indicator"
 	"github.com/c9s/bbgo/pkg/service"
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/c9s/bbgo/pkg/util"
 )
 
-var (
-	debugEWMA = false
-	debugSMA  = false
-)
-
-func init() {
-	// when using --dotenv option, the dotenv is loaded from command.PersistentPreRunE, not init.
-	// hence here the env var won't enable the debug flag
-	util.SetEnvVarBool("DEBUG_EWMA", &debugEWMA)
-	util.SetEnvVarBool("DEBUG_SMA", &debugSMA)
-}
-
-type StandardIndicatorSet struct {
-	Symbol string
-	// Standard indicators
-	// interval -> window
-	sma        map[types.IntervalWindow]*indicator.SMA
-	ewma       map[types.IntervalWindow]*indicator.EWMA
-	boll       map[types.IntervalWindowBandWidth]*indicator.BOLL
-	stoch      map[types.IntervalWindow]*indicator.STOCH
-	volatility map[types.IntervalWindow]*indicator.VOLATILITY
-
-	store *MarketDataStore
-}
-
-func NewStandardIndicatorSet(symbol string, store *MarketDataStore) *StandardIndicatorSet {
-	set := &StandardIndicatorSet{
-		Symbol:     symbol,
-		sma:        make(map[types.IntervalWindow]*indicator.SMA),
-		ewma:       make(map[types.IntervalWindow]*indicator.EWMA),
-		boll:       make(map[types.IntervalWindowBandWidth]*indicator.BOLL),
-		stoch:      make(map[types.IntervalWindow]*indicator.STOCH),
-		volatility: make(map[types.IntervalWindow]*indicator.VOLATILITY),
-		store:      store,
-	}
-
-	// let us pre-defined commonly used intervals
-	for interval := range types.SupportedIntervals {
-		for _, window := range []int{7, 25, 99} {
-			iw := types.IntervalWindow{Interval: interval, Window: window}
-			set.sma[iw] = &indicator.SMA{IntervalWindow: iw}
-			set.sma[iw].Bind(store)
-			if debugSMA {
-				set.sma[iw].OnUpdate(func(value float64) {
-					log.Infof("%s SMA %s: %f", symbol, iw.String(), value)
-				})
-			}
-
-			set.ewma[iw] = &indicator.EWMA{IntervalWindow: iw}
-			set.ewma[iw].Bind(store)
-
-			// if debug EWMA is enabled, we add the debug handler
-			if debugEWMA {
-				set.ewma[iw].OnUpdate(func(value float64) {
-					log.Infof("%s EWMA %s: %f", symbol, iw.String(), value)
-				})
-			}
-
-		}
-
-		// setup boll indicator, we may refactor boll indicator by subscribing SMA indicator,
-		// however, since general used BOLLINGER band use window 21, which is not in the existing SMA indicator sets.
-		// Pull out the bandwidth configuration as the boll Key
-		iw := types.IntervalWindow{Interval: interval, Window: 21}
-
-		// set efault band width to 2.0
-		iwb := types.IntervalWindowBandWidth{IntervalWindow: iw, BandWidth: 2.0}
-		set.boll[iwb] = &indicator.BOLL{IntervalWindow: iw, K: iwb.BandWidth}
-		set.boll[iwb].Bind(store)
-	}
-
-	return set
-}
-
-// BOLL returns the bollinger band indicator of the given interval, the window and bandwidth
-func (set *StandardIndicatorSet) BOLL(iw types.IntervalWindow, bandWidth float64) *indicator.BOLL {
-	iwb := types.IntervalWindowBandWidth{IntervalWindow: iw, BandWidth: bandWidth}
-	inc, ok := set.boll[iwb]
-	if !ok {
-		inc = &indicator.BOLL{IntervalWindow: iw, K: bandWidth}
-		inc.Bind(set.store)
-		set.boll[iwb] = inc
-	}
-
-	return inc
-}
-
-// SMA returns the simple moving average indicator of the given interval and the window size.
-func (set *StandardIndicatorSet) SMA(iw types.IntervalWindow) *indicator.SMA {
-	inc, ok := set.sma[iw]
-	if !ok {
-		inc = &indicator.SMA{IntervalWindow: iw}
-		inc.Bind(set.store)
-		set.sma[iw] = inc
-	}
-
-	return inc
-}
-
-// EWMA returns the exponential weighed moving average indicator of the given interval and the window size.
-func (set *StandardIndicatorSet) EWMA(iw types.IntervalWindow) *indicator.EWMA {
-	inc, ok := set.ewma[iw]
-	if !ok {
-		inc = &indicator.EWMA{IntervalWindow: iw}
-		inc.Bind(set.store)
-		set.ewma[iw] = inc
-	}
-
-	return inc
-}
-
-func (set *StandardIndicatorSet) STOCH(iw types.IntervalWindow) *indicator.STOCH {
-	inc, ok := set.stoch[iw]
-	if !ok {
-		inc = &indicator.STOCH{IntervalWindow: iw}
-		inc.Bind(set.store)
-		set.stoch[iw] = inc
-	}
-
-	return inc
-}
-
-// VOLATILITY returns the volatility(stddev) indicator of the given interval and the window size.
-func (set *StandardIndicatorSet) VOLATILITY(iw types.IntervalWindow) *indicator.VOLATILITY {
-	inc, ok := set.volatility[iw]
-	if !ok {
-		inc = &indicator.VOLATILITY{IntervalWindow: iw}
-		inc.Bind(set.store)
-		set.volatility[iw] = inc
-	}
-
-	return inc
-}
-
 // ExchangeSession presents the exchange connection Session
 // It also maintains and collects the data returned from the stream.
 type ExchangeSession struct {
@@ -234,6 +99,9 @@ type ExchangeSession struct {
 
 	positions map[string]*types.Position
 
+	// priceLookup converts the fees of the positions into the quote currency
+	priceLookup *SessionPriceLookup
+
 	// standard indicators of each market
 	standardIndicatorSets map[string]*StandardIndicatorSet
 
@@ -284,6 +152,7 @@ func NewExchangeSession(name string, exchange types.Exchange) *ExchangeSession {
 		Session:       session,
 	}
 
+	session.priceLookup = &SessionPriceLookup{Session: session}
 	return session
 }
 
@@ -335,6 +204,16 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 
 	session.markets = markets
 
+	// the stored klines are used for converting the fees of the historical trades
+	if environ.BacktestService != nil {
+		session.priceLookup.Historical = &service.KLinePriceService{Backtest: environ.BacktestService, Exchange: session.ExchangeName}
+	} else if environ.DatabaseService != nil {
+		session.priceLookup.Historical = &service.KLinePriceService{
+			Backtest: &service.BacktestService{DB: environ.DatabaseService.DB},
+			Exchange: session.ExchangeName,
+		}
+	}
+
 	// query and initialize the balances
 	if !session.PublicOnly {
 		account, err := session.Exchange.QueryAccount(ctx)
@@ -469,6 +348,7 @@ func (session *ExchangeSession) initSymbol(ctx context.Context, environ *Environ
 		BaseCurrency:  market.BaseCurrency,
 		QuoteCurrency: market.QuoteCurrency,
 	}
+	position.SetPriceLookup(session.priceLookup.Live())
 	position.AddTrades(trades)
 	position.BindStream(session.UserDataStream)
 	session.positions[symbol] = position
@@ -505,9 +385,19 @@ func (session *ExchangeSession) initSymbol(ctx context.Context, environ *Environ
 				continue
 			}
 
-			if sub.Symbol == symbol {
-				klineSubscriptions[types.Interval(sub.Options.Interval)] = struct{}{}
+			if sub.Symbol != symbol {
+				continue
+			}
+
+			// the derived klines are transformed from the source klines by the market data store
+			if interval := types.Interval(sub.Options.Interval); interval.IsDerived() {
+				if err := marketDataStore.AddDerivedInterval(interval); err != nil {
+					return err
+				}
+				continue
 			}
+
+			klineSubscriptions[types.Interval(sub.Options.Interval)] = struct{}{}
 		}
 	}
 
@@ -567,6 +457,7 @@ func (session *ExchangeSession) Position(symbol string) (pos *types.Position, ok
 		BaseCurrency:  market.BaseCurrency,
 		QuoteCurrency: market.QuoteCurrency,
 	}
+	pos.SetPriceLookup(session.priceLookup.Live())
 	ok = true
 	session.positions[symbol] = pos
 	return pos, ok
@@ -576,6 +467,18 @@ func (session *ExchangeSession) Positions() map[string]*types.Position {
 	return session.positions
 }
 
+// PriceLookup returns the price lookup for converting the fees into the quote currency at the trade time,
+// it queries the ticker and the stored klines, so it's used by the reports like the pnl calculators
+func (session *ExchangeSession) PriceLookup() types.PriceLookup {
+	return session.priceLookup
+}
+
+// LivePriceLookup returns the price lookup of the cached prices for the trade processing,
+// strategies can set it on their own positions by Position.SetPriceLookup
+func (session *ExchangeSession) LivePriceLookup() types.PriceLookup {
+	return session.priceLookup.Live()
+}
+
 // MarketDataStore returns the market data store of a symbol
 func (session *ExchangeSession) MarketDataStore(symbol string) (s *MarketDataStore, ok bool) {
 	s, ok = session.marketDataStores[symbol]
@@ -630,6 +533,21 @@ func (session *ExchangeSession) Subscribe(channel types.Channel, symbol string,
 		panic("subscription interval for kline can not be empty")
 	}
 
+	// the derived klines are built from the source klines or the market trades,
+	// so we subscribe the source channel as well
+	if interval := types.Interval(options.Interval); channel == types.KLineChannel && interval.IsDerived() {
+		derived, err := types.ParseDerivedInterval(interval)
+		if err != nil {
+			panic(err)
+		}
+
+		if derived.FromTrades() {
+			session.Subscribe(types.MarketTradeChannel, symbol, types.SubscribeOptions{})
+		} else {
+			session.Subscribe(types.KLineChannel, symbol, types.SubscribeOptions{Interval: derived.Source.String()})
+		}
+	}
+
 	sub := types.Subscription{
 		Channel: channel,
 		Symbol:  symbol,
@@ -664,6 +582,11 @@ func (session *ExchangeSession) UpdatePrices(ctx context.Context, currencies []s
 		symbols = append(symbols, fiat+c) // USDT/TWD
 	}
 
+	return session.UpdateLastPrices(ctx, symbols...)
+}
+
+// UpdateLastPrices queries the tickers of the given symbols and updates the last prices
+func (session *ExchangeSession) UpdateLastPrices(ctx context.Context, symbols ...string) error {
 	tickers, err := session.Exchange.QueryTickers(ctx, symbols...)
 	if err != nil || len(tickers) == 0 {
 		return err
@@ -679,7 +602,7 @@ func (session *ExchangeSession) UpdatePrices(ctx context.Context, currencies []s
 	}
 
 	session.lastPriceUpdatedAt = lastTime
-	return err
+	return nil
 }
 
 func (session *ExchangeSession) FindPossibleSymbols() (symbols []string, err error) {
@@ -802,6 +725,7 @@ func (session *ExchangeSession) InitExchange(name string, exchange types.Exchang
 		Session:       session,
 	}
 
+	session.priceLookup = &SessionPriceLookup{Session: session}
 	session.usedSymbols = make(map[string]struct{})
 	session.initializedSymbols = make(map[string]struct{})
 	session.logger = log.WithField("session", name)
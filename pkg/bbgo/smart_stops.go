@@ -138,7 +138,7 @@ func (c *TrailingStopController) Run(ctx context.Context, session *ExchangeSessi
 					return
 				}
 
-				createdOrders, err := session.Exchange.SubmitOrders(ctx, *marketOrder)
+				createdOrders, err := session.OrderExecutor.SubmitOrders(ctx, *marketOrder)
 				if err != nil {
 					log.WithError(err).Errorf("stop market order place error")
 					return
@@ -160,7 +160,7 @@ func (c *TrailingStopController) Run(ctx context.Context, session *ExchangeSessi
 				if orderForm != nil {
 					log.Infof("updating %s stop limit order to simulate trailing stop order...", c.Symbol)
 
-					createdOrders, err := session.Exchange.SubmitOrders(ctx, *orderForm)
+					createdOrders, err := session.OrderExecutor.SubmitOrders(ctx, *orderForm)
 					if err != nil {
 						log.WithError(err).Errorf("%s stop order place error", c.Symbol)
 						return
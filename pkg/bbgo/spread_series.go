@@ -0,0 +1,243 @@
+package bbgo
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// SpreadSeries is the series of the spread or the ratio of two markets, the markets can be on the different sessions, for example,
+//
+//	// the spread of BTCUSDT between binance and max
+//	spread := bbgo.NewSpreadSeries(types.Interval1m, binanceStore, maxStore)
+//
+//	// the ETH/BTC ratio from the two USDT markets
+//	ratio := bbgo.NewRatioSeries(types.Interval1h, ethUsdtStore, btcUsdtStore)
+//
+// The klines of the two markets are aligned by the start time. When a market misses the kline of a start time,
+// the last close of the market is used after the other market has moved on to the next kline.
+//
+// The aligned klines of the spread are emitted to the kline window update callbacks,
+// so any indicator can be bound to the series like a market data store:
+//
+//	boll := &indicator.BOLL{IntervalWindow: types.IntervalWindow{Interval: types.Interval1m, Window: 20}, K: 2}
+//	boll.Bind(spread)
+//
+//go:generate callbackgen -type SpreadSeries
+type SpreadSeries struct {
+	Interval types.Interval
+
+	// Ratio calculates a / b instead of the spread a - HedgeRatio * b
+	Ratio bool
+
+	// HedgeRatio is the quantity of b hedged against one a in the spread, default 1
+	HedgeRatio float64
+
+	// Values are the aligned values of the close prices
+	Values types.Float64Slice
+
+	// KLines are the aligned klines, the open, the high, the low and the close are calculated from the prices of the klines
+	KLines types.KLineWindow
+
+	legs [2]spreadLeg
+
+	updateCallbacks            []func(value float64)
+	kLineWindowUpdateCallbacks []func(interval types.Interval, klines types.KLineWindow)
+}
+
+type spreadLeg struct {
+	// pending are the klines that are not aligned yet, in the order of the start time
+	pending []types.KLine
+
+	// last is the last aligned kline
+	last *types.KLine
+
+	// startTime is the start time of the latest kline
+	startTime time.Time
+}
+
+// NewSpreadSeries creates the series of the spread a - b, and binds the series to the market data stores
+func NewSpreadSeries(interval types.Interval, a, b *MarketDataStore) *SpreadSeries {
+	s := &SpreadSeries{Interval: interval, HedgeRatio: 1}
+	s.Bind(a, b)
+	return s
+}
+
+// NewRatioSeries creates the series of the ratio a / b, and binds the series to the market data stores
+func NewRatioSeries(interval types.Interval, a, b *MarketDataStore) *SpreadSeries {
+	s := &SpreadSeries{Interval: interval, Ratio: true}
+	s.Bind(a, b)
+	return s
+}
+
+// Bind binds the series to the market data stores of the two markets,
+// the klines already loaded in the stores are aligned immediately
+func (s *SpreadSeries) Bind(a, b *MarketDataStore) {
+	for i, store := range []*MarketDataStore{a, b} {
+		leg := i
+		if window, ok := store.KLinesOfInterval(s.Interval); ok {
+			for _, k := range *window {
+				s.AddKLine(leg, k)
+			}
+		}
+
+		store.OnKLineWindowUpdate(func(interval types.Interval, window types.KLineWindow) {
+			if interval != s.Interval || len(window) == 0 {
+				return
+			}
+
+			s.AddKLine(leg, window.Last())
+		})
+	}
+}
+
+// AddKLine adds the kline of the market a (leg 0) or b (leg 1), the klines that don't start after the latest kline are ignored
+func (s *SpreadSeries) AddKLine(leg int, k types.KLine) {
+	l := &s.legs[leg]
+	if !k.StartTime.After(l.startTime) {
+		return
+	}
+
+	l.pending = append(l.pending, k)
+	l.startTime = k.StartTime.Time()
+	s.align()
+}
+
+// align aligns the pending klines until the start time that both markets have reached
+func (s *SpreadSeries) align() {
+	a, b := &s.legs[0], &s.legs[1]
+	if a.startTime.IsZero() || b.startTime.IsZero() {
+		return
+	}
+
+	ready := a.startTime
+	if b.startTime.Before(ready) {
+		ready = b.startTime
+	}
+
+	for {
+		startTime, ok := nextPendingStartTime(a, b)
+		if !ok || startTime.After(ready) {
+			return
+		}
+
+		ka, kb := a.take(startTime), b.take(startTime)
+		if a.last == nil || b.last == nil {
+			continue
+		}
+
+		endTime := a.last.EndTime
+		if ka == nil || (kb != nil && kb.EndTime.After(endTime.Time())) {
+			endTime = b.last.EndTime
+		}
+
+		s.push(s.combine(a.lastAt(ka), b.lastAt(kb), startTime, endTime))
+	}
+}
+
+func nextPendingStartTime(a, b *spreadLeg) (startTime time.Time, ok bool) {
+	for _, l := range []*spreadLeg{a, b} {
+		if len(l.pending) == 0 {
+			continue
+		}
+
+		if t := l.pending[0].StartTime.Time(); !ok || t.Before(startTime) {
+			startTime, ok = t, true
+		}
+	}
+
+	return startTime, ok
+}
+
+// take pops the pending kline of the start time, and updates the last aligned kline
+func (l *spreadLeg) take(startTime time.Time) *types.KLine {
+	if len(l.pending) == 0 || !l.pending[0].StartTime.Time().Equal(startTime) {
+		return nil
+	}
+
+	k := l.pending[0]
+	l.pending = l.pending[1:]
+	l.last = &k
+	return &k
+}
+
+// lastAt returns the kline of the start time, or the flat kline at the last close if the kline is missing
+func (l *spreadLeg) lastAt(k *types.KLine) types.KLine {
+	if k != nil {
+		return *k
+	}
+
+	flat := *l.last
+	flat.Open = flat.Close
+	flat.High = flat.Close
+	flat.Low = flat.Close
+	return flat
+}
+
+func (s *SpreadSeries) calculate(a, b fixedpoint.Value) float64 {
+	if s.Ratio {
+		if b.IsZero() {
+			return 0
+		}
+		return a.Float64() / b.Float64()
+	}
+
+	hedgeRatio := s.HedgeRatio
+	if hedgeRatio == 0 {
+		hedgeRatio = 1
+	}
+	return a.Float64() - hedgeRatio*b.Float64()
+}
+
+func (s *SpreadSeries) combine(a, b types.KLine, startTime time.Time, endTime types.Time) types.KLine {
+	open := s.calculate(a.Open, b.Open)
+	cloze := s.calculate(a.Close, b.Close)
+	high, low := open, open
+	for _, v := range []float64{s.calculate(a.High, b.High), s.calculate(a.Low, b.Low), cloze} {
+		if v > high {
+			high = v
+		}
+		if v < low {
+			low = v
+		}
+	}
+
+	return types.KLine{
+		Symbol:    a.Symbol + "/" + b.Symbol,
+		Interval:  s.Interval,
+		StartTime: types.Time(startTime),
+		EndTime:   endTime,
+		Open:      fixedpoint.NewFromFloat(open),
+		High:      fixedpoint.NewFromFloat(high),
+		Low:       fixedpoint.NewFromFloat(low),
+		Close:     fixedpoint.NewFromFloat(cloze),
+		Closed:    true,
+	}
+}
+
+func (s *SpreadSeries) push(k types.KLine) {
+	s.Values.Push(k.Close.Float64())
+	s.KLines.Add(k)
+	if len(s.KLines) > MaxNumOfKLines {
+		s.KLines = s.KLines[MaxNumOfKLinesTruncate-1:]
+		s.Values = s.Values[MaxNumOfKLinesTruncate-1:]
+	}
+
+	s.EmitUpdate(s.Values.Last())
+	s.EmitKLineWindowUpdate(s.Interval, s.KLines)
+}
+
+func (s *SpreadSeries) Last() float64 {
+	return s.Values.Last()
+}
+
+func (s *SpreadSeries) Index(i int) float64 {
+	return s.Values.Index(i)
+}
+
+func (s *SpreadSeries) Length() int {
+	return s.Values.Length()
+}
+
+var _ types.Series = &SpreadSeries{}
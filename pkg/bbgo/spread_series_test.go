@@ -0,0 +1,120 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestSymbolKLine(symbol string, i int, open, high, low, cloze float64) types.KLine {
+	k := newTestKLine(i, open, high, low, cloze)
+	k.Symbol = symbol
+	return k
+}
+
+func TestSpreadSeries_Align(t *testing.T) {
+	a, b := NewMarketDataStore("BTCUSDT"), NewMarketDataStore("ETHUSDT")
+	spread := NewSpreadSeries(types.Interval1m, a, b)
+
+	var updates []float64
+	spread.OnUpdate(func(value float64) {
+		updates = append(updates, value)
+	})
+
+	aCloses := []float64{100, 102, 104, 103, 105}
+	bCloses := map[int]float64{0: 50, 1: 51, 3: 52, 4: 53} // b misses the kline 2
+	for i, c := range aCloses {
+		a.AddKLine(newTestSymbolKLine("BTCUSDT", i, c, c, c, c))
+		if c, ok := bCloses[i]; ok {
+			b.AddKLine(newTestSymbolKLine("ETHUSDT", i, c, c, c, c))
+		}
+
+		// the kline 2 is aligned after b has moved on to the kline 3
+		if i == 2 {
+			assert.Equal(t, 2, spread.Length())
+		}
+	}
+
+	// the kline 2 uses the last close of b
+	assert.Equal(t, []float64{50, 51, 53, 51, 52}, updates)
+	assert.Equal(t, 5, spread.Length())
+	assert.Equal(t, 52.0, spread.Last())
+	assert.Equal(t, 53.0, spread.Index(2))
+
+	if assert.Len(t, spread.KLines, 5) {
+		k := spread.KLines[2]
+		assert.Equal(t, "BTCUSDT/ETHUSDT", k.Symbol)
+		assert.Equal(t, types.Interval1m, k.Interval)
+		assert.Equal(t, time.Date(2022, 6, 1, 0, 2, 0, 0, time.UTC), k.StartTime.Time())
+		assert.Equal(t, 53.0, k.Close.Float64())
+	}
+
+	// the duplicated klines and the klines of the other intervals are ignored
+	a.AddKLine(newTestSymbolKLine("BTCUSDT", 4, 1, 1, 1, 1))
+	a.AddKLine(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval5m})
+	b.AddKLine(types.KLine{Symbol: "ETHUSDT", Interval: types.Interval5m})
+	assert.Equal(t, 5, spread.Length())
+}
+
+func TestSpreadSeries_HedgeRatio(t *testing.T) {
+	spread := &SpreadSeries{Interval: types.Interval1m, HedgeRatio: 2}
+	spread.AddKLine(0, newTestSymbolKLine("BTCUSDT", 0, 100, 110, 90, 105))
+	spread.AddKLine(1, newTestSymbolKLine("ETHUSDT", 0, 40, 45, 30, 50))
+
+	if assert.Len(t, spread.KLines, 1) {
+		k := spread.KLines[0]
+		assert.InDelta(t, 20.0, k.Open.Float64(), 1e-9)
+		assert.InDelta(t, 5.0, k.Close.Float64(), 1e-9)
+		// the high and the low are the extremes of the spreads of the open, the high, the low and the close
+		// open 100 - 2 * 40, high 110 - 2 * 45, low 90 - 2 * 30, close 105 - 2 * 50
+		assert.InDelta(t, 30.0, k.High.Float64(), 1e-9)
+		assert.InDelta(t, 5.0, k.Low.Float64(), 1e-9)
+	}
+}
+
+func TestRatioSeries_WarmUp(t *testing.T) {
+	a, b := NewMarketDataStore("BTCUSDT"), NewMarketDataStore("ETHUSDT")
+	a.AddKLine(newTestSymbolKLine("BTCUSDT", 0, 100, 110, 90, 105))
+	a.AddKLine(newTestSymbolKLine("BTCUSDT", 1, 105, 110, 100, 110))
+	b.AddKLine(newTestSymbolKLine("ETHUSDT", 0, 50, 50, 50, 50))
+	b.AddKLine(newTestSymbolKLine("ETHUSDT", 1, 55, 55, 55, 55))
+
+	// the klines loaded in the stores are aligned when the series is created
+	ratio := NewRatioSeries(types.Interval1m, a, b)
+	if assert.Equal(t, 2, ratio.Length()) {
+		k := ratio.KLines[0]
+		assert.InDelta(t, 2.0, k.Open.Float64(), 1e-9)
+		assert.InDelta(t, 2.2, k.High.Float64(), 1e-9)
+		assert.InDelta(t, 1.8, k.Low.Float64(), 1e-9)
+		assert.InDelta(t, 2.1, k.Close.Float64(), 1e-9)
+		assert.InDelta(t, 2.0, ratio.Last(), 1e-9)
+	}
+
+	a.AddKLine(newTestSymbolKLine("BTCUSDT", 2, 110, 120, 110, 120))
+	b.AddKLine(newTestSymbolKLine("ETHUSDT", 2, 55, 55, 40, 40))
+	assert.InDelta(t, 3.0, ratio.Last(), 1e-9)
+}
+
+func TestSpreadSeries_Indicator(t *testing.T) {
+	a, b := NewMarketDataStore("BTCUSDT"), NewMarketDataStore("ETHUSDT")
+	spread := NewSpreadSeries(types.Interval1m, a, b)
+
+	bound := &indicator.EWMA{IntervalWindow: types.IntervalWindow{Interval: types.Interval1m, Window: 3}}
+	bound.Bind(spread)
+
+	expected := &indicator.EWMA{IntervalWindow: types.IntervalWindow{Interval: types.Interval1m, Window: 3}}
+	spread.OnUpdate(expected.Update)
+
+	for i, c := range []float64{100, 103, 101, 106, 104, 108} {
+		a.AddKLine(newTestSymbolKLine("BTCUSDT", i, c, c, c, c))
+		b.AddKLine(newTestSymbolKLine("ETHUSDT", i, 50, 50, 50, 50))
+	}
+
+	assert.Equal(t, 6, bound.Length())
+	assert.InDelta(t, expected.Last(), bound.Last(), 1e-9)
+	assert.InDelta(t, expected.Index(2), bound.Index(2), 1e-9)
+}
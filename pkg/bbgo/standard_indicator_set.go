@@ -0,0 +1,352 @@
+package bbgo
+
+import (
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+var (
+	debugEWMA = false
+	debugSMA  = false
+)
+
+func init() {
+	// when using --dotenv option, the dotenv is loaded from command.PersistentPreRunE, not init.
+	// hence here the env var won't enable the debug flag
+	util.SetEnvVarBool("DEBUG_EWMA", &debugEWMA)
+	util.SetEnvVarBool("DEBUG_SMA", &debugSMA)
+}
+
+// KLineWindowIndicator is the indicator that can be bound to the kline window updater
+type KLineWindowIndicator interface {
+	Bind(updater indicator.KLineWindowUpdater)
+}
+
+// indicatorKey is the key of the allocated indicators,
+// params stores the extra parameters of the indicator, e.g., the band width of BOLL.
+type indicatorKey struct {
+	name string
+	types.IntervalWindow
+	params string
+}
+
+// kLineWindowReplayer replays the loaded klines to the handler when an indicator is bound to it,
+// the window is sent kline by kline like the market data store does when the klines are loaded,
+// so that the indicator allocated after the klines are loaded is warmed up immediately instead of at the next closed kline.
+type kLineWindowReplayer struct {
+	interval types.Interval
+	window   types.KLineWindow
+}
+
+func (r *kLineWindowReplayer) OnKLineWindowUpdate(cb func(interval types.Interval, window types.KLineWindow)) {
+	for i := range r.window {
+		cb(r.interval, r.window[:i+1])
+	}
+}
+
+// StandardIndicatorSet allocates the indicators of a symbol.
+// The indicators are memoized by the interval, the window and the parameters,
+// so the strategies on the same session share the same indicator instance.
+type StandardIndicatorSet struct {
+	Symbol string
+
+	mu         sync.Mutex
+	indicators map[indicatorKey]KLineWindowIndicator
+
+	store *MarketDataStore
+}
+
+func NewStandardIndicatorSet(symbol string, store *MarketDataStore) *StandardIndicatorSet {
+	set := &StandardIndicatorSet{
+		Symbol:     symbol,
+		indicators: make(map[indicatorKey]KLineWindowIndicator),
+		store:      store,
+	}
+
+	// let us pre-defined commonly used intervals
+	for interval := range types.SupportedIntervals {
+		for _, window := range []int{7, 25, 99} {
+			iw := types.IntervalWindow{Interval: interval, Window: window}
+			sma := set.SMA(iw)
+			if debugSMA {
+				sma.OnUpdate(func(value float64) {
+					log.Infof("%s SMA %s: %f", symbol, iw.String(), value)
+				})
+			}
+
+			ewma := set.EWMA(iw)
+
+			// if debug EWMA is enabled, we add the debug handler
+			if debugEWMA {
+				ewma.OnUpdate(func(value float64) {
+					log.Infof("%s EWMA %s: %f", symbol, iw.String(), value)
+				})
+			}
+		}
+
+		// setup boll indicator, we may refactor boll indicator by subscribing SMA indicator,
+		// however, since general used BOLLINGER band use window 21, which is not in the existing SMA indicator sets.
+		// set default band width to 2.0
+		set.BOLL(types.IntervalWindow{Interval: interval, Window: 21}, 2.0)
+	}
+
+	return set
+}
+
+// allocate returns the memoized indicator of the key, or binds the indicator created by newIndicator to the market data store.
+// The klines already loaded in the store are sent to the new indicator, so the indicators allocated before
+// and after the klines are loaded have the same values in both the live mode and the backtest.
+func (set *StandardIndicatorSet) allocate(key indicatorKey, newIndicator func() KLineWindowIndicator) KLineWindowIndicator {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	if inc, ok := set.indicators[key]; ok {
+		return inc
+	}
+
+	inc := newIndicator()
+	set.store.BindIndicator(key.Interval, inc)
+	set.indicators[key] = inc
+	return inc
+}
+
+// BOLL returns the bollinger band indicator of the given interval, the window and bandwidth
+func (set *StandardIndicatorSet) BOLL(iw types.IntervalWindow, bandWidth float64) *indicator.BOLL {
+	return set.allocate(indicatorKey{name: "boll", IntervalWindow: iw, params: fmt.Sprintf("%f", bandWidth)}, func() KLineWindowIndicator {
+		return &indicator.BOLL{IntervalWindow: iw, K: bandWidth}
+	}).(*indicator.BOLL)
+}
+
+// LegacyBOLL returns the bollinger band indicator with the sample standard deviation of the previous versions,
+// it's memoized separately from BOLL, so the strategies depending on the old bands don't change the bands of the others.
+func (set *StandardIndicatorSet) LegacyBOLL(iw types.IntervalWindow, bandWidth float64) *indicator.BOLL {
+	return set.allocate(indicatorKey{name: "boll-legacy", IntervalWindow: iw, params: fmt.Sprintf("%f", bandWidth)}, func() KLineWindowIndicator {
+		return &indicator.BOLL{IntervalWindow: iw, K: bandWidth, Legacy: true}
+	}).(*indicator.BOLL)
+}
+
+// SMA returns the simple moving average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) SMA(iw types.IntervalWindow) *indicator.SMA {
+	return set.allocate(indicatorKey{name: "sma", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.SMA{IntervalWindow: iw}
+	}).(*indicator.SMA)
+}
+
+// EWMA returns the exponential weighed moving average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) EWMA(iw types.IntervalWindow) *indicator.EWMA {
+	return set.allocate(indicatorKey{name: "ewma", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.EWMA{IntervalWindow: iw}
+	}).(*indicator.EWMA)
+}
+
+// STOCH returns the stochastic oscillator indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) STOCH(iw types.IntervalWindow) *indicator.STOCH {
+	return set.allocate(indicatorKey{name: "stoch", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.STOCH{IntervalWindow: iw}
+	}).(*indicator.STOCH)
+}
+
+// VOLATILITY returns the volatility(stddev) indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) VOLATILITY(iw types.IntervalWindow) *indicator.VOLATILITY {
+	return set.allocate(indicatorKey{name: "volatility", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.VOLATILITY{IntervalWindow: iw}
+	}).(*indicator.VOLATILITY)
+}
+
+// DMI returns the directional movement index indicator of the given interval and the window size,
+// the ADX is smoothed with the same window.
+func (set *StandardIndicatorSet) DMI(iw types.IntervalWindow) *indicator.DMI {
+	return set.allocate(indicatorKey{name: "dmi", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.DMI{IntervalWindow: iw}
+	}).(*indicator.DMI)
+}
+
+// PSAR returns the parabolic SAR indicator of the given interval with the default acceleration factors.
+func (set *StandardIndicatorSet) PSAR(interval types.Interval) *indicator.PSAR {
+	return set.allocate(indicatorKey{name: "psar", IntervalWindow: types.IntervalWindow{Interval: interval}}, func() KLineWindowIndicator {
+		return &indicator.PSAR{Interval: interval}
+	}).(*indicator.PSAR)
+}
+
+// SuperTrend returns the supertrend indicator of the given interval, the ATR window and the ATR multiplier
+func (set *StandardIndicatorSet) SuperTrend(iw types.IntervalWindow, multiplier float64) *indicator.SuperTrend {
+	return set.allocate(indicatorKey{name: "supertrend", IntervalWindow: iw, params: fmt.Sprintf("%f", multiplier)}, func() KLineWindowIndicator {
+		return &indicator.SuperTrend{IntervalWindow: iw, ATRMultiplier: multiplier}
+	}).(*indicator.SuperTrend)
+}
+
+// Ichimoku returns the ichimoku cloud indicator of the given interval with the default periods 9, 26, 52.
+func (set *StandardIndicatorSet) Ichimoku(interval types.Interval) *indicator.Ichimoku {
+	return set.allocate(indicatorKey{name: "ichimoku", IntervalWindow: types.IntervalWindow{Interval: interval}}, func() KLineWindowIndicator {
+		return &indicator.Ichimoku{Interval: interval}
+	}).(*indicator.Ichimoku)
+}
+
+// KeltnerChannel returns the keltner channel indicator of the given interval, the window and the ATR multiplier,
+// the ATR window is the same as the EWMA window.
+func (set *StandardIndicatorSet) KeltnerChannel(iw types.IntervalWindow, multiplier float64) *indicator.KeltnerChannel {
+	return set.allocate(indicatorKey{name: "keltner", IntervalWindow: iw, params: fmt.Sprintf("%f", multiplier)}, func() KLineWindowIndicator {
+		return &indicator.KeltnerChannel{IntervalWindow: iw, ATRMultiplier: multiplier}
+	}).(*indicator.KeltnerChannel)
+}
+
+// DonchianChannel returns the donchian channel indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) DonchianChannel(iw types.IntervalWindow) *indicator.DonchianChannel {
+	return set.allocate(indicatorKey{name: "donchian", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.DonchianChannel{IntervalWindow: iw}
+	}).(*indicator.DonchianChannel)
+}
+
+// MFI returns the money flow index indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) MFI(iw types.IntervalWindow) *indicator.MFI {
+	return set.allocate(indicatorKey{name: "mfi", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.MFI{IntervalWindow: iw}
+	}).(*indicator.MFI)
+}
+
+// CMF returns the chaikin money flow indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) CMF(iw types.IntervalWindow) *indicator.CMF {
+	return set.allocate(indicatorKey{name: "cmf", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.CMF{IntervalWindow: iw}
+	}).(*indicator.CMF)
+}
+
+// StochRSI returns the stochastic RSI indicator of the given interval and the RSI window,
+// the stochastic window is the same as the RSI window and the K, D lines are smoothed by 3.
+func (set *StandardIndicatorSet) StochRSI(iw types.IntervalWindow) *indicator.StochRSI {
+	return set.allocate(indicatorKey{name: "stochrsi", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.StochRSI{IntervalWindow: iw}
+	}).(*indicator.StochRSI)
+}
+
+// RSI returns the relative strength index indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) RSI(iw types.IntervalWindow) *indicator.RSI {
+	return set.allocate(indicatorKey{name: "rsi", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.RSI{IntervalWindow: iw}
+	}).(*indicator.RSI)
+}
+
+// ATR returns the average true range indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) ATR(iw types.IntervalWindow) *indicator.ATR {
+	return set.allocate(indicatorKey{name: "atr", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.ATR{IntervalWindow: iw}
+	}).(*indicator.ATR)
+}
+
+// MACD returns the MACD indicator of the given interval, the signal window, the short period and the long period.
+func (set *StandardIndicatorSet) MACD(iw types.IntervalWindow, shortPeriod, longPeriod int) *indicator.MACD {
+	return set.allocate(indicatorKey{name: "macd", IntervalWindow: iw, params: fmt.Sprintf("%d,%d", shortPeriod, longPeriod)}, func() KLineWindowIndicator {
+		return &indicator.MACD{IntervalWindow: iw, ShortPeriod: shortPeriod, LongPeriod: longPeriod}
+	}).(*indicator.MACD)
+}
+
+// AD returns the accumulation/distribution indicator of the given interval.
+func (set *StandardIndicatorSet) AD(iw types.IntervalWindow) *indicator.AD {
+	return set.allocate(indicatorKey{name: "ad", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.AD{IntervalWindow: iw}
+	}).(*indicator.AD)
+}
+
+// OBV returns the on-balance volume indicator of the given interval.
+func (set *StandardIndicatorSet) OBV(iw types.IntervalWindow) *indicator.OBV {
+	return set.allocate(indicatorKey{name: "obv", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.OBV{IntervalWindow: iw}
+	}).(*indicator.OBV)
+}
+
+// CA returns the cumulative average indicator of the given interval.
+func (set *StandardIndicatorSet) CA(interval types.Interval) *indicator.CA {
+	return set.allocate(indicatorKey{name: "ca", IntervalWindow: types.IntervalWindow{Interval: interval}}, func() KLineWindowIndicator {
+		return &indicator.CA{Interval: interval}
+	}).(*indicator.CA)
+}
+
+// CCI returns the commodity channel index indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) CCI(iw types.IntervalWindow) *indicator.CCI {
+	return set.allocate(indicatorKey{name: "cci", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.CCI{IntervalWindow: iw}
+	}).(*indicator.CCI)
+}
+
+// RMA returns the running moving average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) RMA(iw types.IntervalWindow) *indicator.RMA {
+	return set.allocate(indicatorKey{name: "rma", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.RMA{IntervalWindow: iw}
+	}).(*indicator.RMA)
+}
+
+// WWMA returns the welles wilder's moving average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) WWMA(iw types.IntervalWindow) *indicator.WWMA {
+	return set.allocate(indicatorKey{name: "wwma", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.WWMA{IntervalWindow: iw}
+	}).(*indicator.WWMA)
+}
+
+// DEMA returns the double exponential moving average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) DEMA(iw types.IntervalWindow) *indicator.DEMA {
+	return set.allocate(indicatorKey{name: "dema", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.DEMA{IntervalWindow: iw}
+	}).(*indicator.DEMA)
+}
+
+// TEMA returns the triple exponential moving average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) TEMA(iw types.IntervalWindow) *indicator.TEMA {
+	return set.allocate(indicatorKey{name: "tema", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.TEMA{IntervalWindow: iw}
+	}).(*indicator.TEMA)
+}
+
+// TMA returns the triangular moving average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) TMA(iw types.IntervalWindow) *indicator.TMA {
+	return set.allocate(indicatorKey{name: "tma", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.TMA{IntervalWindow: iw}
+	}).(*indicator.TMA)
+}
+
+// HULL returns the hull moving average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) HULL(iw types.IntervalWindow) *indicator.HULL {
+	return set.allocate(indicatorKey{name: "hull", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.HULL{IntervalWindow: iw}
+	}).(*indicator.HULL)
+}
+
+// TILL returns the tillson T3 moving average indicator of the given interval and the window size with the default volume factor.
+func (set *StandardIndicatorSet) TILL(iw types.IntervalWindow) *indicator.TILL {
+	return set.allocate(indicatorKey{name: "till", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.TILL{IntervalWindow: iw}
+	}).(*indicator.TILL)
+}
+
+// ZLEMA returns the zero lag exponential moving average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) ZLEMA(iw types.IntervalWindow) *indicator.ZLEMA {
+	return set.allocate(indicatorKey{name: "zlema", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.ZLEMA{IntervalWindow: iw}
+	}).(*indicator.ZLEMA)
+}
+
+// VIDYA returns the variable index dynamic average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) VIDYA(iw types.IntervalWindow) *indicator.VIDYA {
+	return set.allocate(indicatorKey{name: "vidya", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.VIDYA{IntervalWindow: iw}
+	}).(*indicator.VIDYA)
+}
+
+// VWAP returns the volume weighted average price indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) VWAP(iw types.IntervalWindow) *indicator.VWAP {
+	return set.allocate(indicatorKey{name: "vwap", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.VWAP{IntervalWindow: iw}
+	}).(*indicator.VWAP)
+}
+
+// VWMA returns the volume weighted moving average indicator of the given interval and the window size.
+func (set *StandardIndicatorSet) VWMA(iw types.IntervalWindow) *indicator.VWMA {
+	return set.allocate(indicatorKey{name: "vwma", IntervalWindow: iw}, func() KLineWindowIndicator {
+		return &indicator.VWMA{IntervalWindow: iw}
+	}).(*indicator.VWMA)
+}
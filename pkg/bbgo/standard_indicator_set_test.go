@@ -0,0 +1,80 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func addTestKLines(store *MarketDataStore, from, to int) {
+	t0 := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i := from; i < to; i++ {
+		price := fixedpoint.NewFromFloat(100 + float64(i%7) - float64(i%3))
+		startTime := t0.Add(time.Duration(i) * time.Minute)
+		store.AddKLine(types.KLine{
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1m,
+			StartTime: types.Time(startTime),
+			EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+			Open:      price,
+			High:      price.Add(fixedpoint.One),
+			Low:       price.Sub(fixedpoint.One),
+			Close:     price,
+			Volume:    fixedpoint.NewFromInt(int64(i + 1)),
+			Closed:    true,
+		})
+	}
+}
+
+func TestStandardIndicatorSet_Memoized(t *testing.T) {
+	store := NewMarketDataStore("BTCUSDT")
+	set := NewStandardIndicatorSet("BTCUSDT", store)
+
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 14}
+	assert.Same(t, set.RSI(iw), set.RSI(iw))
+	assert.Same(t, set.BOLL(iw, 2.0), set.BOLL(iw, 2.0))
+	assert.NotSame(t, set.BOLL(iw, 2.0), set.BOLL(iw, 1.0))
+	assert.Same(t, set.LegacyBOLL(iw, 2.0), set.LegacyBOLL(iw, 2.0))
+	assert.NotSame(t, set.BOLL(iw, 2.0), set.LegacyBOLL(iw, 2.0))
+	assert.True(t, set.LegacyBOLL(iw, 2.0).Legacy)
+	assert.False(t, set.BOLL(iw, 2.0).Legacy)
+	assert.Same(t, set.MACD(iw, 12, 26), set.MACD(iw, 12, 26))
+	assert.NotSame(t, set.MACD(iw, 12, 26), set.MACD(iw, 5, 26))
+	assert.NotSame(t, set.SMA(iw), set.SMA(types.IntervalWindow{Interval: types.Interval5m, Window: 14}))
+}
+
+func TestStandardIndicatorSet_WarmUp(t *testing.T) {
+	store := NewMarketDataStore("BTCUSDT")
+	set := NewStandardIndicatorSet("BTCUSDT", store)
+
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 7}
+
+	// the indicators allocated before the klines are loaded
+	otherStore := NewMarketDataStore("BTCUSDT")
+	otherSet := NewStandardIndicatorSet("BTCUSDT", otherStore)
+	sma := otherSet.SMA(iw)
+	dema := otherSet.DEMA(iw)
+	ca := otherSet.CA(types.Interval1m)
+	rsi := otherSet.RSI(iw)
+
+	addTestKLines(store, 0, 30)
+	addTestKLines(otherStore, 0, 30)
+
+	// the indicators allocated after the klines are loaded should be warmed up with the same values
+	assert.Equal(t, sma.Values, set.SMA(iw).Values)
+	assert.Equal(t, dema.Values, set.DEMA(iw).Values)
+	assert.Equal(t, ca.Values, set.CA(types.Interval1m).Values)
+	assert.Equal(t, rsi.Values, set.RSI(iw).Values)
+	assert.Equal(t, 30, set.CA(types.Interval1m).Length())
+
+	addTestKLines(store, 30, 31)
+	addTestKLines(otherStore, 30, 31)
+	assert.Equal(t, sma.Values, set.SMA(iw).Values)
+	assert.Equal(t, dema.Values, set.DEMA(iw).Values)
+	assert.Equal(t, ca.Values, set.CA(types.Interval1m).Values)
+	assert.Equal(t, 31, set.CA(types.Interval1m).Length())
+}
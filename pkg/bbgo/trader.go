@@ -10,6 +10,7 @@ import (
 
 	_ "github.com/go-sql-driver/mysql"
 
+	"github.com/c9s/bbgo/pkg/accounting/pnl"
 	"github.com/c9s/bbgo/pkg/interact"
 )
 
@@ -114,9 +115,15 @@ func (trader *Trader) Configure(userConfig *Config) error {
 	for _, report := range userConfig.PnLReporters {
 		if len(report.AverageCostBySymbols) > 0 {
 
-			log.Infof("setting up average cost pnl reporter on symbols: %v", report.AverageCostBySymbols)
+			method, err := pnl.ParseCostBasisMethod(report.Method)
+			if err != nil {
+				return err
+			}
+
+			log.Infof("setting up %s cost pnl reporter on symbols: %v", method, report.AverageCostBySymbols)
 			trader.ReportPnL().
 				AverageCostBySymbols(report.AverageCostBySymbols...).
+				Method(method).
 				Of(report.Of...).
 				When(report.When...)
 
@@ -162,6 +169,20 @@ func (trader *Trader) AttachCrossExchangeStrategy(strategy CrossExchangeStrategy
 // TODO: provide a more DSL way to configure risk controls
 func (trader *Trader) SetRiskControls(riskControls *RiskControls) {
 	trader.riskControls = riskControls
+
+	if riskControls.PreTrade != nil {
+		riskControls.PreTrade.Notifiability = &trader.environment.Notifiability
+		for _, session := range trader.environment.sessions {
+			session.OrderExecutor.SetPreTradeRiskControl(riskControls.PreTrade)
+		}
+	}
+
+	if riskControls.CircuitBreaker != nil {
+		riskControls.CircuitBreaker.Notifiability = &trader.environment.Notifiability
+		for _, session := range trader.environment.sessions {
+			session.OrderExecutor.SetCircuitBreaker(riskControls.CircuitBreaker)
+		}
+	}
 }
 
 func (trader *Trader) Subscribe() {
@@ -212,6 +233,11 @@ func (trader *Trader) RunSingleExchangeStrategy(ctx context.Context, strategy Si
 		return err
 	}
 
+	// assign the client order IDs of the strategy instance, so that the orders can be attributed to the instance
+	if provider, ok := strategy.(InstanceIDProvider); ok {
+		orderExecutor = NewInstanceOrderExecutor(orderExecutor, session, strategy.ID(), provider.InstanceID())
+	}
+
 	if err := injectField(rs, "OrderExecutor", orderExecutor, false); err != nil {
 		return errors.Wrapf(err, "failed to inject OrderExecutor on %T", strategy)
 	}
@@ -335,6 +361,10 @@ func (trader *Trader) Run(ctx context.Context) error {
 		}
 	}
 
+	if trader.riskControls != nil && trader.riskControls.CircuitBreaker != nil {
+		go trader.riskControls.CircuitBreaker.Run(ctx, trader)
+	}
+
 	return trader.environment.Connect(ctx)
 }
 
@@ -447,5 +477,7 @@ func (trader *Trader) injectCommonServices(s interface{}) error {
 
 // ReportPnL configure and set the PnLReporter with the given notifier
 func (trader *Trader) ReportPnL() *PnLReporterManager {
-	return NewPnLReporter(&trader.environment.Notifiability)
+	manager := NewPnLReporter(&trader.environment.Notifiability)
+	manager.environment = trader.environment
+	return manager
 }
@@ -36,6 +36,7 @@ func init() {
 	BacktestCmd.Flags().String("session", "", "specify only one exchange session to run backtest")
 
 	BacktestCmd.Flags().Bool("verify", false, "verify the kline back-test data")
+	BacktestCmd.Flags().Bool("repair", false, "repair the kline back-test data: remove the duplicated klines, re-fetch the missing ranges and mark the ranges that the exchange has no data")
 
 	BacktestCmd.Flags().Bool("base-asset-baseline", false, "use base asset performance as the competitive baseline performance")
 	BacktestCmd.Flags().CountP("verbose", "v", "verbose level")
@@ -121,6 +122,11 @@ var BacktestCmd = &cobra.Command{
 			return err
 		}
 
+		shouldRepair, err := cmd.Flags().GetBool("repair")
+		if err != nil {
+			return err
+		}
+
 		userConfig, err := bbgo.Load(configFile, true)
 		if err != nil {
 			return err
@@ -226,6 +232,17 @@ var BacktestCmd = &cobra.Command{
 				}
 			}
 
+			if syncOnly && !shouldRepair {
+				return nil
+			}
+		}
+
+		if shouldRepair {
+			// the backward market data before the start time is also repaired
+			if err := repair(ctx, userConfig, backtestService, sourceExchanges, startTime.AddDate(0, -1, 0), endTime); err != nil {
+				return err
+			}
+
 			if syncOnly {
 				return nil
 			}
@@ -413,23 +430,18 @@ var BacktestCmd = &cobra.Command{
 				return
 			}
 
-		RunMultiExchangeData:
-			for {
-				for _, exK := range exchangeSources {
-					k, more := <-exK.C
-					if !more {
-						if err := exK.Exchange.CloseMarketData(); err != nil {
-							log.WithError(err).Errorf("close market data error")
-							return
-						}
-						break RunMultiExchangeData
-					}
+			// feed the klines of the sessions in the order of the end time
+			backtest.MergeExchangeDataSources(exchangeSources, func(k types.KLine, exSource *backtest.ExchangeDataSource) {
+				exSource.Exchange.ConsumeKLine(k)
 
-					exK.Exchange.ConsumeKLine(k)
+				for _, h := range kLineHandlers {
+					h(k, exSource)
+				}
+			})
 
-					for _, h := range kLineHandlers {
-						h(k, &exK)
-					}
+			for _, exSource := range exchangeSources {
+				if err := exSource.Exchange.CloseMarketData(); err != nil {
+					log.WithError(err).Errorf("close market data error")
 				}
 			}
 		}()
@@ -491,6 +503,7 @@ var BacktestCmd = &cobra.Command{
 				calculator := &pnl.AverageCostCalculator{
 					TradingFeeCurrency: backtestExchange.PlatformFeeCurrency(),
 					Market:             market,
+					PriceLookup:        session.PriceLookup(),
 				}
 
 				startPrice, ok := session.StartPrice(symbol)
@@ -593,6 +606,33 @@ func verify(userConfig *bbgo.Config, backtestService *service.BacktestService, s
 	return nil
 }
 
+func repair(ctx context.Context, userConfig *bbgo.Config, backtestService *service.BacktestService, sourceExchanges map[types.ExchangeName]types.Exchange, since, until time.Time) error {
+	for _, symbol := range userConfig.Backtest.Symbols {
+		for _, sourceExchange := range sourceExchanges {
+			supportIntervals := types.SupportedIntervals
+			if exCustom, ok := sourceExchange.(types.CustomIntervalProvider); ok {
+				supportIntervals = exCustom.SupportedInterval()
+			}
+
+			for interval := range supportIntervals {
+				result, err := backtestService.RepairKLines(ctx, sourceExchange, symbol, interval, since, until)
+				if err != nil {
+					return errors.Wrapf(err, "failed to repair %s %s %s klines", sourceExchange.Name(), symbol, interval)
+				}
+
+				log.Infof("repaired %s %s %s klines: deleted %d, inserted %d, marked %d no data ranges",
+					sourceExchange.Name(), symbol, interval, result.Deleted, result.Inserted, len(result.Marked))
+
+				for _, gap := range result.Report.Gaps {
+					log.Errorf("%s %s %s klines are still missing %s", sourceExchange.Name(), symbol, interval, gap)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 func confirmation(s string) bool {
 	reader := bufio.NewReader(os.Stdin)
 	for {
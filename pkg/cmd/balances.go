@@ -3,15 +3,18 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/types"
 )
 
 func init() {
 	balancesCmd.Flags().String("session", "", "the exchange session name for querying balances")
+	balancesCmd.Flags().String("reporting-currency", "", "the currency that the total balance value is converted into, e.g. USD, TWD or BTC")
 	RootCmd.AddCommand(balancesCmd)
 }
 
@@ -31,12 +34,22 @@ var balancesCmd = &cobra.Command{
 			return err
 		}
 
+		reportingCurrency, err := cmd.Flags().GetString("reporting-currency")
+		if err != nil {
+			return err
+		}
+
 		environ := bbgo.NewEnvironment()
 
 		if err := environ.ConfigureExchangeSessions(userConfig); err != nil {
 			return err
 		}
 
+		if len(reportingCurrency) > 0 {
+			environ.SetReportingCurrency(reportingCurrency)
+		}
+
+		totalBalances := types.BalanceMap{}
 		if len(sessionName) > 0 {
 			session, ok := environ.Session(sessionName)
 			if !ok {
@@ -49,6 +62,7 @@ var balancesCmd = &cobra.Command{
 			}
 
 			b.Print()
+			totalBalances = totalBalances.Add(b)
 		} else {
 			for _, session := range environ.Sessions() {
 
@@ -59,9 +73,18 @@ var balancesCmd = &cobra.Command{
 
 				log.Infof("SESSION %s", session.Name)
 				b.Print()
+				totalBalances = totalBalances.Add(b)
 			}
 		}
 
+		// the prices are routed through the markets of all sessions
+		if err := environ.UpdateReportingPrices(ctx, totalBalances.Currencies()); err != nil {
+			return err
+		}
+
+		assets := environ.ConvertAssets(totalBalances.Assets(nil, time.Now()))
+		log.Infof("TOTAL (%s)", environ.ReportingCurrency())
+		fmt.Print(assets.PlainText())
 		return nil
 	},
 }
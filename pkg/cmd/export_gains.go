@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/accounting/pnl"
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func init() {
+	ExportGainsCmd.Flags().String("session", "", "target exchange session")
+	ExportGainsCmd.Flags().String("reporting-currency", "USDT", "the currency of the proceeds, the cost basis and the gains")
+	ExportGainsCmd.Flags().String("method", "fifo", "cost basis method: fifo, lifo or hifo (highest cost first)")
+	ExportGainsCmd.Flags().String("since", "", "export the disposals since the date (UTC), format: 2006-01-02")
+	ExportGainsCmd.Flags().String("until", "", "export the disposals before the date (UTC), format: 2006-01-02")
+	ExportGainsCmd.Flags().String("interval", "1d", "the interval of the stored klines used for the historical prices")
+	ExportGainsCmd.Flags().StringP("output", "o", "", "the output csv file, defaults to stdout")
+	RootCmd.AddCommand(ExportGainsCmd)
+}
+
+// ExportGainsCmd exports the realized gains from the synchronized trades, deposits, withdrawals and rewards.
+// The historical prices are looked up from the stored klines, which can be synchronized by the backtest command.
+//
+// go run ./cmd/bbgo export-gains --session binance --since 2021-01-01 --until 2022-01-01 -o gains-2021.csv
+var ExportGainsCmd = &cobra.Command{
+	Use:          "export-gains",
+	Short:        "export the realized gains per disposal as csv",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		configFile, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		if len(configFile) == 0 {
+			return errors.New("--config option is required")
+		}
+
+		if _, err := os.Stat(configFile); os.IsNotExist(err) {
+			return err
+		}
+
+		userConfig, err := bbgo.Load(configFile, false)
+		if err != nil {
+			return err
+		}
+
+		sessionName, err := cmd.Flags().GetString("session")
+		if err != nil {
+			return err
+		}
+
+		reportingCurrency, err := cmd.Flags().GetString("reporting-currency")
+		if err != nil {
+			return err
+		}
+
+		methodStr, err := cmd.Flags().GetString("method")
+		if err != nil {
+			return err
+		}
+
+		method, err := pnl.ParseCostBasisMethod(methodStr)
+		if err != nil {
+			return err
+		}
+
+		if method == pnl.CostBasisAverage {
+			return errors.New("the average cost method can not be used for the gains export, use fifo, lifo or hifo")
+		}
+
+		intervalStr, err := cmd.Flags().GetString("interval")
+		if err != nil {
+			return err
+		}
+
+		interval := types.Interval(intervalStr)
+		if _, ok := types.SupportedIntervals[interval]; !ok {
+			return fmt.Errorf("unsupported interval %s", intervalStr)
+		}
+
+		since, err := parseDateFlag(cmd, "since")
+		if err != nil {
+			return err
+		}
+
+		until, err := parseDateFlag(cmd, "until")
+		if err != nil {
+			return err
+		}
+
+		outputFile, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		environ := bbgo.NewEnvironment()
+
+		if err := environ.ConfigureDatabase(ctx); err != nil {
+			return err
+		}
+
+		if environ.DatabaseService == nil {
+			return errors.New("database is not configured, the trades, the deposits, the withdrawals and the rewards are loaded from the database")
+		}
+
+		if err := environ.ConfigureExchangeSessions(userConfig); err != nil {
+			return err
+		}
+
+		session, ok := environ.Session(sessionName)
+		if !ok {
+			return fmt.Errorf("session %s not found", sessionName)
+		}
+
+		if err = environ.Init(ctx); err != nil {
+			return err
+		}
+
+		exchangeName := session.Exchange.Name()
+		db := environ.DatabaseService.DB
+
+		trades, err := environ.TradeService.Query(service.QueryTradesOptions{
+			Exchange: exchangeName,
+		})
+		if err != nil {
+			return err
+		}
+
+		deposits, err := (&service.DepositService{DB: db}).Query(exchangeName)
+		if err != nil {
+			return err
+		}
+
+		withdraws, err := (&service.WithdrawService{DB: db}).Query(exchangeName)
+		if err != nil {
+			return err
+		}
+
+		rewards, err := environ.RewardService.Query(ctx, exchangeName)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("loaded %d trades, %d deposits, %d withdrawals and %d rewards", len(trades), len(deposits), len(withdraws), len(rewards))
+
+		calculator := &pnl.GainsCalculator{
+			ReportingCurrency: reportingCurrency,
+			Method:            method,
+			Markets:           session.Markets(),
+			Prices: &service.KLinePriceService{
+				Backtest: &service.BacktestService{DB: db},
+				Exchange: exchangeName,
+				Interval: interval,
+			},
+		}
+
+		records, err := calculator.Calculate(trades, deposits, withdraws, rewards)
+		if err != nil {
+			return err
+		}
+
+		// all the records are replayed for the lots, only the disposals in the time range are exported
+		var exported []pnl.GainRecord
+		for _, record := range records {
+			if !since.IsZero() && record.DateSold.Before(since) {
+				continue
+			}
+
+			if !until.IsZero() && !record.DateSold.Before(until) {
+				continue
+			}
+
+			exported = append(exported, record)
+		}
+
+		var writer io.Writer = os.Stdout
+		if len(outputFile) > 0 {
+			f, err := os.Create(outputFile)
+			if err != nil {
+				return err
+			}
+
+			defer f.Close()
+			writer = f
+		}
+
+		if err := pnl.WriteGainsCSV(writer, exported); err != nil {
+			return err
+		}
+
+		log.Infof("exported %d gain records in %s", len(exported), reportingCurrency)
+		return nil
+	},
+}
+
+func parseDateFlag(cmd *cobra.Command, name string) (time.Time, error) {
+	s, err := cmd.Flags().GetString(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(s) == 0 {
+		return time.Time{}, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", s, time.UTC)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid --%s date", name)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func init() {
+	ImportKLinesCmd.Flags().String("exchange", "binance", "the exchange of the kline table that the klines are imported into")
+	ImportKLinesCmd.Flags().String("symbol", "", "the symbol of the klines, parsed from the binance file name if not given")
+	ImportKLinesCmd.Flags().String("interval", "", "the interval of the klines, parsed from the binance file name if not given")
+	ImportKLinesCmd.Flags().String("layout", "binance", "the csv layout: binance or generic")
+	ImportKLinesCmd.Flags().String("columns", "start_time,open,high,low,close,volume", "the comma separated column names of the generic layout, use - for the ignored columns")
+	ImportKLinesCmd.Flags().String("time-format", "", "the time format of the generic layout: s, ms, us or a Go time layout, detected by default")
+	ImportKLinesCmd.Flags().Int("batch-size", 500, "the number of klines inserted in one statement")
+	RootCmd.AddCommand(ImportKLinesCmd)
+}
+
+// ImportKLinesCmd imports the klines from the local csv files or zip archives, e.g. the binance public data archives,
+// which is much faster than syncing the klines from the exchange API.
+//
+// go run ./cmd/bbgo import-klines ./data/spot/monthly/klines/BTCUSDT/1m
+// go run ./cmd/bbgo import-klines --exchange ftx --symbol BTCUSDT --interval 1h --layout generic --columns "start_time,open,high,low,close,volume" ftx-btcusdt-1h.csv
+var ImportKLinesCmd = &cobra.Command{
+	Use:          "import-klines [FILE|DIRECTORY]...",
+	Short:        "import the klines from the csv files or the zip archives into the backtest kline tables",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		exchangeNameStr, err := cmd.Flags().GetString("exchange")
+		if err != nil {
+			return err
+		}
+
+		exchangeName, err := types.ValidExchangeName(exchangeNameStr)
+		if err != nil {
+			return err
+		}
+
+		symbol, err := cmd.Flags().GetString("symbol")
+		if err != nil {
+			return err
+		}
+
+		intervalStr, err := cmd.Flags().GetString("interval")
+		if err != nil {
+			return err
+		}
+
+		layoutName, err := cmd.Flags().GetString("layout")
+		if err != nil {
+			return err
+		}
+
+		batchSize, err := cmd.Flags().GetInt("batch-size")
+		if err != nil {
+			return err
+		}
+
+		var layout service.KLineCSVLayout
+		switch layoutName {
+		case "binance":
+			layout = service.BinanceKLineCSVLayout
+
+		case "generic":
+			columns, err := cmd.Flags().GetString("columns")
+			if err != nil {
+				return err
+			}
+
+			timeFormat, err := cmd.Flags().GetString("time-format")
+			if err != nil {
+				return err
+			}
+
+			layout, err = service.ParseKLineCSVLayout(columns, timeFormat)
+			if err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("unsupported csv layout: %s", layoutName)
+		}
+
+		files, err := collectKLineFiles(args)
+		if err != nil {
+			return err
+		}
+
+		environ := bbgo.NewEnvironment()
+		if err := environ.ConfigureDatabase(ctx); err != nil {
+			return err
+		}
+
+		if environ.DatabaseService == nil {
+			return errors.New("database is not configured, the klines are imported into the database")
+		}
+
+		importer := &service.KLineImporter{
+			Backtest:  &service.BacktestService{DB: environ.DatabaseService.DB},
+			BatchSize: batchSize,
+		}
+
+		for _, file := range files {
+			fileSymbol, interval := strings.ToUpper(symbol), types.Interval(intervalStr)
+			if len(fileSymbol) == 0 || len(interval) == 0 {
+				s, i, ok := service.ParseBinanceKLineFileName(file)
+				if !ok {
+					return fmt.Errorf("can not parse the symbol and the interval from the file name %s, please specify --symbol and --interval", file)
+				}
+
+				if len(fileSymbol) == 0 {
+					fileSymbol = s
+				}
+
+				if len(interval) == 0 {
+					interval = i
+				}
+			}
+
+			if _, ok := types.SupportedIntervals[interval]; !ok {
+				return fmt.Errorf("unsupported interval: %s", interval)
+			}
+
+			klines, err := service.ReadKLineFile(file, layout, exchangeName, fileSymbol, interval)
+			if err != nil {
+				return errors.Wrapf(err, "can not read the klines from %s", file)
+			}
+
+			result, err := importer.Import(ctx, klines)
+			if err != nil {
+				return errors.Wrapf(err, "can not import the klines from %s", file)
+			}
+
+			log.Infof("%s: %s %s parsed %d, inserted %d, duplicated %d",
+				file, fileSymbol, interval, result.Parsed, result.Inserted, result.Duplicated)
+
+			for _, gap := range result.Gaps {
+				log.Warnf("%s: %s %s klines are not continuous, missing %s", file, fileSymbol, interval, gap)
+			}
+		}
+
+		return nil
+	},
+}
+
+// collectKLineFiles collects the csv and the zip files from the arguments, the directories are walked recursively
+func collectKLineFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+
+		err = filepath.Walk(arg, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".csv", ".zip":
+				if !info.IsDir() {
+					files = append(files, path)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
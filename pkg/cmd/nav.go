@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func init() {
+	navCmd.Flags().String("session", "", "filter the nav history by the session name")
+	navCmd.Flags().String("exchange", "", "filter the nav history by the exchange name")
+	navCmd.Flags().String("currency", "", "filter the nav history by the currency")
+	navCmd.Flags().String("since", "", "show the nav history since the date (UTC), format: 2006-01-02")
+	navCmd.Flags().String("interval", "1d", "the resampling interval, e.g. 1h, 1d")
+	navCmd.Flags().String("group-by", "", "group the nav history by session, exchange or currency")
+	navCmd.Flags().Bool("series", false, "print the resampled nav series")
+	RootCmd.AddCommand(navCmd)
+}
+
+// navCmd prints the returns of the net asset value recorded by the xnav strategy.
+//
+// go run ./cmd/bbgo nav --interval 1h --series
+var navCmd = &cobra.Command{
+	Use:          "nav",
+	Short:        "show the net asset value history and the returns",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		sessionName, err := cmd.Flags().GetString("session")
+		if err != nil {
+			return err
+		}
+
+		exchangeName, err := cmd.Flags().GetString("exchange")
+		if err != nil {
+			return err
+		}
+
+		currency, err := cmd.Flags().GetString("currency")
+		if err != nil {
+			return err
+		}
+
+		intervalStr, err := cmd.Flags().GetString("interval")
+		if err != nil {
+			return err
+		}
+
+		groupBy, err := cmd.Flags().GetString("group-by")
+		if err != nil {
+			return err
+		}
+
+		showSeries, err := cmd.Flags().GetBool("series")
+		if err != nil {
+			return err
+		}
+
+		since, err := parseDateFlag(cmd, "since")
+		if err != nil {
+			return err
+		}
+
+		environ := bbgo.NewEnvironment()
+		if err := environ.ConfigureDatabase(ctx); err != nil {
+			return err
+		}
+
+		if environ.NavService == nil {
+			return errors.New("database is not configured, the nav history is loaded from the database")
+		}
+
+		options := service.QueryNavOptions{
+			Session:  sessionName,
+			Exchange: types.ExchangeName(exchangeName),
+			Currency: currency,
+			Since:    since,
+			Interval: types.Interval(intervalStr),
+			GroupBy:  service.NavGroupBy(groupBy),
+		}
+
+		points, err := environ.NavService.Query(ctx, options)
+		if err != nil {
+			return err
+		}
+
+		if len(points) == 0 {
+			fmt.Println("no nav history found, the nav history is recorded by the xnav strategy")
+			return nil
+		}
+
+		if showSeries || options.GroupBy != service.NavGroupByNone {
+			fmt.Printf("%-20s %-12s %20s %16s %24s\n", "TIME", "GROUP", "IN USD", "IN BTC", "IN REPORTING CURRENCY")
+			for _, p := range points {
+				inReportingCurrency := "-"
+				if p.ReportingCurrency != "" {
+					inReportingCurrency = p.InReportingCurrency.FormatString(2) + " " + p.ReportingCurrency
+				}
+
+				fmt.Printf("%-20s %-12s %20s %16s %24s\n", p.Time.UTC().Format("2006-01-02 15:04"), p.Group, p.InUSD.FormatString(2), p.InBTC.FormatString(8), inReportingCurrency)
+			}
+		}
+
+		if options.GroupBy != service.NavGroupByNone {
+			return nil
+		}
+
+		fmt.Printf("\n%-6s %-8s %-12s %-12s %20s %20s %16s %10s\n", "RANGE", "CURRENCY", "START", "END", "START VALUE", "END VALUE", "CHANGE", "RETURN")
+		for _, r := range service.CalculateNavReturns(points, service.DefaultNavReturnRanges) {
+			fmt.Printf("%-6s %-8s %-12s %-12s %20s %20s %16s %10s\n",
+				r.Range,
+				r.Currency,
+				r.StartTime.UTC().Format("2006-01-02"),
+				r.EndTime.UTC().Format("2006-01-02"),
+				r.StartValue.FormatString(2),
+				r.EndValue.FormatString(2),
+				r.Change.FormatString(2),
+				r.Return.FormatPercentage(2))
+		}
+
+		fmt.Printf("\nlast updated at %s\n", points[len(points)-1].Time.UTC().Format(time.RFC3339))
+		return nil
+	},
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -14,6 +15,7 @@ import (
 	"github.com/c9s/bbgo/pkg/accounting"
 	"github.com/c9s/bbgo/pkg/accounting/pnl"
 	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/service"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -22,7 +24,11 @@ func init() {
 	PnLCmd.Flags().String("session", "", "target exchange")
 	PnLCmd.Flags().String("symbol", "", "trading symbol")
 	PnLCmd.Flags().Bool("include-transfer", false, "convert transfer records into trades")
+	PnLCmd.Flags().Bool("include-funding", false, "sync and include the funding fees and the isolated margin interests in the net profit")
 	PnLCmd.Flags().Int("limit", 500, "number of trades")
+	PnLCmd.Flags().Bool("by-strategy", false, "break down the pnl by the strategy instances recorded in the database")
+	PnLCmd.Flags().String("method", "average", "cost basis method: average, fifo, lifo or hifo (highest cost first)")
+	PnLCmd.Flags().String("reporting-currency", "", "the currency that the profits are converted into, e.g. USD, TWD or BTC")
 	RootCmd.AddCommand(PnLCmd)
 }
 
@@ -70,6 +76,16 @@ var PnLCmd = &cobra.Command{
 			return err
 		}
 
+		methodStr, err := cmd.Flags().GetString("method")
+		if err != nil {
+			return err
+		}
+
+		method, err := pnl.ParseCostBasisMethod(methodStr)
+		if err != nil {
+			return err
+		}
+
 		environ := bbgo.NewEnvironment()
 
 		if err := environ.ConfigureDatabase(ctx); err != nil {
@@ -80,6 +96,15 @@ var PnLCmd = &cobra.Command{
 			return err
 		}
 
+		reportingCurrency, err := cmd.Flags().GetString("reporting-currency")
+		if err != nil {
+			return err
+		}
+
+		if len(reportingCurrency) > 0 {
+			environ.SetReportingCurrency(reportingCurrency)
+		}
+
 		session, ok := environ.Session(sessionName)
 		if !ok {
 			return fmt.Errorf("session %s not found", sessionName)
@@ -133,6 +158,35 @@ var PnLCmd = &cobra.Command{
 			}
 		}
 
+		includeFunding, err := cmd.Flags().GetBool("include-funding")
+		if err != nil {
+			return err
+		}
+
+		var fundingFees []types.FundingFee
+		var interests []types.MarginInterest
+		if includeFunding {
+			if err := environ.SyncService.SyncFundingFeeHistory(ctx, exchange); err != nil {
+				return err
+			}
+
+			if err := environ.SyncService.SyncMarginInterestHistory(ctx, exchange); err != nil {
+				return err
+			}
+
+			fundingFees, err = environ.FundingFeeService.Query(ctx, exchange.Name(), symbol, since, until)
+			if err != nil {
+				return err
+			}
+
+			interests, err = environ.MarginInterestService.Query(ctx, exchange.Name(), []string{market.BaseCurrency, market.QuoteCurrency}, since, until)
+			if err != nil {
+				return err
+			}
+
+			log.Infof("%d funding fees and %d margin interests loaded", len(fundingFees), len(interests))
+		}
+
 		var trades []types.Trade
 		tradingFeeCurrency := exchange.PlatformFeeCurrency()
 		if strings.HasPrefix(symbol, tradingFeeCurrency) {
@@ -179,12 +233,118 @@ var PnLCmd = &cobra.Command{
 
 		currentPrice := currentTick.Last
 
+		// the fees paid in other currencies are converted by the stored klines
+		priceLookup := &service.KLinePriceService{
+			Backtest: &service.BacktestService{DB: environ.DatabaseService.DB},
+			Exchange: exchange.Name(),
+		}
+
+		if method != pnl.CostBasisAverage {
+			calculator := &pnl.LotCostCalculator{
+				TradingFeeCurrency: tradingFeeCurrency,
+				Market:             market,
+				Method:             method,
+				PriceLookup:        priceLookup,
+				FundingFees:        fundingFees,
+				Interests:          interests,
+			}
+
+			report := calculator.Calculate(symbol, trades, currentPrice)
+			report.Print()
+
+			if err := printReportingProfits(ctx, environ, market.QuoteCurrency, report.NetProfit, report.UnrealizedProfit); err != nil {
+				return err
+			}
+
+			return printStrategyProfits(cmd, environ, exchange.Name(), symbol, currentPrice)
+		}
+
 		calculator := &pnl.AverageCostCalculator{
 			TradingFeeCurrency: tradingFeeCurrency,
+			Market:             market,
+			PriceLookup:        priceLookup,
+			FundingFees:        fundingFees,
+			Interests:          interests,
 		}
 
 		report := calculator.Calculate(symbol, trades, currentPrice)
 		report.Print()
-		return nil
+
+		if err := printReportingProfits(ctx, environ, market.QuoteCurrency, report.NetProfit, report.UnrealizedProfit); err != nil {
+			return err
+		}
+
+		return printStrategyProfits(cmd, environ, exchange.Name(), symbol, currentPrice)
 	},
 }
+
+// printReportingProfits prints the net profit and the unrealized profit converted into the reporting currency,
+// the conversion is routed through the markets of all sessions.
+func printReportingProfits(ctx context.Context, environ *bbgo.Environment, quoteCurrency string, netProfit, unrealizedProfit fixedpoint.Value) error {
+	reportingCurrency := environ.ReportingCurrency()
+	if reportingCurrency == quoteCurrency {
+		return nil
+	}
+
+	if err := environ.UpdateReportingPrices(ctx, []string{quoteCurrency}); err != nil {
+		return err
+	}
+
+	rate, ok := environ.ConversionGraph().Rate(quoteCurrency, reportingCurrency)
+	if !ok {
+		log.Warnf("can not convert %s into the reporting currency %s", quoteCurrency, reportingCurrency)
+		return nil
+	}
+
+	color.Green("NET PROFIT IN %s: %s", reportingCurrency, types.FormatMoney(reportingCurrency, netProfit.Mul(rate)))
+	color.Green("UNREALIZED PROFIT IN %s: %s", reportingCurrency, types.FormatMoney(reportingCurrency, unrealizedProfit.Mul(rate)))
+	return nil
+}
+
+// printStrategyProfits prints the pnl of each strategy instance if the --by-strategy flag is set,
+// the unrealized profit is calculated by the current price.
+func printStrategyProfits(cmd *cobra.Command, environ *bbgo.Environment, exchangeName types.ExchangeName, symbol string, currentPrice fixedpoint.Value) error {
+	byStrategy, err := cmd.Flags().GetBool("by-strategy")
+	if err != nil {
+		return err
+	}
+
+	if !byStrategy {
+		return nil
+	}
+
+	summaries, err := environ.ProfitService.QueryStrategyProfits(context.Background(), service.QueryStrategyProfitOptions{
+		Exchange: exchangeName,
+		Symbol:   symbol,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(summaries) == 0 {
+		fmt.Println("no strategy position records found, the records are saved by the strategies with the database configured")
+		return nil
+	}
+
+	fmt.Printf("\n%-40s %8s %16s %16s %14s %14s %14s %14s\n", "STRATEGY INSTANCE", "TRADES", "VOLUME", "QUOTE VOLUME", "FEE", "PROFIT", "NET PROFIT", "UNREALIZED")
+	for _, summary := range summaries {
+		summary.UpdateUnrealizedProfit(currentPrice)
+
+		instanceID := summary.StrategyInstanceID
+		if instanceID == "" {
+			instanceID = summary.Strategy
+		}
+
+		fmt.Printf("%-40s %8d %16s %16s %14s %14s %14s %14s\n",
+			instanceID,
+			summary.NumTrades,
+			summary.Volume.String(),
+			summary.QuoteVolume.FormatString(2),
+			summary.FeeInQuote.FormatString(4),
+			summary.Profit.FormatString(4),
+			summary.NetProfit.FormatString(4),
+			summary.UnrealizedProfit.FormatString(4))
+	}
+
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/c9s/bbgo/pkg/bbgo"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 )
 
 var userConfig *bbgo.Config
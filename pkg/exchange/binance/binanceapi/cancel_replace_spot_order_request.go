@@ -0,0 +1,69 @@
+package binanceapi
+
+import (
+	"encoding/json"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type CancelReplaceMode string
+
+const (
+	CancelReplaceModeStopOnFailure CancelReplaceMode = "STOP_ON_FAILURE"
+	CancelReplaceModeAllowFailure  CancelReplaceMode = "ALLOW_FAILURE"
+)
+
+type SpotOrderResponse struct {
+	Symbol                   string                     `json:"symbol"`
+	OrderID                  int64                      `json:"orderId"`
+	ClientOrderID            string                     `json:"clientOrderId"`
+	OrigClientOrderID        string                     `json:"origClientOrderId,omitempty"`
+	TransactTime             types.MillisecondTimestamp `json:"transactTime"`
+	Price                    fixedpoint.Value           `json:"price"`
+	OrigQuantity             fixedpoint.Value           `json:"origQty"`
+	ExecutedQuantity         fixedpoint.Value           `json:"executedQty"`
+	CummulativeQuoteQuantity fixedpoint.Value           `json:"cummulativeQuoteQty"`
+	Status                   string                     `json:"status"`
+	TimeInForce              string                     `json:"timeInForce"`
+	Type                     string                     `json:"type"`
+	Side                     string                     `json:"side"`
+}
+
+type CancelReplaceSpotOrderResponse struct {
+	CancelResult     string             `json:"cancelResult"`
+	NewOrderResult   string             `json:"newOrderResult"`
+	CancelResponse   *SpotOrderResponse `json:"cancelResponse"`
+	NewOrderResponse *SpotOrderResponse `json:"newOrderResponse"`
+
+	// Data is only returned when one of the operations fails
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+//go:generate requestgen -method POST -url "/api/v3/order/cancelReplace" -type CancelReplaceSpotOrderRequest -responseType .CancelReplaceSpotOrderResponse
+type CancelReplaceSpotOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	// cancels an existing order and places a new order on the same symbol, see
+	// https://binance-docs.github.io/apidocs/spot/en/#cancel-an-existing-order-and-send-a-new-order-trade
+
+	symbol            string            `param:"symbol,query"`
+	side              string            `param:"side,query"`
+	orderType         string            `param:"type,query"`
+	cancelReplaceMode CancelReplaceMode `param:"cancelReplaceMode,query"`
+	timeInForce       *string           `param:"timeInForce,query"`
+	quantity          *string           `param:"quantity,query"`
+	price             *string           `param:"price,query"`
+	stopPrice         *string           `param:"stopPrice,query"`
+
+	cancelOrderID           *int64  `param:"cancelOrderId,query"`
+	cancelOrigClientOrderID *string `param:"cancelOrigClientOrderId,query"`
+	newClientOrderID        *string `param:"newClientOrderId,query"`
+	newOrderRespType        *string `param:"newOrderRespType,query"`
+}
+
+func (c *RestClient) NewCancelReplaceSpotOrderRequest() *CancelReplaceSpotOrderRequest {
+	return &CancelReplaceSpotOrderRequest{client: c, cancelReplaceMode: CancelReplaceModeStopOnFailure}
+}
@@ -0,0 +1,48 @@
+package binanceapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// MarginInterest is the interest charged for the borrowed margin asset,
+// the type can be PERIODIC (hourly interest), ON_BORROW (the first interest charged on borrow),
+// PERIODIC_CONVERTED or ON_BORROW_CONVERTED (the interest converted into BNB)
+type MarginInterest struct {
+	Asset               string                     `json:"asset"`
+	Interest            fixedpoint.Value           `json:"interest"`
+	InterestAccuredTime types.MillisecondTimestamp `json:"interestAccuredTime"`
+	InterestRate        fixedpoint.Value           `json:"interestRate"`
+	Principal           fixedpoint.Value           `json:"principal"`
+	IsolatedSymbol      string                     `json:"isolatedSymbol"`
+	Type                string                     `json:"type"`
+}
+
+type RowsResponse struct {
+	Rows  json.RawMessage `json:"rows"`
+	Total int             `json:"total"`
+}
+
+// GetMarginInterestHistoryRequest queries the interest charged for the borrowed margin assets.
+// The max interval between startTime and endTime is 30 days, and the max page size is 100.
+//
+//go:generate requestgen -method GET -url "/sapi/v1/margin/interestHistory" -type GetMarginInterestHistoryRequest -responseType RowsResponse -responseDataField Rows -responseDataType []MarginInterest
+type GetMarginInterestHistoryRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	asset          *string    `param:"asset"`
+	isolatedSymbol *string    `param:"isolatedSymbol"`
+	startTime      *time.Time `param:"startTime,milliseconds"`
+	endTime        *time.Time `param:"endTime,milliseconds"`
+	current        *int       `param:"current"`
+	size           *int       `param:"size"`
+}
+
+func (c *RestClient) NewGetMarginInterestHistoryRequest() *GetMarginInterestHistoryRequest {
+	return &GetMarginInterestHistoryRequest{client: c}
+}
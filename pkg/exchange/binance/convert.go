@@ -2,6 +2,7 @@ package binance
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/adshao/go-binance/v2/futures"
 	"github.com/pkg/errors"
 
+	"github.com/c9s/bbgo/pkg/exchange/binance/binanceapi"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -662,3 +664,31 @@ func convertPositionRisk(risk *futures.PositionRisk) (*types.PositionRisk, error
 		LiquidationPrice: liquidationPrice,
 	}, nil
 }
+
+func toGlobalFundingFee(income *futures.IncomeHistory) (*types.FundingFee, error) {
+	amount, err := fixedpoint.NewFromString(income.Income)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.FundingFee{
+		Exchange:      types.ExchangeBinance,
+		Symbol:        income.Symbol,
+		Asset:         income.Asset,
+		Amount:        amount,
+		TransactionID: strconv.FormatInt(income.TranID, 10),
+		Time:          types.Time(millisecondTime(income.Time)),
+	}, nil
+}
+
+func toGlobalMarginInterest(interest binanceapi.MarginInterest) types.MarginInterest {
+	return types.MarginInterest{
+		Exchange:       types.ExchangeBinance,
+		Asset:          interest.Asset,
+		IsolatedSymbol: interest.IsolatedSymbol,
+		Principal:      interest.Principal,
+		Interest:       interest.Interest,
+		InterestRate:   interest.InterestRate,
+		Time:           types.Time(interest.InterestAccuredTime.Time()),
+	}
+}
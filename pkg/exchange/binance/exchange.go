@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/c9s/bbgo/pkg/exchange/binance/binanceapi"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/c9s/bbgo/pkg/util"
@@ -49,6 +52,7 @@ func init() {
 	_ = types.Exchange(&Exchange{})
 	_ = types.MarginExchange(&Exchange{})
 	_ = types.FuturesExchange(&Exchange{})
+	_ = types.ExchangeOrderAmendService(&Exchange{})
 
 	// FIXME: this is not effected since dotenv is loaded in the rootCmd, not in the init function
 	if ok, _ := strconv.ParseBool(os.Getenv("DEBUG_BINANCE_STREAM")); ok {
@@ -77,6 +81,9 @@ type Exchange struct {
 	// futuresClient is used for usdt-m futures
 	futuresClient *futures.Client // USDT-M Futures
 	// deliveryClient	*delivery.Client // Coin-M Futures
+
+	// client2 is a newer version of the binance api client implemented by ourselves.
+	client2 *binanceapi.RestClient
 }
 
 var timeSetter sync.Once
@@ -89,12 +96,19 @@ func New(key, secret string) *Exchange {
 	var futuresClient = binance.NewFuturesClient(key, secret)
 	futuresClient.HTTPClient = &http.Client{Timeout: 15 * time.Second}
 
+	var client2 = binanceapi.NewClient()
+	if len(key) > 0 && len(secret) > 0 {
+		client2.Auth(key, secret)
+	}
+
 	if isBinanceUs() {
 		client.BaseURL = BinanceUSBaseURL
+		client2.BaseURL, _ = url.Parse(BinanceUSBaseURL)
 	}
 
 	if paperTrade() {
 		client.BaseURL = BinanceTestBaseURL
+		client2.BaseURL, _ = url.Parse(BinanceTestBaseURL)
 		futuresClient.BaseURL = FutureTestBaseURL
 	}
 
@@ -110,6 +124,10 @@ func New(key, secret string) *Exchange {
 			if err != nil {
 				log.WithError(err).Error("can not set server time")
 			}
+
+			if err = client2.SetTimeOffsetFromServer(context.Background()); err != nil {
+				log.WithError(err).Error("can not set server time")
+			}
 		})
 	}
 
@@ -118,6 +136,7 @@ func New(key, secret string) *Exchange {
 		secret:        secret,
 		client:        client,
 		futuresClient: futuresClient,
+		client2:       client2,
 		// deliveryClient: deliveryClient,
 	}
 }
@@ -691,17 +710,42 @@ func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders [
 	return toGlobalOrders(binanceOrders)
 }
 
+// QueryOrder queries the order by the order ID, or by the client order ID if the order ID is not given.
 func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
-	orderID, err := strconv.ParseInt(q.OrderID, 10, 64)
-	if err != nil {
-		return nil, err
+	var orderID int64
+	var clientOrderID string
+	if len(q.OrderID) > 0 {
+		var err error
+		orderID, err = strconv.ParseInt(q.OrderID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(q.ClientOrderID) > 0 {
+		clientOrderID = newSpotClientOrderID(q.ClientOrderID)
+	} else {
+		return nil, errors.New("binance: order id or client order id is required for querying the order")
 	}
 
 	var order *binance.Order
+	var err error
 	if e.IsMargin {
-		order, err = e.client.NewGetMarginOrderService().Symbol(q.Symbol).OrderID(orderID).Do(ctx)
+		req := e.client.NewGetMarginOrderService().Symbol(q.Symbol)
+		if orderID > 0 {
+			req.OrderID(orderID)
+		} else {
+			req.OrigClientOrderID(clientOrderID)
+		}
+
+		order, err = req.Do(ctx)
 	} else {
-		order, err = e.client.NewGetOrderService().Symbol(q.Symbol).OrderID(orderID).Do(ctx)
+		req := e.client.NewGetOrderService().Symbol(q.Symbol)
+		if orderID > 0 {
+			req.OrderID(orderID)
+		} else {
+			req.OrigClientOrderID(clientOrderID)
+		}
+
+		order, err = req.Do(ctx)
 	}
 
 	if err != nil {
@@ -870,6 +914,110 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) (err
 	return err
 }
 
+// AmendOrder amends the price or the quantity of an existing spot order through the cancel-replace api,
+// the new order inherits the side, type and time-in-force of the original order.
+func (e *Exchange) AmendOrder(ctx context.Context, amend types.OrderAmend) (*types.Order, error) {
+	if e.IsMargin || e.IsFutures {
+		return nil, types.ErrOrderAmendNotSupported
+	}
+
+	if err := orderLimiter.Wait(ctx); err != nil {
+		log.WithError(err).Errorf("order rate limiter wait error")
+	}
+
+	order := amend.Order
+	orderType, err := toLocalOrderType(order.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	switch order.Type {
+	case types.OrderTypeLimit, types.OrderTypeLimitMaker, types.OrderTypeStopLimit:
+	default:
+		return nil, types.ErrOrderAmendNotSupported
+	}
+
+	req := e.client2.NewCancelReplaceSpotOrderRequest().
+		Symbol(order.Symbol).
+		Side(string(order.Side)).
+		OrderType(string(orderType)).
+		NewOrderRespType(string(binance.NewOrderRespTypeRESULT))
+
+	if order.OrderID > 0 {
+		req.CancelOrderID(int64(order.OrderID))
+	} else if len(order.ClientOrderID) > 0 {
+		req.CancelOrigClientOrderID(order.ClientOrderID)
+	} else {
+		return nil, types.NewOrderError(
+			fmt.Errorf("can not amend %s order, order does not contain orderID or clientOrderID", order.Symbol),
+			order)
+	}
+
+	// binance does not allow reusing the client order id, so we generate a new one with the same prefix
+	req.NewClientOrderID(newSpotClientOrderID(""))
+
+	quantity := amend.RemainingQuantity()
+	price := amend.NewPrice()
+	if order.Market.Symbol != "" {
+		req.Quantity(order.Market.FormatQuantity(quantity))
+		req.Price(order.Market.FormatPrice(price))
+	} else {
+		req.Quantity(quantity.FormatString(8))
+		req.Price(price.FormatString(8))
+	}
+
+	if order.Type == types.OrderTypeStopLimit {
+		if order.Market.Symbol != "" {
+			req.StopPrice(order.Market.FormatPrice(order.StopPrice))
+		} else {
+			req.StopPrice(order.StopPrice.FormatString(8))
+		}
+	}
+
+	if order.Type != types.OrderTypeLimitMaker {
+		if len(order.TimeInForce) > 0 {
+			req.TimeInForce(string(order.TimeInForce))
+		} else {
+			req.TimeInForce(string(binance.TimeInForceTypeGTC))
+		}
+	}
+
+	response, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.NewOrderResponse == nil {
+		return nil, fmt.Errorf("binance cancel-replace failed, cancel result: %s, new order result: %s, data: %s",
+			response.CancelResult, response.NewOrderResult, string(response.Data))
+	}
+
+	log.Infof("spot order cancel-replace response: %+v", response)
+
+	o := response.NewOrderResponse
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: o.ClientOrderID,
+			Symbol:        o.Symbol,
+			Side:          toGlobalSideType(binance.SideType(o.Side)),
+			Type:          toGlobalOrderType(binance.OrderType(o.Type)),
+			Quantity:      o.OrigQuantity,
+			Price:         o.Price,
+			StopPrice:     order.StopPrice,
+			TimeInForce:   types.TimeInForce(o.TimeInForce),
+			Market:        order.Market,
+			GroupID:       order.GroupID,
+		},
+		Exchange:         types.ExchangeBinance,
+		OrderID:          uint64(o.OrderID),
+		Status:           toGlobalOrderStatus(binance.OrderStatusType(o.Status)),
+		ExecutedQuantity: o.ExecutedQuantity,
+		IsWorking:        true,
+		CreationTime:     types.Time(o.TransactTime.Time()),
+		UpdateTime:       types.Time(o.TransactTime.Time()),
+	}, nil
+}
+
 func (e *Exchange) submitMarginOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
 	orderType, err := toLocalOrderType(order.Type)
 	if err != nil {
@@ -1062,6 +1210,11 @@ func newSpotClientOrderID(originalID string) (clientOrderID string) {
 	prefixLen := len(prefix)
 
 	if originalID != "" {
+		// the client order ID is already prefixed, e.g., the ID of a submitted order
+		if strings.HasPrefix(originalID, prefix) {
+			return originalID
+		}
+
 		// try to keep the whole original client order ID if user specifies it.
 		if prefixLen+len(originalID) > 32 {
 			return originalID
@@ -1092,6 +1245,11 @@ func newFuturesClientOrderID(originalID string) (clientOrderID string) {
 	prefixLen := len(prefix)
 
 	if originalID != "" {
+		// the client order ID is already prefixed, e.g., the ID of a submitted order
+		if strings.HasPrefix(originalID, prefix) {
+			return originalID
+		}
+
 		// try to keep the whole original client order ID if user specifies it.
 		if prefixLen+len(originalID) > 32 {
 			return originalID
@@ -1532,6 +1690,111 @@ func (e *Exchange) QueryFundingRateHistory(ctx context.Context, symbol string) (
 	}, nil
 }
 
+// QueryFundingFeeHistory queries the funding payments from the futures income history,
+// only the recent 3 months are available.
+func (e *Exchange) QueryFundingFeeHistory(ctx context.Context, symbol string, since, until time.Time) (fees []types.FundingFee, err error) {
+	const limit = 1000
+
+	if since.IsZero() {
+		since = until.AddDate(0, -3, 0)
+	}
+
+	startTime := since
+	for startTime.Before(until) {
+		// the income history returns at most 7 days of the records in one query
+		endTime := startTime.AddDate(0, 0, 7)
+		if endTime.After(until) {
+			endTime = until
+		}
+
+		incomes, err := e.futuresClient.NewGetIncomeHistoryService().
+			Symbol(symbol).
+			IncomeType("FUNDING_FEE").
+			StartTime(startTime.UnixNano() / int64(time.Millisecond)).
+			EndTime(endTime.UnixNano() / int64(time.Millisecond)).
+			Limit(limit).
+			Do(ctx)
+		if err != nil {
+			return fees, err
+		}
+
+		for _, income := range incomes {
+			fee, err := toGlobalFundingFee(income)
+			if err != nil {
+				return fees, err
+			}
+
+			fees = append(fees, *fee)
+		}
+
+		// the window is not fully loaded, continue from the last record
+		if len(incomes) == limit {
+			startTime = millisecondTime(incomes[len(incomes)-1].Time + 1)
+			continue
+		}
+
+		startTime = endTime
+	}
+
+	return fees, nil
+}
+
+// QueryInterestHistory queries the interest charged for the borrowed margin assets,
+// the records older than 6 months are archived and not queried.
+func (e *Exchange) QueryInterestHistory(ctx context.Context, asset string, since, until time.Time) (interests []types.MarginInterest, err error) {
+	const pageSize = 100
+
+	if since.IsZero() {
+		since = until.AddDate(0, -6, 0)
+	}
+
+	startTime := since
+	for startTime.Before(until) {
+		// startTime ~ endTime must be in 30 days
+		endTime := startTime.AddDate(0, 0, 30)
+		if endTime.After(until) {
+			endTime = until
+		}
+
+		for page := 1; ; page++ {
+			req := e.client2.NewGetMarginInterestHistoryRequest()
+			if len(asset) > 0 {
+				req.Asset(asset)
+			}
+
+			if e.IsIsolatedMargin {
+				req.IsolatedSymbol(e.IsolatedMarginSymbol)
+			}
+
+			records, err := req.
+				StartTime(startTime).
+				EndTime(endTime).
+				Current(page).
+				Size(pageSize).
+				Do(ctx)
+			if err != nil {
+				return interests, err
+			}
+
+			for _, record := range records {
+				interests = append(interests, toGlobalMarginInterest(record))
+			}
+
+			if len(records) < pageSize {
+				break
+			}
+		}
+
+		startTime = endTime
+	}
+
+	sort.Slice(interests, func(i, j int) bool {
+		return interests[i].Time.Time().Before(interests[j].Time.Time())
+	})
+
+	return interests, nil
+}
+
 func (e *Exchange) QueryPositionRisk(ctx context.Context, symbol string) (*types.PositionRisk, error) {
 	futuresClient := binance.NewFuturesClient(e.key, e.secret)
 
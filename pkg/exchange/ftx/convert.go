@@ -2,6 +2,7 @@ package ftx
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -247,3 +248,26 @@ func toLocalOrderType(orderType types.OrderType) (ftxapi.OrderType, error) {
 
 	return "", fmt.Errorf("order type %s not supported", orderType)
 }
+
+func toGlobalFundingFee(input fundingPayment) types.FundingFee {
+	return types.FundingFee{
+		Exchange: types.ExchangeFTX,
+		Symbol:   toGlobalSymbol(input.Future),
+		// FTX settles the funding payments in USD
+		Asset:         "USD",
+		Amount:        input.Payment.Neg(),
+		TransactionID: strconv.FormatInt(input.ID, 10),
+		Time:          types.Time(input.Time.Time),
+	}
+}
+
+func toGlobalMarginInterest(input borrowHistory) types.MarginInterest {
+	return types.MarginInterest{
+		Exchange:     types.ExchangeFTX,
+		Asset:        toGlobalCurrency(input.Coin),
+		Principal:    input.Size,
+		Interest:     input.Cost,
+		InterestRate: input.Rate,
+		Time:         types.Time(input.Time.Time),
+	}
+}
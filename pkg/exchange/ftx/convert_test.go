@@ -102,6 +102,20 @@ func Test_toGlobalSymbol(t *testing.T) {
 	assert.Equal(t, "BTCUSDT", toGlobalSymbol("BTC/USDT"))
 }
 
+func Test_toGlobalFundingFee(t *testing.T) {
+	var payment fundingPayment
+	err := json.Unmarshal([]byte(`{"future": "ETH-PERP", "id": 33830, "payment": 0.0441342, "time": "2019-05-15T18:00:00+00:00", "rate": 0.0001}`), &payment)
+	assert.NoError(t, err)
+
+	fee := toGlobalFundingFee(payment)
+	assert.Equal(t, types.ExchangeFTX, fee.Exchange)
+	assert.Equal(t, "ETH-PERP", fee.Symbol)
+	assert.Equal(t, "USD", fee.Asset)
+	assert.Equal(t, "33830", fee.TransactionID)
+	// the positive payment is paid by the user
+	assert.Equal(t, "-0.0441342", fee.Amount.String())
+}
+
 func Test_toLocalOrderTypeWithLimitMaker(t *testing.T) {
 	orderType, err := toLocalOrderType(types.OrderTypeLimitMaker)
 	assert.NoError(t, err)
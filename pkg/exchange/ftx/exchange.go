@@ -428,6 +428,98 @@ func (e *Exchange) QueryDepositHistory(ctx context.Context, asset string, since,
 	return
 }
 
+// QueryFundingFeeHistory queries the funding payments of the futures, the records are paginated by the end time
+func (e *Exchange) QueryFundingFeeHistory(ctx context.Context, symbol string, since, until time.Time) (fees []types.FundingFee, err error) {
+	if until == (time.Time{}) {
+		until = time.Now()
+	}
+
+	ids := map[int64]struct{}{}
+	for {
+		resp, err := e.newRest().FundingPayments(ctx, toLocalSymbol(symbol), since, until)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success {
+			return nil, fmt.Errorf("ftx returns failure")
+		}
+
+		numNew := 0
+		for _, r := range resp.Result {
+			if _, ok := ids[r.ID]; ok {
+				continue
+			}
+
+			ids[r.ID] = struct{}{}
+			fees = append(fees, toGlobalFundingFee(r))
+			numNew++
+
+			if r.Time.Before(until) {
+				until = r.Time.Time
+			}
+		}
+
+		if numNew == 0 || !until.After(since) {
+			break
+		}
+	}
+
+	sort.Slice(fees, func(i, j int) bool {
+		return fees[i].Time.Time().Before(fees[j].Time.Time())
+	})
+	return fees, nil
+}
+
+// QueryInterestHistory queries the interest charged for the spot margin borrows, the records are paginated by the end time
+func (e *Exchange) QueryInterestHistory(ctx context.Context, asset string, since, until time.Time) (interests []types.MarginInterest, err error) {
+	if until == (time.Time{}) {
+		until = time.Now()
+	}
+	asset = TrimUpperString(asset)
+
+	keys := map[string]struct{}{}
+	for {
+		resp, err := e.newRest().BorrowHistory(ctx, since, until)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success {
+			return nil, fmt.Errorf("ftx returns failure")
+		}
+
+		numNew := 0
+		for _, r := range resp.Result {
+			key := r.Coin + r.Time.String()
+			if _, ok := keys[key]; ok {
+				continue
+			}
+
+			keys[key] = struct{}{}
+			numNew++
+
+			if r.Time.Before(until) {
+				until = r.Time.Time
+			}
+
+			interest := toGlobalMarginInterest(r)
+			if len(asset) > 0 && interest.Asset != asset {
+				continue
+			}
+
+			interests = append(interests, interest)
+		}
+
+		if numNew == 0 || !until.After(since) {
+			break
+		}
+	}
+
+	sort.Slice(interests, func(i, j int) bool {
+		return interests[i].Time.Time().Before(interests[j].Time.Time())
+	})
+	return interests, nil
+}
+
 func (e *Exchange) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
 	var createdOrders types.OrderSlice
 	// TODO: currently only support limit and market order
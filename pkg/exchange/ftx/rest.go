@@ -56,6 +56,7 @@ type restRequest struct {
 	*walletRequest
 	*marketRequest
 	*transferRequest
+	*fundingRequest
 
 	key, secret string
 	// Optional sub-account name
@@ -87,6 +88,7 @@ func newRestRequest(c *http.Client, baseURL *url.URL) *restRequest {
 
 	r.marketRequest = &marketRequest{restRequest: r}
 	r.walletRequest = &walletRequest{restRequest: r}
+	r.fundingRequest = &fundingRequest{restRequest: r}
 	return r
 }
 
@@ -0,0 +1,71 @@
+package ftx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type fundingRequest struct {
+	*restRequest
+}
+
+func (r *fundingRequest) FundingPayments(ctx context.Context, future string, since, until time.Time) (fundingPaymentsResponse, error) {
+	q := make(map[string]string)
+	if len(future) > 0 {
+		q["future"] = future
+	}
+
+	if since != (time.Time{}) {
+		q["start_time"] = strconv.FormatInt(since.Unix(), 10)
+	}
+	if until != (time.Time{}) {
+		q["end_time"] = strconv.FormatInt(until.Unix(), 10)
+	}
+
+	resp, err := r.
+		Method("GET").
+		ReferenceURL("api/funding_payments").
+		Query(q).
+		DoAuthenticatedRequest(ctx)
+
+	if err != nil {
+		return fundingPaymentsResponse{}, err
+	}
+
+	var p fundingPaymentsResponse
+	if err := json.Unmarshal(resp.Body, &p); err != nil {
+		return fundingPaymentsResponse{}, fmt.Errorf("failed to unmarshal funding payments response body to json: %w", err)
+	}
+
+	return p, nil
+}
+
+func (r *fundingRequest) BorrowHistory(ctx context.Context, since, until time.Time) (borrowHistoryResponse, error) {
+	q := make(map[string]string)
+	if since != (time.Time{}) {
+		q["start_time"] = strconv.FormatInt(since.Unix(), 10)
+	}
+	if until != (time.Time{}) {
+		q["end_time"] = strconv.FormatInt(until.Unix(), 10)
+	}
+
+	resp, err := r.
+		Method("GET").
+		ReferenceURL("api/spot_margin/borrow_history").
+		Query(q).
+		DoAuthenticatedRequest(ctx)
+
+	if err != nil {
+		return borrowHistoryResponse{}, err
+	}
+
+	var b borrowHistoryResponse
+	if err := json.Unmarshal(resp.Body, &b); err != nil {
+		return borrowHistoryResponse{}, fmt.Errorf("failed to unmarshal borrow history response body to json: %w", err)
+	}
+
+	return b, nil
+}
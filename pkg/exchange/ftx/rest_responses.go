@@ -389,3 +389,48 @@ type transfer struct {
 func (t *transfer) String() string {
 	return fmt.Sprintf("%+v", *t)
 }
+
+type fundingPaymentsResponse struct {
+	Success bool             `json:"success"`
+	Result  []fundingPayment `json:"result"`
+}
+
+/*
+	{
+	  "future": "ETH-PERP",
+	  "id": 33830,
+	  "payment": 0.0441342,
+	  "time": "2019-05-15T18:00:00+00:00",
+	  "rate": 0.0001
+	}
+*/
+type fundingPayment struct {
+	ID     int64  `json:"id"`
+	Future string `json:"future"`
+	// Payment is positive when the funding is paid by the user
+	Payment fixedpoint.Value `json:"payment"`
+	Rate    fixedpoint.Value `json:"rate"`
+	Time    datetime         `json:"time"`
+}
+
+type borrowHistoryResponse struct {
+	Success bool            `json:"success"`
+	Result  []borrowHistory `json:"result"`
+}
+
+/*
+	{
+	  "coin": "BTC",
+	  "cost": 0.00047864470072,
+	  "rate": 1.961096e-05,
+	  "size": 24.407,
+	  "time": "2020-11-30T12:00:00+00:00"
+	}
+*/
+type borrowHistory struct {
+	Coin string           `json:"coin"`
+	Cost fixedpoint.Value `json:"cost"`
+	Rate fixedpoint.Value `json:"rate"`
+	Size fixedpoint.Value `json:"size"`
+	Time datetime         `json:"time"`
+}
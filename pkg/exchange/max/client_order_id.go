@@ -1,6 +1,8 @@
 package max
 
 import (
+	"strings"
+
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/google/uuid"
 )
@@ -23,6 +25,11 @@ func NewClientOrderID(originalID string, tags ...string) (clientOrderID string)
 	prefixLen := len(prefix)
 
 	if originalID != "" {
+		// the client order ID is already prefixed, e.g., the ID of a submitted order
+		if strings.HasPrefix(originalID, prefix) {
+			return originalID
+		}
+
 		// try to keep the whole original client order ID if user specifies it.
 		if prefixLen+len(originalID) > 32 {
 			return originalID
@@ -158,13 +158,23 @@ func (e *Exchange) NewStream() types.Stream {
 	return NewStream(e.key, e.secret)
 }
 
+// QueryOrder queries the order by the order ID, or by the client order ID if the order ID is not given.
 func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
-	orderID, err := strconv.ParseInt(q.OrderID, 10, 64)
-	if err != nil {
-		return nil, err
+	req := e.client.OrderService.NewGetOrderRequest()
+	if len(q.OrderID) > 0 {
+		orderID, err := strconv.ParseInt(q.OrderID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Id(uint64(orderID))
+	} else if len(q.ClientOrderID) > 0 {
+		req.ClientOrderID(NewClientOrderID(q.ClientOrderID))
+	} else {
+		return nil, errors.New("max: order id or client order id is required for querying the order")
 	}
 
-	maxOrder, err := e.client.OrderService.NewGetOrderRequest().Id(uint64(orderID)).Do(ctx)
+	maxOrder, err := req.Do(ctx)
 	if err != nil {
 		return nil, err
 	}
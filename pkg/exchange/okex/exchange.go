@@ -24,6 +24,9 @@ var log = logrus.WithFields(logrus.Fields{
 	"exchange": "okex",
 })
 
+var _ types.ExchangeOrderAmendService = &Exchange{}
+var _ types.ExchangeOrderQueryService = &Exchange{}
+
 type Exchange struct {
 	key, secret, passphrase string
 
@@ -171,6 +174,10 @@ func (e *Exchange) SubmitOrders(ctx context.Context, orders ...types.SubmitOrder
 		orderReq.InstrumentID(toLocalSymbol(order.Symbol))
 		orderReq.Side(toLocalSideType(order.Side))
 
+		if len(order.ClientOrderID) > 0 && order.ClientOrderID != types.NoClientOrderID {
+			orderReq.ClientOrderID(order.ClientOrderID)
+		}
+
 		if order.Market.Symbol != "" {
 			orderReq.Quantity(order.Market.FormatQuantity(order.Quantity))
 		} else {
@@ -244,6 +251,36 @@ func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders [
 	return orders, err
 }
 
+// QueryOrder queries the order by the order ID, or by the client order ID if the order ID is not given.
+func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	if len(q.Symbol) == 0 {
+		return nil, errors.New("symbol is required for querying an okex order")
+	}
+
+	req := e.client.TradeService.NewGetOrderDetailsRequest()
+	req.InstrumentID(toLocalSymbol(q.Symbol))
+
+	if len(q.OrderID) > 0 {
+		req.OrderID(q.OrderID)
+	} else if len(q.ClientOrderID) > 0 {
+		req.ClientOrderID(q.ClientOrderID)
+	} else {
+		return nil, errors.New("order id or client order id is required for querying an okex order")
+	}
+
+	orderDetails, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := toGlobalOrders([]okexapi.OrderDetails{*orderDetails})
+	if err != nil {
+		return nil, err
+	}
+
+	return &orders[0], nil
+}
+
 func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) error {
 	if len(orders) == 0 {
 		return nil
@@ -270,6 +307,52 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) erro
 	return err
 }
 
+// AmendOrder amends the price or the quantity of an existing order through the amend-order api.
+// Unlike cancel-replace, the order keeps its order ID.
+func (e *Exchange) AmendOrder(ctx context.Context, amend types.OrderAmend) (*types.Order, error) {
+	order := amend.Order
+	if len(order.Symbol) == 0 {
+		return nil, errors.New("symbol is required for amending an okex order")
+	}
+
+	req := e.client.TradeService.NewAmendOrderRequest()
+	req.InstrumentID(toLocalSymbol(order.Symbol))
+
+	if order.OrderID > 0 {
+		req.OrderID(strconv.FormatUint(order.OrderID, 10))
+	} else if len(order.ClientOrderID) > 0 {
+		req.ClientOrderID(order.ClientOrderID)
+	} else {
+		return nil, types.NewOrderError(errors.New("order does not contain orderID or clientOrderID"), order)
+	}
+
+	if amend.Quantity.Sign() > 0 {
+		if order.Market.Symbol != "" {
+			req.NewQuantity(order.Market.FormatQuantity(amend.Quantity))
+		} else {
+			req.NewQuantity(amend.Quantity.FormatString(8))
+		}
+	}
+
+	if amend.Price.Sign() > 0 {
+		if order.Market.Symbol != "" {
+			req.NewPrice(order.Market.FormatPrice(amend.Price))
+		} else {
+			req.NewPrice(amend.Price.FormatString(8))
+		}
+	}
+
+	if _, err := req.Do(ctx); err != nil {
+		return nil, err
+	}
+
+	amendedOrder := order
+	amendedOrder.Price = amend.NewPrice()
+	amendedOrder.Quantity = amend.NewQuantity()
+	amendedOrder.UpdateTime = types.Time(time.Now())
+	return &amendedOrder, nil
+}
+
 func (e *Exchange) NewStream() types.Stream {
 	return NewStream(e.client)
 }
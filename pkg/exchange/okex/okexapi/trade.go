@@ -2,6 +2,7 @@ package okexapi
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strings"
 
@@ -40,6 +41,12 @@ func (c *TradeService) NewCancelOrderRequest() *CancelOrderRequest {
 	}
 }
 
+func (c *TradeService) NewAmendOrderRequest() *AmendOrderRequest {
+	return &AmendOrderRequest{
+		client: c.client,
+	}
+}
+
 func (c *TradeService) NewBatchCancelOrderRequest() *BatchCancelOrderRequest {
 	return &BatchCancelOrderRequest{
 		client: c.client,
@@ -171,6 +178,75 @@ func (r *CancelOrderRequest) Do(ctx context.Context) ([]OrderResponse, error) {
 	return orderResponse.Data, nil
 }
 
+//go:generate requestgen -type AmendOrderRequest
+type AmendOrderRequest struct {
+	client *RestClient
+
+	instrumentID  string  `param:"instId"`
+	orderID       *string `param:"ordId"`
+	clientOrderID *string `param:"clOrdId"`
+
+	// cancelOnFail cancels the order when the amendment fails, defaults to false
+	cancelOnFail *bool `param:"cxlOnFail"`
+
+	// requestID is the client request ID for the amendment
+	requestID *string `param:"reqId"`
+
+	// newQuantity is the new total quantity, for a partially-filled order it should include the filled quantity
+	newQuantity *string `param:"newSz"`
+
+	newPrice *string `param:"newPx"`
+}
+
+func (r *AmendOrderRequest) Parameters() map[string]interface{} {
+	payload, _ := r.GetParameters()
+	return payload
+}
+
+func (r *AmendOrderRequest) Do(ctx context.Context) (*OrderResponse, error) {
+	payload, err := r.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.clientOrderID == nil && r.orderID == nil {
+		return nil, errors.New("either orderID or clientOrderID is required for amending order")
+	}
+
+	if r.newQuantity == nil && r.newPrice == nil {
+		return nil, errors.New("either newQuantity or newPrice is required for amending order")
+	}
+
+	req, err := r.client.newAuthenticatedRequest("POST", "/api/v5/trade/amend-order", nil, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.client.sendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var orderResponse struct {
+		Code    string          `json:"code"`
+		Message string          `json:"msg"`
+		Data    []OrderResponse `json:"data"`
+	}
+	if err := response.DecodeJSON(&orderResponse); err != nil {
+		return nil, err
+	}
+
+	if len(orderResponse.Data) == 0 {
+		return nil, errors.New("order amend error")
+	}
+
+	if orderResponse.Data[0].Code != "0" {
+		return nil, fmt.Errorf("order amend error: %s %s", orderResponse.Data[0].Code, orderResponse.Data[0].Message)
+	}
+
+	return &orderResponse.Data[0], nil
+}
+
 type BatchCancelOrderRequest struct {
 	client *RestClient
 
@@ -0,0 +1,188 @@
+package expr
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	gotypes "go/types"
+	"strconv"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func (env *Environment) compile(node ast.Expr) (value, error) {
+	key := gotypes.ExprString(node)
+	if v, ok := env.compiled[key]; ok {
+		return v, nil
+	}
+
+	v, err := env.compileNode(node)
+	if err != nil {
+		return v, err
+	}
+
+	env.compiled[key] = v
+	return v, nil
+}
+
+func (env *Environment) compileNode(node ast.Expr) (value, error) {
+	switch n := node.(type) {
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT {
+			return value{}, fmt.Errorf("unsupported literal %s", n.Value)
+		}
+
+		v, err := strconv.ParseFloat(n.Value, 64)
+		if err != nil {
+			return value{}, err
+		}
+		return newConstant(v), nil
+
+	case *ast.Ident:
+		series, ok := env.source(n.Name)
+		if !ok {
+			return value{}, fmt.Errorf("undefined identifier %s", n.Name)
+		}
+		return value{series: series}, nil
+
+	case *ast.ParenExpr:
+		return env.compile(n.X)
+
+	case *ast.UnaryExpr:
+		return env.compileUnary(n)
+
+	case *ast.BinaryExpr:
+		return env.compileBinary(n)
+
+	case *ast.IndexExpr:
+		x, err := env.compile(n.X)
+		if err != nil {
+			return x, err
+		}
+		if x.series == nil {
+			return value{}, fmt.Errorf("%s is not a series", gotypes.ExprString(n.X))
+		}
+
+		offset, err := env.compile(n.Index)
+		if err != nil {
+			return offset, err
+		}
+		if !offset.constant || offset.series.Last() < 0 || offset.series.Last() != float64(int(offset.series.Last())) {
+			return value{}, fmt.Errorf("the index of %s must be a non-negative integer", gotypes.ExprString(n))
+		}
+		return value{series: &shiftSeries{a: x.series, offset: int(offset.series.Last())}}, nil
+
+	case *ast.CallExpr:
+		ident, ok := n.Fun.(*ast.Ident)
+		if !ok {
+			return value{}, fmt.Errorf("unsupported function %s", gotypes.ExprString(n.Fun))
+		}
+
+		f, ok := functions[ident.Name]
+		if !ok {
+			return value{}, fmt.Errorf("undefined function %s", ident.Name)
+		}
+
+		var args []value
+		for _, arg := range n.Args {
+			v, err := env.compile(arg)
+			if err != nil {
+				return v, err
+			}
+			args = append(args, v)
+		}
+
+		return f(env, &callArgs{name: ident.Name, args: args})
+	}
+
+	return value{}, fmt.Errorf("unsupported expression %s", gotypes.ExprString(node))
+}
+
+func (env *Environment) compileUnary(n *ast.UnaryExpr) (value, error) {
+	x, err := env.compile(n.X)
+	if err != nil {
+		return x, err
+	}
+
+	switch n.Op {
+	case token.ADD, token.SUB:
+		if x.series == nil {
+			return value{}, fmt.Errorf("operator %s expects a number, got %s", n.Op, gotypes.ExprString(n.X))
+		}
+		if n.Op == token.ADD {
+			return x, nil
+		}
+		if x.constant {
+			return newConstant(-x.series.Last()), nil
+		}
+		return value{series: types.Mul(x.series, -1.)}, nil
+
+	case token.NOT:
+		if x.bools == nil {
+			return value{}, fmt.Errorf("operator not expects a condition, got %s", gotypes.ExprString(n.X))
+		}
+		return value{bools: &notSeries{a: x.bools}}, nil
+	}
+
+	return value{}, fmt.Errorf("unsupported operator %s", n.Op)
+}
+
+func (env *Environment) compileBinary(n *ast.BinaryExpr) (value, error) {
+	x, err := env.compile(n.X)
+	if err != nil {
+		return x, err
+	}
+
+	y, err := env.compile(n.Y)
+	if err != nil {
+		return y, err
+	}
+
+	switch n.Op {
+	case token.LAND, token.LOR:
+		if x.bools == nil || y.bools == nil {
+			return value{}, fmt.Errorf("operator %s expects conditions, got %s", n.Op, gotypes.ExprString(n))
+		}
+		return value{bools: &logicalSeries{a: x.bools, b: y.bools, op: n.Op}}, nil
+	}
+
+	if x.series == nil || y.series == nil {
+		return value{}, fmt.Errorf("operator %s expects numbers, got %s", n.Op, gotypes.ExprString(n))
+	}
+
+	switch n.Op {
+	case token.ADD, token.SUB, token.MUL, token.QUO:
+		if x.constant && y.constant {
+			return newConstant(arithmetic(n.Op, x.series.Last(), y.series.Last())), nil
+		}
+
+		switch n.Op {
+		case token.ADD:
+			return value{series: types.Add(x.series, y.series)}, nil
+		case token.SUB:
+			return value{series: types.Minus(x.series, y.series)}, nil
+		case token.MUL:
+			return value{series: types.Mul(x.series, y.series)}, nil
+		default:
+			return value{series: types.Div(x.series, y.series)}, nil
+		}
+
+	case token.LSS, token.LEQ, token.GTR, token.GEQ, token.EQL, token.NEQ:
+		return value{bools: &compareSeries{a: x.series, b: y.series, op: n.Op}}, nil
+	}
+
+	return value{}, fmt.Errorf("unsupported operator %s", n.Op)
+}
+
+func arithmetic(op token.Token, a, b float64) float64 {
+	switch op {
+	case token.ADD:
+		return a + b
+	case token.SUB:
+		return a - b
+	case token.MUL:
+		return a * b
+	default:
+		return a / b
+	}
+}
@@ -0,0 +1,197 @@
+package expr
+
+import (
+	"fmt"
+	"go/parser"
+	"regexp"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+expr implements the indicator expression language for the strategy config, for example:
+
+	crossover(ema(close, 9), sma(close, 21)) and rsi(close, 14) < 70
+
+An expression is made of
+- the kline sources: open, high, low, close, volume, hl2, hlc3 and ohlc4
+- the numbers, the arithmetic operators + - * / and the comparison operators < <= > >= == !=
+- the logical operators and, or, not (or &&, ||, !)
+- the previous value of a series, close[1]
+- the functions, see the functions map
+
+The expressions are compiled to types.Series or types.BoolSeries with the indicators in pkg/indicator,
+and updated by the klines of the environment interval.
+*/
+
+const (
+	maxNumOfValues             = 5_000
+	maxNumOfValuesTruncateSize = 100
+)
+
+var zeroTime time.Time
+
+var logicalOperatorWords = regexp.MustCompile(`\b(and|or|not)\b`)
+
+// Environment holds the kline sources and the indicators of the expressions of an interval.
+// The expressions compiled in the same environment share the indicators of the same sub-expressions.
+type Environment struct {
+	Interval types.Interval
+
+	Open, High, Low, Close, Volume types.Float64Slice
+
+	EndTime time.Time
+
+	// updaters are the stateful nodes in the order of dependency
+	updaters []updater
+
+	// compiled are the compiled sub-expressions indexed by the formatted sub-expressions
+	compiled map[string]value
+}
+
+func NewEnvironment(interval types.Interval) *Environment {
+	return &Environment{
+		Interval: interval,
+		compiled: make(map[string]value),
+	}
+}
+
+// Compile compiles the expression, the expressions should be compiled before the environment is updated,
+// since the indicators of the new expressions can't be warmed up with the klines that are already processed
+func (env *Environment) Compile(source string) (*Expression, error) {
+	if env.EndTime != zeroTime {
+		return nil, fmt.Errorf("can not compile expression %q, the environment is already updated", source)
+	}
+
+	rewritten := logicalOperatorWords.ReplaceAllStringFunc(source, func(word string) string {
+		switch word {
+		case "and":
+			return "&&"
+		case "or":
+			return "||"
+		default:
+			return "!"
+		}
+	})
+
+	node, err := parser.ParseExpr(rewritten)
+	if err != nil {
+		return nil, fmt.Errorf("can not parse expression %q: %w", source, err)
+	}
+
+	v, err := env.compile(node)
+	if err != nil {
+		return nil, fmt.Errorf("can not compile expression %q: %w", source, err)
+	}
+
+	return &Expression{Source: source, value: v}, nil
+}
+
+// Update pushes the klines to the kline sources and updates the indicators,
+// the klines that don't end after the last updated kline are skipped
+func (env *Environment) Update(kLines ...types.KLine) {
+	for _, k := range kLines {
+		if env.EndTime != zeroTime && !k.EndTime.After(env.EndTime) {
+			continue
+		}
+
+		env.Open = pushValue(env.Open, k.Open.Float64())
+		env.High = pushValue(env.High, k.High.Float64())
+		env.Low = pushValue(env.Low, k.Low.Float64())
+		env.Close = pushValue(env.Close, k.Close.Float64())
+		env.Volume = pushValue(env.Volume, k.Volume.Float64())
+
+		for _, u := range env.updaters {
+			u.update(k)
+		}
+
+		env.EndTime = k.EndTime.Time()
+	}
+}
+
+func pushValue(values types.Float64Slice, v float64) types.Float64Slice {
+	values.Push(v)
+	if len(values) > maxNumOfValues {
+		values = values[maxNumOfValuesTruncateSize-1:]
+	}
+	return values
+}
+
+func (env *Environment) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if env.Interval != interval {
+		return
+	}
+
+	env.Update(window...)
+}
+
+func (env *Environment) Bind(updater indicator.KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(env.handleKLineWindowUpdate)
+}
+
+// Expression is the compiled expression, which is either a series or a bool series
+type Expression struct {
+	Source string
+
+	value
+}
+
+// IsBool returns true if the expression is a condition, like a comparison or a crossover
+func (e *Expression) IsBool() bool {
+	return e.bools != nil
+}
+
+// Series returns the numeric series of the expression, the conditions are converted to 1 and 0
+func (e *Expression) Series() types.Series {
+	if e.series != nil {
+		return e.series
+	}
+	return &boolNumberSeries{e.bools}
+}
+
+// BoolSeries returns the bool series of the expression, the numbers are true if they are not 0
+func (e *Expression) BoolSeries() types.BoolSeries {
+	if e.bools != nil {
+		return e.bools
+	}
+	return &numberBoolSeries{e.series}
+}
+
+// value is the compiled node, which is either a series or a bool series
+type value struct {
+	series types.Series
+	bools  types.BoolSeries
+
+	// constant is true if the series is a number
+	constant bool
+}
+
+func newConstant(v float64) value {
+	return value{series: types.NumberSeries(v), constant: true}
+}
+
+// source returns the kline source of the identifier
+func (env *Environment) source(name string) (types.Series, bool) {
+	switch name {
+	case "open":
+		return &env.Open, true
+	case "high":
+		return &env.High, true
+	case "low":
+		return &env.Low, true
+	case "close":
+		return &env.Close, true
+	case "volume":
+		return &env.Volume, true
+	case "hl2":
+		return types.Div(types.Add(&env.High, &env.Low), 2.), true
+	case "hlc3":
+		return types.Div(types.Add(types.Add(&env.High, &env.Low), &env.Close), 3.), true
+	case "ohlc4":
+		return types.Div(types.Add(types.Add(types.Add(&env.Open, &env.High), &env.Low), &env.Close), 4.), true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,137 @@
+package expr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var testCloses = []float64{10, 11, 12, 11, 10, 9, 8, 9, 11, 13, 14, 13}
+
+func buildTestKLines(closes []float64) (kLines []types.KLine) {
+	t0 := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i, c := range closes {
+		startTime := t0.Add(time.Duration(i) * time.Minute)
+		kLines = append(kLines, types.KLine{
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1m,
+			StartTime: types.Time(startTime),
+			EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+			Open:      fixedpoint.NewFromFloat(c - 0.5),
+			High:      fixedpoint.NewFromFloat(c + 1),
+			Low:       fixedpoint.NewFromFloat(c - 1),
+			Close:     fixedpoint.NewFromFloat(c),
+			Volume:    fixedpoint.NewFromInt(int64(i + 1)),
+			Closed:    true,
+		})
+	}
+	return kLines
+}
+
+func TestEnvironment_Compile(t *testing.T) {
+	env := NewEnvironment(types.Interval1m)
+
+	sma, err := env.Compile("sma(close, 3)")
+	assert.NoError(t, err)
+	assert.False(t, sma.IsBool())
+
+	arithmetic, err := env.Compile("(close - close[1]) / close[1] * 100 + -1 * (2 - 1)")
+	assert.NoError(t, err)
+
+	hl2, err := env.Compile("hl2 - low")
+	assert.NoError(t, err)
+
+	condition, err := env.Compile("close > open and not (close < 5 or volume >= 100)")
+	assert.NoError(t, err)
+	assert.True(t, condition.IsBool())
+
+	cross, err := env.Compile("crossover(close, sma(close, 3))")
+	assert.NoError(t, err)
+
+	highest, err := env.Compile("highest(close, 4)")
+	assert.NoError(t, err)
+
+	// the same sub-expressions share the same indicator
+	sma2, err := env.Compile("sma(close,3)")
+	assert.NoError(t, err)
+	assert.Same(t, sma.Series(), sma2.Series())
+
+	env.Update(buildTestKLines(testCloses)...)
+
+	assert.InDelta(t, (14.+13.+11.)/3, sma.Series().Index(1), 1e-9)
+	assert.InDelta(t, (14.+13.+13.)/3, sma.Series().Last(), 1e-9)
+	assert.InDelta(t, (13.-14.)/14.*100-1, arithmetic.Series().Last(), 1e-9)
+	assert.InDelta(t, 1., hl2.Series().Last(), 1e-9)
+	assert.True(t, condition.BoolSeries().Last())
+	assert.Equal(t, 1., condition.Series().Last())
+	assert.InDelta(t, 14., highest.Series().Last(), 1e-9)
+	assert.InDelta(t, 11., highest.Series().Index(3), 1e-9)
+
+	// close crosses over sma(close, 3) at the kline 7: close 9, sma (9 + 8 + 9) / 3
+	crosses := cross.BoolSeries()
+	for i := 0; i < len(testCloses)-1; i++ {
+		assert.Equal(t, i == 4, crosses.Index(i), "crossover at index %d", i)
+	}
+
+	_, err = env.Compile("sma(close, 5)")
+	assert.Error(t, err, "can not compile after the environment is updated")
+}
+
+func TestEnvironment_CompileError(t *testing.T) {
+	for _, source := range []string{
+		"close +",
+		"ema(close)",
+		"ema(close, 2.5)",
+		"ema(close, 0)",
+		"ema(close > open, 3)",
+		"foo(close, 3)",
+		"price > 3",
+		"close and open",
+		"not close",
+		"close[-1]",
+		"close[open]",
+		"percentile(close, 10, open)",
+		"\"close\"",
+		"close.x",
+	} {
+		_, err := NewEnvironment(types.Interval1m).Compile(source)
+		assert.Error(t, err, source)
+	}
+}
+
+func TestEnvironment_Bind(t *testing.T) {
+	source := "ema(close, 2) - sma(close, 4) + rsi(close, 3) / 100 + atr(3)"
+	kLines := buildTestKLines(testCloses)
+
+	store := bbgo.NewMarketDataStore("BTCUSDT")
+	env := NewEnvironment(types.Interval1m)
+	bound, err := env.Compile(source)
+	assert.NoError(t, err)
+	env.Bind(store)
+
+	// the klines of the other intervals are ignored
+	store.AddKLine(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval5m, Close: fixedpoint.One})
+
+	// the environment is warmed up with the loaded klines, and then updated by the store
+	env.Update(kLines[:5]...)
+	for _, k := range kLines[3:] {
+		store.AddKLine(k)
+	}
+
+	other := NewEnvironment(types.Interval1m)
+	expected, err := other.Compile(source)
+	assert.NoError(t, err)
+	other.Update(kLines...)
+
+	assert.Equal(t, len(testCloses), env.Close.Length())
+	if assert.Equal(t, expected.Series().Length(), bound.Series().Length()) {
+		for i := 0; i < expected.Series().Length(); i++ {
+			assert.InDelta(t, expected.Series().Index(i), bound.Series().Index(i), 1e-9)
+		}
+	}
+}
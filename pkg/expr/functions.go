@@ -0,0 +1,300 @@
+package expr
+
+import (
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type function func(env *Environment, call *callArgs) (value, error)
+
+// functions are the functions of the expressions, the window arguments must be positive integers:
+//
+//	sma(series, window)
+//	ema, rma, wwma, dema, tema, tma, hull, zlema, till, vidya, rsi, cci (series, window)
+//	atr(window)
+//	crossover(a, b), crossunder(a, b)
+//	change(series[, offset]), abs(series), highest(series, window), lowest(series, window)
+//	stdev, variance, zscore, skew, kurtosis, rank, slope, linreg (series, window)
+//	percentile(series, window, percentile)
+//	corr, cov, beta (a, b, window)
+var functions = map[string]function{
+	"ema":   valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.EWMA{IntervalWindow: iw} }),
+	"rma":   valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.RMA{IntervalWindow: iw} }),
+	"wwma":  valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.WWMA{IntervalWindow: iw} }),
+	"dema":  valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.DEMA{IntervalWindow: iw} }),
+	"tema":  valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.TEMA{IntervalWindow: iw} }),
+	"tma":   valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.TMA{IntervalWindow: iw} }),
+	"hull":  valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.HULL{IntervalWindow: iw} }),
+	"zlema": valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.ZLEMA{IntervalWindow: iw} }),
+	"till":  valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.TILL{IntervalWindow: iw} }),
+	"vidya": valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.VIDYA{IntervalWindow: iw} }),
+	"rsi":   valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.RSI{IntervalWindow: iw} }),
+	"cci":   valueIndicator(func(iw types.IntervalWindow) updatableSeries { return &indicator.CCI{IntervalWindow: iw} }),
+
+	"atr": func(env *Environment, call *callArgs) (value, error) {
+		if err := call.expect(1); err != nil {
+			return value{}, err
+		}
+
+		window, err := call.window(0)
+		if err != nil {
+			return value{}, err
+		}
+
+		atr := &indicator.ATR{IntervalWindow: types.IntervalWindow{Interval: env.Interval, Window: window}}
+		env.updaters = append(env.updaters, &kLineUpdater{indicator: atr})
+		return value{series: atr}, nil
+	},
+
+	"crossover": func(env *Environment, call *callArgs) (value, error) {
+		a, b, err := call.seriesPair()
+		if err != nil {
+			return value{}, err
+		}
+		return value{bools: types.CrossOver(a, b)}, nil
+	},
+	"crossunder": func(env *Environment, call *callArgs) (value, error) {
+		a, b, err := call.seriesPair()
+		if err != nil {
+			return value{}, err
+		}
+		return value{bools: types.CrossUnder(a, b)}, nil
+	},
+
+	"change": func(env *Environment, call *callArgs) (value, error) {
+		if err := call.expect(1, 2); err != nil {
+			return value{}, err
+		}
+
+		a, err := call.series(0)
+		if err != nil {
+			return value{}, err
+		}
+
+		offset := 1
+		if len(call.args) > 1 {
+			if offset, err = call.window(1); err != nil {
+				return value{}, err
+			}
+		}
+		return value{series: types.Change(a, offset)}, nil
+	},
+	"abs": func(env *Environment, call *callArgs) (value, error) {
+		if err := call.expect(1); err != nil {
+			return value{}, err
+		}
+
+		a, err := call.series(0)
+		if err != nil {
+			return value{}, err
+		}
+		return value{series: types.Abs(a)}, nil
+	},
+	// the simple moving average is calculated from the window of the series,
+	// so it follows the history of any series without being updated
+	"sma": windowFunction(func(a types.Series, window int) types.Series {
+		return &windowSeries{a: a, window: window, f: func(a types.Series, window int) float64 {
+			return types.Mean(a, window)
+		}}
+	}),
+	"highest": windowFunction(func(a types.Series, window int) types.Series {
+		return &windowSeries{a: a, window: window, f: types.Highest}
+	}),
+	"lowest": windowFunction(func(a types.Series, window int) types.Series {
+		return &windowSeries{a: a, window: window, f: types.Lowest}
+	}),
+
+	"stdev":    windowFunction(types.RollingStdev),
+	"variance": windowFunction(types.RollingVariance),
+	"zscore":   windowFunction(types.RollingZScore),
+	"skew":     windowFunction(types.RollingSkew),
+	"kurtosis": windowFunction(types.RollingKurtosis),
+	"rank":     windowFunction(types.RollingRank),
+	"slope": windowFunction(func(a types.Series, window int) types.Series {
+		slope, _, _ := types.RollingLinearRegression(a, window)
+		return slope
+	}),
+	// linreg is the regression value of the latest value, same as ta.linreg in pine script
+	"linreg": windowFunction(func(a types.Series, window int) types.Series {
+		slope, intercept, _ := types.RollingLinearRegression(a, window)
+		return types.Add(intercept, types.Mul(slope, float64(window-1)))
+	}),
+	"percentile": func(env *Environment, call *callArgs) (value, error) {
+		if err := call.expect(3); err != nil {
+			return value{}, err
+		}
+
+		a, err := call.series(0)
+		if err != nil {
+			return value{}, err
+		}
+
+		window, err := call.window(1)
+		if err != nil {
+			return value{}, err
+		}
+
+		percentile, err := call.number(2)
+		if err != nil {
+			return value{}, err
+		}
+		return value{series: types.RollingPercentile(a, window, percentile)}, nil
+	},
+
+	"corr": pairWindowFunction(types.RollingCorrelation),
+	"cov":  pairWindowFunction(types.RollingCovariance),
+	"beta": pairWindowFunction(types.RollingBeta),
+}
+
+type updatableSeries interface {
+	types.Series
+	Update(value float64)
+}
+
+// updater is the stateful node that is updated by every kline
+type updater interface {
+	update(k types.KLine)
+}
+
+// valueUpdater updates the indicator with the latest value of the source series
+type valueUpdater struct {
+	indicator updatableSeries
+	source    types.Series
+}
+
+func (u *valueUpdater) update(_ types.KLine) {
+	// the source is not ready, for example, the change of the first kline
+	if u.source.Length() == 0 {
+		return
+	}
+
+	u.indicator.Update(u.source.Last())
+}
+
+// kLineUpdater updates the indicator with the high, the low and the close of the kline
+type kLineUpdater struct {
+	indicator interface {
+		Update(high, low, cloze float64)
+	}
+}
+
+func (u *kLineUpdater) update(k types.KLine) {
+	u.indicator.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+}
+
+// valueIndicator is the function of the indicator updated by the values of the series, like ema(close, 9)
+func valueIndicator(newIndicator func(iw types.IntervalWindow) updatableSeries) function {
+	return func(env *Environment, call *callArgs) (value, error) {
+		if err := call.expect(2); err != nil {
+			return value{}, err
+		}
+
+		source, err := call.series(0)
+		if err != nil {
+			return value{}, err
+		}
+
+		window, err := call.window(1)
+		if err != nil {
+			return value{}, err
+		}
+
+		inc := newIndicator(types.IntervalWindow{Interval: env.Interval, Window: window})
+		env.updaters = append(env.updaters, &valueUpdater{indicator: inc, source: source})
+		return value{series: inc}, nil
+	}
+}
+
+func windowFunction(f func(a types.Series, window int) types.Series) function {
+	return func(env *Environment, call *callArgs) (value, error) {
+		if err := call.expect(2); err != nil {
+			return value{}, err
+		}
+
+		a, err := call.series(0)
+		if err != nil {
+			return value{}, err
+		}
+
+		window, err := call.window(1)
+		if err != nil {
+			return value{}, err
+		}
+		return value{series: f(a, window)}, nil
+	}
+}
+
+func pairWindowFunction(f func(a, b types.Series, window int) types.Series) function {
+	return func(env *Environment, call *callArgs) (value, error) {
+		if err := call.expect(3); err != nil {
+			return value{}, err
+		}
+
+		a, b, err := call.seriesPair()
+		if err != nil {
+			return value{}, err
+		}
+
+		window, err := call.window(2)
+		if err != nil {
+			return value{}, err
+		}
+		return value{series: f(a, b, window)}, nil
+	}
+}
+
+type callArgs struct {
+	name string
+	args []value
+}
+
+// expect checks the number of the arguments
+func (c *callArgs) expect(counts ...int) error {
+	for _, count := range counts {
+		if len(c.args) == count {
+			return nil
+		}
+	}
+	return fmt.Errorf("function %s expects %v arguments, got %d", c.name, counts, len(c.args))
+}
+
+func (c *callArgs) series(i int) (types.Series, error) {
+	if c.args[i].series == nil {
+		return nil, fmt.Errorf("argument %d of function %s must be a number", i+1, c.name)
+	}
+	return c.args[i].series, nil
+}
+
+func (c *callArgs) seriesPair() (a, b types.Series, err error) {
+	if len(c.args) < 2 {
+		return nil, nil, fmt.Errorf("function %s expects 2 series, got %d arguments", c.name, len(c.args))
+	}
+
+	if a, err = c.series(0); err != nil {
+		return nil, nil, err
+	}
+	b, err = c.series(1)
+	return a, b, err
+}
+
+func (c *callArgs) number(i int) (float64, error) {
+	if !c.args[i].constant {
+		return 0, fmt.Errorf("argument %d of function %s must be a constant number", i+1, c.name)
+	}
+	return c.args[i].series.Last(), nil
+}
+
+// window returns the argument as a positive integer
+func (c *callArgs) window(i int) (int, error) {
+	v, err := c.number(i)
+	if err != nil {
+		return 0, err
+	}
+
+	if v <= 0 || v != float64(int(v)) {
+		return 0, fmt.Errorf("argument %d of function %s must be a positive integer, got %v", i+1, c.name, v)
+	}
+	return int(v), nil
+}
@@ -0,0 +1,189 @@
+package expr
+
+import (
+	"go/token"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// shiftSeries is the series of the previous values, Index(i) is a.Index(i + offset)
+type shiftSeries struct {
+	a      types.Series
+	offset int
+}
+
+func (s *shiftSeries) Last() float64 {
+	return s.Index(0)
+}
+
+func (s *shiftSeries) Index(i int) float64 {
+	if i+s.offset >= s.a.Length() {
+		return 0
+	}
+	return s.a.Index(i + s.offset)
+}
+
+func (s *shiftSeries) Length() int {
+	length := s.a.Length() - s.offset
+	if length < 0 {
+		return 0
+	}
+	return length
+}
+
+var _ types.Series = &shiftSeries{}
+
+// windowSeries applies the function to the window of the series, Index(i) is f of a.Index(i) ... a.Index(i + window - 1)
+type windowSeries struct {
+	a      types.Series
+	window int
+	f      func(a types.Series, window int) float64
+}
+
+func (s *windowSeries) Last() float64 {
+	return s.Index(0)
+}
+
+func (s *windowSeries) Index(i int) float64 {
+	if i >= s.a.Length() {
+		return 0
+	}
+	return s.f(&shiftSeries{a: s.a, offset: i}, s.window)
+}
+
+func (s *windowSeries) Length() int {
+	return s.a.Length()
+}
+
+var _ types.Series = &windowSeries{}
+
+type compareSeries struct {
+	a, b types.Series
+	op   token.Token
+}
+
+func (s *compareSeries) Last() bool {
+	return s.Index(0)
+}
+
+func (s *compareSeries) Index(i int) bool {
+	if i >= s.Length() {
+		return false
+	}
+
+	a, b := s.a.Index(i), s.b.Index(i)
+	switch s.op {
+	case token.LSS:
+		return a < b
+	case token.LEQ:
+		return a <= b
+	case token.GTR:
+		return a > b
+	case token.GEQ:
+		return a >= b
+	case token.EQL:
+		return a == b
+	default:
+		return a != b
+	}
+}
+
+func (s *compareSeries) Length() int {
+	return minLength(s.a.Length(), s.b.Length())
+}
+
+var _ types.BoolSeries = &compareSeries{}
+
+type logicalSeries struct {
+	a, b types.BoolSeries
+	op   token.Token
+}
+
+func (s *logicalSeries) Last() bool {
+	return s.Index(0)
+}
+
+func (s *logicalSeries) Index(i int) bool {
+	if s.op == token.LAND {
+		return s.a.Index(i) && s.b.Index(i)
+	}
+	return s.a.Index(i) || s.b.Index(i)
+}
+
+func (s *logicalSeries) Length() int {
+	return minLength(s.a.Length(), s.b.Length())
+}
+
+var _ types.BoolSeries = &logicalSeries{}
+
+type notSeries struct {
+	a types.BoolSeries
+}
+
+func (s *notSeries) Last() bool {
+	return s.Index(0)
+}
+
+func (s *notSeries) Index(i int) bool {
+	if i >= s.a.Length() {
+		return false
+	}
+	return !s.a.Index(i)
+}
+
+func (s *notSeries) Length() int {
+	return s.a.Length()
+}
+
+var _ types.BoolSeries = &notSeries{}
+
+// boolNumberSeries converts the conditions to 1 and 0
+type boolNumberSeries struct {
+	a types.BoolSeries
+}
+
+func (s *boolNumberSeries) Last() float64 {
+	return s.Index(0)
+}
+
+func (s *boolNumberSeries) Index(i int) float64 {
+	if s.a.Index(i) {
+		return 1
+	}
+	return 0
+}
+
+func (s *boolNumberSeries) Length() int {
+	return s.a.Length()
+}
+
+var _ types.Series = &boolNumberSeries{}
+
+// numberBoolSeries converts the numbers to true if they are not 0
+type numberBoolSeries struct {
+	a types.Series
+}
+
+func (s *numberBoolSeries) Last() bool {
+	return s.Index(0)
+}
+
+func (s *numberBoolSeries) Index(i int) bool {
+	if i >= s.a.Length() {
+		return false
+	}
+	return s.a.Index(i) != 0
+}
+
+func (s *numberBoolSeries) Length() int {
+	return s.a.Length()
+}
+
+var _ types.BoolSeries = &numberBoolSeries{}
+
+func minLength(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
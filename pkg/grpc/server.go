@@ -46,7 +46,7 @@ func (s *TradingService) SubmitOrder(ctx context.Context, request *pb.SubmitOrde
 		}
 	}
 
-	createdOrders, err := session.Exchange.SubmitOrders(ctx, submitOrders...)
+	createdOrders, err := session.OrderExecutor.SubmitOrders(ctx, submitOrders...)
 	if err != nil {
 		return nil, err
 	}
@@ -19,7 +19,7 @@ type AD struct {
 	PrePrice float64
 
 	EndTime         time.Time
-	UpdateCallbacks []func(value float64)
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *AD) Update(high, low, cloze, volume float64) {
@@ -61,10 +61,10 @@ func (inc *AD) calculateAndUpdate(kLines []types.KLine) {
 			continue
 		}
 		inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64(), k.Volume.Float64())
+		inc.EndTime = k.EndTime.Time()
 	}
 
 	inc.EmitUpdate(inc.Last())
-	inc.EndTime = kLines[len(kLines)-1].EndTime.Time()
 }
 func (inc *AD) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
 	if inc.Interval != interval {
@@ -16,7 +16,7 @@ type ATR struct {
 	RMA           *RMA
 
 	EndTime         time.Time
-	UpdateCallbacks []func(value float64)
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *ATR) Update(high, low, cloze float64) {
@@ -78,10 +78,10 @@ func (inc *ATR) calculateAndUpdate(kLines []types.KLine) {
 			continue
 		}
 		inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 	}
 
 	inc.EmitUpdate(inc.Last())
-	inc.EndTime = kLines[len(kLines)-1].EndTime.Time()
 }
 
 func (inc *ATR) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
@@ -1,11 +1,9 @@
 package indicator
 
 import (
+	"math"
 	"time"
 
-	log "github.com/sirupsen/logrus"
-	"gonum.org/v1/gonum/stat"
-
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -20,6 +18,9 @@ Bollinger Bands
 
 Bollinger Bands Technical indicator guide:
 - https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/bollinger-bands
+
+The standard deviation is the population standard deviation of the window like the charts of the exchanges,
+Legacy uses the sample standard deviation of the previous versions.
 */
 
 //go:generate callbackgen -type BOLL
@@ -36,6 +37,14 @@ type BOLL struct {
 
 	EndTime time.Time
 
+	// Precise keeps the rolling sums in fixedpoint.Value
+	Precise bool
+
+	// Legacy uses the sample standard deviation
+	Legacy bool
+
+	Rolling *RollingWindow
+
 	updateCallbacks []func(sma, upBand, downBand float64)
 }
 
@@ -88,49 +97,51 @@ func (inc *BOLL) LastSMA() float64 {
 	return 0.0
 }
 
-func (inc *BOLL) calculateAndUpdate(kLines []types.KLine) {
-	if len(kLines) < inc.Window {
-		return
-	}
-
-	var index = len(kLines) - 1
-	var kline = kLines[index]
+func (inc *BOLL) Update(value float64) {
+	inc.rolling().Push(value)
+	inc.pushBands()
+}
 
-	if inc.EndTime != zeroTime && kline.EndTime.Before(inc.EndTime) {
-		return
+func (inc *BOLL) rolling() *RollingWindow {
+	if inc.Rolling == nil {
+		inc.Rolling = NewRollingWindow(inc.Window, inc.Precise, true)
 	}
+	return inc.Rolling
+}
 
-	var recentK = kLines[index-(inc.Window-1) : index+1]
-	sma, err := calculateSMA(recentK, inc.Window, KLineClosePriceMapper)
-	if err != nil {
-		log.WithError(err).Error("SMA error")
+func (inc *BOLL) pushBands() {
+	if !inc.Rolling.Full() {
 		return
 	}
 
-	inc.SMA.Push(sma)
-
-	var prices []float64
-	for _, k := range recentK {
-		prices = append(prices, k.Close.Float64())
+	sma := inc.Rolling.Mean()
+	std := inc.Rolling.StdDev()
+	if inc.Legacy && inc.Window > 1 {
+		std *= math.Sqrt(float64(inc.Window) / float64(inc.Window-1))
 	}
 
-	var std = stat.StdDev(prices, nil)
+	inc.SMA.Push(sma)
 	inc.StdDev.Push(std)
 
 	var band = inc.K * std
+	inc.UpBand.Push(sma + band)
+	inc.DownBand.Push(sma - band)
+}
 
-	var upBand = sma + band
-	inc.UpBand.Push(upBand)
-
-	var downBand = sma - band
-	inc.DownBand.Push(downBand)
-
-	// update end time
-	inc.EndTime = kLines[index].EndTime.Time()
-
-	// log.Infof("update boll: sma=%f, up=%f, down=%f", sma, upBand, downBand)
-
-	inc.EmitUpdate(sma, upBand, downBand)
+func (inc *BOLL) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+
+		length := len(inc.SMA)
+		inc.rolling().PushFixed(k.Close)
+		inc.pushBands()
+		inc.EndTime = k.EndTime.Time()
+		if len(inc.SMA) != length {
+			inc.EmitUpdate(inc.LastSMA(), inc.LastUpBand(), inc.LastDownBand())
+		}
+	}
 }
 
 func (inc *BOLL) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
@@ -0,0 +1,69 @@
+package indicator
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+book_imbalance implements the order book imbalance indicator
+
+Order Book Imbalance, the difference of the bid and the ask volumes over the sum of them, in the range of [-1, 1]
+- https://towardsdatascience.com/price-impact-of-order-book-imbalance-in-cryptocurrency-markets-bf39695246f6
+
+	imbalance = (bid volume - ask volume) / (bid volume + ask volume)
+
+The volumes are summed over the top Depth levels of the book, a value is pushed for every book update.
+*/
+//go:generate callbackgen -type BookImbalance
+type BookImbalance struct {
+	Symbol string
+
+	// Depth is the number of the levels of each side, default 1
+	Depth int
+
+	Values types.Float64Slice
+
+	UpdateCallbacks []func(value float64) `json:"-"`
+}
+
+func (inc *BookImbalance) Update(bids, asks types.PriceVolumeSlice) {
+	bidVolume := sumVolume(bids)
+	askVolume := sumVolume(asks)
+	if bidVolume+askVolume == 0 {
+		return
+	}
+
+	pushOrderFlowValue(&inc.Values, (bidVolume-askVolume)/(bidVolume+askVolume))
+}
+
+func (inc *BookImbalance) Last() float64 {
+	return inc.Values.Last()
+}
+
+func (inc *BookImbalance) Index(i int) float64 {
+	return inc.Values.Index(i)
+}
+
+func (inc *BookImbalance) Length() int {
+	return inc.Values.Length()
+}
+
+var _ types.Series = &BookImbalance{}
+
+func (inc *BookImbalance) handleBook(bids, asks types.PriceVolumeSlice) {
+	length := inc.Length()
+	inc.Update(bids, asks)
+	if inc.Length() != length {
+		inc.EmitUpdate(inc.Last())
+	}
+}
+
+// Bind binds the indicator to the book snapshots and updates of the symbol
+func (inc *BookImbalance) Bind(updater OrderBookUpdater) {
+	depth := inc.Depth
+	if depth <= 0 {
+		depth = 1
+	}
+
+	bindOrderBook(updater, inc.Symbol, depth, inc.handleBook)
+}
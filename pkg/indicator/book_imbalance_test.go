@@ -0,0 +1,60 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func buildPriceVolumes(pairs ...float64) (slice types.PriceVolumeSlice) {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		slice = append(slice, types.PriceVolume{
+			Price:  fixedpoint.NewFromFloat(pairs[i]),
+			Volume: fixedpoint.NewFromFloat(pairs[i+1]),
+		})
+	}
+	return slice
+}
+
+func TestBookImbalance_Bind(t *testing.T) {
+	stream := &types.StandardStream{}
+
+	top := &BookImbalance{Symbol: "BTCUSDT"}
+	top.Bind(stream)
+
+	// the indicator applies the book updates itself, the stream book bound after it does not affect the values
+	book := types.NewStreamBook("BTCUSDT")
+
+	deep := &BookImbalance{Symbol: "BTCUSDT", Depth: 2}
+	deep.Bind(stream)
+	book.BindStream(stream)
+
+	stream.EmitBookSnapshot(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   buildPriceVolumes(100, 3, 99, 5),
+		Asks:   buildPriceVolumes(101, 1, 102, 1),
+	})
+	assert.InDelta(t, (3.-1.)/(3.+1.), top.Last(), Delta)
+	assert.InDelta(t, (8.-2.)/(8.+2.), deep.Last(), Delta)
+
+	// the best ask is increased to 7
+	stream.EmitBookUpdate(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Asks:   buildPriceVolumes(101, 7),
+	})
+	assert.InDelta(t, (3.-7.)/(3.+7.), top.Last(), Delta)
+	assert.InDelta(t, (8.-8.)/(8.+8.), deep.Last(), Delta)
+
+	// the updates of the other symbols are ignored
+	stream.EmitBookUpdate(types.SliceOrderBook{Symbol: "ETHUSDT", Asks: buildPriceVolumes(10, 1)})
+	assert.Equal(t, 2, top.Length())
+
+	bid, ask, ok := book.BestBidAndAsk()
+	if assert.True(t, ok) {
+		assert.Equal(t, "100", bid.Price.String())
+		assert.Equal(t, "7", ask.Volume.String())
+	}
+}
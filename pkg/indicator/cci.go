@@ -2,6 +2,7 @@ package indicator
 
 import (
 	"math"
+	"time"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
@@ -18,7 +19,8 @@ type CCI struct {
 	MA           types.Float64Slice
 	Values       types.Float64Slice
 
-	UpdateCallbacks []func(value float64)
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *CCI) Update(value float64) {
@@ -80,14 +82,12 @@ var _ types.Series = &CCI{}
 var three = fixedpoint.NewFromInt(3)
 
 func (inc *CCI) calculateAndUpdate(allKLines []types.KLine) {
-	if inc.TypicalPrice.Length() == 0 {
-		for _, k := range allKLines {
-			inc.Update(k.High.Add(k.Low).Add(k.Close).Div(three).Float64())
-			inc.EmitUpdate(inc.Last())
+	for _, k := range allKLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
 		}
-	} else {
-		k := allKLines[len(allKLines)-1]
 		inc.Update(k.High.Add(k.Low).Add(k.Close).Div(three).Float64())
+		inc.EndTime = k.EndTime.Time()
 		inc.EmitUpdate(inc.Last())
 	}
 }
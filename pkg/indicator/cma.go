@@ -1,6 +1,8 @@
 package indicator
 
 import (
+	"time"
+
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -8,15 +10,19 @@ import (
 // Refer: https://en.wikipedia.org/wiki/Moving_average
 //go:generate callbackgen -type CA
 type CA struct {
-	Interval        types.Interval
-	Values          types.Float64Slice
-	length          float64
-	UpdateCallbacks []func(value float64)
+	Interval types.Interval
+	Values   types.Float64Slice
+
+	// Count is the number of the averaged values
+	Count float64
+
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *CA) Update(x float64) {
-	newVal := (inc.Values.Last()*inc.length + x) / (inc.length + 1.)
-	inc.length += 1
+	newVal := (inc.Values.Last()*inc.Count + x) / (inc.Count + 1.)
+	inc.Count += 1
 	inc.Values.Push(newVal)
 	if len(inc.Values) > MaxNumOfEWMA {
 		inc.Values = inc.Values[MaxNumOfEWMATruncateSize-1:]
@@ -45,7 +51,11 @@ var _ types.Series = &CA{}
 
 func (inc *CA) calculateAndUpdate(allKLines []types.KLine) {
 	for _, k := range allKLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
 		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 		inc.EmitUpdate(inc.Last())
 	}
 }
@@ -0,0 +1,96 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+cmf implements the chaikin money flow indicator
+
+Chaikin Money Flow (CMF), the sum of the money flow volume divided by the sum of the volume in the window
+- https://www.investopedia.com/ask/answers/071414/whats-difference-between-chaikin-money-flow-cmf-and-money-flow-index-mfi.asp
+
+The money flow volume of the window is the change of the accumulation/distribution line,
+the values are pushed after the first Window klines are collected.
+*/
+//go:generate callbackgen -type CMF
+type CMF struct {
+	types.IntervalWindow
+	Values  types.Float64Slice
+	Volumes types.Float64Slice
+
+	AD *AD
+
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
+}
+
+func (inc *CMF) Update(high, low, cloze, volume float64) {
+	if inc.Window <= 0 {
+		panic("window must be greater than 0")
+	}
+
+	if inc.AD == nil {
+		inc.AD = &AD{IntervalWindow: types.IntervalWindow{Window: inc.Window}}
+	}
+
+	inc.AD.Update(high, low, cloze, volume)
+	inc.Volumes.Push(volume)
+	if len(inc.Volumes) > inc.Window {
+		inc.Volumes = inc.Volumes[1:]
+	}
+
+	if len(inc.Volumes) < inc.Window {
+		return
+	}
+
+	volumeSum := inc.Volumes.Sum()
+	if volumeSum == 0 {
+		inc.Values.Push(0)
+		return
+	}
+
+	// the AD line starts from zero, so Index(Window) is zero when only Window klines are collected
+	moneyFlowVolume := inc.AD.Last() - inc.AD.Index(inc.Window)
+	inc.Values.Push(moneyFlowVolume / volumeSum)
+}
+
+func (inc *CMF) Last() float64 {
+	return inc.Values.Last()
+}
+
+func (inc *CMF) Index(i int) float64 {
+	return inc.Values.Index(i)
+}
+
+func (inc *CMF) Length() int {
+	return inc.Values.Length()
+}
+
+var _ types.Series = &CMF{}
+
+func (inc *CMF) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+		inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64(), k.Volume.Float64())
+		inc.EndTime = k.EndTime.Time()
+	}
+
+	inc.EmitUpdate(inc.Last())
+}
+
+func (inc *CMF) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.calculateAndUpdate(window)
+}
+
+func (inc *CMF) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
@@ -0,0 +1,27 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+python:
+
+mfv = ((2 * close - high - low) / (high - low)) * volume
+cmf = sum(mfv, 20) / sum(volume, 20)
+*/
+func TestCMF(t *testing.T) {
+	var Delta = 1e-9
+	kLines := buildHighLowCloseKLines(t, channelTestKLines)
+
+	cmf := &CMF{IntervalWindow: types.IntervalWindow{Window: 20}}
+	cmf.calculateAndUpdate(kLines)
+
+	assert.Equal(t, len(kLines)-19, cmf.Length())
+	assert.InDelta(t, 0.22935703217339823, cmf.Last(), Delta)
+	assert.InDelta(t, 0.22048939098235967, cmf.Index(1), Delta)
+}
@@ -0,0 +1,57 @@
+package indicator
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+cvd implements the cumulative volume delta indicator of the market trades
+
+Cumulative Volume Delta (CVD), the volume of the taker buy trades minus the volume of the taker sell trades
+- https://www.tradingview.com/support/solutions/43000725058-cumulative-volume-delta/
+
+The side of the market trade is the taker side, a value is pushed for every trade.
+*/
+//go:generate callbackgen -type CVD
+type CVD struct {
+	Symbol string
+
+	Values types.Float64Slice
+
+	UpdateCallbacks []func(value float64) `json:"-"`
+}
+
+func (inc *CVD) Update(side types.SideType, quantity float64) {
+	delta := inc.Values.Last()
+	switch side {
+	case types.SideTypeBuy:
+		delta += quantity
+	case types.SideTypeSell:
+		delta -= quantity
+	}
+
+	pushOrderFlowValue(&inc.Values, delta)
+}
+
+func (inc *CVD) Last() float64 {
+	return inc.Values.Last()
+}
+
+func (inc *CVD) Index(i int) float64 {
+	return inc.Values.Index(i)
+}
+
+func (inc *CVD) Length() int {
+	return inc.Values.Length()
+}
+
+var _ types.Series = &CVD{}
+
+func (inc *CVD) handleMarketTrade(trade types.Trade) {
+	inc.Update(trade.Side, trade.Quantity.Float64())
+	inc.EmitUpdate(inc.Last())
+}
+
+func (inc *CVD) Bind(updater MarketTradeUpdater) {
+	bindMarketTrades(updater, inc.Symbol, inc.handleMarketTrade)
+}
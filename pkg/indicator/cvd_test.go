@@ -0,0 +1,40 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func buildMarketTrade(symbol string, side types.SideType, price, quantity float64) types.Trade {
+	return types.Trade{
+		Symbol:   symbol,
+		Side:     side,
+		Price:    fixedpoint.NewFromFloat(price),
+		Quantity: fixedpoint.NewFromFloat(quantity),
+	}
+}
+
+func TestCVD_Bind(t *testing.T) {
+	stream := &types.StandardStream{}
+	cvd := &CVD{Symbol: "BTCUSDT"}
+	cvd.Bind(stream)
+
+	var updates []float64
+	cvd.OnUpdate(func(value float64) {
+		updates = append(updates, value)
+	})
+
+	stream.EmitMarketTrade(buildMarketTrade("BTCUSDT", types.SideTypeBuy, 100, 2))
+	stream.EmitMarketTrade(buildMarketTrade("BTCUSDT", types.SideTypeSell, 99, 0.5))
+	stream.EmitMarketTrade(buildMarketTrade("ETHUSDT", types.SideTypeSell, 10, 100))
+	stream.EmitMarketTrade(buildMarketTrade("BTCUSDT", types.SideTypeSell, 98, 3))
+
+	assert.InDeltaSlice(t, []float64{2, 1.5, -1.5}, updates, Delta)
+	assert.Equal(t, 3, cvd.Length())
+	assert.InDelta(t, -1.5, cvd.Last(), Delta)
+	assert.InDelta(t, 1.5, cvd.Index(1), Delta)
+}
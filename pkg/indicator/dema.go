@@ -1,6 +1,8 @@
 package indicator
 
 import (
+	"time"
+
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -11,21 +13,22 @@ import (
 type DEMA struct {
 	types.IntervalWindow
 	Values types.Float64Slice
-	a1     *EWMA
-	a2     *EWMA
+	A1     *EWMA
+	A2     *EWMA
 
-	UpdateCallbacks []func(value float64)
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *DEMA) Update(value float64) {
 	if len(inc.Values) == 0 {
-		inc.a1 = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, inc.Window}}
-		inc.a2 = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, inc.Window}}
+		inc.A1 = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, inc.Window}}
+		inc.A2 = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, inc.Window}}
 	}
 
-	inc.a1.Update(value)
-	inc.a2.Update(inc.a1.Last())
-	inc.Values.Push(2*inc.a1.Last() - inc.a2.Last())
+	inc.A1.Update(value)
+	inc.A2.Update(inc.A1.Last())
+	inc.Values.Push(2*inc.A1.Last() - inc.A2.Last())
 	if len(inc.Values) > MaxNumOfEWMA {
 		inc.Values = inc.Values[MaxNumOfEWMATruncateSize-1:]
 	}
@@ -49,13 +52,12 @@ func (inc *DEMA) Length() int {
 var _ types.Series = &DEMA{}
 
 func (inc *DEMA) calculateAndUpdate(allKLines []types.KLine) {
-	if inc.a1 == nil {
-		for _, k := range allKLines {
-			inc.Update(k.Close.Float64())
-			inc.EmitUpdate(inc.Last())
+	for _, k := range allKLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
 		}
-	} else {
-		inc.Update(allKLines[len(allKLines)-1].Close.Float64())
+		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 		inc.EmitUpdate(inc.Last())
 	}
 }
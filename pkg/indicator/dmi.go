@@ -0,0 +1,153 @@
+package indicator
+
+import (
+	"math"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+dmi implements the directional movement index indicator
+
+Directional Movement Index (DMI), the +DI, the -DI and the Average Directional Index (ADX)
+- https://www.investopedia.com/terms/d/dmi.asp
+- https://www.investopedia.com/terms/a/adx.asp
+
+The directional indicators and the ADX are smoothed by the RMA like the ATR,
+the values are pushed after the first Window true ranges are collected.
+*/
+//go:generate callbackgen -type DMI
+type DMI struct {
+	types.IntervalWindow
+
+	// ADXSmoothing is the window of the ADX smoothing, the Window is used if it's not set
+	ADXSmoothing int
+
+	DIPlus  types.Float64Slice
+	DIMinus types.Float64Slice
+	ADX     types.Float64Slice
+
+	PreviousHigh  float64
+	PreviousLow   float64
+	PreviousClose float64
+
+	// TrueRange, DirectionalPlus and DirectionalMinus are the RMAs of the true range and the directional movements,
+	// DirectionalIndex is the RMA of the directional index, which is the ADX
+	TrueRange        *RMA
+	DirectionalPlus  *RMA
+	DirectionalMinus *RMA
+	DirectionalIndex *RMA
+
+	EndTime         time.Time
+	UpdateCallbacks []func(diPlus, diMinus, adx float64) `json:"-"`
+}
+
+func (inc *DMI) Update(high, low, cloze float64) {
+	if inc.Window <= 0 {
+		panic("window must be greater than 0")
+	}
+
+	if inc.TrueRange == nil {
+		smoothing := inc.ADXSmoothing
+		if smoothing <= 0 {
+			smoothing = inc.Window
+		}
+
+		inc.TrueRange = &RMA{IntervalWindow: types.IntervalWindow{Window: inc.Window}}
+		inc.DirectionalPlus = &RMA{IntervalWindow: types.IntervalWindow{Window: inc.Window}}
+		inc.DirectionalMinus = &RMA{IntervalWindow: types.IntervalWindow{Window: inc.Window}}
+		inc.DirectionalIndex = &RMA{IntervalWindow: types.IntervalWindow{Window: smoothing}}
+		inc.PreviousHigh, inc.PreviousLow, inc.PreviousClose = high, low, cloze
+		return
+	}
+
+	trueRange := math.Max(high-low, math.Max(math.Abs(high-inc.PreviousClose), math.Abs(low-inc.PreviousClose)))
+
+	up := high - inc.PreviousHigh
+	down := inc.PreviousLow - low
+	plusDM, minusDM := 0.0, 0.0
+	if up > down && up > 0 {
+		plusDM = up
+	}
+	if down > up && down > 0 {
+		minusDM = down
+	}
+
+	inc.PreviousHigh, inc.PreviousLow, inc.PreviousClose = high, low, cloze
+
+	inc.TrueRange.Update(trueRange)
+	inc.DirectionalPlus.Update(plusDM)
+	inc.DirectionalMinus.Update(minusDM)
+	if inc.TrueRange.Length() < inc.Window {
+		return
+	}
+
+	diPlus, diMinus := 0.0, 0.0
+	if atr := inc.TrueRange.Last(); atr > 0 {
+		diPlus = 100 * inc.DirectionalPlus.Last() / atr
+		diMinus = 100 * inc.DirectionalMinus.Last() / atr
+	}
+
+	inc.DIPlus.Push(diPlus)
+	inc.DIMinus.Push(diMinus)
+
+	sum := diPlus + diMinus
+	if sum == 0 {
+		sum = 1
+	}
+
+	// the ADX is 0 until the ADX smoothing window is filled
+	inc.DirectionalIndex.Update(100 * math.Abs(diPlus-diMinus) / sum)
+	inc.ADX.Push(inc.DirectionalIndex.Last())
+}
+
+func (inc *DMI) Last() float64 {
+	return inc.ADX.Last()
+}
+
+func (inc *DMI) Index(i int) float64 {
+	return inc.ADX.Index(i)
+}
+
+func (inc *DMI) Length() int {
+	return inc.ADX.Length()
+}
+
+var _ types.Series = &DMI{}
+
+func (inc *DMI) GetDIPlus() types.Series {
+	return &inc.DIPlus
+}
+
+func (inc *DMI) GetDIMinus() types.Series {
+	return &inc.DIMinus
+}
+
+func (inc *DMI) GetADX() types.Series {
+	return &inc.ADX
+}
+
+func (inc *DMI) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+		inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
+	}
+
+	inc.EmitUpdate(inc.DIPlus.Last(), inc.DIMinus.Last(), inc.Last())
+}
+
+func (inc *DMI) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.calculateAndUpdate(window)
+}
+
+func (inc *DMI) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
@@ -0,0 +1,59 @@
+package indicator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// the binance btcusdt 1h klines, the reference values of the trend indicators are calculated
+// by the tradingview pine script functions ta.dmi, ta.sar, ta.supertrend and the ichimoku donchian lines
+var trendTestKLines = []byte(`{
+	"high": [40145.0, 40186.36, 40196.39, 40344.6, 40245.48, 40273.24, 40464.0, 40699.0, 40627.48, 40436.31, 40370.0, 40376.8, 40227.03, 40056.52, 39721.7, 39597.94, 39750.15, 39927.0, 40289.02, 40189.0],
+	"low": [39870.71, 39834.98, 39866.31, 40108.31, 40016.09, 40094.66, 40105.0, 40196.48, 40154.99, 39800.0, 39959.21, 39922.98, 39940.02, 39632.0, 39261.39, 39254.63, 39473.91, 39555.51, 39819.0, 40006.84],
+	"close": [40105.78, 39935.23, 40183.97, 40182.03, 40212.26, 40149.99, 40378.0, 40618.37, 40401.03, 39990.39, 40179.13, 40097.23, 40014.72, 39667.85, 39303.1, 39519.99, 39693.79, 39827.96, 40074.94, 40059.84]
+}`)
+
+func buildHighLowCloseKLines(t *testing.T, bytes []byte) (kLines []types.KLine) {
+	var prices map[string][]fixedpoint.Value
+	if err := json.Unmarshal(bytes, &prices); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, h := range prices["high"] {
+		kLine := types.KLine{High: h, Low: prices["low"][i], Close: prices["close"][i]}
+		if volumes, ok := prices["volume"]; ok {
+			kLine.Volume = volumes[i]
+		}
+		kLines = append(kLines, kLine)
+	}
+	return kLines
+}
+
+/*
+pine:
+
+[diplus, diminus, adx] = ta.dmi(5, 5)
+*/
+func TestDMI(t *testing.T) {
+	var Delta = 1e-6
+	kLines := buildHighLowCloseKLines(t, trendTestKLines)
+
+	dmi := &DMI{IntervalWindow: types.IntervalWindow{Window: 5}, ADXSmoothing: 5}
+	dmi.calculateAndUpdate(kLines)
+
+	wantDIPlus := []float64{19.060637597992415, 32.9345920955533, 29.512353312989706}
+	wantDIMinus := []float64{21.48932537578317, 16.34554335174891, 14.64707530277064}
+	wantADX := []float64{40.64184714429473, 39.246027694542036, 38.12937213473988}
+
+	assert.Equal(t, len(kLines)-5, dmi.Length())
+	for i := range wantADX {
+		assert.InDelta(t, wantDIPlus[i], dmi.GetDIPlus().Index(2-i), Delta)
+		assert.InDelta(t, wantDIMinus[i], dmi.GetDIMinus().Index(2-i), Delta)
+		assert.InDelta(t, wantADX[i], dmi.Index(2-i), Delta)
+	}
+}
@@ -0,0 +1,103 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+donchian implements the donchian channel indicator
+
+Donchian Channel, the highest high and the lowest low of the window
+- https://www.investopedia.com/terms/d/donchianchannels.asp
+
+The values are pushed after the first Window klines are collected.
+*/
+//go:generate callbackgen -type DonchianChannel
+type DonchianChannel struct {
+	types.IntervalWindow
+
+	Mid      types.Float64Slice
+	UpBand   types.Float64Slice
+	DownBand types.Float64Slice
+
+	HighValues types.Float64Slice
+	LowValues  types.Float64Slice
+
+	EndTime         time.Time
+	UpdateCallbacks []func(mid, upBand, downBand float64) `json:"-"`
+}
+
+func (inc *DonchianChannel) Update(high, low float64) {
+	if inc.Window <= 0 {
+		panic("window must be greater than 0")
+	}
+
+	inc.HighValues.Push(high)
+	inc.LowValues.Push(low)
+	if len(inc.HighValues) > inc.Window {
+		inc.HighValues = inc.HighValues[1:]
+		inc.LowValues = inc.LowValues[1:]
+	}
+
+	if len(inc.HighValues) < inc.Window {
+		return
+	}
+
+	upBand := inc.HighValues.Max()
+	downBand := inc.LowValues.Min()
+	inc.UpBand.Push(upBand)
+	inc.DownBand.Push(downBand)
+	inc.Mid.Push((upBand + downBand) / 2)
+}
+
+func (inc *DonchianChannel) Last() float64 {
+	return inc.Mid.Last()
+}
+
+func (inc *DonchianChannel) Index(i int) float64 {
+	return inc.Mid.Index(i)
+}
+
+func (inc *DonchianChannel) Length() int {
+	return inc.Mid.Length()
+}
+
+var _ types.Series = &DonchianChannel{}
+
+func (inc *DonchianChannel) GetMid() types.Series {
+	return &inc.Mid
+}
+
+func (inc *DonchianChannel) GetUpBand() types.Series {
+	return &inc.UpBand
+}
+
+func (inc *DonchianChannel) GetDownBand() types.Series {
+	return &inc.DownBand
+}
+
+func (inc *DonchianChannel) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+		inc.Update(k.High.Float64(), k.Low.Float64())
+		inc.EndTime = k.EndTime.Time()
+	}
+
+	inc.EmitUpdate(inc.Mid.Last(), inc.UpBand.Last(), inc.DownBand.Last())
+}
+
+func (inc *DonchianChannel) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.calculateAndUpdate(window)
+}
+
+func (inc *DonchianChannel) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
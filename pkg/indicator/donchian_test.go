@@ -0,0 +1,26 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestDonchianChannel(t *testing.T) {
+	var Delta = 1e-9
+	kLines := buildHighLowCloseKLines(t, channelTestKLines)
+
+	dc := &DonchianChannel{IntervalWindow: types.IntervalWindow{Window: 20}}
+	dc.calculateAndUpdate(kLines)
+
+	assert.Equal(t, len(kLines)-19, dc.Length())
+	assert.InDelta(t, 109.4, dc.GetUpBand().Last(), Delta)
+	assert.InDelta(t, 94.97, dc.GetDownBand().Last(), Delta)
+	assert.InDelta(t, (109.4+94.97)/2, dc.Last(), Delta)
+
+	// the first channel of the window 0 ~ 19
+	assert.InDelta(t, 114.18, dc.GetUpBand().Index(dc.Length()-1), Delta)
+	assert.InDelta(t, 99.0, dc.GetDownBand().Index(dc.Length()-1), Delta)
+}
@@ -1,11 +1,8 @@
 package indicator
 
 import (
-	"math"
 	"time"
 
-	log "github.com/sirupsen/logrus"
-
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -19,7 +16,7 @@ type EWMA struct {
 	Values       types.Float64Slice
 	LastOpenTime time.Time
 
-	UpdateCallbacks []func(value float64)
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *EWMA) Update(value float64) {
@@ -63,49 +60,15 @@ func (inc *EWMA) calculateAndUpdate(allKLines []types.KLine) {
 	}
 
 	var priceF = KLineClosePriceMapper
-	var dataLen = len(allKLines)
-	var multiplier = 2.0 / (float64(inc.Window) + 1)
-
-	// init the values fromNthK the kline data
-	var fromNthK = 1
-	if len(inc.Values) == 0 {
-		// for the first value, we should use the close price
-		inc.Values = []float64{priceF(allKLines[0])}
-	} else {
-		if len(inc.Values) >= MaxNumOfEWMA {
-			inc.Values = inc.Values[MaxNumOfEWMATruncateSize-1:]
+	for _, k := range allKLines {
+		// skip the klines that are already calculated, including the klines before the restored snapshot
+		if len(inc.Values) > 0 && !k.StartTime.After(inc.LastOpenTime) {
+			continue
 		}
 
-		fromNthK = len(inc.Values)
-
-		// update ewma with the existing values
-		for i := dataLen - 1; i > 0; i-- {
-			var k = allKLines[i]
-			if k.StartTime.After(inc.LastOpenTime) {
-				fromNthK = i
-			} else {
-				break
-			}
-		}
-	}
-
-	for i := fromNthK; i < dataLen; i++ {
-		var k = allKLines[i]
-		var ewma = priceF(k)*multiplier + (1-multiplier)*inc.Values[i-1]
-		inc.Values.Push(ewma)
+		inc.Update(priceF(k))
 		inc.LastOpenTime = k.StartTime.Time()
-		inc.EmitUpdate(ewma)
-	}
-
-	if len(inc.Values) != dataLen {
-		// check error
-		log.Warnf("%s EMA (%d) value length (%d) != kline window length (%d)", inc.Interval, inc.Window, len(inc.Values), dataLen)
-	}
-
-	v1 := math.Floor(inc.Values[len(inc.Values)-1]*100.0) / 100.0
-	v2 := math.Floor(CalculateKLinesEMA(allKLines, priceF, inc.Window)*100.0) / 100.0
-	if v1 != v2 {
-		log.Warnf("ACCUMULATED %s EMA (%d) %f != EMA %f", inc.Interval, inc.Window, v1, v2)
+		inc.EmitUpdate(inc.Last())
 	}
 }
 
@@ -2,6 +2,7 @@ package indicator
 
 import (
 	"math"
+	"time"
 
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -11,61 +12,56 @@ import (
 //go:generate callbackgen -type HULL
 type HULL struct {
 	types.IntervalWindow
-	ma1    *EWMA
-	ma2    *EWMA
-	result *EWMA
+	MA1    *EWMA
+	MA2    *EWMA
+	Result *EWMA
 
-	UpdateCallbacks []func(value float64)
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *HULL) Update(value float64) {
-	if inc.result == nil {
-		inc.ma1 = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, inc.Window / 2}}
-		inc.ma2 = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, inc.Window}}
-		inc.result = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, int(math.Sqrt(float64(inc.Window)))}}
+	if inc.Result == nil {
+		inc.MA1 = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, inc.Window / 2}}
+		inc.MA2 = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, inc.Window}}
+		inc.Result = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, int(math.Sqrt(float64(inc.Window)))}}
 	}
-	inc.ma1.Update(value)
-	inc.ma2.Update(value)
-	inc.result.Update(2*inc.ma1.Last() - inc.ma2.Last())
+	inc.MA1.Update(value)
+	inc.MA2.Update(value)
+	inc.Result.Update(2*inc.MA1.Last() - inc.MA2.Last())
 }
 
 func (inc *HULL) Last() float64 {
-	if inc.result == nil {
+	if inc.Result == nil {
 		return 0
 	}
-	return inc.result.Last()
+	return inc.Result.Last()
 }
 
 func (inc *HULL) Index(i int) float64 {
-	if inc.result == nil {
+	if inc.Result == nil {
 		return 0
 	}
-	return inc.result.Index(i)
+	return inc.Result.Index(i)
 }
 
 func (inc *HULL) Length() int {
-	if inc.result == nil {
+	if inc.Result == nil {
 		return 0
 	}
-	return inc.result.Length()
+	return inc.Result.Length()
 }
 
 var _ types.Series = &HULL{}
 
-// TODO: should we just ignore the possible overlapping?
 func (inc *HULL) calculateAndUpdate(allKLines []types.KLine) {
-	doable := false
-	if inc.ma1 == nil || inc.ma1.Length() == 0 {
-		doable = true
-	}
 	for _, k := range allKLines {
-		if !doable && k.StartTime.After(inc.ma1.LastOpenTime) {
-			doable = true
-		}
-		if doable {
-			inc.Update(k.Close.Float64())
-			inc.EmitUpdate(inc.Last())
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
 		}
+		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
+		inc.EmitUpdate(inc.Last())
 	}
 }
 
@@ -0,0 +1,143 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+ichimoku implements the ichimoku cloud indicator
+
+Ichimoku Kinko Hyo, the conversion line (tenkan-sen), the base line (kijun-sen),
+the leading span A and B (senkou span) and the lagging span (chikou span)
+- https://www.investopedia.com/terms/i/ichimoku-cloud.asp
+
+The leading spans are stored at the kline they are calculated from, they are plotted Displacement - 1 klines ahead,
+use Cloud() to get the spans of the current kline. The lagging span is the close plotted Displacement - 1 klines behind.
+The lines are calculated with the available klines before the periods are filled.
+*/
+//go:generate callbackgen -type Ichimoku
+type Ichimoku struct {
+	Interval types.Interval
+
+	// ConversionPeriod is the period of the conversion line, default 9
+	ConversionPeriod int
+
+	// BasePeriod is the period of the base line, default 26
+	BasePeriod int
+
+	// LeadingSpanBPeriod is the period of the leading span B, default 52
+	LeadingSpanBPeriod int
+
+	// Displacement is the offset of the leading spans and the lagging span, default 26
+	Displacement int
+
+	ConversionLine types.Float64Slice
+	BaseLine       types.Float64Slice
+	LeadingSpanA   types.Float64Slice
+	LeadingSpanB   types.Float64Slice
+	LaggingSpan    types.Float64Slice
+
+	High types.Float64Slice
+	Low  types.Float64Slice
+
+	EndTime         time.Time
+	UpdateCallbacks []func(conversionLine, baseLine, leadingSpanA, leadingSpanB float64) `json:"-"`
+}
+
+func (inc *Ichimoku) Update(high, low, cloze float64) {
+	if inc.ConversionPeriod == 0 {
+		inc.ConversionPeriod = 9
+	}
+	if inc.BasePeriod == 0 {
+		inc.BasePeriod = 26
+	}
+	if inc.LeadingSpanBPeriod == 0 {
+		inc.LeadingSpanBPeriod = 52
+	}
+	if inc.Displacement == 0 {
+		inc.Displacement = 26
+	}
+
+	inc.High.Push(high)
+	inc.Low.Push(low)
+
+	conversionLine := inc.donchian(inc.ConversionPeriod)
+	baseLine := inc.donchian(inc.BasePeriod)
+
+	inc.ConversionLine.Push(conversionLine)
+	inc.BaseLine.Push(baseLine)
+	inc.LeadingSpanA.Push((conversionLine + baseLine) / 2)
+	inc.LeadingSpanB.Push(inc.donchian(inc.LeadingSpanBPeriod))
+	inc.LaggingSpan.Push(cloze)
+}
+
+// donchian returns the middle of the highest high and the lowest low in the period
+func (inc *Ichimoku) donchian(period int) float64 {
+	return (inc.High.Tail(period).Max() + inc.Low.Tail(period).Min()) / 2
+}
+
+// Cloud returns the leading spans plotted at the current kline,
+// zeros are returned if there are not enough klines
+func (inc *Ichimoku) Cloud() (leadingSpanA, leadingSpanB float64) {
+	return inc.LeadingSpanA.Index(inc.Displacement - 1), inc.LeadingSpanB.Index(inc.Displacement - 1)
+}
+
+func (inc *Ichimoku) Last() float64 {
+	return inc.BaseLine.Last()
+}
+
+func (inc *Ichimoku) Index(i int) float64 {
+	return inc.BaseLine.Index(i)
+}
+
+func (inc *Ichimoku) Length() int {
+	return inc.BaseLine.Length()
+}
+
+var _ types.Series = &Ichimoku{}
+
+func (inc *Ichimoku) GetConversionLine() types.Series {
+	return &inc.ConversionLine
+}
+
+func (inc *Ichimoku) GetBaseLine() types.Series {
+	return &inc.BaseLine
+}
+
+func (inc *Ichimoku) GetLeadingSpanA() types.Series {
+	return &inc.LeadingSpanA
+}
+
+func (inc *Ichimoku) GetLeadingSpanB() types.Series {
+	return &inc.LeadingSpanB
+}
+
+func (inc *Ichimoku) GetLaggingSpan() types.Series {
+	return &inc.LaggingSpan
+}
+
+func (inc *Ichimoku) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+		inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
+	}
+
+	inc.EmitUpdate(inc.ConversionLine.Last(), inc.BaseLine.Last(), inc.LeadingSpanA.Last(), inc.LeadingSpanB.Last())
+}
+
+func (inc *Ichimoku) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.calculateAndUpdate(window)
+}
+
+func (inc *Ichimoku) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
@@ -0,0 +1,46 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+pine:
+
+donchian(len) => math.avg(ta.lowest(len), ta.highest(len))
+conversionLine = donchian(3)
+baseLine = donchian(5)
+leadLine1 = math.avg(conversionLine, baseLine)
+leadLine2 = donchian(10)
+*/
+func TestIchimoku(t *testing.T) {
+	var Delta = 1e-6
+	kLines := buildHighLowCloseKLines(t, trendTestKLines)
+
+	ichimoku := &Ichimoku{
+		Interval:           types.Interval1h,
+		ConversionPeriod:   3,
+		BasePeriod:         5,
+		LeadingSpanBPeriod: 10,
+		Displacement:       2,
+	}
+	ichimoku.calculateAndUpdate(kLines)
+
+	assert.Equal(t, len(kLines), ichimoku.Length())
+	assert.InDelta(t, 39922.265, ichimoku.GetConversionLine().Last(), Delta)
+	assert.InDelta(t, 39881.465, ichimoku.GetConversionLine().Index(1), Delta)
+	assert.InDelta(t, 39771.825, ichimoku.Last(), Delta)
+	assert.InDelta(t, 39771.825, ichimoku.Index(1), Delta)
+	assert.InDelta(t, 39847.045, ichimoku.GetLeadingSpanA().Last(), Delta)
+	assert.InDelta(t, 39815.715, ichimoku.GetLeadingSpanB().Last(), Delta)
+	assert.InDelta(t, 40059.84, ichimoku.GetLaggingSpan().Last(), Delta)
+
+	// the cloud of the current kline is calculated from the previous kline
+	spanA, spanB := ichimoku.Cloud()
+	assert.InDelta(t, 39826.645, spanA, Delta)
+	assert.InDelta(t, 39845.47, spanB, Delta)
+}
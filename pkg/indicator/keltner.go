@@ -0,0 +1,118 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+keltner implements the keltner channel indicator
+
+Keltner Channel, the ATR bands around the EWMA of the close price
+- https://www.investopedia.com/terms/k/keltnerchannel.asp
+
+The Window is the EWMA window, the values are pushed after the first ATRWindow true ranges are collected.
+*/
+//go:generate callbackgen -type KeltnerChannel
+type KeltnerChannel struct {
+	types.IntervalWindow
+
+	// ATRWindow is the window of the ATR, the Window is used if it's not set
+	ATRWindow int
+
+	// ATRMultiplier is the times of the ATR for the band width, default 2
+	ATRMultiplier float64
+
+	Mid      types.Float64Slice
+	UpBand   types.Float64Slice
+	DownBand types.Float64Slice
+
+	EWMA *EWMA
+	ATR  *ATR
+
+	EndTime         time.Time
+	UpdateCallbacks []func(mid, upBand, downBand float64) `json:"-"`
+}
+
+func (inc *KeltnerChannel) Update(high, low, cloze float64) {
+	if inc.Window <= 0 {
+		panic("window must be greater than 0")
+	}
+
+	if inc.ATRMultiplier == 0 {
+		inc.ATRMultiplier = 2
+	}
+
+	if inc.EWMA == nil {
+		atrWindow := inc.ATRWindow
+		if atrWindow <= 0 {
+			atrWindow = inc.Window
+		}
+
+		inc.EWMA = &EWMA{IntervalWindow: types.IntervalWindow{Window: inc.Window}}
+		inc.ATR = &ATR{IntervalWindow: types.IntervalWindow{Window: atrWindow}}
+	}
+
+	inc.EWMA.Update(cloze)
+	inc.ATR.Update(high, low, cloze)
+	if inc.ATR.Length() < inc.ATR.Window {
+		return
+	}
+
+	mid := inc.EWMA.Last()
+	band := inc.ATRMultiplier * inc.ATR.Last()
+	inc.Mid.Push(mid)
+	inc.UpBand.Push(mid + band)
+	inc.DownBand.Push(mid - band)
+}
+
+func (inc *KeltnerChannel) Last() float64 {
+	return inc.Mid.Last()
+}
+
+func (inc *KeltnerChannel) Index(i int) float64 {
+	return inc.Mid.Index(i)
+}
+
+func (inc *KeltnerChannel) Length() int {
+	return inc.Mid.Length()
+}
+
+var _ types.Series = &KeltnerChannel{}
+
+func (inc *KeltnerChannel) GetMid() types.Series {
+	return &inc.Mid
+}
+
+func (inc *KeltnerChannel) GetUpBand() types.Series {
+	return &inc.UpBand
+}
+
+func (inc *KeltnerChannel) GetDownBand() types.Series {
+	return &inc.DownBand
+}
+
+func (inc *KeltnerChannel) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+		inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
+	}
+
+	inc.EmitUpdate(inc.Mid.Last(), inc.UpBand.Last(), inc.DownBand.Last())
+}
+
+func (inc *KeltnerChannel) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.calculateAndUpdate(window)
+}
+
+func (inc *KeltnerChannel) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
@@ -0,0 +1,50 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// the synthetic klines of the channel and the money flow indicators
+/*
+python:
+
+import random, math
+
+random.seed(7)
+price = 100.0
+for i in range(50):
+    op = price
+    cl = round(op + math.sin(i / 5) * 1.5 + random.uniform(-1, 1), 2)
+    hi = round(max(op, cl) + random.uniform(0, 1), 2)
+    lo = round(min(op, cl) - random.uniform(0, 1), 2)
+    vo = round(random.uniform(100, 1000), 2)
+    price = cl
+*/
+var channelTestKLines = []byte(`{"high": [100.15, 100.39, 100.45, 101.21, 102.66, 103.97, 104.73, 106.03, 107.1, 109.29, 110.43, 111.4, 112.59, 113.67, 113.58, 113.76, 113.98, 114.18, 114.07, 113.38, 111.68, 110.01, 108.16, 106.3, 105.47, 103.04, 100.98, 100.14, 98.77, 98.11, 97.73, 96.77, 95.75, 95.91, 96.34, 97.02, 98.5, 99.31, 100.5, 101.16, 104.04, 104.86, 105.25, 107.65, 107.69, 107.45, 108.54, 109.4, 109.24, 108.78], "low": [99.0, 99.59, 99.61, 99.56, 99.8, 100.85, 103.61, 103.97, 105.39, 106.73, 108.07, 109.6, 109.85, 112.15, 111.73, 111.79, 112.61, 113.02, 111.89, 111.54, 109.7, 107.32, 104.89, 104.31, 101.85, 100.49, 99.57, 97.2, 96.55, 96.36, 96.6, 95.49, 95.55, 95.19, 94.97, 95.34, 96.81, 97.19, 98.52, 99.02, 100.87, 102.81, 103.28, 104.21, 106.48, 106.58, 105.9, 107.87, 107.55, 107.52], "close": [99.65, 100.02, 99.68, 100.38, 101.71, 103.92, 104.61, 105.45, 107.04, 108.86, 110.13, 110.83, 112.3, 112.91, 112.49, 113.45, 113.52, 113.08, 112.71, 111.56, 109.76, 107.71, 105.44, 104.59, 102.81, 100.72, 99.57, 98.15, 97.23, 97.33, 96.7, 95.7, 95.55, 95.22, 96.19, 96.9, 98.02, 99.05, 99.55, 101.13, 103.34, 104.09, 105.03, 106.84, 107.17, 106.84, 108.09, 109.04, 108.17, 108.3], "volume": [165.19, 556.69, 181.64, 300.92, 457.01, 360.65, 834.51, 435.16, 285.36, 627.01, 729.09, 887.62, 206.26, 540.07, 615.72, 634.93, 950.21, 731.34, 356.14, 515.53, 791.41, 884.28, 895.05, 473.77, 235.83, 536.47, 477.05, 721.44, 148.59, 818.09, 670.86, 246.07, 236.14, 886.9, 412.65, 993.79, 191.97, 245.29, 231.94, 980.65, 430.03, 801.15, 986.43, 765.89, 126.08, 723.27, 989.23, 304.16, 910.28, 819.68]}`)
+
+/*
+python:
+
+mid = ema(close, 20) # seeded by the first close
+atr = rma(tr, 10)
+up, down = mid + 2 * atr, mid - 2 * atr
+*/
+func TestKeltnerChannel(t *testing.T) {
+	var Delta = 1e-6
+	kLines := buildHighLowCloseKLines(t, channelTestKLines)
+
+	kc := &KeltnerChannel{IntervalWindow: types.IntervalWindow{Window: 20}, ATRWindow: 10}
+	kc.calculateAndUpdate(kLines)
+
+	assert.Equal(t, len(kLines)-10, kc.Length())
+	assert.InDelta(t, 104.56419358906376, kc.Last(), Delta)
+	assert.InDelta(t, 104.1709508089652, kc.Index(1), Delta)
+	assert.InDelta(t, 108.23450815821428, kc.GetUpBand().Last(), Delta)
+	assert.InDelta(t, 107.96907810802134, kc.GetUpBand().Index(1), Delta)
+	assert.InDelta(t, 100.89387901991324, kc.GetDownBand().Last(), Delta)
+	assert.InDelta(t, 100.37282350990907, kc.GetDownBand().Index(1), Delta)
+}
@@ -26,7 +26,7 @@ type MACD struct {
 
 	EndTime time.Time
 
-	UpdateCallbacks []func(value float64)
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *MACD) Update(x float64) {
@@ -68,10 +68,10 @@ func (inc *MACD) calculateAndUpdate(kLines []types.KLine) {
 			continue
 		}
 		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 	}
 
 	inc.EmitUpdate(inc.Values[len(inc.Values)-1])
-	inc.EndTime = kLines[len(kLines)-1].EndTime.Time()
 }
 
 func (inc *MACD) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
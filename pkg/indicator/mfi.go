@@ -0,0 +1,108 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+mfi implements the money flow index indicator
+
+Money Flow Index (MFI), the volume weighted RSI of the typical price
+- https://www.investopedia.com/terms/m/mfi.asp
+
+The values are pushed after the first Window money flows are collected.
+*/
+//go:generate callbackgen -type MFI
+type MFI struct {
+	types.IntervalWindow
+	Values types.Float64Slice
+
+	PositiveFlows types.Float64Slice
+	NegativeFlows types.Float64Slice
+
+	PreviousTypicalPrice float64
+
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
+}
+
+func (inc *MFI) Update(high, low, cloze, volume float64) {
+	if inc.Window <= 0 {
+		panic("window must be greater than 0")
+	}
+
+	typicalPrice := (high + low + cloze) / 3
+	if inc.PreviousTypicalPrice == 0 {
+		inc.PreviousTypicalPrice = typicalPrice
+		return
+	}
+
+	positiveFlow, negativeFlow := 0.0, 0.0
+	rawMoneyFlow := typicalPrice * volume
+	if typicalPrice > inc.PreviousTypicalPrice {
+		positiveFlow = rawMoneyFlow
+	} else if typicalPrice < inc.PreviousTypicalPrice {
+		negativeFlow = rawMoneyFlow
+	}
+
+	inc.PreviousTypicalPrice = typicalPrice
+	inc.PositiveFlows.Push(positiveFlow)
+	inc.NegativeFlows.Push(negativeFlow)
+	if len(inc.PositiveFlows) > inc.Window {
+		inc.PositiveFlows = inc.PositiveFlows[1:]
+		inc.NegativeFlows = inc.NegativeFlows[1:]
+	}
+
+	if len(inc.PositiveFlows) < inc.Window {
+		return
+	}
+
+	negativeSum := inc.NegativeFlows.Sum()
+	if negativeSum == 0 {
+		inc.Values.Push(100)
+		return
+	}
+
+	moneyRatio := inc.PositiveFlows.Sum() / negativeSum
+	inc.Values.Push(100 - 100/(1+moneyRatio))
+}
+
+func (inc *MFI) Last() float64 {
+	return inc.Values.Last()
+}
+
+func (inc *MFI) Index(i int) float64 {
+	return inc.Values.Index(i)
+}
+
+func (inc *MFI) Length() int {
+	return inc.Values.Length()
+}
+
+var _ types.Series = &MFI{}
+
+func (inc *MFI) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+		inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64(), k.Volume.Float64())
+		inc.EndTime = k.EndTime.Time()
+	}
+
+	inc.EmitUpdate(inc.Last())
+}
+
+func (inc *MFI) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.calculateAndUpdate(window)
+}
+
+func (inc *MFI) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
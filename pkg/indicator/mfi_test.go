@@ -0,0 +1,28 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+python:
+
+tp = (high + low + close) / 3
+flow = tp * volume
+mfi = 100 - 100 / (1 + sum(positive flows, 14) / sum(negative flows, 14))
+*/
+func TestMFI(t *testing.T) {
+	var Delta = 1e-6
+	kLines := buildHighLowCloseKLines(t, channelTestKLines)
+
+	mfi := &MFI{IntervalWindow: types.IntervalWindow{Window: 14}}
+	mfi.calculateAndUpdate(kLines)
+
+	assert.Equal(t, len(kLines)-14, mfi.Length())
+	assert.InDelta(t, 70.39740791307602, mfi.Last(), Delta)
+	assert.InDelta(t, 80.4736041218743, mfi.Index(1), Delta)
+}
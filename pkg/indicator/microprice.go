@@ -0,0 +1,63 @@
+package indicator
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+microprice implements the micro-price of the order book
+
+Micro-Price, the mid price weighted by the volumes of the best bid and the best ask
+- https://papers.ssrn.com/sol3/papers.cfm?abstract_id=2970694
+
+	microprice = (bid price * ask volume + ask price * bid volume) / (bid volume + ask volume)
+
+The price moves toward the ask when the bid volume is larger, a value is pushed for every book update with both sides.
+*/
+//go:generate callbackgen -type Microprice
+type Microprice struct {
+	Symbol string
+	Values types.Float64Slice
+
+	UpdateCallbacks []func(value float64) `json:"-"`
+}
+
+func (inc *Microprice) Update(bid, ask types.PriceVolume) {
+	bidVolume, askVolume := bid.Volume.Float64(), ask.Volume.Float64()
+	if bidVolume+askVolume == 0 {
+		return
+	}
+
+	pushOrderFlowValue(&inc.Values, (bid.Price.Float64()*askVolume+ask.Price.Float64()*bidVolume)/(bidVolume+askVolume))
+}
+
+func (inc *Microprice) Last() float64 {
+	return inc.Values.Last()
+}
+
+func (inc *Microprice) Index(i int) float64 {
+	return inc.Values.Index(i)
+}
+
+func (inc *Microprice) Length() int {
+	return inc.Values.Length()
+}
+
+var _ types.Series = &Microprice{}
+
+func (inc *Microprice) handleBook(bids, asks types.PriceVolumeSlice) {
+	if len(bids) == 0 || len(asks) == 0 {
+		return
+	}
+
+	length := inc.Length()
+	inc.Update(bids[0], asks[0])
+	if inc.Length() != length {
+		inc.EmitUpdate(inc.Last())
+	}
+}
+
+// Bind binds the indicator to the book snapshots and updates of the symbol
+func (inc *Microprice) Bind(updater OrderBookUpdater) {
+	bindOrderBook(updater, inc.Symbol, 1, inc.handleBook)
+}
@@ -0,0 +1,30 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestMicroprice_Bind(t *testing.T) {
+	stream := &types.StandardStream{}
+	microprice := &Microprice{Symbol: "BTCUSDT"}
+	microprice.Bind(stream)
+
+	var updates []float64
+	microprice.OnUpdate(func(value float64) {
+		updates = append(updates, value)
+	})
+
+	// no ask yet
+	stream.EmitBookSnapshot(types.SliceOrderBook{Symbol: "BTCUSDT", Bids: buildPriceVolumes(100, 3)})
+	assert.Equal(t, 0, microprice.Length())
+
+	stream.EmitBookUpdate(types.SliceOrderBook{Symbol: "BTCUSDT", Asks: buildPriceVolumes(102, 1)})
+	stream.EmitBookUpdate(types.SliceOrderBook{Symbol: "BTCUSDT", Asks: buildPriceVolumes(102, 3)})
+
+	// the larger bid volume moves the price toward the ask
+	assert.InDeltaSlice(t, []float64{(100*1 + 102*3) / 4., 101}, updates, Delta)
+}
@@ -19,7 +19,7 @@ type OBV struct {
 	PrePrice float64
 
 	EndTime         time.Time
-	UpdateCallbacks []func(value float64)
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *OBV) Update(price, volume float64) {
@@ -49,9 +49,9 @@ func (inc *OBV) calculateAndUpdate(kLines []types.KLine) {
 			continue
 		}
 		inc.Update(k.Close.Float64(), k.Volume.Float64())
+		inc.EndTime = k.EndTime.Time()
 	}
 	inc.EmitUpdate(inc.Last())
-	inc.EndTime = kLines[len(kLines)-1].EndTime.Time()
 }
 
 func (inc *OBV) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
@@ -0,0 +1,75 @@
+package indicator
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// The order flow indicators are updated by every market trade or order book update,
+// so their values are truncated like the kline indicators
+const MaxNumOfOrderFlow = 5_000
+const MaxNumOfOrderFlowTruncateSize = 100
+
+// MarketTradeUpdater emits the market trades, types.Stream implements it
+type MarketTradeUpdater interface {
+	OnMarketTrade(cb func(trade types.Trade))
+}
+
+// OrderBookUpdater emits the order book snapshots and updates, types.Stream implements it
+type OrderBookUpdater interface {
+	OnBookSnapshot(cb func(book types.SliceOrderBook))
+	OnBookUpdate(cb func(book types.SliceOrderBook))
+}
+
+func pushOrderFlowValue(values *types.Float64Slice, value float64) {
+	values.Push(value)
+	if len(*values) > MaxNumOfOrderFlow {
+		*values = (*values)[MaxNumOfOrderFlowTruncateSize-1:]
+	}
+}
+
+// bindMarketTrades calls the handler with the market trades of the symbol
+func bindMarketTrades(updater MarketTradeUpdater, symbol string, handler func(trade types.Trade)) {
+	updater.OnMarketTrade(func(trade types.Trade) {
+		if trade.Symbol != symbol {
+			return
+		}
+
+		handler(trade)
+	})
+}
+
+// bindOrderBook calls the handler with the top depth levels of the symbol when the book is updated by the stream,
+// the indicator applies the snapshots and the updates to its own book,
+// so it does not depend on the order of the callbacks registered on the stream
+func bindOrderBook(updater OrderBookUpdater, symbol string, depth int, handler func(bids, asks types.PriceVolumeSlice)) {
+	book := types.NewMutexOrderBook(symbol)
+	handle := func() {
+		snapshot := book.CopyDepth(depth)
+		handler(snapshot.SideBook(types.SideTypeBuy), snapshot.SideBook(types.SideTypeSell))
+	}
+
+	updater.OnBookSnapshot(func(snapshot types.SliceOrderBook) {
+		if snapshot.Symbol != symbol {
+			return
+		}
+
+		book.Load(snapshot)
+		handle()
+	})
+
+	updater.OnBookUpdate(func(update types.SliceOrderBook) {
+		if update.Symbol != symbol {
+			return
+		}
+
+		book.Update(update)
+		handle()
+	})
+}
+
+func sumVolume(slice types.PriceVolumeSlice) (volume float64) {
+	for _, pv := range slice {
+		volume += pv.Volume.Float64()
+	}
+	return volume
+}
@@ -0,0 +1,167 @@
+package indicator
+
+import (
+	"math"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+psar implements the parabolic stop and reverse indicator
+
+Parabolic SAR
+- https://www.investopedia.com/terms/p/parabolicindicator.asp
+
+The initial trend is decided by the first two closes, the values are pushed from the second kline.
+*/
+//go:generate callbackgen -type PSAR
+type PSAR struct {
+	Interval types.Interval
+
+	// AFStart is the initial acceleration factor, default 0.02
+	AFStart float64
+
+	// AFIncrement is added to the acceleration factor when a new extreme point is made, default 0.02
+	AFIncrement float64
+
+	// AFMax is the maximum acceleration factor, default 0.2
+	AFMax float64
+
+	Values types.Float64Slice
+
+	// Falling is true when the SAR is above the price
+	Falling bool
+
+	// ExtremePoint is the highest high of the rising trend or the lowest low of the falling trend
+	ExtremePoint float64
+
+	// AF is the current acceleration factor
+	AF float64
+
+	High          types.Float64Slice
+	Low           types.Float64Slice
+	PreviousClose float64
+
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
+}
+
+func (inc *PSAR) Update(high, low, cloze float64) {
+	if inc.AFStart == 0 {
+		inc.AFStart = 0.02
+	}
+	if inc.AFIncrement == 0 {
+		inc.AFIncrement = 0.02
+	}
+	if inc.AFMax == 0 {
+		inc.AFMax = 0.2
+	}
+
+	inc.High.Push(high)
+	inc.Low.Push(low)
+
+	length := len(inc.High)
+	if length == 1 {
+		inc.PreviousClose = cloze
+		return
+	}
+
+	sar := inc.Values.Last()
+	newTrend := false
+	if length == 2 {
+		if cloze > inc.PreviousClose {
+			inc.Falling = false
+			inc.ExtremePoint = high
+			sar = inc.Low.Index(1)
+		} else {
+			inc.Falling = true
+			inc.ExtremePoint = low
+			sar = inc.High.Index(1)
+		}
+
+		newTrend = true
+		inc.AF = inc.AFStart
+	}
+
+	sar += inc.AF * (inc.ExtremePoint - sar)
+
+	// reverse the trend when the price penetrates the SAR
+	if !inc.Falling && sar > low {
+		newTrend = true
+		inc.Falling = true
+		sar = math.Max(high, inc.ExtremePoint)
+		inc.ExtremePoint = low
+		inc.AF = inc.AFStart
+	} else if inc.Falling && sar < high {
+		newTrend = true
+		inc.Falling = false
+		sar = math.Min(low, inc.ExtremePoint)
+		inc.ExtremePoint = high
+		inc.AF = inc.AFStart
+	}
+
+	if !newTrend {
+		if !inc.Falling && high > inc.ExtremePoint {
+			inc.ExtremePoint = high
+			inc.AF = math.Min(inc.AF+inc.AFIncrement, inc.AFMax)
+		} else if inc.Falling && low < inc.ExtremePoint {
+			inc.ExtremePoint = low
+			inc.AF = math.Min(inc.AF+inc.AFIncrement, inc.AFMax)
+		}
+	}
+
+	// the SAR can not move into the range of the previous two klines
+	if inc.Falling {
+		sar = math.Max(sar, inc.High.Index(1))
+		if length > 2 {
+			sar = math.Max(sar, inc.High.Index(2))
+		}
+	} else {
+		sar = math.Min(sar, inc.Low.Index(1))
+		if length > 2 {
+			sar = math.Min(sar, inc.Low.Index(2))
+		}
+	}
+
+	inc.PreviousClose = cloze
+	inc.Values.Push(sar)
+}
+
+func (inc *PSAR) Last() float64 {
+	return inc.Values.Last()
+}
+
+func (inc *PSAR) Index(i int) float64 {
+	return inc.Values.Index(i)
+}
+
+func (inc *PSAR) Length() int {
+	return inc.Values.Length()
+}
+
+var _ types.Series = &PSAR{}
+
+func (inc *PSAR) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+		inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
+	}
+
+	inc.EmitUpdate(inc.Last())
+}
+
+func (inc *PSAR) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.calculateAndUpdate(window)
+}
+
+func (inc *PSAR) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
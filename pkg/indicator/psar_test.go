@@ -0,0 +1,31 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+pine:
+
+sar = ta.sar(0.02, 0.02, 0.2)
+*/
+func TestPSAR(t *testing.T) {
+	var Delta = 1e-6
+	kLines := buildHighLowCloseKLines(t, trendTestKLines)
+
+	psar := &PSAR{Interval: types.Interval1h}
+	psar.calculateAndUpdate(kLines)
+
+	want := []float64{40509.644779798014, 40409.24359741417, 40316.87450962103, 39254.63, 39275.3178}
+	assert.Equal(t, len(kLines)-1, psar.Length())
+	for i, v := range want {
+		assert.InDelta(t, v, psar.Index(len(want)-1-i), Delta)
+	}
+
+	// the trend is reversed to rising at the second last kline
+	assert.False(t, psar.Falling)
+}
@@ -14,7 +14,7 @@ type RMA struct {
 	Sources types.Float64Slice
 
 	EndTime         time.Time
-	UpdateCallbacks []func(value float64)
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *RMA) Update(x float64) {
@@ -59,10 +59,10 @@ func (inc *RMA) calculateAndUpdate(kLines []types.KLine) {
 			continue
 		}
 		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 	}
 
 	inc.EmitUpdate(inc.Last())
-	inc.EndTime = kLines[len(kLines)-1].EndTime.Time()
 }
 func (inc *RMA) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
 	if inc.Interval != interval {
@@ -0,0 +1,217 @@
+package indicator
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// RollingWindow keeps the last Window values in a ring buffer with the rolling sums of them,
+// so the mean and the standard deviation of the window are updated without summing the window again.
+//
+// The values are summed after subtracting Shift, so the sum of the squares keeps the precision of the small deviations
+// of the large prices. The sums are calculated again from the ring buffer when it wraps around,
+// hence the rounding errors of adding and removing the values don't accumulate.
+//
+// When Precise is set, the values and the sums are kept in fixedpoint.Value, so a removed value is subtracted exactly
+// as it was added. Push the values of the klines with PushFixed to keep their precision.
+// The default int64 fixedpoint.Value overflows beyond about 9.2e10, so the sum of the window should stay in the range,
+// and the square sum falls back to float64 when the deviations of the values are too large to be squared in the range.
+//
+// The square sums are only tracked when Squares is set, which is required by Variance and StdDev.
+type RollingWindow struct {
+	Window  int
+	Precise bool
+	Squares bool
+
+	// Values is the ring buffer, Head is the index of the oldest value when the buffer is full
+	Values []float64
+	Head   int
+
+	Shift     float64
+	Sum       float64
+	SquareSum float64
+
+	// FixedValues is the ring buffer of the precise values
+	FixedValues    []fixedpoint.Value
+	FixedShift     fixedpoint.Value
+	FixedSum       fixedpoint.Value
+	FixedSquareSum fixedpoint.Value
+
+	// LargeDeviations is the number of the precise values in the window that are not squared in fixedpoint.Value
+	LargeDeviations int
+}
+
+// maxFixedSquareSum is the safe range of the precise square sum, the max int64 fixedpoint.Value is about 9.2e10
+const maxFixedSquareSum = 9e10
+
+// NewRollingWindow creates the rolling window, set squares to track the square sums for the variance
+func NewRollingWindow(window int, precise, squares bool) *RollingWindow {
+	if window <= 0 {
+		panic("window must be greater than 0")
+	}
+
+	return &RollingWindow{Window: window, Precise: precise, Squares: squares}
+}
+
+// Push adds the value to the window, the oldest value is removed when the window is full
+func (w *RollingWindow) Push(value float64) {
+	if w.Precise {
+		w.PushFixed(fixedpoint.NewFromFloat(value))
+		return
+	}
+
+	w.push(value, fixedpoint.Zero)
+}
+
+// PushFixed adds the fixedpoint value to the window, the oldest value is removed when the window is full
+func (w *RollingWindow) PushFixed(value fixedpoint.Value) {
+	w.push(value.Float64(), value)
+}
+
+func (w *RollingWindow) push(value float64, fixed fixedpoint.Value) {
+	if len(w.Values) < w.Window {
+		if len(w.Values) == 0 {
+			w.Shift, w.FixedShift = value, fixed
+		}
+
+		w.Values = append(w.Values, value)
+		if w.Precise {
+			w.FixedValues = append(w.FixedValues, fixed)
+		}
+		w.add(len(w.Values)-1, false)
+		return
+	}
+
+	w.add(w.Head, true)
+	w.Values[w.Head] = value
+	if w.Precise {
+		w.FixedValues[w.Head] = fixed
+	}
+	w.add(w.Head, false)
+
+	w.Head = (w.Head + 1) % w.Window
+	if w.Head == 0 {
+		w.resum()
+	}
+}
+
+// add adds the value at the index of the ring buffer to the sums, or removes it from the sums
+func (w *RollingWindow) add(i int, remove bool) {
+	if w.Precise {
+		d := w.FixedValues[i].Sub(w.FixedShift)
+		if remove {
+			w.FixedSum = w.FixedSum.Sub(d)
+		} else {
+			w.FixedSum = w.FixedSum.Add(d)
+		}
+
+		if w.Squares {
+			w.addFixedSquare(d, remove)
+		}
+		return
+	}
+
+	d := w.Values[i] - w.Shift
+	if remove {
+		w.Sum -= d
+	} else {
+		w.Sum += d
+	}
+
+	if w.Squares {
+		if remove {
+			w.SquareSum -= d * d
+		} else {
+			w.SquareSum += d * d
+		}
+	}
+}
+
+// addFixedSquare adds the square of the precise deviation to the square sums,
+// the float square sum is used by Variance when any deviation in the window is too large to be squared in fixedpoint.Value
+func (w *RollingWindow) addFixedSquare(d fixedpoint.Value, remove bool) {
+	df := d.Float64()
+	large := df*df > maxFixedSquareSum/float64(w.Window)
+	if remove {
+		w.SquareSum -= df * df
+		if large {
+			w.LargeDeviations--
+		} else {
+			w.FixedSquareSum = w.FixedSquareSum.Sub(d.Mul(d))
+		}
+	} else {
+		w.SquareSum += df * df
+		if large {
+			w.LargeDeviations++
+		} else {
+			w.FixedSquareSum = w.FixedSquareSum.Add(d.Mul(d))
+		}
+	}
+}
+
+// resum moves the shift to the latest value and sums the values again
+func (w *RollingWindow) resum() {
+	last := (w.Head + w.Window - 1) % w.Window
+	w.Shift = w.Values[last]
+	w.Sum, w.SquareSum = 0, 0
+	w.LargeDeviations = 0
+	if w.Precise {
+		w.FixedShift = w.FixedValues[last]
+		w.FixedSum, w.FixedSquareSum = fixedpoint.Zero, fixedpoint.Zero
+	}
+
+	for i := range w.Values {
+		w.add(i, false)
+	}
+}
+
+// Full returns true when the window is filled with the values
+func (w *RollingWindow) Full() bool {
+	return len(w.Values) == w.Window
+}
+
+func (w *RollingWindow) Length() int {
+	return len(w.Values)
+}
+
+// Mean returns the mean of the values in the window
+func (w *RollingWindow) Mean() float64 {
+	if len(w.Values) == 0 {
+		return 0
+	}
+
+	n := float64(len(w.Values))
+	if w.Precise {
+		return w.FixedShift.Float64() + w.FixedSum.Float64()/n
+	}
+
+	return w.Shift + w.Sum/n
+}
+
+// Variance returns the population variance of the values in the window, it requires Squares to be set
+func (w *RollingWindow) Variance() float64 {
+	if !w.Squares {
+		panic("the square sums are not tracked, set Squares to calculate the variance")
+	}
+
+	if len(w.Values) == 0 {
+		return 0
+	}
+
+	n := float64(len(w.Values))
+	sum, squareSum := w.Sum, w.SquareSum
+	if w.Precise {
+		sum = w.FixedSum.Float64()
+		if w.LargeDeviations == 0 {
+			squareSum = w.FixedSquareSum.Float64()
+		}
+	}
+
+	return math.Max(0, (squareSum-sum*sum/n)/n)
+}
+
+// StdDev returns the population standard deviation of the values in the window
+func (w *RollingWindow) StdDev() float64 {
+	return math.Sqrt(w.Variance())
+}
@@ -0,0 +1,199 @@
+package indicator
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type movingAverageGolden struct {
+	kLines                              []types.KLine
+	sma, vwma, stdDev, upBand, downBand []float64
+}
+
+/*
+testdata/moving_averages.csv contains the synthetic klines and the reference values of the 20 window,
+they are calculated with the rational numbers by the following definitions, the columns are rounded to 8 decimals:
+
+	w = rows[i - 19:i + 1]
+	sma = sum(c for c, _ in w) / 20
+	vwma = sum(c * v for c, v in w) / sum(v for _, v in w)
+	stdev = sqrt(sum((c - sma) ** 2 for c, _ in w) / 20)
+	upper, lower = sma + 2 * stdev, sma - 2 * stdev
+*/
+func loadMovingAverageGolden(t *testing.T) (golden movingAverageGolden) {
+	f, err := os.Open("testdata/moving_averages.csv")
+	require.NoError(t, err)
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, record := range records[1:] {
+		var values []float64
+		for _, field := range record[2:] {
+			value, err := strconv.ParseFloat(field, 64)
+			require.NoError(t, err)
+			values = append(values, value)
+		}
+
+		golden.kLines = append(golden.kLines, types.KLine{
+			StartTime: types.Time(start.Add(time.Duration(i) * time.Hour)),
+			EndTime:   types.Time(start.Add(time.Duration(i+1)*time.Hour - time.Millisecond)),
+			Close:     fixedpoint.MustNewFromString(record[0]),
+			Volume:    fixedpoint.MustNewFromString(record[1]),
+		})
+		golden.sma = append(golden.sma, values[0])
+		golden.vwma = append(golden.vwma, values[1])
+		golden.stdDev = append(golden.stdDev, values[2])
+		golden.upBand = append(golden.upBand, values[3])
+		golden.downBand = append(golden.downBand, values[4])
+	}
+
+	return golden
+}
+
+// tail returns the values after the first window - 1 NaN values
+func tail(values []float64, window int) []float64 {
+	return values[window-1:]
+}
+
+func TestMovingAverages_Golden(t *testing.T) {
+	golden := loadMovingAverageGolden(t)
+	iw := types.IntervalWindow{Interval: types.Interval1h, Window: 20}
+
+	for _, precise := range []bool{false, true} {
+		sma := &SMA{IntervalWindow: iw, Precise: precise}
+		vwma := &VWMA{IntervalWindow: iw, Precise: precise}
+		boll := &BOLL{IntervalWindow: iw, K: 2, Precise: precise}
+		volatility := &VOLATILITY{IntervalWindow: iw, Precise: precise}
+
+		// the klines are sent one by one like the market data store
+		for i := range golden.kLines {
+			window := golden.kLines[:i+1]
+			sma.calculateAndUpdate(window)
+			vwma.calculateAndUpdate(window)
+			boll.calculateAndUpdate(window)
+			volatility.calculateAndUpdate(window)
+		}
+
+		assert.InDeltaSlice(t, tail(golden.sma, 20), sma.Values, 1e-6, "sma precise=%v", precise)
+		assert.InDeltaSlice(t, tail(golden.vwma, 20), vwma.Values, 1e-6, "vwma precise=%v", precise)
+		assert.InDeltaSlice(t, tail(golden.sma, 20), boll.SMA, 1e-6, "boll sma precise=%v", precise)
+		assert.InDeltaSlice(t, tail(golden.stdDev, 20), boll.StdDev, 1e-6, "boll std precise=%v", precise)
+		assert.InDeltaSlice(t, tail(golden.upBand, 20), boll.UpBand, 1e-6, "boll up band precise=%v", precise)
+		assert.InDeltaSlice(t, tail(golden.downBand, 20), boll.DownBand, 1e-6, "boll down band precise=%v", precise)
+		assert.InDeltaSlice(t, tail(golden.stdDev, 20), volatility.Values, 1e-6, "volatility precise=%v", precise)
+	}
+}
+
+func TestSMA_Update(t *testing.T) {
+	golden := loadMovingAverageGolden(t)
+	iw := types.IntervalWindow{Interval: types.Interval1h, Window: 20}
+
+	sma := &SMA{IntervalWindow: iw}
+	legacy := &SMA{IntervalWindow: iw, Legacy: true}
+	for _, k := range golden.kLines {
+		sma.Update(k.Close.Float64())
+		legacy.Update(k.Close.Float64())
+	}
+
+	// the values of Update are the same as the values of the klines
+	assert.InDeltaSlice(t, tail(golden.sma, 20), sma.Values, 1e-6)
+
+	// the recursive approximation pushes a value for every update
+	assert.Equal(t, len(golden.kLines), legacy.Length())
+	expected := golden.kLines[0].Close.Float64()
+	for _, k := range golden.kLines[1:] {
+		expected = (expected*19 + k.Close.Float64()) / 20
+	}
+	assert.InDelta(t, expected, legacy.Last(), 1e-9)
+}
+
+func TestBOLL_Legacy(t *testing.T) {
+	golden := loadMovingAverageGolden(t)
+	boll := &BOLL{IntervalWindow: types.IntervalWindow{Interval: types.Interval1h, Window: 20}, K: 2, Legacy: true}
+	boll.calculateAndUpdate(golden.kLines)
+
+	// the sample standard deviation
+	assert.InDelta(t, golden.stdDev[len(golden.stdDev)-1]*math.Sqrt(20./19.), boll.LastStdDev(), 1e-6)
+}
+
+func TestRollingWindow_Precision(t *testing.T) {
+	const window = 30
+	float := NewRollingWindow(window, false, true)
+	precise := NewRollingWindow(window, true, true)
+
+	var values []float64
+	price := 45000.0
+	for i := 0; i < 100_000; i++ {
+		price += float64(i%7-3) * 0.01
+		value := math.Round(price*100) / 100
+		values = append(values, value)
+		float.Push(value)
+		precise.Push(value)
+	}
+
+	recent := types.Float64Slice(values[len(values)-window:])
+	mean := recent.Sum() / window
+	variance := 0.
+	for _, value := range recent {
+		variance += (value - mean) * (value - mean)
+	}
+	variance /= window
+
+	for _, w := range []*RollingWindow{float, precise} {
+		assert.True(t, w.Full())
+		assert.InDelta(t, mean, w.Mean(), 1e-8)
+		assert.InDelta(t, variance, w.Variance(), 1e-8)
+	}
+
+	assert.InDelta(t, math.Sqrt(variance), precise.StdDev(), 1e-8)
+}
+
+func TestRollingWindow_LargeDeviations(t *testing.T) {
+	// the squares of the deviations are out of the range of the int64 fixedpoint.Value
+	w := NewRollingWindow(4, true, true)
+	for _, value := range []float64{1_000_000, 2_000_000, 1_000_000, 3_000_000} {
+		w.Push(value)
+	}
+
+	assert.Greater(t, w.LargeDeviations, 0)
+	assert.InDelta(t, 1_750_000, w.Mean(), 1e-8)
+	assert.InDelta(t, 687_500_000_000, w.Variance(), 1e-3)
+
+	// the fixedpoint square sum is used again after the large values are removed
+	for _, value := range []float64{10, 12, 10, 12} {
+		w.Push(value)
+	}
+
+	assert.Equal(t, 0, w.LargeDeviations)
+	assert.InDelta(t, 11, w.Mean(), 1e-8)
+	assert.InDelta(t, 1, w.Variance(), 1e-8)
+}
+
+func TestRollingWindow_Squares(t *testing.T) {
+	w := NewRollingWindow(3, false, false)
+	for _, value := range []float64{1, 2, 3, 4} {
+		w.Push(value)
+	}
+
+	assert.InDelta(t, 3, w.Mean(), 1e-8)
+	assert.Zero(t, w.SquareSum)
+	assert.Panics(t, func() { w.Variance() })
+
+	vwma := &VWMA{IntervalWindow: types.IntervalWindow{Interval: types.Interval1h, Window: 3}, Precise: true}
+	vwma.calculateAndUpdate(loadMovingAverageGolden(t).kLines)
+	assert.True(t, vwma.PriceVolumes.FixedSquareSum.IsZero())
+	assert.True(t, vwma.Volumes.FixedSquareSum.IsZero())
+}
@@ -21,7 +21,7 @@ type RSI struct {
 	PreviousAvgGain float64
 
 	EndTime         time.Time
-	UpdateCallbacks []func(value float64)
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *RSI) Update(price float64) {
@@ -43,8 +43,8 @@ func (inc *RSI) Update(price float64) {
 		currentGain := math.Max(difference, 0)
 		currentLoss := -math.Min(difference, 0)
 
-		avgGain = (inc.PreviousAvgGain*13 + currentGain) / float64(inc.Window)
-		avgLoss = (inc.PreviousAvgLoss*13 + currentLoss) / float64(inc.Window)
+		avgGain = (inc.PreviousAvgGain*float64(inc.Window-1) + currentGain) / float64(inc.Window)
+		avgLoss = (inc.PreviousAvgLoss*float64(inc.Window-1) + currentLoss) / float64(inc.Window)
 	}
 
 	rs := avgGain / avgLoss
@@ -82,10 +82,10 @@ func (inc *RSI) calculateAndUpdate(kLines []types.KLine) {
 			continue
 		}
 		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 	}
 
 	inc.EmitUpdate(inc.Last())
-	inc.EndTime = kLines[len(kLines)-1].EndTime.Time()
 }
 
 func (inc *RSI) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
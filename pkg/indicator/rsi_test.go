@@ -54,6 +54,21 @@ func Test_calculateRSI(t *testing.T) {
 				37.78877198205783,
 			},
 		},
+		{
+			// the previous averages are smoothed by window - 1
+			name:   "RSI window 5",
+			kLines: buildKLines(values[:12]),
+			window: 5,
+			want: types.Float64Slice{
+				61.835748792270444,
+				67.1858774662513,
+				72.82889079965607,
+				78.80794701986757,
+				81.68646769052445,
+				72.00755134174017,
+				74.76189319536775,
+			},
+		},
 	}
 
 	for _, tt := range tests {
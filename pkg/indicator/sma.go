@@ -1,11 +1,8 @@
 package indicator
 
 import (
-	"fmt"
 	"time"
 
-	log "github.com/sirupsen/logrus"
-
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -14,13 +11,30 @@ const MaxNumOfSMATruncateSize = 100
 
 var zeroTime time.Time
 
+/*
+sma implements the simple moving average indicator, the mean of the last Window values
+
+The sum of the window is updated by the rolling window, the values are pushed when the window is filled.
+
+Legacy keeps the recursive approximation of Update in the previous versions, (last * (window - 1) + value) / window,
+which pushes a value for every update. It's only for the strategies that depend on the old values,
+the values of the klines are always the mean of the window.
+*/
 //go:generate callbackgen -type SMA
 type SMA struct {
 	types.IntervalWindow
 	Values  types.Float64Slice
 	EndTime time.Time
 
-	UpdateCallbacks []func(value float64)
+	// Precise keeps the rolling sum in fixedpoint.Value
+	Precise bool
+
+	// Legacy uses the recursive approximation in Update
+	Legacy bool
+
+	Rolling *RollingWindow
+
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *SMA) Last() float64 {
@@ -46,43 +60,54 @@ func (inc *SMA) Length() int {
 var _ types.Series = &SMA{}
 
 func (inc *SMA) Update(value float64) {
-	length := len(inc.Values)
-	if length == 0 {
-		inc.Values = append(inc.Values, value)
+	if inc.Legacy {
+		length := len(inc.Values)
+		if length == 0 {
+			inc.Values = append(inc.Values, value)
+			return
+		}
+		newVal := (inc.Values[length-1]*float64(inc.Window-1) + value) / float64(inc.Window)
+		inc.Values = append(inc.Values, newVal)
 		return
 	}
-	newVal := (inc.Values[length-1]*float64(inc.Window-1) + value) / float64(inc.Window)
-	inc.Values = append(inc.Values, newVal)
-}
 
-func (inc *SMA) calculateAndUpdate(kLines []types.KLine) {
-	if len(kLines) < inc.Window {
-		return
-	}
-
-	var index = len(kLines) - 1
-	var kline = kLines[index]
+	inc.rolling().Push(value)
+	inc.pushMean()
+}
 
-	if inc.EndTime != zeroTime && kline.EndTime.Before(inc.EndTime) {
-		return
+func (inc *SMA) rolling() *RollingWindow {
+	if inc.Rolling == nil {
+		inc.Rolling = NewRollingWindow(inc.Window, inc.Precise, false)
 	}
+	return inc.Rolling
+}
 
-	var recentK = kLines[index-(inc.Window-1) : index+1]
-
-	sma, err := calculateSMA(recentK, inc.Window, KLineClosePriceMapper)
-	if err != nil {
-		log.WithError(err).Error("SMA error")
+// pushMean pushes the mean of the rolling window when the window is filled
+func (inc *SMA) pushMean() {
+	if !inc.Rolling.Full() {
 		return
 	}
-	inc.Values.Push(sma)
 
+	inc.Values.Push(inc.Rolling.Mean())
 	if len(inc.Values) > MaxNumOfSMA {
 		inc.Values = inc.Values[MaxNumOfSMATruncateSize-1:]
 	}
+}
 
-	inc.EndTime = kLines[index].EndTime.Time()
-
-	inc.EmitUpdate(sma)
+func (inc *SMA) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+
+		length := inc.Length()
+		inc.rolling().PushFixed(k.Close)
+		inc.pushMean()
+		inc.EndTime = k.EndTime.Time()
+		if inc.Length() != length {
+			inc.EmitUpdate(inc.Last())
+		}
+	}
 }
 
 func (inc *SMA) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
@@ -96,18 +121,3 @@ func (inc *SMA) handleKLineWindowUpdate(interval types.Interval, window types.KL
 func (inc *SMA) Bind(updater KLineWindowUpdater) {
 	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
 }
-
-func calculateSMA(kLines []types.KLine, window int, priceF KLinePriceMapper) (float64, error) {
-	length := len(kLines)
-	if length == 0 || length < window {
-		return 0.0, fmt.Errorf("insufficient elements for calculating SMA with window = %d", window)
-	}
-
-	sum := 0.0
-	for _, k := range kLines {
-		sum += priceF(k)
-	}
-
-	avg := sum / float64(window)
-	return avg, nil
-}
@@ -0,0 +1,125 @@
+package indicator
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type kLineCalculator interface {
+	calculateAndUpdate(kLines []types.KLine)
+}
+
+func buildSnapshotTestKLines(count int) (kLines []types.KLine) {
+	t0 := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		startTime := t0.Add(time.Duration(i) * time.Minute)
+		price := 100 + 10*math.Sin(float64(i)/5) + 3*math.Cos(float64(i)/2)
+		kLines = append(kLines, types.KLine{
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1m,
+			StartTime: types.Time(startTime),
+			EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+			Open:      fixedpoint.NewFromFloat(price - 1),
+			High:      fixedpoint.NewFromFloat(price + 2 + math.Abs(math.Sin(float64(i)))),
+			Low:       fixedpoint.NewFromFloat(price - 2 - math.Abs(math.Cos(float64(i)))),
+			Close:     fixedpoint.NewFromFloat(price),
+			Volume:    fixedpoint.NewFromFloat(10 + float64(i%7)),
+			Closed:    true,
+		})
+	}
+	return kLines
+}
+
+// the indicator restored from the snapshot continues with the klines after the snapshot,
+// and has the same state as the indicator that is never restarted
+func TestIndicatorSnapshot(t *testing.T) {
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 7}
+	tests := map[string]func() kLineCalculator{
+		"AD":         func() kLineCalculator { return &AD{IntervalWindow: iw} },
+		"ATR":        func() kLineCalculator { return &ATR{IntervalWindow: iw} },
+		"BOLL":       func() kLineCalculator { return &BOLL{IntervalWindow: iw, K: 2} },
+		"CA":         func() kLineCalculator { return &CA{Interval: iw.Interval} },
+		"CCI":        func() kLineCalculator { return &CCI{IntervalWindow: iw} },
+		"CMF":        func() kLineCalculator { return &CMF{IntervalWindow: iw} },
+		"DEMA":       func() kLineCalculator { return &DEMA{IntervalWindow: iw} },
+		"DMI":        func() kLineCalculator { return &DMI{IntervalWindow: iw, ADXSmoothing: 5} },
+		"Donchian":   func() kLineCalculator { return &DonchianChannel{IntervalWindow: iw} },
+		"EWMA":       func() kLineCalculator { return &EWMA{IntervalWindow: iw} },
+		"HULL":       func() kLineCalculator { return &HULL{IntervalWindow: iw} },
+		"Ichimoku":   func() kLineCalculator { return &Ichimoku{Interval: iw.Interval} },
+		"Keltner":    func() kLineCalculator { return &KeltnerChannel{IntervalWindow: iw} },
+		"MACD":       func() kLineCalculator { return &MACD{IntervalWindow: iw, ShortPeriod: 12, LongPeriod: 26} },
+		"MFI":        func() kLineCalculator { return &MFI{IntervalWindow: iw} },
+		"OBV":        func() kLineCalculator { return &OBV{IntervalWindow: iw} },
+		"PSAR":       func() kLineCalculator { return &PSAR{Interval: iw.Interval} },
+		"RMA":        func() kLineCalculator { return &RMA{IntervalWindow: iw} },
+		"RSI":        func() kLineCalculator { return &RSI{IntervalWindow: iw} },
+		"SMA":        func() kLineCalculator { return &SMA{IntervalWindow: iw} },
+		"STOCH":      func() kLineCalculator { return &STOCH{IntervalWindow: iw} },
+		"StochRSI":   func() kLineCalculator { return &StochRSI{IntervalWindow: iw} },
+		"SuperTrend": func() kLineCalculator { return &SuperTrend{IntervalWindow: iw, ATRMultiplier: 3} },
+		"TEMA":       func() kLineCalculator { return &TEMA{IntervalWindow: iw} },
+		"TILL":       func() kLineCalculator { return &TILL{IntervalWindow: iw} },
+		"TMA":        func() kLineCalculator { return &TMA{IntervalWindow: iw} },
+		"VIDYA":      func() kLineCalculator { return &VIDYA{IntervalWindow: iw} },
+		"VOLATILITY": func() kLineCalculator { return &VOLATILITY{IntervalWindow: iw} },
+		"VWAP":       func() kLineCalculator { return &VWAP{IntervalWindow: iw} },
+		"VWMA":       func() kLineCalculator { return &VWMA{IntervalWindow: iw} },
+		"WWMA":       func() kLineCalculator { return &WWMA{IntervalWindow: iw} },
+		"ZLEMA":      func() kLineCalculator { return &ZLEMA{IntervalWindow: iw} },
+	}
+
+	kLines := buildSnapshotTestKLines(120)
+	const snapshotAt = 80
+
+	for name, newIndicator := range tests {
+		t.Run(name, func(t *testing.T) {
+			expected := newIndicator()
+			for i := range kLines {
+				expected.calculateAndUpdate(kLines[:i+1])
+			}
+
+			inc := newIndicator()
+			for i := range kLines[:snapshotAt] {
+				inc.calculateAndUpdate(kLines[:i+1])
+			}
+
+			snapshot, err := json.Marshal(inc)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			restored := newIndicator()
+			if !assert.NoError(t, json.Unmarshal(snapshot, restored)) {
+				return
+			}
+
+			// the klines are loaded from the beginning after the restart, the klines before the snapshot are skipped
+			for i := range kLines {
+				restored.calculateAndUpdate(kLines[:i+1])
+			}
+
+			if series, ok := restored.(types.Series); ok {
+				assert.Equal(t, reflect.ValueOf(expected).Interface().(types.Series).Length(), series.Length())
+				assert.NotZero(t, series.Length())
+			}
+
+			assert.Equal(t, stateOf(t, expected), stateOf(t, restored))
+		})
+	}
+}
+
+func stateOf(t *testing.T, inc interface{}) (state interface{}) {
+	data, err := json.Marshal(inc)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &state))
+	return state
+}
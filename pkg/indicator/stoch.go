@@ -24,7 +24,7 @@ type STOCH struct {
 	LowValues  types.Float64Slice
 
 	EndTime         time.Time
-	UpdateCallbacks []func(k float64, d float64)
+	UpdateCallbacks []func(k float64, d float64) `json:"-"`
 }
 
 func (inc *STOCH) Update(high, low, cloze float64) {
@@ -65,10 +65,10 @@ func (inc *STOCH) calculateAndUpdate(kLines []types.KLine) {
 			continue
 		}
 		inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 	}
 
 	inc.EmitUpdate(inc.LastK(), inc.LastD())
-	inc.EndTime = kLines[len(kLines)-1].EndTime.Time()
 }
 
 func (inc *STOCH) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
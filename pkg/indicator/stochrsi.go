@@ -0,0 +1,135 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+stochrsi implements the stochastic RSI indicator
+
+Stochastic RSI (StochRSI), the stochastic oscillator applied to the RSI values
+- https://www.investopedia.com/terms/s/stochrsi.asp
+
+The Window is the RSI window, the K line is the SMA of the stochastic RSI and the D line is the SMA of the K line.
+The K values are pushed after SmoothK stochastic values are collected, and the D values are pushed after SmoothD K values are collected.
+*/
+//go:generate callbackgen -type StochRSI
+type StochRSI struct {
+	types.IntervalWindow
+
+	// StochWindow is the window of the highest and the lowest RSI, the Window is used if it's not set
+	StochWindow int
+
+	// SmoothK is the SMA window of the K line, default 3
+	SmoothK int
+
+	// SmoothD is the SMA window of the D line, default 3
+	SmoothD int
+
+	K types.Float64Slice
+	D types.Float64Slice
+
+	RSI         *RSI
+	StochValues types.Float64Slice
+
+	EndTime         time.Time
+	UpdateCallbacks []func(k float64, d float64) `json:"-"`
+}
+
+func (inc *StochRSI) Update(price float64) {
+	if inc.Window <= 0 {
+		panic("window must be greater than 0")
+	}
+
+	if inc.RSI == nil {
+		if inc.StochWindow <= 0 {
+			inc.StochWindow = inc.Window
+		}
+		if inc.SmoothK <= 0 {
+			inc.SmoothK = 3
+		}
+		if inc.SmoothD <= 0 {
+			inc.SmoothD = 3
+		}
+
+		inc.RSI = &RSI{IntervalWindow: types.IntervalWindow{Window: inc.Window}}
+	}
+
+	inc.RSI.Update(price)
+	if inc.RSI.Length() < inc.StochWindow {
+		return
+	}
+
+	rsiValues := inc.RSI.Values.Tail(inc.StochWindow)
+	lowest := rsiValues.Min()
+	highest := rsiValues.Max()
+
+	stoch := 0.0
+	if highest > lowest {
+		stoch = 100.0 * (inc.RSI.Last() - lowest) / (highest - lowest)
+	}
+
+	inc.StochValues.Push(stoch)
+	if len(inc.StochValues) > inc.SmoothK {
+		inc.StochValues = inc.StochValues[1:]
+	}
+
+	if len(inc.StochValues) < inc.SmoothK {
+		return
+	}
+
+	inc.K.Push(inc.StochValues.Mean())
+	if len(inc.K) < inc.SmoothD {
+		return
+	}
+
+	inc.D.Push(inc.K.Tail(inc.SmoothD).Mean())
+}
+
+func (inc *StochRSI) Last() float64 {
+	return inc.K.Last()
+}
+
+func (inc *StochRSI) Index(i int) float64 {
+	return inc.K.Index(i)
+}
+
+func (inc *StochRSI) Length() int {
+	return inc.K.Length()
+}
+
+var _ types.Series = &StochRSI{}
+
+func (inc *StochRSI) GetK() types.Series {
+	return &inc.K
+}
+
+func (inc *StochRSI) GetD() types.Series {
+	return &inc.D
+}
+
+func (inc *StochRSI) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
+	}
+
+	inc.EmitUpdate(inc.K.Last(), inc.D.Last())
+}
+
+func (inc *StochRSI) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.calculateAndUpdate(window)
+}
+
+func (inc *StochRSI) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
@@ -0,0 +1,32 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+pine:
+
+rsi = ta.rsi(close, 14)
+k = ta.sma(ta.stoch(rsi, rsi, rsi, 14), 3)
+d = ta.sma(k, 3)
+*/
+func TestStochRSI(t *testing.T) {
+	var Delta = 1e-6
+	kLines := buildHighLowCloseKLines(t, channelTestKLines)
+
+	stochRSI := &StochRSI{IntervalWindow: types.IntervalWindow{Window: 14}}
+	stochRSI.calculateAndUpdate(kLines)
+
+	// the first stochastic value is at the 28th kline
+	assert.Equal(t, len(kLines)-27-2, stochRSI.Length())
+	assert.Equal(t, len(kLines)-27-4, stochRSI.GetD().Length())
+	assert.InDelta(t, 91.44999755851836, stochRSI.Last(), Delta)
+	assert.InDelta(t, 95.90286629569714, stochRSI.Index(1), Delta)
+	assert.InDelta(t, 95.35220935230755, stochRSI.GetD().Last(), Delta)
+	assert.InDelta(t, 97.77013156703714, stochRSI.GetD().Index(1), Delta)
+}
@@ -0,0 +1,143 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+supertrend implements the supertrend indicator
+
+SuperTrend, the trailing bands of the ATR around the median price
+- https://www.investopedia.com/supertrend-indicator-7976167
+
+The Window is the ATR window, the values are pushed after the first Window true ranges are collected.
+*/
+//go:generate callbackgen -type SuperTrend
+type SuperTrend struct {
+	types.IntervalWindow
+
+	// ATRMultiplier is the factor of the ATR for the band width, default 3
+	ATRMultiplier float64
+
+	Values     types.Float64Slice
+	UpperBand  types.Float64Slice
+	LowerBand  types.Float64Slice
+	Directions []types.Direction
+
+	ATR           *ATR
+	PreviousClose float64
+
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64, direction types.Direction) `json:"-"`
+}
+
+func (inc *SuperTrend) Update(high, low, cloze float64) {
+	if inc.Window <= 0 {
+		panic("window must be greater than 0")
+	}
+
+	if inc.ATRMultiplier == 0 {
+		inc.ATRMultiplier = 3
+	}
+
+	if inc.ATR == nil {
+		inc.ATR = &ATR{IntervalWindow: types.IntervalWindow{Window: inc.Window}}
+	}
+
+	inc.ATR.Update(high, low, cloze)
+	prevClose := inc.PreviousClose
+	inc.PreviousClose = cloze
+	if inc.ATR.Length() < inc.Window {
+		return
+	}
+
+	src := (high + low) / 2
+	band := inc.ATRMultiplier * inc.ATR.Last()
+	upperBand := src + band
+	lowerBand := src - band
+
+	// the bands only move toward the price unless the previous close crossed them
+	if prevUpperBand := inc.UpperBand.Last(); len(inc.UpperBand) > 0 && upperBand > prevUpperBand && prevClose <= prevUpperBand {
+		upperBand = prevUpperBand
+	}
+	if prevLowerBand := inc.LowerBand.Last(); len(inc.LowerBand) > 0 && lowerBand < prevLowerBand && prevClose >= prevLowerBand {
+		lowerBand = prevLowerBand
+	}
+
+	var direction types.Direction = types.DirectionDown
+	switch inc.Direction() {
+	case types.DirectionDown:
+		if cloze > upperBand {
+			direction = types.DirectionUp
+		}
+	case types.DirectionUp:
+		if cloze >= lowerBand {
+			direction = types.DirectionUp
+		}
+	}
+
+	inc.UpperBand.Push(upperBand)
+	inc.LowerBand.Push(lowerBand)
+	inc.Directions = append(inc.Directions, direction)
+	if direction == types.DirectionUp {
+		inc.Values.Push(lowerBand)
+	} else {
+		inc.Values.Push(upperBand)
+	}
+}
+
+// Direction returns the current trend, DirectionNone is returned if the value is not ready
+func (inc *SuperTrend) Direction() types.Direction {
+	if len(inc.Directions) == 0 {
+		return types.DirectionNone
+	}
+	return inc.Directions[len(inc.Directions)-1]
+}
+
+func (inc *SuperTrend) Last() float64 {
+	return inc.Values.Last()
+}
+
+func (inc *SuperTrend) Index(i int) float64 {
+	return inc.Values.Index(i)
+}
+
+func (inc *SuperTrend) Length() int {
+	return inc.Values.Length()
+}
+
+var _ types.Series = &SuperTrend{}
+
+func (inc *SuperTrend) GetUpperBand() types.Series {
+	return &inc.UpperBand
+}
+
+func (inc *SuperTrend) GetLowerBand() types.Series {
+	return &inc.LowerBand
+}
+
+func (inc *SuperTrend) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+		inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
+	}
+
+	inc.EmitUpdate(inc.Last(), inc.Direction())
+}
+
+func (inc *SuperTrend) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.calculateAndUpdate(window)
+}
+
+func (inc *SuperTrend) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
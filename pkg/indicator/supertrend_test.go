@@ -0,0 +1,56 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+pine:
+
+[supertrend, direction] = ta.supertrend(factor, 5)
+
+the pine direction -1 is the uptrend and 1 is the downtrend
+*/
+func TestSuperTrend(t *testing.T) {
+	var Delta = 1e-6
+	kLines := buildHighLowCloseKLines(t, trendTestKLines)
+
+	tests := []struct {
+		name       string
+		multiplier float64
+		want       []float64
+		directions []types.Direction
+	}{
+		{
+			name:       "factor_3",
+			multiplier: 3,
+			want:       []float64{40621.14094438472, 40621.14094438472, 40621.14094438472, 40621.14094438472, 40621.14094438472},
+			directions: []types.Direction{types.DirectionDown, types.DirectionDown, types.DirectionDown, types.DirectionDown, types.DirectionDown},
+		},
+		{
+			name:       "factor_1",
+			multiplier: 1,
+			want:       []float64{40477.343661708794, 40244.21892936704, 39903.57414349363, 39824.57031479491, 39824.57031479491, 39367.85599853127, 39661.28679882501, 39747.30943906},
+			directions: []types.Direction{types.DirectionDown, types.DirectionDown, types.DirectionDown, types.DirectionDown, types.DirectionDown, types.DirectionUp, types.DirectionUp, types.DirectionUp},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			supertrend := &SuperTrend{IntervalWindow: types.IntervalWindow{Window: 5}, ATRMultiplier: tt.multiplier}
+			supertrend.calculateAndUpdate(kLines)
+
+			assert.Equal(t, len(kLines)-5, supertrend.Length())
+			offset := len(supertrend.Directions) - len(tt.directions)
+			for i, v := range tt.want {
+				assert.InDelta(t, v, supertrend.Index(len(tt.want)-1-i), Delta)
+				assert.Equal(t, tt.directions[i], supertrend.Directions[offset+i])
+			}
+			assert.Equal(t, tt.directions[len(tt.directions)-1], supertrend.Direction())
+		})
+	}
+}
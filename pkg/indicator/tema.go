@@ -1,6 +1,8 @@
 package indicator
 
 import (
+	"time"
+
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -15,7 +17,8 @@ type TEMA struct {
 	A2     *EWMA
 	A3     *EWMA
 
-	UpdateCallbacks []func(value float64)
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *TEMA) Update(value float64) {
@@ -54,13 +57,12 @@ func (inc *TEMA) Length() int {
 var _ types.Series = &TEMA{}
 
 func (inc *TEMA) calculateAndUpdate(allKLines []types.KLine) {
-	if inc.A1 == nil {
-		for _, k := range allKLines {
-			inc.Update(k.Close.Float64())
-			inc.EmitUpdate(inc.Last())
+	for _, k := range allKLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
 		}
-	} else {
-		inc.Update(allKLines[len(allKLines)-1].Close.Float64())
+		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 		inc.EmitUpdate(inc.Last())
 	}
 }
@@ -1,6 +1,8 @@
 package indicator
 
 import (
+	"time"
+
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -9,53 +11,55 @@ import (
 //go:generate callbackgen -type TMA
 type TMA struct {
 	types.IntervalWindow
-	s1              *SMA
-	s2              *SMA
-	UpdateCallbacks []func(value float64)
+	S1              *SMA
+	S2              *SMA
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *TMA) Update(value float64) {
-	if inc.s1 == nil {
+	if inc.S1 == nil {
 		w := (inc.Window + 1) / 2
-		inc.s1 = &SMA{IntervalWindow: types.IntervalWindow{inc.Interval, w}}
-		inc.s2 = &SMA{IntervalWindow: types.IntervalWindow{inc.Interval, w}}
+		inc.S1 = &SMA{IntervalWindow: types.IntervalWindow{inc.Interval, w}}
+		inc.S2 = &SMA{IntervalWindow: types.IntervalWindow{inc.Interval, w}}
 	}
 
-	inc.s1.Update(value)
-	inc.s2.Update(inc.s1.Last())
+	inc.S1.Update(value)
+	if inc.S1.Length() > 0 {
+		inc.S2.Update(inc.S1.Last())
+	}
 }
 
 func (inc *TMA) Last() float64 {
-	if inc.s2 == nil {
+	if inc.S2 == nil {
 		return 0
 	}
-	return inc.s2.Last()
+	return inc.S2.Last()
 }
 
 func (inc *TMA) Index(i int) float64 {
-	if inc.s2 == nil {
+	if inc.S2 == nil {
 		return 0
 	}
-	return inc.s2.Index(i)
+	return inc.S2.Index(i)
 }
 
 func (inc *TMA) Length() int {
-	if inc.s2 == nil {
+	if inc.S2 == nil {
 		return 0
 	}
-	return inc.s2.Length()
+	return inc.S2.Length()
 }
 
 var _ types.Series = &TMA{}
 
 func (inc *TMA) calculateAndUpdate(allKLines []types.KLine) {
-	if inc.s1 == nil {
-		for _, k := range allKLines {
-			inc.Update(k.Close.Float64())
-			inc.EmitUpdate(inc.Last())
+	for _, k := range allKLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
 		}
-	} else {
-		inc.Update(allKLines[len(allKLines)-1].Close.Float64())
+		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 		inc.EmitUpdate(inc.Last())
 	}
 }
@@ -0,0 +1,132 @@
+package indicator
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+testdata/tradingview/*.csv are the chart data exported by "Export chart data..." of TradingView,
+with the following pine script added to the chart, the plot titles are the column names of the export:
+
+	//@version=5
+	indicator("bbgo moving averages", overlay=true)
+	plot(ta.sma(close, 20), "SMA")
+	plot(ta.vwma(close, 20), "VWMA")
+	plot(ta.stdev(close, 20), "StDev")
+	[basis, upper, lower] = ta.bb(close, 20, 2)
+	plot(basis, "Basis")
+	plot(upper, "Upper")
+	plot(lower, "Lower")
+
+The time column is the unix timestamp of the kline start time, the values of the first 19 rows are NaN.
+*/
+type tradingViewExport struct {
+	kLines []types.KLine
+	values map[string][]float64
+}
+
+func loadTradingViewExport(t *testing.T, file string, interval types.Interval) (export tradingViewExport) {
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+
+	for _, name := range []string{"time", "close", "Volume", "SMA", "VWMA", "StDev", "Basis", "Upper", "Lower"} {
+		_, ok := columns[name]
+		require.True(t, ok, "column %s is not found in %s", name, file)
+	}
+
+	export.values = make(map[string][]float64)
+	for _, record := range records[1:] {
+		ts, err := strconv.ParseInt(record[columns["time"]], 10, 64)
+		require.NoError(t, err)
+
+		start := time.Unix(ts, 0).UTC()
+		export.kLines = append(export.kLines, types.KLine{
+			Interval:  interval,
+			StartTime: types.Time(start),
+			EndTime:   types.Time(start.Add(interval.Duration() - time.Millisecond)),
+			Close:     fixedpoint.MustNewFromString(record[columns["close"]]),
+			Volume:    fixedpoint.MustNewFromString(record[columns["Volume"]]),
+		})
+
+		for _, name := range []string{"SMA", "VWMA", "StDev", "Basis", "Upper", "Lower"} {
+			value := math.NaN()
+			if field := record[columns[name]]; field != "" && field != "NaN" {
+				value, err = strconv.ParseFloat(field, 64)
+				require.NoError(t, err)
+			}
+
+			export.values[name] = append(export.values[name], value)
+		}
+	}
+
+	return export
+}
+
+// assertTradingViewValues compares the indicator values with the exported values after the window is filled,
+// the exported values are rounded by TradingView, so the values are compared by the relative error
+func assertTradingViewValues(t *testing.T, expected []float64, actual []float64, msgAndArgs ...interface{}) {
+	expected = tail(expected, 20)
+	if !assert.Len(t, actual, len(expected), msgAndArgs...) {
+		return
+	}
+
+	for i := range expected {
+		assert.InDelta(t, expected[i], actual[i], math.Abs(expected[i])*1e-6+1e-8, msgAndArgs...)
+	}
+}
+
+func TestMovingAverages_TradingView(t *testing.T) {
+	files, err := filepath.Glob("testdata/tradingview/*.csv")
+	require.NoError(t, err)
+	if len(files) == 0 {
+		t.Skip("no TradingView export in testdata/tradingview")
+	}
+
+	iw := types.IntervalWindow{Interval: types.Interval1h, Window: 20}
+	for _, file := range files {
+		export := loadTradingViewExport(t, file, iw.Interval)
+		for _, precise := range []bool{false, true} {
+			sma := &SMA{IntervalWindow: iw, Precise: precise}
+			vwma := &VWMA{IntervalWindow: iw, Precise: precise}
+			boll := &BOLL{IntervalWindow: iw, K: 2, Precise: precise}
+			volatility := &VOLATILITY{IntervalWindow: iw, Precise: precise}
+
+			for i := range export.kLines {
+				window := export.kLines[:i+1]
+				sma.calculateAndUpdate(window)
+				vwma.calculateAndUpdate(window)
+				boll.calculateAndUpdate(window)
+				volatility.calculateAndUpdate(window)
+			}
+
+			assertTradingViewValues(t, export.values["SMA"], sma.Values, "%s ta.sma precise=%v", file, precise)
+			assertTradingViewValues(t, export.values["VWMA"], vwma.Values, "%s ta.vwma precise=%v", file, precise)
+			assertTradingViewValues(t, export.values["StDev"], volatility.Values, "%s ta.stdev precise=%v", file, precise)
+			assertTradingViewValues(t, export.values["Basis"], boll.SMA, "%s ta.bb basis precise=%v", file, precise)
+			assertTradingViewValues(t, export.values["Upper"], boll.UpBand, "%s ta.bb upper precise=%v", file, precise)
+			assertTradingViewValues(t, export.values["Lower"], boll.DownBand, "%s ta.bb lower precise=%v", file, precise)
+		}
+	}
+}
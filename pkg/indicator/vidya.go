@@ -2,6 +2,7 @@ package indicator
 
 import (
 	"math"
+	"time"
 
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -12,28 +13,29 @@ import (
 type VIDYA struct {
 	types.IntervalWindow
 	Values types.Float64Slice
-	input  types.Float64Slice
+	Input  types.Float64Slice
 
-	UpdateCallbacks []func(value float64)
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *VIDYA) Update(value float64) {
 	if inc.Values.Length() == 0 {
 		inc.Values.Push(value)
-		inc.input.Push(value)
+		inc.Input.Push(value)
 		return
 	}
-	inc.input.Push(value)
-	if len(inc.input) > MaxNumOfEWMA {
-		inc.input = inc.input[MaxNumOfEWMATruncateSize-1:]
+	inc.Input.Push(value)
+	if len(inc.Input) > MaxNumOfEWMA {
+		inc.Input = inc.Input[MaxNumOfEWMATruncateSize-1:]
 	}
 	/*upsum := 0.
 	downsum := 0.
 	for i := 0; i < inc.Window; i++ {
-		if len(inc.input) <= i+1 {
+		if len(inc.Input) <= i+1 {
 			break
 		}
-		diff := inc.input.Index(i) - inc.input.Index(i+1)
+		diff := inc.Input.Index(i) - inc.Input.Index(i+1)
 		if diff > 0 {
 			upsum += diff
 		} else {
@@ -45,7 +47,7 @@ func (inc *VIDYA) Update(value float64) {
 		return
 	}
 	CMO := math.Abs((upsum - downsum) / (upsum + downsum))*/
-	change := types.Change(&inc.input)
+	change := types.Change(&inc.Input)
 	CMO := math.Abs(types.Sum(change, inc.Window) / types.Sum(types.Abs(change), inc.Window))
 	alpha := 2. / float64(inc.Window+1)
 	inc.Values.Push(value*alpha*CMO + inc.Values.Last()*(1.-alpha*CMO))
@@ -69,13 +71,12 @@ func (inc *VIDYA) Length() int {
 var _ types.Series = &VIDYA{}
 
 func (inc *VIDYA) calculateAndUpdate(allKLines []types.KLine) {
-	if inc.input.Length() == 0 {
-		for _, k := range allKLines {
-			inc.Update(k.Close.Float64())
-			inc.EmitUpdate(inc.Last())
+	for _, k := range allKLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
 		}
-	} else {
-		inc.Update(allKLines[len(allKLines)-1].Close.Float64())
+		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 		inc.EmitUpdate(inc.Last())
 	}
 }
@@ -1,6 +1,7 @@
 package indicator
 
 import (
+	"math"
 	"testing"
 
 	"github.com/c9s/bbgo/pkg/types"
@@ -17,3 +18,33 @@ func Test_VIDYA(t *testing.T) {
 	vidya.Update(1)
 	assert.Equal(t, vidya.Last(), vidya.Index(1))
 }
+
+// the CMO is calculated from the changes in the window, the previous versions used the changes of the whole input
+func Test_VIDYA_Window(t *testing.T) {
+	const window = 3
+	vidya := &VIDYA{IntervalWindow: types.IntervalWindow{Window: window}}
+
+	inputs := []float64{1, 2, 3, 5, 4, 3, 3.5, 2}
+	expected := inputs[0]
+	for i, v := range inputs {
+		vidya.Update(v)
+		if i == 0 {
+			continue
+		}
+
+		upSum, downSum := 0., 0.
+		for j := i; j > 0 && j > i-window; j-- {
+			diff := inputs[j] - inputs[j-1]
+			if diff > 0 {
+				upSum += diff
+			} else {
+				downSum -= diff
+			}
+		}
+
+		cmo := math.Abs((upSum - downSum) / (upSum + downSum))
+		alpha := 2. / float64(window+1)
+		expected = v*alpha*cmo + expected*(1.-alpha*cmo)
+		assert.InDelta(t, expected, vidya.Last(), 1e-9, "update %d", i)
+	}
+}
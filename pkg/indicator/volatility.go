@@ -1,12 +1,8 @@
 package indicator
 
 import (
-	"fmt"
-	"math"
 	"time"
 
-	log "github.com/sirupsen/logrus"
-
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -15,13 +11,21 @@ const MaxNumOfVOLTruncateSize = 100
 
 //var zeroTime time.Time
 
+/*
+volatility implements the volatility indicator, the population standard deviation of the close prices in the window
+*/
 //go:generate callbackgen -type VOLATILITY
 type VOLATILITY struct {
 	types.IntervalWindow
 	Values  types.Float64Slice
 	EndTime time.Time
 
-	UpdateCallbacks []func(value float64)
+	// Precise keeps the rolling sums in fixedpoint.Value
+	Precise bool
+
+	Rolling *RollingWindow
+
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *VOLATILITY) Last() float64 {
@@ -31,34 +35,43 @@ func (inc *VOLATILITY) Last() float64 {
 	return inc.Values[len(inc.Values)-1]
 }
 
-func (inc *VOLATILITY) calculateAndUpdate(klines []types.KLine) {
-	if len(klines) < inc.Window {
-		return
-	}
-
-	var end = len(klines) - 1
-	var lastKLine = klines[end]
+func (inc *VOLATILITY) Update(value float64) {
+	inc.rolling().Push(value)
+	inc.pushStdDev()
+}
 
-	if inc.EndTime != zeroTime && lastKLine.GetEndTime().Before(inc.EndTime) {
-		return
+func (inc *VOLATILITY) rolling() *RollingWindow {
+	if inc.Rolling == nil {
+		inc.Rolling = NewRollingWindow(inc.Window, inc.Precise, true)
 	}
+	return inc.Rolling
+}
 
-	var recentT = klines[end-(inc.Window-1) : end+1]
-
-	volatility, err := calculateVOLATILITY(recentT, inc.Window, KLineClosePriceMapper)
-	if err != nil {
-		log.WithError(err).Error("can not calculate volatility")
+func (inc *VOLATILITY) pushStdDev() {
+	if !inc.Rolling.Full() {
 		return
 	}
-	inc.Values.Push(volatility)
 
+	inc.Values.Push(inc.Rolling.StdDev())
 	if len(inc.Values) > MaxNumOfVOL {
 		inc.Values = inc.Values[MaxNumOfVOLTruncateSize-1:]
 	}
+}
 
-	inc.EndTime = klines[end].GetEndTime().Time()
-
-	inc.EmitUpdate(volatility)
+func (inc *VOLATILITY) calculateAndUpdate(klines []types.KLine) {
+	for _, k := range klines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+
+		length := len(inc.Values)
+		inc.rolling().PushFixed(k.Close)
+		inc.pushStdDev()
+		inc.EndTime = k.EndTime.Time()
+		if len(inc.Values) != length {
+			inc.EmitUpdate(inc.Last())
+		}
+	}
 }
 
 func (inc *VOLATILITY) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
@@ -72,26 +85,3 @@ func (inc *VOLATILITY) handleKLineWindowUpdate(interval types.Interval, window t
 func (inc *VOLATILITY) Bind(updater KLineWindowUpdater) {
 	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
 }
-
-func calculateVOLATILITY(klines []types.KLine, window int, priceF KLinePriceMapper) (float64, error) {
-	length := len(klines)
-	if length == 0 || length < window {
-		return 0.0, fmt.Errorf("insufficient elements for calculating VOL with window = %d", window)
-	}
-
-	sum := 0.0
-	for _, k := range klines {
-		sum += priceF(k)
-	}
-
-	avg := sum / float64(window)
-	sv := 0.0 // sum of variance
-
-	for _, j := range klines {
-		// The use of Pow math function func Pow(x, y float64) float64
-		sv += math.Pow(priceF(j)-avg, 2)
-	}
-	// The use of Sqrt math function func Sqrt(x float64) float64
-	sd := math.Sqrt(sv / float64(len(klines)))
-	return sd, nil
-}
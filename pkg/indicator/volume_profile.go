@@ -0,0 +1,228 @@
+package indicator
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const DefaultValueAreaRatio = 0.7
+const DefaultVolumeProfileMaxLevels = 1_000
+
+/*
+volume_profile implements the volume profile of the market trades
+
+Volume Profile, the traded volume at the price levels
+- https://www.tradingview.com/support/solutions/43000502040-volume-profile/
+
+The trade prices are grouped into the levels of the Delta size, the price of a level is the lowest price of the level.
+The point of control is the level with the most volume, the value area is expanded from the point of control
+to the larger adjacent level until the ValueAreaRatio of the total volume is covered.
+
+When Window is set, the trades older than the window are removed from the profile, otherwise all the trades are kept.
+The number of the levels is bounded by MaxLevels, the levels farthest from the latest trade price are removed first.
+*/
+//go:generate callbackgen -type VolumeProfile
+type VolumeProfile struct {
+	Symbol string
+
+	// Delta is the price size of the levels
+	Delta float64
+
+	// Window is the time range of the trades in the profile, 0 keeps all the trades
+	Window time.Duration
+
+	// ValueAreaRatio is the ratio of the volume in the value area, default 0.7
+	ValueAreaRatio float64
+
+	// MaxLevels is the max number of the levels, default 1000
+	MaxLevels int
+
+	// Levels are the volumes of the levels, the key is the price divided by the delta
+	Levels map[int64]float64
+
+	// levels are the sorted keys of Levels, they are rebuilt from Levels after the indicator is restored
+	levels []int64
+
+	// Entries are the trades in the window, only kept when the window is set
+	Entries []VolumeProfileEntry
+
+	PointOfControl types.Float64Slice
+	ValueAreaHigh  types.Float64Slice
+	ValueAreaLow   types.Float64Slice
+
+	UpdateCallbacks []func(poc, vah, val float64) `json:"-"`
+}
+
+type VolumeProfileEntry struct {
+	Level  int64     `json:"level"`
+	Volume float64   `json:"volume"`
+	Time   time.Time `json:"time"`
+}
+
+func (inc *VolumeProfile) Update(price, volume float64, tradeTime time.Time) {
+	if inc.Delta <= 0 {
+		panic("delta must be greater than 0")
+	}
+
+	if inc.Levels == nil {
+		inc.Levels = make(map[int64]float64)
+	}
+
+	if len(inc.levels) != len(inc.Levels) {
+		inc.levels = make([]int64, 0, len(inc.Levels))
+		for level := range inc.Levels {
+			inc.levels = append(inc.levels, level)
+		}
+		sort.Slice(inc.levels, func(i, j int) bool { return inc.levels[i] < inc.levels[j] })
+	}
+
+	// the epsilon avoids the floor of the prices on the level boundaries, e.g. 0.3 / 0.1 = 2.9999999999999996
+	level := int64(math.Floor(price/inc.Delta + 1e-9))
+	inc.addLevel(level, volume)
+
+	if inc.Window > 0 {
+		inc.Entries = append(inc.Entries, VolumeProfileEntry{Level: level, Volume: volume, Time: tradeTime})
+		inc.evict(tradeTime.Add(-inc.Window))
+	}
+
+	inc.truncate(level)
+
+	poc, vah, val := inc.calculate()
+	pushOrderFlowValue(&inc.PointOfControl, poc)
+	pushOrderFlowValue(&inc.ValueAreaHigh, vah)
+	pushOrderFlowValue(&inc.ValueAreaLow, val)
+}
+
+// searchLevel returns the index of the level in the sorted levels, or the index to insert it
+func (inc *VolumeProfile) searchLevel(level int64) int {
+	return sort.Search(len(inc.levels), func(i int) bool { return inc.levels[i] >= level })
+}
+
+func (inc *VolumeProfile) addLevel(level int64, volume float64) {
+	if _, ok := inc.Levels[level]; !ok {
+		i := inc.searchLevel(level)
+		inc.levels = append(inc.levels, 0)
+		copy(inc.levels[i+1:], inc.levels[i:])
+		inc.levels[i] = level
+	}
+
+	inc.Levels[level] += volume
+}
+
+func (inc *VolumeProfile) removeLevel(level int64) {
+	delete(inc.Levels, level)
+
+	if i := inc.searchLevel(level); i < len(inc.levels) && inc.levels[i] == level {
+		inc.levels = append(inc.levels[:i], inc.levels[i+1:]...)
+	}
+}
+
+// evict removes the trades before the given time from the levels
+func (inc *VolumeProfile) evict(since time.Time) {
+	i := 0
+	for ; i < len(inc.Entries) && inc.Entries[i].Time.Before(since); i++ {
+		entry := inc.Entries[i]
+
+		// the level may be removed by truncate already
+		volume, ok := inc.Levels[entry.Level]
+		if !ok {
+			continue
+		}
+
+		volume -= entry.Volume
+		if volume <= 1e-12 {
+			inc.removeLevel(entry.Level)
+		} else {
+			inc.Levels[entry.Level] = volume
+		}
+	}
+
+	inc.Entries = inc.Entries[i:]
+}
+
+// truncate removes the levels farthest from the given level until the number of the levels is not greater than MaxLevels
+func (inc *VolumeProfile) truncate(level int64) {
+	maxLevels := inc.MaxLevels
+	if maxLevels <= 0 {
+		maxLevels = DefaultVolumeProfileMaxLevels
+	}
+
+	for len(inc.levels) > maxLevels {
+		lowest, highest := inc.levels[0], inc.levels[len(inc.levels)-1]
+		if level-lowest >= highest-level {
+			inc.removeLevel(lowest)
+		} else {
+			inc.removeLevel(highest)
+		}
+	}
+}
+
+func (inc *VolumeProfile) calculate() (poc, vah, val float64) {
+	levels := inc.levels
+	if len(levels) == 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	center := 0
+	total := 0.
+	for i, level := range levels {
+		total += inc.Levels[level]
+		if inc.Levels[level] > inc.Levels[levels[center]] {
+			center = i
+		}
+	}
+
+	ratio := inc.ValueAreaRatio
+	if ratio == 0 {
+		ratio = DefaultValueAreaRatio
+	}
+
+	low, high := center, center
+	covered := inc.Levels[levels[center]]
+	for covered < total*ratio && (low > 0 || high < len(levels)-1) {
+		below, above := -1., -1.
+		if low > 0 {
+			below = inc.Levels[levels[low-1]]
+		}
+		if high < len(levels)-1 {
+			above = inc.Levels[levels[high+1]]
+		}
+
+		if above >= below {
+			high++
+			covered += above
+		} else {
+			low--
+			covered += below
+		}
+	}
+
+	return float64(levels[center]) * inc.Delta, float64(levels[high]) * inc.Delta, float64(levels[low]) * inc.Delta
+}
+
+// Last returns the price of the point of control
+func (inc *VolumeProfile) Last() float64 {
+	return inc.PointOfControl.Last()
+}
+
+func (inc *VolumeProfile) Index(i int) float64 {
+	return inc.PointOfControl.Index(i)
+}
+
+func (inc *VolumeProfile) Length() int {
+	return inc.PointOfControl.Length()
+}
+
+var _ types.Series = &VolumeProfile{}
+
+func (inc *VolumeProfile) handleMarketTrade(trade types.Trade) {
+	inc.Update(trade.Price.Float64(), trade.Quantity.Float64(), trade.Time.Time())
+	inc.EmitUpdate(inc.PointOfControl.Last(), inc.ValueAreaHigh.Last(), inc.ValueAreaLow.Last())
+}
+
+func (inc *VolumeProfile) Bind(updater MarketTradeUpdater) {
+	bindMarketTrades(updater, inc.Symbol, inc.handleMarketTrade)
+}
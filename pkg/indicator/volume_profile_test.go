@@ -0,0 +1,104 @@
+package indicator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestVolumeProfile_Update(t *testing.T) {
+	vp := &VolumeProfile{Symbol: "BTCUSDT", Delta: 1}
+
+	// level: volume => 100: 10, 101: 30, 102: 20, 103: 25, 104: 5, 105: 10
+	for _, tc := range []struct{ price, volume float64 }{
+		{100.5, 10}, {101.2, 30}, {102, 20}, {103.9, 25}, {104.1, 5}, {105, 10},
+	} {
+		vp.Update(tc.price, tc.volume, time.Time{})
+	}
+
+	// the value area covers 70 of 100: 101 (30), then 102 (20) and 103 (25) are larger than 100 (10)
+	assert.InDelta(t, 101, vp.Last(), Delta)
+	assert.InDelta(t, 103, vp.ValueAreaHigh.Last(), Delta)
+	assert.InDelta(t, 101, vp.ValueAreaLow.Last(), Delta)
+	assert.Equal(t, 6, vp.Length())
+}
+
+func TestVolumeProfile_Window(t *testing.T) {
+	stream := &types.StandardStream{}
+	vp := &VolumeProfile{Symbol: "BTCUSDT", Delta: 0.1, Window: time.Minute, ValueAreaRatio: 0.5}
+	vp.Bind(stream)
+
+	var pocs []float64
+	vp.OnUpdate(func(poc, vah, val float64) {
+		pocs = append(pocs, poc)
+	})
+
+	now := time.Now()
+	for i, tc := range []struct {
+		price, volume float64
+		after         time.Duration
+	}{
+		{0.3, 5, 0},
+		{0.45, 2, 30 * time.Second},
+		{0.41, 2, 40 * time.Second},
+		// the first trade is out of the window
+		{0.5, 1, 70 * time.Second},
+	} {
+		trade := buildMarketTrade("BTCUSDT", types.SideTypeBuy, tc.price, tc.volume)
+		trade.Time = types.Time(now.Add(tc.after))
+		trade.ID = uint64(i)
+		stream.EmitMarketTrade(trade)
+	}
+
+	assert.InDeltaSlice(t, []float64{0.3, 0.3, 0.3, 0.4}, pocs, Delta)
+	assert.Len(t, vp.Entries, 3)
+	assert.Equal(t, map[int64]float64{4: 4, 5: 1}, vp.Levels)
+	assert.InDelta(t, 0.4, vp.ValueAreaHigh.Last(), Delta)
+	assert.InDelta(t, 0.4, vp.ValueAreaLow.Last(), Delta)
+}
+
+func TestVolumeProfile_MaxLevels(t *testing.T) {
+	vp := &VolumeProfile{Symbol: "BTCUSDT", Delta: 1, MaxLevels: 3}
+	for _, price := range []float64{100, 101, 102, 110} {
+		vp.Update(price, 1, time.Time{})
+	}
+
+	// the level farthest from the latest trade is removed
+	assert.Equal(t, map[int64]float64{101: 1, 102: 1, 110: 1}, vp.Levels)
+	assert.Equal(t, []int64{101, 102, 110}, vp.levels)
+
+	vp.Update(95, 1, time.Time{})
+	assert.Equal(t, map[int64]float64{95: 1, 101: 1, 102: 1}, vp.Levels)
+	assert.Equal(t, []int64{95, 101, 102}, vp.levels)
+}
+
+func TestVolumeProfile_Restore(t *testing.T) {
+	vp := &VolumeProfile{Symbol: "BTCUSDT", Delta: 1}
+	for _, tc := range []struct{ price, volume float64 }{
+		{103, 5}, {100, 10}, {102, 3}, {101, 30},
+	} {
+		vp.Update(tc.price, tc.volume, time.Time{})
+	}
+
+	data, err := json.Marshal(vp)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	restored := &VolumeProfile{}
+	if !assert.NoError(t, json.Unmarshal(data, restored)) {
+		return
+	}
+
+	// the sorted levels are rebuilt from the restored levels
+	vp.Update(99, 1, time.Time{})
+	restored.Update(99, 1, time.Time{})
+	assert.Equal(t, []int64{99, 100, 101, 102, 103}, restored.levels)
+	assert.Equal(t, vp.PointOfControl, restored.PointOfControl)
+	assert.Equal(t, vp.ValueAreaHigh, restored.ValueAreaHigh)
+	assert.Equal(t, vp.ValueAreaLow, restored.ValueAreaLow)
+}
@@ -0,0 +1,98 @@
+package indicator
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+vpin implements the volume-synchronized probability of informed trading indicator
+
+Volume-Synchronized Probability of Informed Trading (VPIN)
+- https://www.stern.nyu.edu/sites/default/files/assets/documents/con_035928.pdf
+
+The trades are filled into the buckets of BucketVolume, a trade across the buckets is split into them.
+VPIN is the order imbalance of the last Window buckets:
+
+	VPIN = sum(|buy volume - sell volume|) / (Window * BucketVolume)
+
+The taker side of the market trade is used as the trade classification,
+the values are pushed when a bucket is filled after the first Window buckets.
+*/
+//go:generate callbackgen -type VPIN
+type VPIN struct {
+	Symbol string
+
+	// BucketVolume is the base volume of a bucket
+	BucketVolume float64
+
+	// Window is the number of the buckets
+	Window int
+
+	Values types.Float64Slice
+
+	// Imbalances are the absolute differences of the buy and the sell volumes of the filled buckets
+	Imbalances types.Float64Slice
+
+	// BuyVolume and SellVolume are the volumes of the bucket being filled
+	BuyVolume  float64
+	SellVolume float64
+
+	UpdateCallbacks []func(value float64) `json:"-"`
+}
+
+func (inc *VPIN) Update(side types.SideType, quantity float64) {
+	if inc.BucketVolume <= 0 || inc.Window <= 0 {
+		panic("bucket volume and window must be greater than 0")
+	}
+
+	// the residual of the float subtraction is ignored, so it doesn't start a new bucket
+	for quantity > inc.BucketVolume*1e-12 {
+		room := inc.BucketVolume - inc.BuyVolume - inc.SellVolume
+		filled := math.Min(quantity, room)
+		if side == types.SideTypeBuy {
+			inc.BuyVolume += filled
+		} else {
+			inc.SellVolume += filled
+		}
+
+		quantity -= filled
+		if filled < room {
+			return
+		}
+
+		pushOrderFlowValue(&inc.Imbalances, math.Abs(inc.BuyVolume-inc.SellVolume))
+		inc.BuyVolume, inc.SellVolume = 0, 0
+
+		if len(inc.Imbalances) >= inc.Window {
+			pushOrderFlowValue(&inc.Values, inc.Imbalances.Tail(inc.Window).Sum()/(float64(inc.Window)*inc.BucketVolume))
+		}
+	}
+}
+
+func (inc *VPIN) Last() float64 {
+	return inc.Values.Last()
+}
+
+func (inc *VPIN) Index(i int) float64 {
+	return inc.Values.Index(i)
+}
+
+func (inc *VPIN) Length() int {
+	return inc.Values.Length()
+}
+
+var _ types.Series = &VPIN{}
+
+func (inc *VPIN) handleMarketTrade(trade types.Trade) {
+	length := inc.Length()
+	inc.Update(trade.Side, trade.Quantity.Float64())
+	if inc.Length() != length {
+		inc.EmitUpdate(inc.Last())
+	}
+}
+
+func (inc *VPIN) Bind(updater MarketTradeUpdater) {
+	bindMarketTrades(updater, inc.Symbol, inc.handleMarketTrade)
+}
@@ -0,0 +1,36 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestVPIN_Update(t *testing.T) {
+	vpin := &VPIN{Symbol: "BTCUSDT", BucketVolume: 10, Window: 2}
+
+	var updates []float64
+	vpin.OnUpdate(func(value float64) {
+		updates = append(updates, value)
+	})
+
+	stream := &types.StandardStream{}
+	vpin.Bind(stream)
+
+	// bucket 1: buy 8, sell 2 => 6
+	stream.EmitMarketTrade(buildMarketTrade("BTCUSDT", types.SideTypeBuy, 100, 8))
+	assert.Equal(t, 0, vpin.Length())
+
+	// the sell trade is split into bucket 1 (2), bucket 2 (10) and bucket 3 (3)
+	stream.EmitMarketTrade(buildMarketTrade("BTCUSDT", types.SideTypeSell, 100, 15))
+	assert.InDeltaSlice(t, []float64{6, 10}, vpin.Imbalances, Delta)
+	assert.InDelta(t, 3, vpin.SellVolume, Delta)
+
+	// bucket 3: sell 3, buy 7 => 4
+	stream.EmitMarketTrade(buildMarketTrade("BTCUSDT", types.SideTypeBuy, 100, 7))
+	assert.InDeltaSlice(t, []float64{(6 + 10) / 20., (10 + 4) / 20.}, updates, Delta)
+	assert.InDelta(t, 0.7, vpin.Last(), Delta)
+	assert.Zero(t, vpin.BuyVolume+vpin.SellVolume)
+}
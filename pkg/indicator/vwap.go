@@ -25,7 +25,7 @@ type VWAP struct {
 	VolumeSum   float64
 
 	EndTime         time.Time
-	UpdateCallbacks []func(value float64)
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *VWAP) Update(price, volume float64) {
@@ -75,10 +75,10 @@ func (inc *VWAP) calculateAndUpdate(kLines []types.KLine) {
 			continue
 		}
 		inc.Update(priceF(k), k.Volume.Float64())
+		inc.EndTime = k.EndTime.Time()
 	}
 
 	inc.EmitUpdate(inc.Last())
-	inc.EndTime = kLines[len(kLines)-1].EndTime.Time()
 }
 
 func (inc *VWAP) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
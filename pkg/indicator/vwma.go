@@ -3,8 +3,6 @@ package indicator
 import (
 	"time"
 
-	log "github.com/sirupsen/logrus"
-
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -24,7 +22,13 @@ type VWMA struct {
 	Values  types.Float64Slice
 	EndTime time.Time
 
-	UpdateCallbacks []func(value float64)
+	// Precise keeps the rolling sums in fixedpoint.Value
+	Precise bool
+
+	PriceVolumes *RollingWindow
+	Volumes      *RollingWindow
+
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *VWMA) Last() float64 {
@@ -56,41 +60,48 @@ func KLineVolumeMapper(k types.KLine) float64 {
 	return k.Volume.Float64()
 }
 
-func (inc *VWMA) calculateAndUpdate(kLines []types.KLine) {
-	if len(kLines) < inc.Window {
-		return
-	}
-
-	var index = len(kLines) - 1
-	var kline = kLines[index]
+func (inc *VWMA) Update(price, volume float64) {
+	inc.rolling()
+	inc.PriceVolumes.Push(price * volume)
+	inc.Volumes.Push(volume)
+	inc.pushAverage()
+}
 
-	if inc.EndTime != zeroTime && kline.EndTime.Before(inc.EndTime) {
-		return
+func (inc *VWMA) rolling() {
+	if inc.PriceVolumes == nil {
+		inc.PriceVolumes = NewRollingWindow(inc.Window, inc.Precise, false)
+		inc.Volumes = NewRollingWindow(inc.Window, inc.Precise, false)
 	}
+}
 
-	var recentK = kLines[index-(inc.Window-1) : index+1]
-
-	pv, err := calculateSMA(recentK, inc.Window, KLinePriceVolumeMapper)
-	if err != nil {
-		log.WithError(err).Error("price x volume SMA error")
+// pushAverage pushes the average of the rolling windows when the windows are filled
+func (inc *VWMA) pushAverage() {
+	if !inc.Volumes.Full() {
 		return
 	}
-	v, err := calculateSMA(recentK, inc.Window, KLineVolumeMapper)
-	if err != nil {
-		log.WithError(err).Error("volume SMA error")
-		return
-	}
-
-	vwma := pv / v
-	inc.Values.Push(vwma)
 
+	inc.Values.Push(inc.PriceVolumes.Mean() / inc.Volumes.Mean())
 	if len(inc.Values) > MaxNumOfSMA {
 		inc.Values = inc.Values[MaxNumOfSMATruncateSize-1:]
 	}
+}
 
-	inc.EndTime = kLines[index].EndTime.Time()
-
-	inc.EmitUpdate(vwma)
+func (inc *VWMA) calculateAndUpdate(kLines []types.KLine) {
+	for _, k := range kLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
+		}
+
+		length := inc.Length()
+		inc.rolling()
+		inc.PriceVolumes.PushFixed(k.Close.Mul(k.Volume))
+		inc.Volumes.PushFixed(k.Volume)
+		inc.pushAverage()
+		inc.EndTime = k.EndTime.Time()
+		if inc.Length() != length {
+			inc.EmitUpdate(inc.Last())
+		}
+	}
 }
 
 func (inc *VWMA) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
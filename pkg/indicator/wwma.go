@@ -18,7 +18,7 @@ type WWMA struct {
 	Values       types.Float64Slice
 	LastOpenTime time.Time
 
-	UpdateCallbacks []func(value float64)
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *WWMA) Update(value float64) {
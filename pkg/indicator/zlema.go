@@ -1,6 +1,8 @@
 package indicator
 
 import (
+	"time"
+
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -11,60 +13,59 @@ import (
 type ZLEMA struct {
 	types.IntervalWindow
 
-	data  types.Float64Slice
-	zlema *EWMA
-	lag   int
+	Data types.Float64Slice
+	EWMA *EWMA
 
-	UpdateCallbacks []func(value float64)
+	EndTime         time.Time
+	UpdateCallbacks []func(value float64) `json:"-"`
 }
 
 func (inc *ZLEMA) Index(i int) float64 {
-	if inc.zlema == nil {
+	if inc.EWMA == nil {
 		return 0
 	}
-	return inc.zlema.Index(i)
+	return inc.EWMA.Index(i)
 }
 
 func (inc *ZLEMA) Last() float64 {
-	if inc.zlema == nil {
+	if inc.EWMA == nil {
 		return 0
 	}
-	return inc.zlema.Last()
+	return inc.EWMA.Last()
 }
 
 func (inc *ZLEMA) Length() int {
-	if inc.zlema == nil {
+	if inc.EWMA == nil {
 		return 0
 	}
-	return inc.zlema.Length()
+	return inc.EWMA.Length()
 }
 
 func (inc *ZLEMA) Update(value float64) {
-	if inc.lag == 0 || inc.zlema == nil {
-		inc.zlema = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, inc.Window}}
-		inc.lag = int((float64(inc.Window)-1.)/2. + 0.5)
+	if inc.EWMA == nil {
+		inc.EWMA = &EWMA{IntervalWindow: types.IntervalWindow{inc.Interval, inc.Window}}
 	}
-	inc.data.Push(value)
-	if len(inc.data) > MaxNumOfEWMA {
-		inc.data = inc.data[MaxNumOfEWMATruncateSize-1:]
+	inc.Data.Push(value)
+	if len(inc.Data) > MaxNumOfEWMA {
+		inc.Data = inc.Data[MaxNumOfEWMATruncateSize-1:]
 	}
-	if inc.lag >= inc.data.Length() {
+	lag := int((float64(inc.Window)-1.)/2. + 0.5)
+	if lag >= inc.Data.Length() {
 		return
 	}
-	emaData := 2.*value - inc.data[len(inc.data)-1-inc.lag]
-	inc.zlema.Update(emaData)
+	emaData := 2.*value - inc.Data[len(inc.Data)-1-lag]
+	inc.EWMA.Update(emaData)
 }
 
 var _ types.Series = &ZLEMA{}
 
 func (inc *ZLEMA) calculateAndUpdate(allKLines []types.KLine) {
-	if inc.zlema == nil {
-		for _, k := range allKLines {
-			inc.Update(k.Close.Float64())
-			inc.EmitUpdate(inc.Last())
+	for _, k := range allKLines {
+		if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+			continue
 		}
-	} else {
-		inc.Update(allKLines[len(allKLines)-1].Close.Float64())
+		inc.Update(k.Close.Float64())
+		inc.EndTime = k.EndTime.Time()
 		inc.EmitUpdate(inc.Last())
 	}
 }
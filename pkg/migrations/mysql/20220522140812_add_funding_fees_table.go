@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper"
+)
+
+func init() {
+	AddMigration(upAddFundingFeesTable, downAddFundingFeesTable)
+
+}
+
+func upAddFundingFeesTable(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `funding_fees`\n(\n    `gid`      BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n    `exchange` VARCHAR(24)     NOT NULL DEFAULT '',\n    `symbol`   VARCHAR(32)     NOT NULL,\n    -- asset is the settlement currency of the funding payment\n    `asset`    VARCHAR(10)     NOT NULL,\n    -- amount is positive when the funding is received, negative when it is paid\n    `amount`   DECIMAL(16, 8)  NOT NULL,\n    `txn_id`   VARCHAR(64)     NOT NULL,\n    `time`     DATETIME(3)     NOT NULL,\n    PRIMARY KEY (`gid`),\n    UNIQUE KEY `txn_id` (`exchange`, `txn_id`)\n);")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func downAddFundingFeesTable(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+
+	_, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS `funding_fees`;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper"
+)
+
+func init() {
+	AddMigration(upAddMarginInterestsTable, downAddMarginInterestsTable)
+
+}
+
+func upAddMarginInterestsTable(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `margin_interests`\n(\n    `gid`             BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n    `exchange`        VARCHAR(24)     NOT NULL DEFAULT '',\n    `asset`           VARCHAR(10)     NOT NULL,\n    -- isolated_symbol is empty for the cross margin account\n    `isolated_symbol` VARCHAR(32)     NOT NULL DEFAULT '',\n    `principal`       DECIMAL(16, 8)  NOT NULL,\n    `interest`        DECIMAL(20, 16) NOT NULL,\n    `interest_rate`   DECIMAL(20, 16) NOT NULL,\n    `time`            DATETIME(3)     NOT NULL,\n    PRIMARY KEY (`gid`),\n    UNIQUE KEY `asset_time` (`exchange`, `asset`, `isolated_symbol`, `time`)\n);")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func downAddMarginInterestsTable(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+
+	_, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS `margin_interests`;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
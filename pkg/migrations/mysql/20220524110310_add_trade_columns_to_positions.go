@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper"
+)
+
+func init() {
+	AddMigration(upAddTradeColumnsToPositions, downAddTradeColumnsToPositions)
+
+}
+
+func upAddTradeColumnsToPositions(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `positions`\n    ADD COLUMN `price`          DECIMAL(16, 8) NOT NULL DEFAULT 0.00000000,\n    ADD COLUMN `quantity`       DECIMAL(16, 8) NOT NULL DEFAULT 0.00000000,\n    ADD COLUMN `quote_quantity` DECIMAL(16, 8) NOT NULL DEFAULT 0.00000000,\n    ADD COLUMN `fee_in_quote`   DECIMAL(16, 8) NOT NULL DEFAULT 0.00000000;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "CREATE INDEX `positions_strategy_instance_id` ON `positions` (`strategy_instance_id`, `symbol`, `traded_at`);")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func downAddTradeColumnsToPositions(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+
+	_, err = tx.ExecContext(ctx, "DROP INDEX `positions_strategy_instance_id` ON `positions`;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `positions`\n    DROP COLUMN `price`,\n    DROP COLUMN `quantity`,\n    DROP COLUMN `quote_quantity`,\n    DROP COLUMN `fee_in_quote`;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
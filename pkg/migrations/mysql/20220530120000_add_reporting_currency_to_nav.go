@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper"
+)
+
+func init() {
+	AddMigration(upAddReportingCurrencyToNav, downAddReportingCurrencyToNav)
+
+}
+
+func upAddReportingCurrencyToNav(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `nav_history_details`\n    ADD COLUMN `reporting_currency`              VARCHAR(10)    NOT NULL DEFAULT '',\n    ADD COLUMN `net_asset_in_reporting_currency` DECIMAL(32, 8) NOT NULL DEFAULT 0.00000000\n;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func downAddReportingCurrencyToNav(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `nav_history_details`\n    DROP COLUMN `reporting_currency`,\n    DROP COLUMN `net_asset_in_reporting_currency`\n;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
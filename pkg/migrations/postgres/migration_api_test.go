@@ -0,0 +1,20 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/c9s/rockhopper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMigrationsMap(t *testing.T) {
+	mm := GetMigrationsMap()
+	assert.NotEmpty(t, mm)
+}
+
+func TestMergeMigrationsMap(t *testing.T) {
+	MergeMigrationsMap(map[int64]*rockhopper.Migration{
+		2: &rockhopper.Migration{},
+		3: &rockhopper.Migration{},
+	})
+}
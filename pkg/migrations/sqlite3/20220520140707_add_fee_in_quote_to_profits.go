@@ -0,0 +1,34 @@
+package sqlite3
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper"
+)
+
+func init() {
+	AddMigration(upAddFeeInQuoteToProfits, downAddFeeInQuoteToProfits)
+
+}
+
+func upAddFeeInQuoteToProfits(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `profits` ADD COLUMN `fee_in_quote` DECIMAL DEFAULT 0.00000000 NOT NULL;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func downAddFeeInQuoteToProfits(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+
+	_, err = tx.ExecContext(ctx, "SELECT 1;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
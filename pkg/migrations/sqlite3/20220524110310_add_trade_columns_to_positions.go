@@ -0,0 +1,54 @@
+package sqlite3
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper"
+)
+
+func init() {
+	AddMigration(upAddTradeColumnsToPositions, downAddTradeColumnsToPositions)
+
+}
+
+func upAddTradeColumnsToPositions(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `positions` ADD COLUMN `price` DECIMAL DEFAULT 0.00000000 NOT NULL;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `positions` ADD COLUMN `quantity` DECIMAL DEFAULT 0.00000000 NOT NULL;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `positions` ADD COLUMN `quote_quantity` DECIMAL DEFAULT 0.00000000 NOT NULL;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `positions` ADD COLUMN `fee_in_quote` DECIMAL DEFAULT 0.00000000 NOT NULL;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "CREATE INDEX `positions_strategy_instance_id` ON `positions` (`strategy_instance_id`, `symbol`, `traded_at`);")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func downAddTradeColumnsToPositions(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+
+	_, err = tx.ExecContext(ctx, "DROP INDEX IF EXISTS `positions_strategy_instance_id`;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
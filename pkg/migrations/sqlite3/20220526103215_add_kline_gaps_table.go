@@ -0,0 +1,44 @@
+package sqlite3
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper"
+)
+
+func init() {
+	AddMigration(upAddKlineGapsTable, downAddKlineGapsTable)
+
+}
+
+func upAddKlineGapsTable(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `kline_gaps`\n(\n    `gid`        INTEGER PRIMARY KEY AUTOINCREMENT,\n    `exchange`   VARCHAR(24) NOT NULL,\n    `symbol`     VARCHAR(20) NOT NULL,\n    `interval`   VARCHAR(3)  NOT NULL,\n    -- the kline range [start_time, end_time) that the exchange has no data\n    `start_time` DATETIME(3) NOT NULL,\n    `end_time`   DATETIME(3) NOT NULL\n);")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "CREATE UNIQUE INDEX `kline_gaps_start_time` ON `kline_gaps` (`exchange`, `symbol`, `interval`, `start_time`);")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func downAddKlineGapsTable(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+
+	_, err = tx.ExecContext(ctx, "DROP INDEX IF EXISTS `kline_gaps_start_time`;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS `kline_gaps`;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
@@ -0,0 +1,39 @@
+package sqlite3
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper"
+)
+
+func init() {
+	AddMigration(upAddReportingCurrencyToNav, downAddReportingCurrencyToNav)
+
+}
+
+func upAddReportingCurrencyToNav(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `nav_history_details` ADD COLUMN `reporting_currency` VARCHAR(10) DEFAULT '' NOT NULL;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE `nav_history_details` ADD COLUMN `net_asset_in_reporting_currency` DECIMAL DEFAULT 0.00000000 NOT NULL;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func downAddReportingCurrencyToNav(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+
+	_, err = tx.ExecContext(ctx, "SELECT 1;")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
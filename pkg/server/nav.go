@@ -0,0 +1,90 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// parseNavQuery parses the query parameters of the nav api:
+// session, exchange, currency, since, until (RFC3339), interval (1h, 1d, ...) and group-by (session, exchange, currency)
+func parseNavQuery(c *gin.Context) (options service.QueryNavOptions, err error) {
+	options = service.QueryNavOptions{
+		Session:  c.Query("session"),
+		Exchange: types.ExchangeName(c.Query("exchange")),
+		Currency: c.Query("currency"),
+		Interval: types.Interval(c.DefaultQuery("interval", "1d")),
+		GroupBy:  service.NavGroupBy(c.Query("group-by")),
+	}
+
+	if v := c.Query("since"); v != "" {
+		if options.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			return options, err
+		}
+	} else {
+		options.Since = time.Now().AddDate(0, 0, -30)
+	}
+
+	if v := c.Query("until"); v != "" {
+		if options.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			return options, err
+		}
+	}
+
+	return options, nil
+}
+
+func (s *Server) listNav(c *gin.Context) {
+	if s.Environ.NavService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "database is not configured"})
+		return
+	}
+
+	options, err := parseNavQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	points, err := s.Environ.NavService.Query(c, options)
+	if err != nil {
+		logrus.WithError(err).Error("nav query error")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"nav": points})
+}
+
+func (s *Server) listNavReturns(c *gin.Context) {
+	if s.Environ.NavService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "database is not configured"})
+		return
+	}
+
+	options, err := parseNavQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// the returns are calculated from the total value of all the filtered sessions
+	options.GroupBy = service.NavGroupByNone
+	if c.Query("since") == "" {
+		options.Since = time.Time{}
+	}
+
+	points, err := s.Environ.NavService.Query(c, options)
+	if err != nil {
+		logrus.WithError(err).Error("nav query error")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"returns": service.CalculateNavReturns(points, service.DefaultNavReturnRanges)})
+}
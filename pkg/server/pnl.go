@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/service"
+)
+
+// listSessionStrategyProfits returns the profit breakdown of the strategy instances traded on the session exchange,
+// the symbol is read from the path or the query, and the other query parameters are:
+// strategy, instance (the strategy instance ID), since and until (RFC3339)
+func (s *Server) listSessionStrategyProfits(c *gin.Context) {
+	sessionName := c.Param("session")
+	session, ok := s.Environ.Session(sessionName)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("session %s not found", sessionName)})
+		return
+	}
+
+	if s.Environ.ProfitService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "database is not configured"})
+		return
+	}
+
+	options := service.QueryStrategyProfitOptions{
+		Exchange:           session.ExchangeName,
+		Symbol:             c.Param("symbol"),
+		Strategy:           c.Query("strategy"),
+		StrategyInstanceID: c.Query("instance"),
+	}
+
+	if options.Symbol == "" {
+		options.Symbol = c.Query("symbol")
+	}
+
+	var err error
+	if v := c.Query("since"); v != "" {
+		if options.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if v := c.Query("until"); v != "" {
+		if options.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	summaries, err := s.Environ.ProfitService.QueryStrategyProfits(c, options)
+	if err != nil {
+		logrus.WithError(err).Error("strategy profit query error")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	for i := range summaries {
+		if price, ok := session.LastPrice(summaries[i].Symbol); ok {
+			summaries[i].UpdateUnrealizedProfit(price)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"profits": summaries})
+}
@@ -220,6 +220,8 @@ func (s *Server) newEngine() *gin.Engine {
 	})
 
 	r.GET("/api/assets", s.listAssets)
+	r.GET("/api/nav", s.listNav)
+	r.GET("/api/nav/returns", s.listNavReturns)
 	r.GET("/api/sessions/:session", s.listSessions)
 	r.GET("/api/sessions/:session/trades", s.listSessionTrades)
 	r.GET("/api/sessions/:session/open-orders", s.listSessionOpenOrders)
@@ -227,9 +229,7 @@ func (s *Server) newEngine() *gin.Engine {
 	r.GET("/api/sessions/:session/account/balances", s.getSessionAccountBalance)
 	r.GET("/api/sessions/:session/symbols", s.listSessionSymbols)
 
-	r.GET("/api/sessions/:session/pnl", func(c *gin.Context) {
-		c.JSON(200, gin.H{"message": "pong"})
-	})
+	r.GET("/api/sessions/:session/pnl", s.listSessionStrategyProfits)
 
 	r.GET("/api/sessions/:session/market/:symbol/open-orders", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "pong"})
@@ -239,9 +239,7 @@ func (s *Server) newEngine() *gin.Engine {
 		c.JSON(200, gin.H{"message": "pong"})
 	})
 
-	r.GET("/api/sessions/:session/market/:symbol/pnl", func(c *gin.Context) {
-		c.JSON(200, gin.H{"message": "pong"})
-	})
+	r.GET("/api/sessions/:session/market/:symbol/pnl", s.listSessionStrategyProfits)
 
 	r.GET("/api/strategies/single", s.listStrategies)
 	r.NoRoute(s.assetsHandler)
@@ -473,7 +471,23 @@ func (s *Server) listAssets(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"assets": totalAssets})
+	var currencies []string
+	for currency := range totalAssets {
+		currencies = append(currencies, currency)
+	}
+
+	// the assets are returned without the converted values if the reporting prices can not be updated
+	if err := s.Environ.UpdateReportingPrices(c, currencies); err != nil {
+		logrus.WithError(err).Error("reporting price update failed")
+	} else {
+		totalAssets = s.Environ.ConvertAssets(totalAssets)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"assets":              totalAssets,
+		"reportingCurrency":   s.Environ.ReportingCurrency(),
+		"inReportingCurrency": totalAssets.InReportingCurrency(),
+	})
 }
 
 func (s *Server) setupSaveConfig(c *gin.Context) {
@@ -24,7 +24,7 @@ func (s *AccountService) InsertAsset(time time.Time, session string, name types.
 
 	var err error
 	for _, v := range assets {
-		_, _err := s.DB.Exec(`
+		_, _err := s.DB.Exec(s.DB.Rebind(`
 			INSERT INTO nav_history_details (
 			                 session,
 							 exchange,
@@ -39,8 +39,10 @@ func (s *AccountService) InsertAsset(time time.Time, session string, name types.
 							 borrowed,
 							 net_asset,
 							 price_in_usd,
+							 reporting_currency,
+							 net_asset_in_reporting_currency,
 			                 is_margin, is_isolated, isolated_symbol)
-				values (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);`,
+				values (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);`),
 				session,
 				name,
 				account,
@@ -54,6 +56,8 @@ func (s *AccountService) InsertAsset(time time.Time, session string, name types.
 				v.Borrowed,
 				v.NetAsset,
 				v.PriceInUSD,
+				v.ReportingCurrency,
+				v.InReportingCurrency,
 				isMargin,
 				isIsolatedMargin,
 				isolatedMarginSymbol)
@@ -35,7 +35,20 @@ func TestAccountService(t *testing.T) {
 			Borrowed:   fixedpoint.MustNewFromString("0"),
 			NetAsset:   fixedpoint.MustNewFromString("1"),
 			PriceInUSD: fixedpoint.MustNewFromString("44870"),
+
+			ReportingCurrency:   "TWD",
+			InReportingCurrency: fixedpoint.MustNewFromString("300"),
 		},
 	})
 	assert.NoError(t, err)
+
+	var row struct {
+		ReportingCurrency   string           `db:"reporting_currency"`
+		InReportingCurrency fixedpoint.Value `db:"net_asset_in_reporting_currency"`
+	}
+	err = xdb.Get(&row, "SELECT `reporting_currency`, `net_asset_in_reporting_currency` FROM `nav_history_details` WHERE `currency` = 'BTC'")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "TWD", row.ReportingCurrency)
+		assert.Equal(t, "300", row.InReportingCurrency.String())
+	}
 }
@@ -121,7 +121,7 @@ func (s *BacktestService) QueryKLine(ex types.ExchangeName, symbol string, inter
 	// make the SQL syntax IDE friendly, so that it can analyze it.
 	sql := fmt.Sprintf("SELECT * FROM `%s` WHERE  `symbol` = :symbol AND `interval` = :interval  and exchange = :exchange  ORDER BY end_time "+orderBy+" LIMIT "+strconv.Itoa(limit), tableName)
 
-	rows, err := s.DB.NamedQuery(sql, map[string]interface{}{
+	rows, err := s.DB.NamedQuery(dialectSQL(s.DB, sql), map[string]interface{}{
 		"exchange": ex.String(),
 		"interval": interval,
 		"symbol":   symbol,
@@ -151,7 +151,7 @@ func (s *BacktestService) QueryKLinesForward(exchange types.ExchangeName, symbol
 	sql := "SELECT * FROM `binance_klines` WHERE `end_time` >= :start_time AND `symbol` = :symbol AND `interval` = :interval and exchange = :exchange ORDER BY end_time ASC LIMIT :limit"
 	sql = strings.ReplaceAll(sql, "binance_klines", tableName)
 
-	rows, err := s.DB.NamedQuery(sql, map[string]interface{}{
+	rows, err := s.DB.NamedQuery(dialectSQL(s.DB, sql), map[string]interface{}{
 		"start_time": startTime,
 		"limit":      limit,
 		"symbol":     symbol,
@@ -172,7 +172,7 @@ func (s *BacktestService) QueryKLinesBackward(exchange types.ExchangeName, symbo
 	sql = strings.ReplaceAll(sql, "binance_klines", tableName)
 	sql = "SELECT t.* FROM (" + sql + ") AS t ORDER BY t.end_time ASC"
 
-	rows, err := s.DB.NamedQuery(sql, map[string]interface{}{
+	rows, err := s.DB.NamedQuery(dialectSQL(s.DB, sql), map[string]interface{}{
 		"limit":    limit,
 		"end_time": endTime,
 		"symbol":   symbol,
@@ -208,7 +208,7 @@ func (s *BacktestService) QueryKLinesCh(since, until time.Time, exchange types.E
 	if err != nil {
 		return returnError(err)
 	}
-	sql = s.DB.Rebind(sql)
+	sql = s.DB.Rebind(dialectSQL(s.DB, sql))
 
 	rows, err := s.DB.Queryx(sql, args...)
 	if err != nil {
@@ -302,7 +302,7 @@ func (s *BacktestService) Insert(kline types.KLine) error {
 	sql := fmt.Sprintf("INSERT INTO `%s` (`exchange`, `start_time`, `end_time`, `symbol`, `interval`, `open`, `high`, `low`, `close`, `closed`, `volume`, `quote_volume`, `taker_buy_base_volume`, `taker_buy_quote_volume`)"+
 		"VALUES (:exchange, :start_time, :end_time, :symbol, :interval, :open, :high, :low, :close, :closed, :volume, :quote_volume, :taker_buy_base_volume, :taker_buy_quote_volume)", tableName)
 
-	_, err := s.DB.NamedExec(sql, kline)
+	_, err := s.DB.NamedExec(dialectSQL(s.DB, sql), kline)
 	return err
 }
 
@@ -320,7 +320,7 @@ func (s *BacktestService) BatchInsert(kline []types.KLine) error {
 	sql := fmt.Sprintf("INSERT INTO `%s` (`exchange`, `start_time`, `end_time`, `symbol`, `interval`, `open`, `high`, `low`, `close`, `closed`, `volume`, `quote_volume`, `taker_buy_base_volume`, `taker_buy_quote_volume`)"+
 		" values (:exchange, :start_time, :end_time, :symbol, :interval, :open, :high, :low, :close, :closed, :volume, :quote_volume, :taker_buy_base_volume, :taker_buy_quote_volume); ", tableName)
 
-	_, err := s.DB.NamedExec(sql, kline)
+	_, err := s.DB.NamedExec(dialectSQL(s.DB, sql), kline)
 	return err
 }
 
@@ -332,7 +332,7 @@ func (s *BacktestService) _deleteDuplicatedKLine(k types.KLine) error {
 
 	tableName := s._targetKlineTable(k.Exchange)
 	sql := fmt.Sprintf("delete from `%s` where gid = :gid  ", tableName)
-	_, err := s.DB.NamedExec(sql, k)
+	_, err := s.DB.NamedExec(dialectSQL(s.DB, sql), k)
 	return err
 }
 
@@ -8,6 +8,7 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	mysqlMigrations "github.com/c9s/bbgo/pkg/migrations/mysql"
+	postgresMigrations "github.com/c9s/bbgo/pkg/migrations/postgres"
 	sqlite3Migrations "github.com/c9s/bbgo/pkg/migrations/sqlite3"
 )
 
@@ -18,7 +19,7 @@ type DatabaseService struct {
 }
 
 func NewDatabaseService(driver, dsn string) *DatabaseService {
-	if driver == "mysql" {
+	if driver == DriverMySQL {
 		var err error
 		dsn, err = ReformatMysqlDSN(dsn)
 		if err != nil {
@@ -53,10 +54,12 @@ func (s *DatabaseService) Upgrade(ctx context.Context) error {
 	var migrations rockhopper.MigrationSlice
 
 	switch s.Driver {
-	case "sqlite3":
+	case DriverSQLite3:
 		migrations = sqlite3Migrations.Migrations()
-	case "mysql":
+	case DriverMySQL:
 		migrations = mysqlMigrations.Migrations()
+	case DriverPostgres:
+		migrations = postgresMigrations.Migrations()
 
 	}
 
@@ -58,7 +58,7 @@ func (s *DepositService) Sync(ctx context.Context, ex types.Exchange) error {
 
 func (s *DepositService) QueryLast(ex types.ExchangeName, limit int) ([]types.Deposit, error) {
 	sql := "SELECT * FROM `deposits` WHERE `exchange` = :exchange ORDER BY `time` DESC LIMIT :limit"
-	rows, err := s.DB.NamedQuery(sql, map[string]interface{}{
+	rows, err := s.DB.NamedQuery(dialectSQL(s.DB, sql), map[string]interface{}{
 		"exchange": ex,
 		"limit":    limit,
 	})
@@ -75,7 +75,7 @@ func (s *DepositService) Query(exchangeName types.ExchangeName) ([]types.Deposit
 		"exchange": exchangeName,
 	}
 	sql := "SELECT * FROM `deposits` WHERE `exchange` = :exchange ORDER BY `time` ASC"
-	rows, err := s.DB.NamedQuery(sql, args)
+	rows, err := s.DB.NamedQuery(dialectSQL(s.DB, sql), args)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// the database driver names that are supported by the database service
+const (
+	DriverMySQL    = "mysql"
+	DriverSQLite3  = "sqlite3"
+	DriverPostgres = "postgres"
+)
+
+// isPostgres returns true if the db connection uses the dollar sign placeholders like postgres
+func isPostgres(db *sqlx.DB) bool {
+	return sqlx.BindType(db.DriverName()) == sqlx.DOLLAR
+}
+
+// dialectSQL converts the mysql style backtick quoted identifiers into the double quoted identifiers for postgres,
+// the queries in the services are written with the backticks since both mysql and sqlite3 accept them.
+func dialectSQL(db *sqlx.DB, sql string) string {
+	if !isPostgres(db) {
+		return sql
+	}
+
+	return strings.ReplaceAll(sql, "`", `"`)
+}
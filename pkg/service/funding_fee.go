@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// FundingFeeService syncs the funding payments of the perpetual futures positions into db
+type FundingFeeService struct {
+	DB *sqlx.DB
+}
+
+// Sync syncs the funding fee records into db
+func (s *FundingFeeService) Sync(ctx context.Context, ex types.Exchange) error {
+	service, ok := ex.(types.ExchangeFundingFeeService)
+	if !ok {
+		return ErrNotImplemented
+	}
+
+	txnIDs := map[string]struct{}{}
+
+	// query descending
+	records, err := s.QueryLast(ex.Name(), 50)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		txnIDs[record.TransactionID] = struct{}{}
+	}
+
+	since := time.Time{}
+	if len(records) > 0 {
+		since = records[len(records)-1].Time.Time()
+	}
+
+	// symbol "" means all symbols
+	fees, err := service.QueryFundingFeeHistory(ctx, "", since, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, fee := range fees {
+		if _, exists := txnIDs[fee.TransactionID]; exists {
+			continue
+		}
+
+		if err := s.Insert(fee); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *FundingFeeService) QueryLast(ex types.ExchangeName, limit int) ([]types.FundingFee, error) {
+	sql := "SELECT * FROM `funding_fees` WHERE `exchange` = :exchange ORDER BY `time` DESC LIMIT :limit"
+	rows, err := s.DB.NamedQuery(dialectSQL(s.DB, sql), map[string]interface{}{
+		"exchange": ex,
+		"limit":    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	return s.scanRows(rows)
+}
+
+// Query returns the funding fees of the symbol in the time range, empty symbol means all symbols
+func (s *FundingFeeService) Query(ctx context.Context, ex types.ExchangeName, symbol string, since, until time.Time) ([]types.FundingFee, error) {
+	var where = []string{"`exchange` = :exchange"}
+	if len(symbol) > 0 {
+		where = append(where, "`symbol` = :symbol")
+	}
+
+	if !since.IsZero() {
+		where = append(where, "`time` >= :since")
+	}
+
+	if !until.IsZero() {
+		where = append(where, "`time` < :until")
+	}
+
+	sql := "SELECT * FROM `funding_fees` WHERE " + strings.Join(where, " AND ") + " ORDER BY `time` ASC"
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), map[string]interface{}{
+		"exchange": ex,
+		"symbol":   symbol,
+		"since":    since,
+		"until":    until,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	return s.scanRows(rows)
+}
+
+func (s *FundingFeeService) scanRows(rows *sqlx.Rows) (fees []types.FundingFee, err error) {
+	for rows.Next() {
+		var fee types.FundingFee
+		if err := rows.StructScan(&fee); err != nil {
+			return fees, err
+		}
+
+		fees = append(fees, fee)
+	}
+
+	return fees, rows.Err()
+}
+
+func (s *FundingFeeService) Insert(fee types.FundingFee) error {
+	sql := `INSERT INTO funding_fees (exchange, symbol, asset, amount, txn_id, time)
+			VALUES (:exchange, :symbol, :asset, :amount, :txn_id, :time)`
+	_, err := s.DB.NamedExec(sql, fee)
+	return err
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestFundingFeeService(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	service := &FundingFeeService{DB: xdb}
+
+	t0 := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i, symbol := range []string{"BTCUSDT", "ETHUSDT", "BTCUSDT"} {
+		err = service.Insert(types.FundingFee{
+			Exchange:      types.ExchangeBinance,
+			Symbol:        symbol,
+			Asset:         "USDT",
+			Amount:        fixedpoint.NewFromFloat(-0.5),
+			TransactionID: string(rune('1' + i)),
+			Time:          types.Time(t0.Add(time.Duration(i) * 8 * time.Hour)),
+		})
+		assert.NoError(t, err)
+	}
+
+	fees, err := service.Query(ctx, types.ExchangeBinance, "BTCUSDT", time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, fees, 2) {
+		assert.Equal(t, "-0.5", fees[0].Amount.String())
+		assert.True(t, fees[0].Time.Time().Before(fees[1].Time.Time()))
+	}
+
+	fees, err = service.Query(ctx, types.ExchangeBinance, "", t0.Add(time.Hour), time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, fees, 2)
+
+	last, err := service.QueryLast(types.ExchangeBinance, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, last, 1) {
+		assert.Equal(t, "3", last[0].TransactionID)
+	}
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// the column names of the kline csv layout, the other column names are ignored
+const (
+	KLineColumnStartTime           = "start_time"
+	KLineColumnEndTime             = "end_time"
+	KLineColumnOpen                = "open"
+	KLineColumnHigh                = "high"
+	KLineColumnLow                 = "low"
+	KLineColumnClose               = "close"
+	KLineColumnVolume              = "volume"
+	KLineColumnQuoteVolume         = "quote_volume"
+	KLineColumnNumberOfTrades      = "num_trades"
+	KLineColumnTakerBuyBaseVolume  = "taker_buy_base_volume"
+	KLineColumnTakerBuyQuoteVolume = "taker_buy_quote_volume"
+)
+
+// KLineCSVLayout describes the columns and the time format of a kline csv file
+type KLineCSVLayout struct {
+	// Columns are the column names in the order of the csv fields, use "-" for the ignored fields
+	Columns []string
+
+	// TimeFormat is one of "s", "ms", "us" or a Go time layout,
+	// the numeric timestamps are detected by their magnitude when it's empty.
+	TimeFormat string
+
+	// Delimiter is the field delimiter, defaults to comma
+	Delimiter rune
+}
+
+// BinanceKLineCSVLayout is the layout of the binance public data archives (https://data.binance.vision)
+var BinanceKLineCSVLayout = KLineCSVLayout{
+	Columns: []string{
+		KLineColumnStartTime,
+		KLineColumnOpen,
+		KLineColumnHigh,
+		KLineColumnLow,
+		KLineColumnClose,
+		KLineColumnVolume,
+		KLineColumnEndTime,
+		KLineColumnQuoteVolume,
+		KLineColumnNumberOfTrades,
+		KLineColumnTakerBuyBaseVolume,
+		KLineColumnTakerBuyQuoteVolume,
+		"-",
+	},
+}
+
+// ParseKLineCSVLayout parses the comma separated column names into the layout
+func ParseKLineCSVLayout(columns string, timeFormat string) (KLineCSVLayout, error) {
+	layout := KLineCSVLayout{TimeFormat: timeFormat}
+	for _, c := range strings.Split(columns, ",") {
+		layout.Columns = append(layout.Columns, strings.TrimSpace(c))
+	}
+
+	if layout.index(KLineColumnStartTime) < 0 {
+		return layout, fmt.Errorf("kline csv layout requires the %s column", KLineColumnStartTime)
+	}
+
+	for _, c := range []string{KLineColumnOpen, KLineColumnHigh, KLineColumnLow, KLineColumnClose} {
+		if layout.index(c) < 0 {
+			return layout, fmt.Errorf("kline csv layout requires the %s column", c)
+		}
+	}
+
+	return layout, nil
+}
+
+func (l KLineCSVLayout) index(column string) int {
+	for i, c := range l.Columns {
+		if c == column {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l KLineCSVLayout) parseTime(s string) (time.Time, error) {
+	switch l.TimeFormat {
+	case "s", "ms", "us", "":
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			if l.TimeFormat == "" {
+				return parseLooseTime(s)
+			}
+			return time.Time{}, err
+		}
+
+		format := l.TimeFormat
+		if format == "" {
+			switch {
+			case n >= 1e15:
+				format = "us"
+			case n >= 1e12:
+				format = "ms"
+			default:
+				format = "s"
+			}
+		}
+
+		switch format {
+		case "s":
+			return time.Unix(n, 0).UTC(), nil
+		case "us":
+			return time.UnixMicro(n).UTC(), nil
+		default:
+			return time.UnixMilli(n).UTC(), nil
+		}
+	}
+
+	return time.ParseInLocation(l.TimeFormat, s, time.UTC)
+}
+
+func parseLooseTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported time format: %s", s)
+}
+
+func (l KLineCSVLayout) field(record []string, column string) (string, bool) {
+	i := l.index(column)
+	if i < 0 || i >= len(record) {
+		return "", false
+	}
+
+	return strings.TrimSpace(record[i]), true
+}
+
+func (l KLineCSVLayout) parseRecord(record []string, exchange types.ExchangeName, symbol string, interval types.Interval) (types.KLine, error) {
+	k := types.KLine{
+		Exchange: exchange,
+		Symbol:   symbol,
+		Interval: interval,
+		Closed:   true,
+	}
+
+	startTimeStr, _ := l.field(record, KLineColumnStartTime)
+	startTime, err := l.parseTime(startTimeStr)
+	if err != nil {
+		return k, err
+	}
+
+	k.StartTime = types.Time(startTime)
+	k.EndTime = types.Time(startTime.Add(interval.Duration() - time.Millisecond))
+
+	if s, ok := l.field(record, KLineColumnEndTime); ok && len(s) > 0 {
+		endTime, err := l.parseTime(s)
+		if err != nil {
+			return k, err
+		}
+
+		k.EndTime = types.Time(endTime)
+	}
+
+	values := map[string]*fixedpoint.Value{
+		KLineColumnOpen:                &k.Open,
+		KLineColumnHigh:                &k.High,
+		KLineColumnLow:                 &k.Low,
+		KLineColumnClose:               &k.Close,
+		KLineColumnVolume:              &k.Volume,
+		KLineColumnQuoteVolume:         &k.QuoteVolume,
+		KLineColumnTakerBuyBaseVolume:  &k.TakerBuyBaseAssetVolume,
+		KLineColumnTakerBuyQuoteVolume: &k.TakerBuyQuoteAssetVolume,
+	}
+
+	for column, v := range values {
+		s, ok := l.field(record, column)
+		if !ok || len(s) == 0 {
+			continue
+		}
+
+		if *v, err = fixedpoint.NewFromString(s); err != nil {
+			return k, errors.Wrapf(err, "can not parse the %s field", column)
+		}
+	}
+
+	if s, ok := l.field(record, KLineColumnNumberOfTrades); ok && len(s) > 0 {
+		if k.NumberOfTrades, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return k, errors.Wrapf(err, "can not parse the %s field", KLineColumnNumberOfTrades)
+		}
+	}
+
+	return k, nil
+}
+
+// ParseKLineCSV parses the kline csv records with the given layout,
+// the header row is skipped if the start time of the first row can not be parsed.
+func ParseKLineCSV(r io.Reader, layout KLineCSVLayout, exchange types.ExchangeName, symbol string, interval types.Interval) ([]types.KLine, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	if layout.Delimiter != 0 {
+		reader.Comma = layout.Delimiter
+	}
+
+	var klines []types.KLine
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return klines, err
+		}
+
+		k, err := layout.parseRecord(record, exchange, symbol, interval)
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+
+			return klines, errors.Wrapf(err, "line %d", line)
+		}
+
+		klines = append(klines, k)
+	}
+
+	return klines, nil
+}
+
+// ReadKLineFile reads the klines from the csv file or the zip archive of the csv files
+func ReadKLineFile(path string, layout KLineCSVLayout, exchange types.ExchangeName, symbol string, interval types.Interval) ([]types.KLine, error) {
+	if strings.ToLower(filepath.Ext(path)) != ".zip" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		defer f.Close()
+		return ParseKLineCSV(f, layout, exchange, symbol, interval)
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer archive.Close()
+
+	var klines []types.KLine
+	for _, file := range archive.File {
+		if strings.ToLower(filepath.Ext(file.Name)) != ".csv" {
+			continue
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		fileKLines, err := ParseKLineCSV(f, layout, exchange, symbol, interval)
+		f.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s", file.Name)
+		}
+
+		klines = append(klines, fileKLines...)
+	}
+
+	return klines, nil
+}
+
+// ParseBinanceKLineFileName parses the symbol and the interval from the binance public data file name,
+// e.g. BTCUSDT-1m-2022-01.zip or BTCUSDT-1h-2022-01-02.csv
+func ParseBinanceKLineFileName(path string) (symbol string, interval types.Interval, ok bool) {
+	name := filepath.Base(path)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	parts := strings.Split(name, "-")
+	if len(parts) < 2 {
+		return "", "", false
+	}
+
+	interval = types.Interval(parts[1])
+	if _, ok := types.SupportedIntervals[interval]; !ok {
+		return "", "", false
+	}
+
+	return strings.ToUpper(parts[0]), interval, true
+}
+
+// KLineGap is a missing time range of the klines, the Until time is exclusive
+type KLineGap struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+}
+
+func (g KLineGap) String() string {
+	return fmt.Sprintf("%s ~ %s", g.Since.Format(time.RFC3339), g.Until.Format(time.RFC3339))
+}
+
+func findTimeGaps(startTimes []time.Time, interval types.Interval) (gaps []KLineGap) {
+	duration := interval.Duration()
+	for i := 1; i < len(startTimes); i++ {
+		expected := startTimes[i-1].Add(duration)
+		if startTimes[i].After(expected) {
+			gaps = append(gaps, KLineGap{Since: expected, Until: startTimes[i]})
+		}
+	}
+	return gaps
+}
+
+// FindKLineGaps finds the missing time ranges between the klines, the klines should be sorted by the start time
+func FindKLineGaps(klines []types.KLine, interval types.Interval) []KLineGap {
+	startTimes := make([]time.Time, len(klines))
+	for i, k := range klines {
+		startTimes[i] = k.StartTime.Time()
+	}
+
+	return findTimeGaps(startTimes, interval)
+}
+
+// KLineImportResult is the summary of the imported klines
+type KLineImportResult struct {
+	Parsed     int
+	Inserted   int
+	Duplicated int
+
+	// Gaps are the missing time ranges of the stored klines within the imported time range
+	Gaps []KLineGap
+}
+
+// KLineImporter imports the klines from the local files into the exchange kline tables
+type KLineImporter struct {
+	Backtest *BacktestService
+
+	// BatchSize is the number of klines inserted in one statement, defaults to 500
+	BatchSize int
+}
+
+// Import inserts the klines that are not stored yet and validates the continuity of the stored klines,
+// all klines should have the same exchange, symbol and interval.
+func (i *KLineImporter) Import(ctx context.Context, klines []types.KLine) (*KLineImportResult, error) {
+	result := &KLineImportResult{Parsed: len(klines)}
+	if len(klines) == 0 {
+		return result, nil
+	}
+
+	sort.Slice(klines, func(a, b int) bool {
+		return klines[a].StartTime.Before(klines[b].StartTime.Time())
+	})
+
+	first, last := klines[0], klines[len(klines)-1]
+	exchange, symbol, interval := first.Exchange, first.Symbol, first.Interval
+
+	existing, err := i.Backtest.queryKLineStartTimes(ctx, exchange, symbol, interval, first.StartTime.Time(), last.StartTime.Time())
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[int64]struct{}, len(existing)+len(klines))
+	for _, t := range existing {
+		stored[t.UnixMilli()] = struct{}{}
+	}
+
+	var inserts []types.KLine
+	for _, k := range klines {
+		key := k.StartTime.UnixMilli()
+		if _, ok := stored[key]; ok {
+			result.Duplicated++
+			continue
+		}
+
+		stored[key] = struct{}{}
+		inserts = append(inserts, k)
+	}
+
+	batchSize := i.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	for len(inserts) > 0 {
+		n := batchSize
+		if n > len(inserts) {
+			n = len(inserts)
+		}
+
+		if err := i.Backtest.BatchInsert(inserts[:n]); err != nil {
+			return result, err
+		}
+
+		result.Inserted += n
+		inserts = inserts[n:]
+	}
+
+	startTimes := make([]time.Time, 0, len(stored))
+	for key := range stored {
+		startTimes = append(startTimes, time.UnixMilli(key))
+	}
+
+	sort.Slice(startTimes, func(a, b int) bool {
+		return startTimes[a].Before(startTimes[b])
+	})
+
+	result.Gaps = findTimeGaps(startTimes, interval)
+
+	log.Infof("imported %s %s %s klines: parsed %d, inserted %d, duplicated %d, gaps %d",
+		exchange, symbol, interval, result.Parsed, result.Inserted, result.Duplicated, len(result.Gaps))
+
+	return result, nil
+}
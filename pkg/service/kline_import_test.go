@@ -0,0 +1,131 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const binanceKLineCSV = `open_time,open,high,low,close,volume,close_time,quote_volume,count,taker_buy_volume,taker_buy_quote_volume,ignore
+1640995200000,46216.93,46271.08,46208.37,46250.00,40.57574000,1640995259999,1876018.22807300,1153,19.64289000,908156.18719000,0
+1640995260000,46250.00,46344.23,46234.39,46312.76,42.38106000,1640995319999,1962309.19393600,1133,26.32281000,1218710.01040600,0
+1640995380000,46312.76,46331.01,46240.63,46263.58,45.52897000,1640995439999,2106436.85627800,1093,14.33051000,663064.76049200,0
+`
+
+func TestParseKLineCSV_Binance(t *testing.T) {
+	klines, err := ParseKLineCSV(strings.NewReader(binanceKLineCSV), BinanceKLineCSVLayout, types.ExchangeBinance, "BTCUSDT", types.Interval1m)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if assert.Len(t, klines, 3, "the header row should be skipped") {
+		k := klines[0]
+		assert.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), k.StartTime.Time().UTC())
+		assert.Equal(t, int64(1640995259999), k.EndTime.UnixMilli())
+		assert.Equal(t, "46216.93", k.Open.String())
+		assert.Equal(t, "46250", k.Close.String())
+		assert.Equal(t, "40.57574", k.Volume.String())
+		assert.Equal(t, uint64(1153), k.NumberOfTrades)
+		assert.Equal(t, "BTCUSDT", k.Symbol)
+	}
+
+	gaps := FindKLineGaps(klines, types.Interval1m)
+	if assert.Len(t, gaps, 1) {
+		assert.Equal(t, time.UnixMilli(1640995320000).UTC(), gaps[0].Since.UTC())
+		assert.Equal(t, time.UnixMilli(1640995380000).UTC(), gaps[0].Until.UTC())
+	}
+}
+
+func TestParseKLineCSV_Generic(t *testing.T) {
+	layout, err := ParseKLineCSVLayout("start_time,-,open,high,low,close,volume", "2006-01-02 15:04:05")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	csv := "2022-01-01 00:00:00,x,1.0,2.0,0.5,1.5,100\n2022-01-01 01:00:00,x,1.5,2.5,1.0,2.0,200\n"
+	klines, err := ParseKLineCSV(strings.NewReader(csv), layout, types.ExchangeFTX, "ETHUSDT", types.Interval1h)
+	if assert.NoError(t, err) && assert.Len(t, klines, 2) {
+		assert.Equal(t, "1.5", klines[0].Close.String())
+		assert.Equal(t, time.Date(2022, 1, 1, 0, 59, 59, int(999*time.Millisecond), time.UTC), klines[0].EndTime.Time())
+		assert.Empty(t, FindKLineGaps(klines, types.Interval1h))
+	}
+
+	_, err = ParseKLineCSVLayout("open,high,low,close", "")
+	assert.Error(t, err, "start_time column is required")
+}
+
+func TestReadKLineFile_Zip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "BTCUSDT-1m-2022-01.zip")
+	f, err := os.Create(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	w := zip.NewWriter(f)
+	entry, err := w.Create("BTCUSDT-1m-2022-01.csv")
+	assert.NoError(t, err)
+	_, err = entry.Write([]byte(binanceKLineCSV))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.NoError(t, f.Close())
+
+	symbol, interval, ok := ParseBinanceKLineFileName(path)
+	assert.True(t, ok)
+	assert.Equal(t, "BTCUSDT", symbol)
+	assert.Equal(t, types.Interval1m, interval)
+
+	klines, err := ReadKLineFile(path, BinanceKLineCSVLayout, types.ExchangeBinance, symbol, interval)
+	if assert.NoError(t, err) {
+		assert.Len(t, klines, 3)
+	}
+
+	_, _, ok = ParseBinanceKLineFileName("klines.csv")
+	assert.False(t, ok)
+}
+
+func TestKLineImporter_Import(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	importer := &KLineImporter{Backtest: &BacktestService{DB: xdb}, BatchSize: 2}
+
+	klines, err := ParseKLineCSV(strings.NewReader(binanceKLineCSV), BinanceKLineCSVLayout, types.ExchangeBinance, "BTCUSDT", types.Interval1m)
+	assert.NoError(t, err)
+
+	result, err := importer.Import(ctx, klines[:2])
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, result.Inserted)
+		assert.Equal(t, 0, result.Duplicated)
+		assert.Empty(t, result.Gaps)
+	}
+
+	// the stored klines are skipped
+	result, err = importer.Import(ctx, klines)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 3, result.Parsed)
+		assert.Equal(t, 1, result.Inserted)
+		assert.Equal(t, 2, result.Duplicated)
+		assert.Len(t, result.Gaps, 1)
+	}
+
+	last, err := importer.Backtest.QueryLastKLine(types.ExchangeBinance, "BTCUSDT", types.Interval1m)
+	if assert.NoError(t, err) && assert.NotNil(t, last) {
+		// the prices are stored as the floats
+		assert.InDelta(t, 46263.58, last.Close.Float64(), 1e-8)
+	}
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	batch2 "github.com/c9s/bbgo/pkg/exchange/batch"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// KLineCheckReport is the integrity check result of the stored klines of one exchange/symbol/interval
+type KLineCheckReport struct {
+	Exchange types.ExchangeName
+	Symbol   string
+	Interval types.Interval
+
+	NumKLines int
+
+	// Gaps are the missing time ranges that are not marked as no data yet
+	Gaps []KLineGap
+
+	// MarkedGaps are the time ranges that the exchange has no data, they are marked by the previous repairs
+	MarkedGaps []KLineGap
+
+	// Duplicates are the klines that have the same start time as the previous one
+	Duplicates []types.KLine
+
+	// OutOfOrder are the klines that are fed before the previous kline by the end time order,
+	// or the end time is before the start time.
+	OutOfOrder []types.KLine
+}
+
+// OK returns true if there is no unmarked gap, no duplicated kline and no out-of-order kline
+func (r *KLineCheckReport) OK() bool {
+	return len(r.Gaps) == 0 && len(r.Duplicates) == 0 && len(r.OutOfOrder) == 0
+}
+
+// KLineRepairResult is the result of the kline repair
+type KLineRepairResult struct {
+	Report *KLineCheckReport
+
+	// Deleted is the number of the deleted duplicated and out-of-order klines
+	Deleted int
+
+	// Inserted is the number of the re-fetched klines
+	Inserted int
+
+	// Marked are the time ranges that the exchange has no data
+	Marked []KLineGap
+}
+
+func (s *BacktestService) queryKLineStartTimes(ctx context.Context, exchange types.ExchangeName, symbol string, interval types.Interval, since, until time.Time) ([]time.Time, error) {
+	tableName := s._targetKlineTable(exchange)
+	sql := "SELECT `start_time` FROM `" + tableName + "` WHERE `exchange` = :exchange AND `symbol` = :symbol AND `interval` = :interval" +
+		" AND `start_time` >= :since AND `start_time` <= :until ORDER BY `start_time` ASC"
+
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), map[string]interface{}{
+		"exchange": exchange.String(),
+		"symbol":   symbol,
+		"interval": interval,
+		"since":    since,
+		"until":    until,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var startTimes []time.Time
+	for rows.Next() {
+		var t types.Time
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+
+		startTimes = append(startTimes, t.Time())
+	}
+
+	return startTimes, rows.Err()
+}
+
+// QueryKLineGaps queries the marked no data ranges that overlap the given time range
+func (s *BacktestService) QueryKLineGaps(ctx context.Context, exchange types.ExchangeName, symbol string, interval types.Interval, since, until time.Time) ([]KLineGap, error) {
+	sql := "SELECT `start_time`, `end_time` FROM `kline_gaps` WHERE `exchange` = :exchange AND `symbol` = :symbol AND `interval` = :interval" +
+		" AND `end_time` > :since AND `start_time` < :until ORDER BY `start_time` ASC"
+
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), map[string]interface{}{
+		"exchange": exchange.String(),
+		"symbol":   symbol,
+		"interval": interval,
+		"since":    since,
+		"until":    until,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var gaps []KLineGap
+	for rows.Next() {
+		var startTime, endTime types.Time
+		if err := rows.Scan(&startTime, &endTime); err != nil {
+			return nil, err
+		}
+
+		gaps = append(gaps, KLineGap{Since: startTime.Time(), Until: endTime.Time()})
+	}
+
+	return gaps, rows.Err()
+}
+
+// MarkKLineGap marks the time range that the exchange has no data, so that the backtest engine can skip or forward-fill it
+func (s *BacktestService) MarkKLineGap(ctx context.Context, exchange types.ExchangeName, symbol string, interval types.Interval, gap KLineGap) error {
+	marked, err := s.QueryKLineGaps(ctx, exchange, symbol, interval, gap.Since, gap.Until)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range marked {
+		if m.Since.Equal(gap.Since) {
+			return nil
+		}
+	}
+
+	sql := "INSERT INTO `kline_gaps` (`exchange`, `symbol`, `interval`, `start_time`, `end_time`)" +
+		" VALUES (:exchange, :symbol, :interval, :start_time, :end_time)"
+	_, err = s.DB.NamedExecContext(ctx, dialectSQL(s.DB, sql), map[string]interface{}{
+		"exchange":   exchange.String(),
+		"symbol":     symbol,
+		"interval":   interval,
+		"start_time": gap.Since,
+		"end_time":   gap.Until,
+	})
+	return err
+}
+
+// subtractGaps removes the marked ranges from the gaps
+func subtractGaps(gaps []KLineGap, marked []KLineGap) (remains []KLineGap) {
+	for _, gap := range gaps {
+		pieces := []KLineGap{gap}
+		for _, m := range marked {
+			var next []KLineGap
+			for _, p := range pieces {
+				if !m.Since.Before(p.Until) || !m.Until.After(p.Since) {
+					next = append(next, p)
+					continue
+				}
+
+				if m.Since.After(p.Since) {
+					next = append(next, KLineGap{Since: p.Since, Until: m.Since})
+				}
+
+				if m.Until.Before(p.Until) {
+					next = append(next, KLineGap{Since: m.Until, Until: p.Until})
+				}
+			}
+			pieces = next
+		}
+
+		remains = append(remains, pieces...)
+	}
+
+	return remains
+}
+
+// findRangeGaps finds the missing time ranges of the start times within the time range,
+// including the ranges before the first start time and after the last start time.
+// A kline is in the range if its end time is between since and until, which is the same condition as the CheckKLines query.
+func findRangeGaps(startTimes []time.Time, interval types.Interval, since, until time.Time) []KLineGap {
+	if len(startTimes) == 0 {
+		if until.After(since) {
+			return []KLineGap{{Since: since, Until: until}}
+		}
+
+		return nil
+	}
+
+	duration := interval.Duration()
+	first := startTimes[0]
+	last := startTimes[len(startTimes)-1]
+
+	var gaps []KLineGap
+
+	// the klines before the first one end after since if their start time is after since - duration
+	if n := (first.Sub(since) + duration - 1) / duration; n > 0 {
+		gaps = append(gaps, KLineGap{Since: first.Add(-n * duration), Until: first})
+	}
+
+	gaps = append(gaps, findTimeGaps(startTimes, interval)...)
+
+	// the klines after the last one end before until if their start time is before until - duration + 1ms
+	if n := (until.Sub(last) - duration + time.Millisecond) / duration; n > 0 {
+		gaps = append(gaps, KLineGap{Since: last.Add(duration), Until: last.Add((n + 1) * duration)})
+	}
+
+	return gaps
+}
+
+// CheckKLines finds the gaps, the duplicated klines and the out-of-order klines of the stored klines,
+// the klines are checked in the order that the backtest engine feeds them.
+func (s *BacktestService) CheckKLines(ctx context.Context, exchange types.ExchangeName, symbol string, interval types.Interval, since, until time.Time) (*KLineCheckReport, error) {
+	report := &KLineCheckReport{
+		Exchange: exchange,
+		Symbol:   symbol,
+		Interval: interval,
+	}
+
+	tableName := s._targetKlineTable(exchange)
+	sql := "SELECT * FROM `" + tableName + "` WHERE `exchange` = :exchange AND `symbol` = :symbol AND `interval` = :interval" +
+		" AND `end_time` BETWEEN :since AND :until ORDER BY `end_time` ASC, `gid` ASC"
+
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), map[string]interface{}{
+		"exchange": exchange.String(),
+		"symbol":   symbol,
+		"interval": interval,
+		"since":    since,
+		"until":    until,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	klines, err := s.scanRows(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	report.NumKLines = len(klines)
+
+	var startTimes []time.Time
+	var lastStartTime time.Time
+	seen := make(map[int64]struct{}, len(klines))
+	for _, k := range klines {
+		key := k.StartTime.UnixMilli()
+		if _, ok := seen[key]; ok {
+			report.Duplicates = append(report.Duplicates, k)
+			continue
+		}
+
+		if k.StartTime.Before(lastStartTime) || k.EndTime.Before(k.StartTime.Time()) {
+			report.OutOfOrder = append(report.OutOfOrder, k)
+			continue
+		}
+
+		seen[key] = struct{}{}
+		lastStartTime = k.StartTime.Time()
+		startTimes = append(startTimes, k.StartTime.Time())
+	}
+
+	gaps := findRangeGaps(startTimes, interval, since, until)
+	if len(gaps) > 0 {
+		report.MarkedGaps, err = s.QueryKLineGaps(ctx, exchange, symbol, interval, since, until)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report.Gaps = subtractGaps(gaps, report.MarkedGaps)
+	return report, nil
+}
+
+// RepairKLines deletes the duplicated and the out-of-order klines, re-fetches the missing ranges from the exchange,
+// and marks the ranges that the exchange has no data.
+func (s *BacktestService) RepairKLines(ctx context.Context, exchange types.Exchange, symbol string, interval types.Interval, since, until time.Time) (*KLineRepairResult, error) {
+	exchangeName := exchange.Name()
+	result := &KLineRepairResult{}
+
+	report, err := s.CheckKLines(ctx, exchangeName, symbol, interval, since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(report.Duplicates) > 0 || len(report.OutOfOrder) > 0 {
+		for _, k := range append(report.Duplicates, report.OutOfOrder...) {
+			if err := s._deleteDuplicatedKLine(k); err != nil {
+				return nil, err
+			}
+
+			result.Deleted++
+		}
+
+		// the deleted out-of-order klines become gaps
+		report, err = s.CheckKLines(ctx, exchangeName, symbol, interval, since, until)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	importer := &KLineImporter{Backtest: s}
+	batch := &batch2.KLineBatchQuery{Exchange: exchange}
+	for _, gap := range report.Gaps {
+		log.Infof("repairing %s %s %s klines %s", exchangeName, symbol, interval, gap)
+
+		// query one more kline, the batch query drops the result if there is only one kline at the start time
+		klineC, errC := batch.Query(ctx, symbol, interval, gap.Since, gap.Until.Add(interval.Duration()-time.Millisecond))
+
+		var klines []types.KLine
+		for ks := range klineC {
+			for _, k := range ks {
+				if k.StartTime.Before(gap.Until) {
+					klines = append(klines, k)
+				}
+			}
+		}
+
+		if err := <-errC; err != nil {
+			return result, err
+		}
+
+		imported, err := importer.Import(ctx, klines)
+		if err != nil {
+			return result, err
+		}
+
+		result.Inserted += imported.Inserted
+
+		// the ranges that are still missing are not provided by the exchange
+		startTimes, err := s.queryKLineStartTimes(ctx, exchangeName, symbol, interval, gap.Since, gap.Until.Add(-time.Millisecond))
+		if err != nil {
+			return result, err
+		}
+
+		startTimes = append([]time.Time{gap.Since.Add(-interval.Duration())}, startTimes...)
+		startTimes = append(startTimes, gap.Until)
+		sort.Slice(startTimes, func(i, j int) bool { return startTimes[i].Before(startTimes[j]) })
+
+		for _, missing := range findTimeGaps(startTimes, interval) {
+			log.Warnf("%s %s %s klines are not provided by the exchange, marking %s", exchangeName, symbol, interval, missing)
+			if err := s.MarkKLineGap(ctx, exchangeName, symbol, interval, missing); err != nil {
+				return result, err
+			}
+
+			result.Marked = append(result.Marked, missing)
+		}
+	}
+
+	result.Report, err = s.CheckKLines(ctx, exchangeName, symbol, interval, since, until)
+	return result, err
+}
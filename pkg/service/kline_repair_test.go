@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// klineSourceExchange provides the klines from the given slice
+type klineSourceExchange struct {
+	types.Exchange
+	klines []types.KLine
+}
+
+func (e *klineSourceExchange) Name() types.ExchangeName {
+	return types.ExchangeBinance
+}
+
+func (e *klineSourceExchange) QueryKLines(ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions) (klines []types.KLine, err error) {
+	for _, k := range e.klines {
+		if k.StartTime.Before(*options.StartTime) || k.StartTime.After(*options.EndTime) {
+			continue
+		}
+
+		klines = append(klines, k)
+	}
+
+	return klines, nil
+}
+
+func newTestKLine(startTime time.Time, minute int) types.KLine {
+	start := startTime.Add(time.Duration(minute) * time.Minute)
+	return types.KLine{
+		Exchange:  types.ExchangeBinance,
+		Symbol:    "BTCUSDT",
+		Interval:  types.Interval1m,
+		StartTime: types.Time(start),
+		EndTime:   types.Time(start.Add(time.Minute - time.Millisecond)),
+		Close:     fixedpoint.NewFromInt(int64(20000 + minute)),
+		Closed:    true,
+	}
+}
+
+func assertKLineGap(t *testing.T, since, until time.Time, gap KLineGap) {
+	assert.True(t, since.Equal(gap.Since), "expected gap since %s, got %s", since, gap.Since)
+	assert.True(t, until.Equal(gap.Until), "expected gap until %s, got %s", until, gap.Until)
+}
+
+func TestBacktestService_RepairKLines(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	s := &BacktestService{DB: xdb}
+
+	startTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := startTime.Add(10 * time.Minute)
+
+	// minute 2, 5 and 6 are missing, minute 1 is duplicated
+	for _, m := range []int{0, 1, 1, 3, 4, 7, 8, 9} {
+		assert.NoError(t, s.Insert(newTestKLine(startTime, m)))
+	}
+
+	report, err := s.CheckKLines(ctx, types.ExchangeBinance, "BTCUSDT", types.Interval1m, startTime, until)
+	if assert.NoError(t, err) {
+		assert.False(t, report.OK())
+		assert.Equal(t, 8, report.NumKLines)
+		assert.Len(t, report.Duplicates, 1)
+		assert.Empty(t, report.OutOfOrder)
+		if assert.Len(t, report.Gaps, 2) {
+			assertKLineGap(t, startTime.Add(2*time.Minute), startTime.Add(3*time.Minute), report.Gaps[0])
+			assertKLineGap(t, startTime.Add(5*time.Minute), startTime.Add(7*time.Minute), report.Gaps[1])
+		}
+	}
+
+	// the exchange does not have the kline of minute 6
+	source := &klineSourceExchange{}
+	for m := 0; m < 10; m++ {
+		if m != 6 {
+			source.klines = append(source.klines, newTestKLine(startTime, m))
+		}
+	}
+
+	result, err := s.RepairKLines(ctx, source, "BTCUSDT", types.Interval1m, startTime, until)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, result.Deleted)
+		assert.Equal(t, 2, result.Inserted)
+		if assert.Len(t, result.Marked, 1) {
+			assertKLineGap(t, startTime.Add(6*time.Minute), startTime.Add(7*time.Minute), result.Marked[0])
+		}
+
+		assert.True(t, result.Report.OK())
+		assert.Len(t, result.Report.MarkedGaps, 1)
+	}
+
+	// the marked ranges are not repaired again
+	result, err = s.RepairKLines(ctx, source, "BTCUSDT", types.Interval1m, startTime, until)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, result.Inserted)
+		assert.Empty(t, result.Marked)
+	}
+
+	gaps, err := s.QueryKLineGaps(ctx, types.ExchangeBinance, "BTCUSDT", types.Interval1m, startTime, until)
+	if assert.NoError(t, err) {
+		if assert.Len(t, gaps, 1) {
+			assertKLineGap(t, startTime.Add(6*time.Minute), startTime.Add(7*time.Minute), gaps[0])
+		}
+	}
+}
+
+func Test_subtractGaps(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return t0.Add(time.Duration(m) * time.Minute) }
+
+	remains := subtractGaps(
+		[]KLineGap{{Since: at(0), Until: at(10)}, {Since: at(20), Until: at(25)}},
+		[]KLineGap{{Since: at(2), Until: at(4)}, {Since: at(20), Until: at(25)}},
+	)
+	assert.Equal(t, []KLineGap{{Since: at(0), Until: at(2)}, {Since: at(4), Until: at(10)}}, remains)
+}
+
+func Test_findRangeGaps(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return t0.Add(time.Duration(m) * time.Minute) }
+
+	// minute 0, 1, 4, 8 and 9 are missing
+	gaps := findRangeGaps([]time.Time{at(2), at(3), at(5), at(6), at(7)}, types.Interval1m, at(0), at(10))
+	assert.Equal(t, []KLineGap{
+		{Since: at(0), Until: at(2)},
+		{Since: at(4), Until: at(5)},
+		{Since: at(8), Until: at(10)},
+	}, gaps)
+
+	// the kline of minute 0 ends after since and the kline of minute 9 ends after until
+	gaps = findRangeGaps([]time.Time{at(1), at(8)}, types.Interval1m, at(0).Add(30*time.Second), at(9).Add(30*time.Second))
+	assert.Equal(t, []KLineGap{
+		{Since: at(0), Until: at(1)},
+		{Since: at(2), Until: at(8)},
+	}, gaps)
+
+	assert.Empty(t, findRangeGaps([]time.Time{at(0), at(1), at(2)}, types.Interval1m, at(0), at(3)))
+	assert.Equal(t, []KLineGap{{Since: at(0), Until: at(3)}}, findRangeGaps(nil, types.Interval1m, at(0), at(3)))
+}
+
+func TestBacktestService_CheckKLines_RangeBounds(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	s := &BacktestService{DB: xdb}
+
+	startTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := startTime.Add(10 * time.Minute)
+
+	// minute 0, 1, 8 and 9 are missing
+	for m := 2; m < 8; m++ {
+		assert.NoError(t, s.Insert(newTestKLine(startTime, m)))
+	}
+
+	report, err := s.CheckKLines(ctx, types.ExchangeBinance, "BTCUSDT", types.Interval1m, startTime, until)
+	if assert.NoError(t, err) {
+		assert.False(t, report.OK())
+		if assert.Len(t, report.Gaps, 2) {
+			assertKLineGap(t, startTime, startTime.Add(2*time.Minute), report.Gaps[0])
+			assertKLineGap(t, startTime.Add(8*time.Minute), until, report.Gaps[1])
+		}
+	}
+
+	source := &klineSourceExchange{}
+	for m := 0; m < 10; m++ {
+		source.klines = append(source.klines, newTestKLine(startTime, m))
+	}
+
+	result, err := s.RepairKLines(ctx, source, "BTCUSDT", types.Interval1m, startTime, until)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 4, result.Inserted)
+		assert.Empty(t, result.Marked)
+		assert.True(t, result.Report.OK())
+		assert.Equal(t, 10, result.Report.NumKLines)
+	}
+}
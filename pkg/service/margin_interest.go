@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// MarginInterestService syncs the interest charged for the borrowed margin assets into db
+type MarginInterestService struct {
+	DB *sqlx.DB
+}
+
+func marginInterestKey(interest types.MarginInterest) string {
+	return interest.Asset + ":" + interest.IsolatedSymbol + ":" + strconv.FormatInt(interest.Time.Time().UnixMilli(), 10)
+}
+
+// Sync syncs the margin interest records into db
+func (s *MarginInterestService) Sync(ctx context.Context, ex types.Exchange) error {
+	service, ok := ex.(types.MarginInterestHistoryService)
+	if !ok {
+		return ErrNotImplemented
+	}
+
+	keys := map[string]struct{}{}
+
+	// query descending
+	records, err := s.QueryLast(ex.Name(), 50)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		keys[marginInterestKey(record)] = struct{}{}
+	}
+
+	since := time.Time{}
+	if len(records) > 0 {
+		since = records[len(records)-1].Time.Time()
+	}
+
+	// asset "" means all assets
+	interests, err := service.QueryInterestHistory(ctx, "", since, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, interest := range interests {
+		if _, exists := keys[marginInterestKey(interest)]; exists {
+			continue
+		}
+
+		if err := s.Insert(interest); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *MarginInterestService) QueryLast(ex types.ExchangeName, limit int) ([]types.MarginInterest, error) {
+	sql := "SELECT * FROM `margin_interests` WHERE `exchange` = :exchange ORDER BY `time` DESC LIMIT :limit"
+	rows, err := s.DB.NamedQuery(dialectSQL(s.DB, sql), map[string]interface{}{
+		"exchange": ex,
+		"limit":    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	return s.scanRows(rows)
+}
+
+// Query returns the margin interests of the assets in the time range, empty assets means all assets
+func (s *MarginInterestService) Query(ctx context.Context, ex types.ExchangeName, assets []string, since, until time.Time) ([]types.MarginInterest, error) {
+	var where = []string{"`exchange` = :exchange"}
+	var args = map[string]interface{}{
+		"exchange": ex,
+		"since":    since,
+		"until":    until,
+	}
+
+	if len(assets) > 0 {
+		var names []string
+		for i, asset := range assets {
+			name := "asset" + strconv.Itoa(i)
+			args[name] = asset
+			names = append(names, ":"+name)
+		}
+
+		where = append(where, "`asset` IN ("+strings.Join(names, ", ")+")")
+	}
+
+	if !since.IsZero() {
+		where = append(where, "`time` >= :since")
+	}
+
+	if !until.IsZero() {
+		where = append(where, "`time` < :until")
+	}
+
+	sql := "SELECT * FROM `margin_interests` WHERE " + strings.Join(where, " AND ") + " ORDER BY `time` ASC"
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), args)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	return s.scanRows(rows)
+}
+
+func (s *MarginInterestService) scanRows(rows *sqlx.Rows) (interests []types.MarginInterest, err error) {
+	for rows.Next() {
+		var interest types.MarginInterest
+		if err := rows.StructScan(&interest); err != nil {
+			return interests, err
+		}
+
+		interests = append(interests, interest)
+	}
+
+	return interests, rows.Err()
+}
+
+func (s *MarginInterestService) Insert(interest types.MarginInterest) error {
+	sql := `INSERT INTO margin_interests (exchange, asset, isolated_symbol, principal, interest, interest_rate, time)
+			VALUES (:exchange, :asset, :isolated_symbol, :principal, :interest, :interest_rate, :time)`
+	_, err := s.DB.NamedExec(sql, interest)
+	return err
+}
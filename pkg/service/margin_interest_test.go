@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestMarginInterestService(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	service := &MarginInterestService{DB: xdb}
+
+	t0 := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i, asset := range []string{"BTC", "USDT", "ETH"} {
+		err = service.Insert(types.MarginInterest{
+			Exchange:     types.ExchangeBinance,
+			Asset:        asset,
+			Principal:    fixedpoint.NewFromInt(10),
+			Interest:     fixedpoint.NewFromFloat(0.0001),
+			InterestRate: fixedpoint.NewFromFloat(0.00001),
+			Time:         types.Time(t0.Add(time.Duration(i) * time.Hour)),
+		})
+		assert.NoError(t, err)
+	}
+
+	// the same asset at the same time is recorded once
+	err = service.Insert(types.MarginInterest{
+		Exchange: types.ExchangeBinance,
+		Asset:    "BTC",
+		Interest: fixedpoint.NewFromFloat(0.0001),
+		Time:     types.Time(t0),
+	})
+	assert.Error(t, err)
+
+	interests, err := service.Query(ctx, types.ExchangeBinance, []string{"BTC", "USDT"}, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, interests, 2) {
+		assert.Equal(t, "BTC", interests[0].Asset)
+		assert.Equal(t, "0.0001", interests[0].Interest.String())
+		assert.Equal(t, "USDT", interests[1].Asset)
+	}
+
+	interests, err = service.Query(ctx, types.ExchangeBinance, nil, t0.Add(time.Hour), t0.Add(2*time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, interests, 1) {
+		assert.Equal(t, "USDT", interests[0].Asset)
+	}
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// NavTotalSession is the session name used by the xnav strategy for recording the total assets of all sessions,
+// the records of this session are excluded from the aggregation unless the session filter is set to it.
+const NavTotalSession = "ALL"
+
+// NavHistoryDetail is a currency row of the asset snapshot recorded by Environment.RecordAsset
+type NavHistoryDetail struct {
+	Time          types.Time         `json:"time" db:"time"`
+	Session       string             `json:"session" db:"session"`
+	Exchange      types.ExchangeName `json:"exchange" db:"exchange"`
+	SubAccount    string             `json:"subAccount" db:"subaccount"`
+	Currency      string             `json:"currency" db:"currency"`
+	NetAssetInUSD fixedpoint.Value   `json:"netAssetInUSD" db:"net_asset_in_usd"`
+	NetAssetInBTC fixedpoint.Value   `json:"netAssetInBTC" db:"net_asset_in_btc"`
+	NetAsset      fixedpoint.Value   `json:"netAsset" db:"net_asset"`
+
+	ReportingCurrency   string           `json:"reportingCurrency" db:"reporting_currency"`
+	InReportingCurrency fixedpoint.Value `json:"inReportingCurrency" db:"net_asset_in_reporting_currency"`
+}
+
+type NavGroupBy string
+
+const (
+	NavGroupByNone     NavGroupBy = ""
+	NavGroupBySession  NavGroupBy = "session"
+	NavGroupByExchange NavGroupBy = "exchange"
+	NavGroupByCurrency NavGroupBy = "currency"
+)
+
+type QueryNavOptions struct {
+	// filters, optional
+	Session  string
+	Exchange types.ExchangeName
+	Currency string
+
+	Since, Until time.Time
+
+	// Interval is the resampling interval, defaults to 1d
+	Interval types.Interval
+
+	GroupBy NavGroupBy
+}
+
+// NavPoint is the resampled net asset value at the end of an interval
+type NavPoint struct {
+	Time  time.Time `json:"time"`
+	Group string    `json:"group,omitempty"`
+
+	InUSD fixedpoint.Value `json:"inUSD"`
+	InBTC fixedpoint.Value `json:"inBTC"`
+
+	// ReportingCurrency is the reporting currency of the snapshots, it's empty if the snapshots were recorded without one
+	ReportingCurrency   string           `json:"reportingCurrency,omitempty"`
+	InReportingCurrency fixedpoint.Value `json:"inReportingCurrency"`
+
+	// NetAsset is the net asset quantity of the currency, it's only set when the points are grouped by currency
+	NetAsset fixedpoint.Value `json:"netAsset,omitempty"`
+}
+
+type NavService struct {
+	DB *sqlx.DB
+}
+
+func NewNavService(db *sqlx.DB) *NavService {
+	return &NavService{DB: db}
+}
+
+func (s *NavService) QueryDetails(ctx context.Context, options QueryNavOptions) ([]NavHistoryDetail, error) {
+	var where = []string{"`session` != :total_session"}
+	if len(options.Session) > 0 {
+		where = []string{"`session` = :session"}
+	}
+
+	if len(options.Exchange) > 0 {
+		where = append(where, "`exchange` = :exchange")
+	}
+
+	if len(options.Currency) > 0 {
+		where = append(where, "`currency` = :currency")
+	}
+
+	if !options.Since.IsZero() {
+		where = append(where, "`time` >= :since")
+	}
+
+	if !options.Until.IsZero() {
+		where = append(where, "`time` < :until")
+	}
+
+	sql := "SELECT `time`, `session`, `exchange`, `subaccount`, `currency`, `net_asset_in_usd`, `net_asset_in_btc`, `net_asset`, `reporting_currency`, `net_asset_in_reporting_currency` FROM `nav_history_details`" +
+		" WHERE " + strings.Join(where, " AND ") +
+		" ORDER BY `time` ASC"
+
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), map[string]interface{}{
+		"total_session": NavTotalSession,
+		"session":       options.Session,
+		"exchange":      options.Exchange,
+		"currency":      options.Currency,
+		"since":         options.Since,
+		"until":         options.Until,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var details []NavHistoryDetail
+	for rows.Next() {
+		var detail NavHistoryDetail
+		if err := rows.StructScan(&detail); err != nil {
+			return details, err
+		}
+
+		details = append(details, detail)
+	}
+
+	return details, rows.Err()
+}
+
+// Query returns the resampled net asset values
+func (s *NavService) Query(ctx context.Context, options QueryNavOptions) ([]NavPoint, error) {
+	details, err := s.QueryDetails(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return ResampleNav(details, options.Interval, options.GroupBy)
+}
+
+// ResampleNav aggregates the asset snapshots into the interval buckets.
+// The last snapshot of each session in a bucket is used, and the snapshot is carried forward to the following buckets
+// until the session records a new one, so the sessions recorded at different frequencies can be summed up,
+// and the series has no gaps between the first and the last record.
+func ResampleNav(details []NavHistoryDetail, interval types.Interval, groupBy NavGroupBy) ([]NavPoint, error) {
+	if interval == "" {
+		interval = types.Interval1d
+	}
+
+	duration := interval.Duration()
+	if duration == 0 {
+		return nil, fmt.Errorf("unsupported interval %s", interval)
+	}
+
+	switch groupBy {
+	case NavGroupByNone, NavGroupBySession, NavGroupByExchange, NavGroupByCurrency:
+	default:
+		return nil, fmt.Errorf("unsupported group by %q, valid values are: session, exchange, currency", groupBy)
+	}
+
+	// sessionSnapshot is the rows of the latest snapshot of a session
+	type sessionSnapshot struct {
+		time time.Time
+		rows []NavHistoryDetail
+	}
+
+	var points []NavPoint
+	var snapshots = make(map[string]*sessionSnapshot)
+	var sessionNames []string
+
+	flush := func(bucket time.Time) {
+		groups := make(map[string]*NavPoint)
+		var keys []string
+		for _, name := range sessionNames {
+			for _, row := range snapshots[name].rows {
+				key := ""
+				switch groupBy {
+				case NavGroupBySession:
+					key = row.Session
+				case NavGroupByExchange:
+					key = row.Exchange.String()
+				case NavGroupByCurrency:
+					key = row.Currency
+				}
+
+				point, ok := groups[key]
+				if !ok {
+					point = &NavPoint{Time: bucket.Add(duration), Group: key}
+					groups[key] = point
+					keys = append(keys, key)
+				}
+
+				point.InUSD = point.InUSD.Add(row.NetAssetInUSD)
+				point.InBTC = point.InBTC.Add(row.NetAssetInBTC)
+
+				// the snapshots recorded before the reporting currency was configured, or in another reporting currency, are not converted
+				if row.ReportingCurrency != "" {
+					if point.ReportingCurrency == "" {
+						point.ReportingCurrency = row.ReportingCurrency
+					}
+
+					if point.ReportingCurrency == row.ReportingCurrency {
+						point.InReportingCurrency = point.InReportingCurrency.Add(row.InReportingCurrency)
+					}
+				}
+				if groupBy == NavGroupByCurrency {
+					point.NetAsset = point.NetAsset.Add(row.NetAsset)
+				}
+			}
+		}
+
+		sort.Strings(keys)
+		for _, key := range keys {
+			points = append(points, *groups[key])
+		}
+	}
+
+	var bucket time.Time
+	for i, detail := range details {
+		t := detail.Time.Time()
+		b := t.Truncate(duration)
+		if i > 0 && !b.Equal(bucket) {
+			// the buckets without records are filled with the carried-forward snapshots
+			for ; bucket.Before(b); bucket = bucket.Add(duration) {
+				flush(bucket)
+			}
+		}
+
+		bucket = b
+
+		snapshot, ok := snapshots[detail.Session]
+		if !ok {
+			snapshot = &sessionSnapshot{}
+			snapshots[detail.Session] = snapshot
+			sessionNames = append(sessionNames, detail.Session)
+			sort.Strings(sessionNames)
+		}
+
+		// a new snapshot of the session replaces the previous one
+		if !t.Equal(snapshot.time) {
+			snapshot.time = t
+			snapshot.rows = nil
+		}
+
+		snapshot.rows = append(snapshot.rows, detail)
+	}
+
+	if len(details) > 0 {
+		flush(bucket)
+	}
+
+	return points, nil
+}
+
+// NavReturn is the change of the net asset value over a time range,
+// the values are in the reporting currency if the points have one, otherwise in USD
+type NavReturn struct {
+	Range      string           `json:"range"`
+	Currency   string           `json:"currency"`
+	StartTime  time.Time        `json:"startTime"`
+	EndTime    time.Time        `json:"endTime"`
+	StartValue fixedpoint.Value `json:"startValue"`
+	EndValue   fixedpoint.Value `json:"endValue"`
+	Change     fixedpoint.Value `json:"change"`
+	Return     fixedpoint.Value `json:"return"`
+}
+
+// NavReturnRange is a named look-back duration, a zero duration covers all the points
+type NavReturnRange struct {
+	Name     string
+	Duration time.Duration
+}
+
+var DefaultNavReturnRanges = []NavReturnRange{
+	{Name: "1d", Duration: 24 * time.Hour},
+	{Name: "7d", Duration: 7 * 24 * time.Hour},
+	{Name: "30d", Duration: 30 * 24 * time.Hour},
+	{Name: "90d", Duration: 90 * 24 * time.Hour},
+	{Name: "1y", Duration: 365 * 24 * time.Hour},
+	{Name: "all"},
+}
+
+// CalculateNavReturns calculates the returns of the ungrouped points over the ranges ending at the last point.
+// The start value of a range is the last point at or before the range start, or the first point if the history is shorter.
+func CalculateNavReturns(points []NavPoint, ranges []NavReturnRange) []NavReturn {
+	if len(points) == 0 {
+		return nil
+	}
+
+	last := points[len(points)-1]
+
+	currency := "USD"
+	value := func(p NavPoint) fixedpoint.Value { return p.InUSD }
+	if last.ReportingCurrency != "" {
+		currency = last.ReportingCurrency
+		value = func(p NavPoint) fixedpoint.Value {
+			if p.ReportingCurrency != currency {
+				return fixedpoint.Zero
+			}
+			return p.InReportingCurrency
+		}
+	}
+
+	var returns []NavReturn
+	for _, r := range ranges {
+		start := points[0]
+		if r.Duration > 0 {
+			from := last.Time.Add(-r.Duration)
+			for _, p := range points {
+				if p.Time.After(from) {
+					break
+				}
+
+				start = p
+			}
+		}
+
+		ret := NavReturn{
+			Range:      r.Name,
+			Currency:   currency,
+			StartTime:  start.Time,
+			EndTime:    last.Time,
+			StartValue: value(start),
+			EndValue:   value(last),
+		}
+
+		ret.Change = ret.EndValue.Sub(ret.StartValue)
+		if ret.StartValue.Sign() > 0 {
+			ret.Return = ret.Change.Div(ret.StartValue)
+		}
+
+		returns = append(returns, ret)
+	}
+
+	return returns
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestNavService_Query(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	accountService := &AccountService{DB: xdb}
+	navService := &NavService{DB: xdb}
+
+	asset := func(currency string, netAsset, inUSD float64) types.Asset {
+		return types.Asset{
+			Currency: currency,
+			Total:    fixedpoint.NewFromFloat(netAsset),
+			NetAsset: fixedpoint.NewFromFloat(netAsset),
+			InUSD:    fixedpoint.NewFromFloat(inUSD),
+		}
+	}
+
+	t0 := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	records := []struct {
+		time    time.Time
+		session string
+		assets  types.AssetMap
+	}{
+		{t0.Add(time.Hour), "binance", types.AssetMap{"BTC": asset("BTC", 1, 30000), "USDT": asset("USDT", 1000, 1000)}},
+		{t0.Add(2 * time.Hour), "max", types.AssetMap{"USDT": asset("USDT", 2000, 2000)}},
+		{t0.Add(2 * time.Hour), NavTotalSession, types.AssetMap{"USDT": asset("USDT", 33000, 33000)}},
+		// the binance session is not recorded on the second day, the last snapshot is carried forward
+		{t0.Add(25 * time.Hour), "max", types.AssetMap{"USDT": asset("USDT", 3000, 3000)}},
+		// the last snapshot of the day is used
+		{t0.Add(49 * time.Hour), "binance", types.AssetMap{"BTC": asset("BTC", 1, 32000), "USDT": asset("USDT", 1000, 1000)}},
+		{t0.Add(50 * time.Hour), "binance", types.AssetMap{"BTC": asset("BTC", 1, 33000)}},
+	}
+
+	for _, record := range records {
+		err := accountService.InsertAsset(record.time, record.session, types.ExchangeBinance, "", false, false, "", record.assets)
+		assert.NoError(t, err)
+	}
+
+	points, err := navService.Query(ctx, QueryNavOptions{Interval: types.Interval1d})
+	assert.NoError(t, err)
+	if assert.Len(t, points, 3) {
+		assert.Equal(t, t0.AddDate(0, 0, 1), points[0].Time.UTC())
+		assert.Equal(t, "33000", points[0].InUSD.String())
+		assert.Equal(t, "34000", points[1].InUSD.String())
+		assert.Equal(t, "36000", points[2].InUSD.String())
+	}
+
+	points, err = navService.Query(ctx, QueryNavOptions{Interval: types.Interval1d, GroupBy: NavGroupByCurrency, Currency: "BTC"})
+	assert.NoError(t, err)
+	if assert.Len(t, points, 3) {
+		assert.Equal(t, "BTC", points[2].Group)
+		assert.Equal(t, "1", points[2].NetAsset.String())
+		assert.Equal(t, "33000", points[2].InUSD.String())
+	}
+
+	points, err = navService.Query(ctx, QueryNavOptions{Interval: types.Interval1d, GroupBy: NavGroupBySession})
+	assert.NoError(t, err)
+	if assert.Len(t, points, 6) {
+		assert.Equal(t, "binance", points[0].Group)
+		assert.Equal(t, "31000", points[0].InUSD.String())
+		assert.Equal(t, "max", points[1].Group)
+		assert.Equal(t, "2000", points[1].InUSD.String())
+	}
+
+	points, err = navService.Query(ctx, QueryNavOptions{Session: NavTotalSession})
+	assert.NoError(t, err)
+	if assert.Len(t, points, 1) {
+		assert.Equal(t, "33000", points[0].InUSD.String())
+	}
+
+	_, err = navService.Query(ctx, QueryNavOptions{GroupBy: "strategy"})
+	assert.Error(t, err)
+}
+
+func TestNavService_QueryReportingCurrency(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	accountService := &AccountService{DB: xdb}
+	navService := &NavService{DB: xdb}
+
+	asset := func(currency string, netAsset, inUSD, inTWD float64) types.Asset {
+		return types.Asset{
+			Currency:            currency,
+			Total:               fixedpoint.NewFromFloat(netAsset),
+			NetAsset:            fixedpoint.NewFromFloat(netAsset),
+			InUSD:               fixedpoint.NewFromFloat(inUSD),
+			ReportingCurrency:   "TWD",
+			InReportingCurrency: fixedpoint.NewFromFloat(inTWD),
+		}
+	}
+
+	t0 := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	err = accountService.InsertAsset(t0.Add(time.Hour), "binance", types.ExchangeBinance, "", false, false, "", types.AssetMap{
+		"BTC":  asset("BTC", 1, 30000, 900000),
+		"USDT": asset("USDT", 1000, 1000, 30000),
+	})
+	assert.NoError(t, err)
+
+	err = accountService.InsertAsset(t0.Add(25*time.Hour), "binance", types.ExchangeBinance, "", false, false, "", types.AssetMap{
+		"BTC": asset("BTC", 1, 33000, 990000),
+	})
+	assert.NoError(t, err)
+
+	details, err := navService.QueryDetails(ctx, QueryNavOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, details, 3) {
+		assert.Equal(t, "TWD", details[0].ReportingCurrency)
+	}
+
+	points, err := navService.Query(ctx, QueryNavOptions{Interval: types.Interval1d})
+	assert.NoError(t, err)
+	if assert.Len(t, points, 2) {
+		assert.Equal(t, "TWD", points[0].ReportingCurrency)
+		assert.Equal(t, "930000", points[0].InReportingCurrency.String())
+		assert.Equal(t, "990000", points[1].InReportingCurrency.String())
+	}
+
+	returns := CalculateNavReturns(points, []NavReturnRange{{Name: "all"}})
+	if assert.Len(t, returns, 1) {
+		assert.Equal(t, "TWD", returns[0].Currency)
+		assert.Equal(t, "60000", returns[0].Change.String())
+	}
+}
+
+func TestCalculateNavReturns(t *testing.T) {
+	t0 := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	var points []NavPoint
+	for i, v := range []float64{1000, 1100, 1050, 1200} {
+		points = append(points, NavPoint{Time: t0.AddDate(0, 0, i), InUSD: fixedpoint.NewFromFloat(v)})
+	}
+
+	returns := CalculateNavReturns(points, []NavReturnRange{
+		{Name: "1d", Duration: 24 * time.Hour},
+		{Name: "2d", Duration: 48 * time.Hour},
+		{Name: "all"},
+	})
+
+	if assert.Len(t, returns, 3) {
+		assert.Equal(t, "USD", returns[0].Currency)
+		assert.Equal(t, "150", returns[0].Change.String())
+		assert.Equal(t, "1050", returns[0].StartValue.String())
+
+		assert.Equal(t, "100", returns[1].Change.String())
+		assert.Equal(t, t0.AddDate(0, 0, 1), returns[1].StartTime)
+
+		assert.Equal(t, "200", returns[2].Change.String())
+		assert.Equal(t, "0.2", returns[2].Return.String())
+	}
+
+	assert.Nil(t, CalculateNavReturns(nil, DefaultNavReturnRanges))
+}
@@ -156,21 +156,21 @@ func genOrderSQL(options QueryOrdersOptions) string {
 	if options.LastGID > 0 {
 		switch ordering {
 		case "ASC":
-			where = append(where, "gid > :gid")
+			where = append(where, "orders.gid > :gid")
 		case "DESC":
-			where = append(where, "gid < :gid")
+			where = append(where, "orders.gid < :gid")
 
 		}
 	}
 
 	if len(options.Exchange) > 0 {
-		where = append(where, "exchange = :exchange")
+		where = append(where, "orders.exchange = :exchange")
 	}
 	if len(options.Symbol) > 0 {
-		where = append(where, "symbol = :symbol")
+		where = append(where, "orders.symbol = :symbol")
 	}
 
-	sql := `SELECT orders.*, IFNULL(SUM(t.price * t.quantity)/SUM(t.quantity), orders.price) AS average_price FROM orders` +
+	sql := `SELECT orders.*, COALESCE(SUM(t.price * t.quantity)/SUM(t.quantity), orders.price) AS average_price FROM orders` +
 		` LEFT JOIN trades AS t ON (t.order_id = orders.order_id)`
 	if len(where) > 0 {
 		sql += ` WHERE ` + strings.Join(where, " AND ")
@@ -210,12 +210,20 @@ func (s *OrderService) scanRows(rows *sqlx.Rows) (orders []types.Order, err erro
 }
 
 func (s *OrderService) Insert(order types.Order) (err error) {
-	if s.DB.DriverName() == "mysql" {
+	switch s.DB.DriverName() {
+	case DriverMySQL:
 		_, err = s.DB.NamedExec(`
 			INSERT INTO orders (exchange, order_id, client_order_id, order_type, status, symbol, price, stop_price, quantity, executed_quantity, side, is_working, time_in_force, created_at, updated_at, is_margin, is_futures, is_isolated)
 			VALUES (:exchange, :order_id, :client_order_id, :order_type, :status, :symbol, :price, :stop_price, :quantity, :executed_quantity, :side, :is_working, :time_in_force, :created_at, :updated_at, :is_margin, :is_futures, :is_isolated)
 			ON DUPLICATE KEY UPDATE status=:status, executed_quantity=:executed_quantity, is_working=:is_working, updated_at=:updated_at`, order)
 		return err
+
+	case DriverPostgres:
+		_, err = s.DB.NamedExec(`
+			INSERT INTO orders (exchange, order_id, client_order_id, order_type, status, symbol, price, stop_price, quantity, executed_quantity, side, is_working, time_in_force, created_at, updated_at, is_margin, is_futures, is_isolated)
+			VALUES (:exchange, :order_id, :client_order_id, :order_type, :status, :symbol, :price, :stop_price, :quantity, :executed_quantity, :side, :is_working, :time_in_force, :created_at, :updated_at, :is_margin, :is_futures, :is_isolated)
+			ON CONFLICT (order_id, exchange) DO UPDATE SET status=:status, executed_quantity=:executed_quantity, is_working=:is_working, updated_at=:updated_at`, order)
+		return err
 	}
 
 	_, err = s.DB.NamedExec(`
@@ -52,6 +52,9 @@ func (s *PositionService) scanRows(rows *sqlx.Rows) (positions []types.Position,
 }
 
 func (s *PositionService) Insert(position *types.Position, trade types.Trade, profit fixedpoint.Value) error {
+	// the fee that can not be converted is recorded as zero
+	feeInQuote, _ := position.FeeInQuote(trade)
+
 	_, err := s.DB.NamedExec(`
 		INSERT INTO positions (
 			strategy,
@@ -66,6 +69,10 @@ func (s *PositionService) Insert(position *types.Position, trade types.Trade, pr
 			trade_id,
 		    exchange,
 		    side,
+			price,
+			quantity,
+			quote_quantity,
+			fee_in_quote,
 			traded_at
 		) VALUES (
 			:strategy,
@@ -80,6 +87,10 @@ func (s *PositionService) Insert(position *types.Position, trade types.Trade, pr
 			:trade_id,
 		    :exchange,
 		    :side,
+			:price,
+			:quantity,
+			:quote_quantity,
+			:fee_in_quote,
 			:traded_at
 	    )`,
 		map[string]interface{}{
@@ -95,6 +106,10 @@ func (s *PositionService) Insert(position *types.Position, trade types.Trade, pr
 			"trade_id":             trade.ID,
 			"exchange":             trade.Exchange,
 			"side":                 trade.Side,
+			"price":                trade.Price,
+			"quantity":             trade.Quantity,
+			"quote_quantity":       trade.QuoteQuantity,
+			"fee_in_quote":         feeInQuote,
 			"traded_at":            trade.Time,
 		})
 	return err
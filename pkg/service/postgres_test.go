@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_dialectSQL(t *testing.T) {
+	sql := "SELECT * FROM `klines` WHERE `interval` = :interval"
+	assert.Equal(t, sql, dialectSQL(sqlx.NewDb(nil, DriverMySQL), sql))
+	assert.Equal(t, sql, dialectSQL(sqlx.NewDb(nil, DriverSQLite3), sql))
+	assert.Equal(t, `SELECT * FROM "klines" WHERE "interval" = :interval`, dialectSQL(sqlx.NewDb(nil, DriverPostgres), sql))
+}
+
+func TestPostgres_Services(t *testing.T) {
+	db, err := preparePostgresDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+	xdb := sqlx.NewDb(db.DB, DriverPostgres)
+	t0 := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("trades", func(t *testing.T) {
+		service := &TradeService{DB: xdb}
+		for i := uint64(1); i <= 3; i++ {
+			err := service.Insert(types.Trade{
+				ID:            i,
+				OrderID:       i,
+				Exchange:      types.ExchangeBinance,
+				Symbol:        "BTCUSDT",
+				Side:          types.SideTypeBuy,
+				Price:         fixedpoint.NewFromInt(30000),
+				Quantity:      fixedpoint.NewFromFloat(0.1),
+				QuoteQuantity: fixedpoint.NewFromInt(3000),
+				Fee:           fixedpoint.NewFromFloat(0.0001),
+				FeeCurrency:   "BTC",
+				Time:          types.Time(t0.Add(time.Duration(i) * 24 * time.Hour)),
+			})
+			assert.NoError(t, err)
+		}
+
+		err := service.Mark(ctx, 1, "grid")
+		assert.NoError(t, err)
+
+		trades, err := service.QueryLast(types.ExchangeBinance, "BTCUSDT", false, false, false, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, trades, 3) {
+			assert.Equal(t, uint64(3), trades[0].ID)
+			assert.True(t, t0.Add(72*time.Hour).Equal(trades[0].Time.Time()))
+		}
+
+		volumes, err := service.QueryTradingVolume(t0, TradingVolumeQueryOptions{GroupByPeriod: "day", SegmentBy: "symbol"})
+		assert.NoError(t, err)
+		if assert.Len(t, volumes, 3) {
+			assert.Equal(t, t0.AddDate(0, 0, 1), volumes[0].Time)
+			assert.Equal(t, 3000.0, volumes[0].QuoteVolume)
+		}
+	})
+
+	t.Run("orders", func(t *testing.T) {
+		service := &OrderService{DB: xdb}
+		order := types.Order{
+			SubmitOrder: types.SubmitOrder{
+				Symbol:      "BTCUSDT",
+				Side:        types.SideTypeBuy,
+				Type:        types.OrderTypeLimit,
+				Quantity:    fixedpoint.NewFromFloat(0.1),
+				Price:       fixedpoint.NewFromInt(30000),
+				TimeInForce: types.TimeInForceGTC,
+			},
+			Exchange:     types.ExchangeBinance,
+			OrderID:      1,
+			Status:       types.OrderStatusNew,
+			IsWorking:    true,
+			CreationTime: types.Time(t0),
+			UpdateTime:   types.Time(t0),
+		}
+		assert.NoError(t, service.Insert(order))
+
+		// the order is updated on conflict
+		order.Status = types.OrderStatusFilled
+		order.ExecutedQuantity = order.Quantity
+		order.IsWorking = false
+		assert.NoError(t, service.Insert(order))
+
+		orders, err := service.Query(QueryOrdersOptions{Exchange: types.ExchangeBinance, Symbol: "BTCUSDT"})
+		assert.NoError(t, err)
+		if assert.Len(t, orders, 1) {
+			assert.Equal(t, types.OrderStatusFilled, orders[0].Status)
+			if assert.NotNil(t, orders[0].AveragePrice) {
+				assert.Equal(t, 30000.0, *orders[0].AveragePrice)
+			}
+		}
+	})
+
+	t.Run("rewards", func(t *testing.T) {
+		service := &RewardService{DB: xdb}
+		for _, reward := range []types.Reward{
+			{UUID: "r1", Exchange: types.ExchangeMax, Type: types.RewardCommission, Currency: "MAX", Quantity: fixedpoint.One, State: "done", CreatedAt: types.Time(t0)},
+			{UUID: "r2", Exchange: types.ExchangeMax, Type: types.RewardAirdrop, Currency: "MAX", Quantity: fixedpoint.One, State: "done", CreatedAt: types.Time(t0)},
+		} {
+			assert.NoError(t, service.Insert(reward))
+		}
+
+		rewards, err := service.QueryUnspent(ctx, types.ExchangeMax)
+		assert.NoError(t, err)
+		assert.Len(t, rewards, 1)
+
+		rewards, err = service.QueryUnspentSince(ctx, types.ExchangeMax, t0, types.RewardAirdrop)
+		assert.NoError(t, err)
+		assert.Len(t, rewards, 1)
+
+		assert.NoError(t, service.MarkCurrencyAsSpent(ctx, "MAX"))
+
+		rewards, err = service.QueryUnspent(ctx, types.ExchangeMax, types.RewardCommission, types.RewardAirdrop)
+		assert.NoError(t, err)
+		assert.Empty(t, rewards)
+	})
+
+	t.Run("nav", func(t *testing.T) {
+		accountService := &AccountService{DB: xdb}
+		navService := &NavService{DB: xdb}
+
+		err := accountService.InsertAsset(t0, "binance", types.ExchangeBinance, "", false, false, "", types.AssetMap{
+			"BTC": {Currency: "BTC", Total: fixedpoint.One, NetAsset: fixedpoint.One, InUSD: fixedpoint.NewFromInt(30000)},
+		})
+		assert.NoError(t, err)
+
+		details, err := navService.QueryDetails(ctx, QueryNavOptions{})
+		assert.NoError(t, err)
+		if assert.Len(t, details, 1) {
+			assert.Equal(t, "30000", details[0].NetAssetInUSD.String())
+		}
+	})
+
+	t.Run("klines", func(t *testing.T) {
+		service := &BacktestService{DB: xdb}
+
+		var klines []types.KLine
+		for i := 0; i < 5; i++ {
+			// the third kline is missing
+			if i == 2 {
+				continue
+			}
+
+			startTime := t0.Add(time.Duration(i) * time.Minute)
+			klines = append(klines, types.KLine{
+				Exchange:  types.ExchangeBinance,
+				Symbol:    "BTCUSDT",
+				Interval:  types.Interval1m,
+				StartTime: types.Time(startTime),
+				EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+				Open:      fixedpoint.NewFromInt(30000),
+				High:      fixedpoint.NewFromInt(30000),
+				Low:       fixedpoint.NewFromInt(30000),
+				Close:     fixedpoint.NewFromInt(30000),
+				Closed:    true,
+			})
+		}
+
+		assert.NoError(t, service.BatchInsert(klines))
+
+		kline, err := service.QueryLastKLine(types.ExchangeBinance, "BTCUSDT", types.Interval1m)
+		assert.NoError(t, err)
+		if assert.NotNil(t, kline) {
+			assert.True(t, t0.Add(4*time.Minute).Equal(kline.StartTime.Time()))
+		}
+
+		report, err := service.CheckKLines(ctx, types.ExchangeBinance, "BTCUSDT", types.Interval1m, t0, t0.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, 4, report.NumKLines)
+		assert.Len(t, report.Gaps, 1)
+
+		assert.NoError(t, service.MarkKLineGap(ctx, types.ExchangeBinance, "BTCUSDT", types.Interval1m, report.Gaps[0]))
+
+		report, err = service.CheckKLines(ctx, types.ExchangeBinance, "BTCUSDT", types.Interval1m, t0, t0.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.True(t, report.OK())
+		assert.Len(t, report.MarkedGaps, 1)
+	})
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// KLinePriceService looks up the historical prices from the stored klines,
+// the close price of the last kline that ends before the given time is used.
+type KLinePriceService struct {
+	Backtest *BacktestService
+	Exchange types.ExchangeName
+
+	// Interval is the kline interval used for the lookups, defaults to 1d
+	Interval types.Interval
+
+	cache map[string]fixedpoint.Value
+}
+
+func (s *KLinePriceService) closePrice(symbol string, t time.Time) (fixedpoint.Value, bool, error) {
+	interval := s.Interval
+	if interval == "" {
+		interval = types.Interval1d
+	}
+
+	key := fmt.Sprintf("%s:%d", symbol, t.Truncate(interval.Duration()).Unix())
+	if price, ok := s.cache[key]; ok {
+		return price, true, nil
+	}
+
+	klines, err := s.Backtest.QueryKLinesBackward(s.Exchange, symbol, interval, t, 1)
+	if err != nil {
+		return fixedpoint.Zero, false, err
+	}
+
+	if len(klines) == 0 {
+		return fixedpoint.Zero, false, nil
+	}
+
+	if s.cache == nil {
+		s.cache = make(map[string]fixedpoint.Value)
+	}
+
+	price := klines[len(klines)-1].Close
+	s.cache[key] = price
+	return price, true, nil
+}
+
+// QueryPrice returns the price of the currency in the quote currency at the given time,
+// the inverse market (quote currency + currency) is used if the direct market is not found.
+func (s *KLinePriceService) QueryPrice(currency, quoteCurrency string, t time.Time) (fixedpoint.Value, error) {
+	if currency == quoteCurrency {
+		return fixedpoint.One, nil
+	}
+
+	price, ok, err := s.closePrice(currency+quoteCurrency, t)
+	if err != nil {
+		return fixedpoint.Zero, err
+	} else if ok {
+		return price, nil
+	}
+
+	price, ok, err = s.closePrice(quoteCurrency+currency, t)
+	if err != nil {
+		return fixedpoint.Zero, err
+	} else if ok && price.Sign() > 0 {
+		return fixedpoint.One.Div(price), nil
+	}
+
+	return fixedpoint.Zero, fmt.Errorf("no %s kline data of %s%s or %s%s before %s", s.Exchange, currency, quoteCurrency, quoteCurrency, currency, t)
+}
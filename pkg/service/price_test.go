@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestKLinePriceService_QueryPrice(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	backtestService := &BacktestService{DB: xdb}
+
+	startTime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, price := range []float64{20000, 25000} {
+		start := startTime.AddDate(0, 0, i)
+		err = backtestService.Insert(types.KLine{
+			Exchange:  types.ExchangeBinance,
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1d,
+			StartTime: types.Time(start),
+			EndTime:   types.Time(start.Add(types.Interval1d.Duration() - time.Millisecond)),
+			Close:     fixedpoint.NewFromFloat(price),
+			Closed:    true,
+		})
+		assert.NoError(t, err)
+	}
+
+	priceService := &KLinePriceService{
+		Backtest: backtestService,
+		Exchange: types.ExchangeBinance,
+	}
+
+	price, err := priceService.QueryPrice("BTC", "USDT", startTime.AddDate(0, 0, 2).Add(time.Hour))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "25000", price.String())
+	}
+
+	price, err = priceService.QueryPrice("BTC", "USDT", startTime.AddDate(0, 0, 1).Add(time.Hour))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "20000", price.String())
+	}
+
+	price, err = priceService.QueryPrice("USDT", "BTC", startTime.AddDate(0, 0, 1).Add(time.Hour))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0.00005", price.String())
+	}
+
+	price, err = priceService.QueryPrice("USDT", "USDT", startTime)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1", price.String())
+	}
+
+	_, err = priceService.QueryPrice("ETH", "USDT", startTime.AddDate(0, 0, 2))
+	assert.Error(t, err)
+}
@@ -73,6 +73,7 @@ func (s *ProfitService) Insert(profit types.Profit) error {
 			fee,
 			fee_currency,
 			fee_in_usd,
+			fee_in_quote,
 			traded_at,
 			exchange,
 			is_margin,
@@ -99,6 +100,7 @@ func (s *ProfitService) Insert(profit types.Profit) error {
 			:fee,
 			:fee_currency,
 			:fee_in_usd,
+			:fee_in_quote,
 			:traded_at,
 			:exchange,
 			:is_margin,
@@ -34,6 +34,9 @@ func TestProfitService(t *testing.T) {
 		Price:         fixedpoint.NewFromFloat(44300),
 		Quantity:      fixedpoint.NewFromFloat(0.001),
 		QuoteQuantity: fixedpoint.NewFromFloat(44.0),
+		Fee:           fixedpoint.NewFromFloat(0.1),
+		FeeCurrency:   "MAX",
+		FeeInQuote:    fixedpoint.NewFromFloat(0.0365),
 		Exchange:      types.ExchangeMax,
 		TradedAt:      time.Now(),
 	})
@@ -25,7 +25,7 @@ type RewardService struct {
 
 func (s *RewardService) QueryLast(ex types.ExchangeName, limit int) ([]types.Reward, error) {
 	sql := "SELECT * FROM `rewards` WHERE `exchange` = :exchange ORDER BY `created_at` DESC LIMIT :limit"
-	rows, err := s.DB.NamedQuery(sql, map[string]interface{}{
+	rows, err := s.DB.NamedQuery(dialectSQL(s.DB, sql), map[string]interface{}{
 		"exchange": ex,
 		"limit":    limit,
 	})
@@ -37,6 +37,20 @@ func (s *RewardService) QueryLast(ex types.ExchangeName, limit int) ([]types.Rew
 	return s.scanRows(rows)
 }
 
+// Query returns all the rewards of the exchange, including the spent rewards and the airdrops
+func (s *RewardService) Query(ctx context.Context, ex types.ExchangeName) ([]types.Reward, error) {
+	sql := "SELECT * FROM `rewards` WHERE `exchange` = :exchange ORDER BY `created_at` ASC"
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), map[string]interface{}{
+		"exchange": ex,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	return s.scanRows(rows)
+}
+
 func (s *RewardService) Sync(ctx context.Context, exchange types.Exchange) error {
 	service, ok := exchange.(types.ExchangeRewardService)
 	if !ok {
@@ -113,24 +127,16 @@ func (s *RewardService) AggregateUnspentCurrencyPosition(ctx context.Context, ex
 }
 
 func (s *RewardService) QueryUnspentSince(ctx context.Context, ex types.ExchangeName, since time.Time, rewardTypes ...types.RewardType) ([]types.Reward, error) {
-	sql := "SELECT * FROM rewards WHERE created_at >= :since AND exchange = :exchange AND spent IS FALSE "
-
-	if len(rewardTypes) == 0 {
-		sql += " AND `reward_type` NOT IN ('airdrop') "
-	} else {
-		var args []string
-		for _, n := range rewardTypes {
-			args = append(args, strconv.Quote(string(n)))
-		}
-		sql += " AND `reward_type` IN (" + strings.Join(args, ", ") + ") "
+	args := map[string]interface{}{
+		"exchange": ex,
+		"since":    since,
 	}
 
+	sql := "SELECT * FROM rewards WHERE created_at >= :since AND exchange = :exchange AND spent IS FALSE "
+	sql += rewardTypeCondition(rewardTypes, args)
 	sql += " ORDER BY created_at ASC"
 
-	rows, err := s.DB.NamedQueryContext(ctx, sql, map[string]interface{}{
-		"exchange":   ex,
-		"since": since,
-	})
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), args)
 
 	if err != nil {
 		return nil, err
@@ -141,21 +147,14 @@ func (s *RewardService) QueryUnspentSince(ctx context.Context, ex types.Exchange
 }
 
 func (s *RewardService) QueryUnspent(ctx context.Context, ex types.ExchangeName, rewardTypes ...types.RewardType) ([]types.Reward, error) {
-	sql := "SELECT * FROM rewards WHERE exchange = :exchange AND spent IS FALSE "
-	if len(rewardTypes) == 0 {
-		sql += " AND `reward_type` NOT IN ('airdrop') "
-	} else {
-		var args []string
-		for _, n := range rewardTypes {
-			args = append(args, strconv.Quote(string(n)))
-		}
-		sql += " AND `reward_type` IN (" + strings.Join(args, ", ") + ") "
+	args := map[string]interface{}{
+		"exchange": ex,
 	}
 
+	sql := "SELECT * FROM rewards WHERE exchange = :exchange AND spent IS FALSE "
+	sql += rewardTypeCondition(rewardTypes, args)
 	sql += " ORDER BY created_at ASC"
-	rows, err := s.DB.NamedQueryContext(ctx, sql, map[string]interface{}{
-		"exchange": ex,
-	})
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), args)
 	if err != nil {
 		return nil, err
 	}
@@ -164,8 +163,25 @@ func (s *RewardService) QueryUnspent(ctx context.Context, ex types.ExchangeName,
 	return s.scanRows(rows)
 }
 
+// rewardTypeCondition returns the reward type condition, the airdrops are excluded if no reward type is given.
+// the reward types are bound as the named arguments since the quoted strings are identifiers in postgres.
+func rewardTypeCondition(rewardTypes []types.RewardType, args map[string]interface{}) string {
+	if len(rewardTypes) == 0 {
+		return " AND `reward_type` NOT IN ('airdrop') "
+	}
+
+	var names []string
+	for i, n := range rewardTypes {
+		name := "reward_type" + strconv.Itoa(i)
+		args[name] = string(n)
+		names = append(names, ":"+name)
+	}
+
+	return " AND `reward_type` IN (" + strings.Join(names, ", ") + ") "
+}
+
 func (s *RewardService) MarkCurrencyAsSpent(ctx context.Context, currency string) error {
-	result, err := s.DB.NamedExecContext(ctx, "UPDATE `rewards` SET `spent` = TRUE WHERE `currency` = :currency AND `spent` IS FALSE", map[string]interface{}{
+	result, err := s.DB.NamedExecContext(ctx, dialectSQL(s.DB, "UPDATE `rewards` SET `spent` = TRUE WHERE `currency` = :currency AND `spent` IS FALSE"), map[string]interface{}{
 		"currency": currency,
 	})
 
@@ -178,7 +194,7 @@ func (s *RewardService) MarkCurrencyAsSpent(ctx context.Context, currency string
 }
 
 func (s *RewardService) MarkAsSpent(ctx context.Context, uuid string) error {
-	result, err := s.DB.NamedExecContext(ctx, "UPDATE `rewards` SET `spent` = TRUE WHERE `uuid` = :uuid", map[string]interface{}{
+	result, err := s.DB.NamedExecContext(ctx, dialectSQL(s.DB, "UPDATE `rewards` SET `spent` = TRUE WHERE `uuid` = :uuid"), map[string]interface{}{
 		"uuid": uuid,
 	})
 	if err != nil {
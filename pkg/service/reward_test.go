@@ -72,6 +72,10 @@ func TestRewardService_InsertAndQueryUnspent(t *testing.T) {
 	assert.NotEmpty(t, rewards)
 	assert.Len(t, rewards, 1, "should select 1 reward")
 	assert.Equal(t, types.RewardCommission, rewards[0].Type)
+
+	rewards, err = service.Query(ctx, types.ExchangeMax)
+	assert.NoError(t, err)
+	assert.Len(t, rewards, 2, "all rewards should be included")
 }
 
 
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type QueryStrategyProfitOptions struct {
+	// filters, optional
+	Exchange           types.ExchangeName
+	Symbol             string
+	Strategy           string
+	StrategyInstanceID string
+
+	Since, Until time.Time
+}
+
+// StrategyProfitSummary is the profit breakdown of a strategy instance on a symbol,
+// aggregated from the position and the profit records of the strategy instance.
+type StrategyProfitSummary struct {
+	Strategy           string             `json:"strategy" db:"strategy"`
+	StrategyInstanceID string             `json:"strategyInstanceID" db:"strategy_instance_id"`
+	Exchange           types.ExchangeName `json:"exchange" db:"exchange"`
+	Symbol             string             `json:"symbol" db:"symbol"`
+	BaseCurrency       string             `json:"baseCurrency" db:"base_currency"`
+	QuoteCurrency      string             `json:"quoteCurrency" db:"quote_currency"`
+
+	NumTrades   int              `json:"numTrades" db:"num_trades"`
+	Volume      fixedpoint.Value `json:"volume" db:"volume"`
+	QuoteVolume fixedpoint.Value `json:"quoteVolume" db:"quote_volume"`
+	FeeInQuote  fixedpoint.Value `json:"feeInQuote" db:"fee_in_quote"`
+
+	// Profit and NetProfit are the realized profit
+	Profit    fixedpoint.Value `json:"profit"`
+	NetProfit fixedpoint.Value `json:"netProfit"`
+
+	// Base and AverageCost are from the last position record
+	Base         fixedpoint.Value `json:"base"`
+	AverageCost  fixedpoint.Value `json:"averageCost"`
+	LastTradedAt types.Time       `json:"lastTradedAt"`
+
+	// UnrealizedProfit is only set after UpdateUnrealizedProfit is called with the current price
+	UnrealizedProfit fixedpoint.Value `json:"unrealizedProfit"`
+}
+
+// UpdateUnrealizedProfit updates the unrealized profit of the remaining position by the current price
+func (s *StrategyProfitSummary) UpdateUnrealizedProfit(price fixedpoint.Value) {
+	if s.Base.IsZero() || price.Sign() <= 0 {
+		s.UnrealizedProfit = fixedpoint.Zero
+		return
+	}
+
+	s.UnrealizedProfit = price.Sub(s.AverageCost).Mul(s.Base)
+}
+
+func (options QueryStrategyProfitOptions) where() (string, map[string]interface{}) {
+	var where []string
+	if len(options.Exchange) > 0 {
+		where = append(where, "`exchange` = :exchange")
+	}
+
+	if len(options.Symbol) > 0 {
+		where = append(where, "`symbol` = :symbol")
+	}
+
+	if len(options.Strategy) > 0 {
+		where = append(where, "`strategy` = :strategy")
+	}
+
+	if len(options.StrategyInstanceID) > 0 {
+		where = append(where, "`strategy_instance_id` = :strategy_instance_id")
+	}
+
+	if !options.Since.IsZero() {
+		where = append(where, "`traded_at` >= :since")
+	}
+
+	if !options.Until.IsZero() {
+		where = append(where, "`traded_at` < :until")
+	}
+
+	if len(where) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(where, " AND "), map[string]interface{}{
+		"exchange":             options.Exchange,
+		"symbol":               options.Symbol,
+		"strategy":             options.Strategy,
+		"strategy_instance_id": options.StrategyInstanceID,
+		"since":                options.Since,
+		"until":                options.Until,
+	}
+}
+
+// QueryStrategyProfits aggregates the volume, the fees and the realized profit of each strategy instance and symbol,
+// the trading volume and the fees come from the position records, so only the trades recorded by the strategies are counted.
+func (s *ProfitService) QueryStrategyProfits(ctx context.Context, options QueryStrategyProfitOptions) ([]StrategyProfitSummary, error) {
+	where, args := options.where()
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+
+	sql := "SELECT `strategy`, `strategy_instance_id`, `exchange`, `symbol`, `base_currency`, `quote_currency`," +
+		" COUNT(*) AS `num_trades`," +
+		" COALESCE(SUM(`quantity`), 0) AS `volume`," +
+		" COALESCE(SUM(`quote_quantity`), 0) AS `quote_volume`," +
+		" COALESCE(SUM(`fee_in_quote`), 0) AS `fee_in_quote`" +
+		" FROM `positions`" + where +
+		" GROUP BY `strategy`, `strategy_instance_id`, `exchange`, `symbol`, `base_currency`, `quote_currency`" +
+		" ORDER BY `strategy_instance_id` ASC, `symbol` ASC"
+
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), args)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var summaries []StrategyProfitSummary
+	for rows.Next() {
+		var summary StrategyProfitSummary
+		if err := rows.StructScan(&summary); err != nil {
+			return nil, err
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range summaries {
+		if err := s.loadRealizedProfit(ctx, &summaries[i], options); err != nil {
+			return nil, err
+		}
+
+		if err := s.loadLastPosition(ctx, &summaries[i], options); err != nil {
+			return nil, err
+		}
+	}
+
+	return summaries, nil
+}
+
+// summaryOptions narrows down the query options to the strategy instance and the symbol of the summary
+func summaryOptions(summary *StrategyProfitSummary, options QueryStrategyProfitOptions) QueryStrategyProfitOptions {
+	options.Exchange = summary.Exchange
+	options.Symbol = summary.Symbol
+	options.Strategy = summary.Strategy
+	options.StrategyInstanceID = summary.StrategyInstanceID
+	return options
+}
+
+func (s *ProfitService) loadRealizedProfit(ctx context.Context, summary *StrategyProfitSummary, options QueryStrategyProfitOptions) error {
+	where, args := summaryOptions(summary, options).where()
+	sql := "SELECT COALESCE(SUM(`profit`), 0) AS `profit`, COALESCE(SUM(`net_profit`), 0) AS `net_profit` FROM `profits`" + where
+
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), args)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&summary.Profit, &summary.NetProfit); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *ProfitService) loadLastPosition(ctx context.Context, summary *StrategyProfitSummary, options QueryStrategyProfitOptions) error {
+	where, args := summaryOptions(summary, options).where()
+	sql := "SELECT `base`, `average_cost`, `traded_at` FROM `positions`" + where + " ORDER BY `traded_at` DESC, `gid` DESC LIMIT 1"
+
+	rows, err := s.DB.NamedQueryContext(ctx, dialectSQL(s.DB, sql), args)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&summary.Base, &summary.AverageCost, &summary.LastTradedAt); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestProfitService_QueryStrategyProfits(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	ctx := context.Background()
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	positionService := &PositionService{DB: xdb}
+	profitService := &ProfitService{DB: xdb}
+
+	t0 := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	tradeID := uint64(0)
+
+	record := func(instanceID string, side types.SideType, price, quantity float64, hours int) {
+		tradeID++
+		position := &types.Position{
+			Symbol:             "BTCUSDT",
+			BaseCurrency:       "BTC",
+			QuoteCurrency:      "USDT",
+			Strategy:           "bollmaker",
+			StrategyInstanceID: instanceID,
+		}
+
+		trade := types.Trade{
+			ID:            tradeID,
+			Exchange:      types.ExchangeBinance,
+			Symbol:        "BTCUSDT",
+			Side:          side,
+			Price:         fixedpoint.NewFromFloat(price),
+			Quantity:      fixedpoint.NewFromFloat(quantity),
+			QuoteQuantity: fixedpoint.NewFromFloat(price * quantity),
+			Fee:           fixedpoint.NewFromFloat(price * quantity * 0.001),
+			FeeCurrency:   "USDT",
+			Time:          types.Time(t0.Add(time.Duration(hours) * time.Hour)),
+		}
+
+		// replay the position of the strategy instance
+		positions, err := profitService.QueryStrategyProfits(ctx, QueryStrategyProfitOptions{StrategyInstanceID: instanceID})
+		assert.NoError(t, err)
+		if len(positions) > 0 {
+			position.Base = positions[0].Base
+			position.AverageCost = positions[0].AverageCost
+		}
+
+		profit, netProfit, madeProfit := position.AddTrade(trade)
+		assert.NoError(t, positionService.Insert(position, trade, profit))
+		if madeProfit {
+			p := position.NewProfit(trade, profit, netProfit)
+			p.Strategy = position.Strategy
+			p.StrategyInstanceID = position.StrategyInstanceID
+			assert.NoError(t, profitService.Insert(p))
+		}
+	}
+
+	record("bollmaker:BTCUSDT:1m", types.SideTypeBuy, 30000, 1, 0)
+	record("bollmaker:BTCUSDT:1m", types.SideTypeSell, 31000, 0.5, 1)
+	record("bollmaker:BTCUSDT:5m", types.SideTypeBuy, 30000, 0.1, 2)
+	record("bollmaker:BTCUSDT:5m", types.SideTypeSell, 29000, 0.1, 3)
+
+	summaries, err := profitService.QueryStrategyProfits(ctx, QueryStrategyProfitOptions{
+		Exchange: types.ExchangeBinance,
+		Symbol:   "BTCUSDT",
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, summaries, 2) {
+		s1 := summaries[0]
+		assert.Equal(t, "bollmaker:BTCUSDT:1m", s1.StrategyInstanceID)
+		assert.Equal(t, 2, s1.NumTrades)
+		assert.Equal(t, "1.5", s1.Volume.String())
+		assert.Equal(t, "45500", s1.QuoteVolume.String())
+		assert.Equal(t, "45.5", s1.FeeInQuote.String())
+		// the quote fee is deducted from the quote quantity: (31000 - 29970) * 0.5
+		assert.Equal(t, "515", s1.Profit.String())
+		assert.Equal(t, "0.5", s1.Base.String())
+		assert.Equal(t, "29970", s1.AverageCost.String())
+
+		s1.UpdateUnrealizedProfit(fixedpoint.NewFromInt(32000))
+		assert.Equal(t, "1015", s1.UnrealizedProfit.String())
+
+		s2 := summaries[1]
+		assert.Equal(t, "bollmaker:BTCUSDT:5m", s2.StrategyInstanceID)
+		assert.Equal(t, "-97", s2.Profit.String())
+		assert.Equal(t, "0", s2.Base.String())
+	}
+
+	// the realized profit is filtered by the time range as well
+	summaries, err = profitService.QueryStrategyProfits(ctx, QueryStrategyProfitOptions{
+		StrategyInstanceID: "bollmaker:BTCUSDT:1m",
+		Until:              t0.Add(30 * time.Minute),
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, summaries, 1) {
+		assert.Equal(t, 1, summaries[0].NumTrades)
+		assert.Equal(t, "0", summaries[0].Profit.String())
+		assert.Equal(t, "1", summaries[0].Base.String())
+	}
+}
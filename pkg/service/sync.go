@@ -22,6 +22,9 @@ type SyncService struct {
 	RewardService   *RewardService
 	WithdrawService *WithdrawService
 	DepositService  *DepositService
+
+	FundingFeeService     *FundingFeeService
+	MarginInterestService *MarginInterestService
 }
 
 func paperTrade() bool {
@@ -92,3 +95,27 @@ func (s *SyncService) SyncWithdrawHistory(ctx context.Context, exchange types.Ex
 
 	return nil
 }
+
+func (s *SyncService) SyncFundingFeeHistory(ctx context.Context, exchange types.Exchange) error {
+	log.Infof("syncing %s funding fee records...", exchange.Name())
+	if err := s.FundingFeeService.Sync(ctx, exchange); err != nil {
+		if err != ErrNotImplemented {
+			log.Warnf("%s funding fee service is not supported", exchange.Name())
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SyncService) SyncMarginInterestHistory(ctx context.Context, exchange types.Exchange) error {
+	log.Infof("syncing %s margin interest records...", exchange.Name())
+	if err := s.MarginInterestService.Sync(ctx, exchange); err != nil {
+		if err != ErrNotImplemented {
+			log.Warnf("%s margin interest service is not supported", exchange.Name())
+			return err
+		}
+	}
+
+	return nil
+}
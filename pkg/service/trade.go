@@ -152,9 +152,12 @@ func (s *TradeService) QueryTradingVolume(startTime time.Time, options TradingVo
 
 	sql := ""
 	driverName := s.DB.DriverName()
-	if driverName == "mysql" {
+	switch driverName {
+	case DriverMySQL:
 		sql = generateMysqlTradingVolumeQuerySQL(options)
-	} else {
+	case DriverPostgres:
+		sql = generatePostgresTradingVolumeSQL(options)
+	default:
 		sql = generateSqliteTradingVolumeSQL(options)
 	}
 
@@ -284,6 +287,61 @@ func generateMysqlTradingVolumeQuerySQL(options TradingVolumeQueryOptions) strin
 	return sql
 }
 
+func generatePostgresTimeRangeClauses(timeRangeColumn, period string) (selectors []string, groupBys []string, orderBys []string) {
+	// the time columns are stored with time zone, the time ranges are grouped in UTC like mysql
+	column := timeRangeColumn + " AT TIME ZONE 'UTC'"
+	year := "CAST(EXTRACT(YEAR FROM " + column + ") AS INTEGER) AS year"
+	month := "CAST(EXTRACT(MONTH FROM " + column + ") AS INTEGER) AS month"
+	day := "CAST(EXTRACT(DAY FROM " + column + ") AS INTEGER) AS day"
+
+	switch period {
+	case "month":
+		selectors = append(selectors, year, month)
+		groupBys = append([]string{"month", "year"}, groupBys...)
+		orderBys = append(orderBys, "year ASC", "month ASC")
+
+	case "year":
+		selectors = append(selectors, year)
+		groupBys = append([]string{"year"}, groupBys...)
+		orderBys = append(orderBys, "year ASC")
+
+	case "day":
+		fallthrough
+
+	default:
+		selectors = append(selectors, year, month, day)
+		groupBys = append([]string{"day", "month", "year"}, groupBys...)
+		orderBys = append(orderBys, "year ASC", "month ASC", "day ASC")
+	}
+
+	return
+}
+
+func generatePostgresTradingVolumeSQL(options TradingVolumeQueryOptions) string {
+	timeRangeColumn := "traded_at"
+	sel, groupBys, orderBys := generatePostgresTimeRangeClauses(timeRangeColumn, options.GroupByPeriod)
+
+	switch options.SegmentBy {
+	case "symbol":
+		sel = append(sel, "symbol")
+		groupBys = append([]string{"symbol"}, groupBys...)
+		orderBys = append(orderBys, "symbol")
+	case "exchange":
+		sel = append(sel, "exchange")
+		groupBys = append([]string{"exchange"}, groupBys...)
+		orderBys = append(orderBys, "exchange")
+	}
+
+	sel = append(sel, "SUM(quantity * price) AS quote_volume")
+	where := []string{timeRangeColumn + " > :start_time"}
+	sql := `SELECT ` + strings.Join(sel, ", ") + ` FROM trades` +
+		` WHERE ` + strings.Join(where, " AND ") +
+		` GROUP BY ` + strings.Join(groupBys, ", ") +
+		` ORDER BY ` + strings.Join(orderBys, ", ")
+
+	return sql
+}
+
 // QueryLast queries the last trade from the database
 func (s *TradeService) QueryLast(ex types.ExchangeName, symbol string, isMargin, isFutures, isIsolated bool, limit int) ([]types.Trade, error) {
 	log.Debugf("querying last trade exchange = %s AND symbol = %s AND is_margin = %v AND is_futures = %v AND is_isolated = %v", ex, symbol, isMargin, isFutures, isIsolated)
@@ -368,7 +426,7 @@ func (s *TradeService) Load(ctx context.Context, id int64) (*types.Trade, error)
 }
 
 func (s *TradeService) Mark(ctx context.Context, id int64, strategyID string) error {
-	result, err := s.DB.NamedExecContext(ctx, "UPDATE `trades` SET `strategy` = :strategy WHERE `id` = :id", map[string]interface{}{
+	result, err := s.DB.NamedExecContext(ctx, dialectSQL(s.DB, "UPDATE `trades` SET `strategy` = :strategy WHERE `id` = :id"), map[string]interface{}{
 		"id":       id,
 		"strategy": strategyID,
 	})
@@ -389,7 +447,7 @@ func (s *TradeService) Mark(ctx context.Context, id int64, strategyID string) er
 }
 
 func (s *TradeService) UpdatePnL(ctx context.Context, id int64, pnl float64) error {
-	result, err := s.DB.NamedExecContext(ctx, "UPDATE `trades` SET `pnl` = :pnl WHERE `id` = :id", map[string]interface{}{
+	result, err := s.DB.NamedExecContext(ctx, dialectSQL(s.DB, "UPDATE `trades` SET `pnl` = :pnl WHERE `id` = :id"), map[string]interface{}{
 		"id":  id,
 		"pnl": pnl,
 	})
@@ -75,6 +75,14 @@ func Test_queryTradingVolumeSQL(t *testing.T) {
 		}
 	})
 
+	t.Run("postgres", func(t *testing.T) {
+		o := TradingVolumeQueryOptions{
+			GroupByPeriod: "month",
+			SegmentBy:     "symbol",
+		}
+		assert.Equal(t, "SELECT CAST(EXTRACT(YEAR FROM traded_at AT TIME ZONE 'UTC') AS INTEGER) AS year, CAST(EXTRACT(MONTH FROM traded_at AT TIME ZONE 'UTC') AS INTEGER) AS month, symbol, SUM(quantity * price) AS quote_volume FROM trades WHERE traded_at > :start_time GROUP BY symbol, month, year ORDER BY year ASC, month ASC, symbol", generatePostgresTradingVolumeSQL(o))
+	})
+
 }
 
 func Test_queryTradesSQL(t *testing.T) {
@@ -69,7 +69,7 @@ func (s *WithdrawService) Sync(ctx context.Context, ex types.Exchange) error {
 
 func (s *WithdrawService) QueryLast(ex types.ExchangeName, limit int) ([]types.Withdraw, error) {
 	sql := "SELECT * FROM `withdraws` WHERE `exchange` = :exchange ORDER BY `time` DESC LIMIT :limit"
-	rows, err := s.DB.NamedQuery(sql, map[string]interface{}{
+	rows, err := s.DB.NamedQuery(dialectSQL(s.DB, sql), map[string]interface{}{
 		"exchange": ex,
 		"limit":    limit,
 	})
@@ -86,7 +86,7 @@ func (s *WithdrawService) Query(exchangeName types.ExchangeName) ([]types.Withdr
 		"exchange": exchangeName,
 	}
 	sql := "SELECT * FROM `withdraws` WHERE `exchange` = :exchange ORDER BY `time` ASC"
-	rows, err := s.DB.NamedQuery(sql, args)
+	rows, err := s.DB.NamedQuery(dialectSQL(s.DB, sql), args)
 	if err != nil {
 		return nil, err
 	}
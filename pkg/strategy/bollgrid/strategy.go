@@ -70,6 +70,9 @@ type Strategy struct {
 	// GridNum is the grid number, how many orders you want to post on the orderbook.
 	GridNum int `json:"gridNumber"`
 
+	// LegacyBollBand uses the sample standard deviation of the previous versions for the bollinger band
+	LegacyBollBand bool `json:"legacyBollBand"`
+
 	// Quantity is the quantity you want to submit for each order.
 	Quantity fixedpoint.Value `json:"quantity"`
 
@@ -332,10 +335,12 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 		s.GridNum = 2
 	}
 
-	s.boll = s.StandardIndicatorSet.BOLL(types.IntervalWindow{
-		Interval: s.Interval,
-		Window:   21,
-	}, 2.0)
+	bollIW := types.IntervalWindow{Interval: s.Interval, Window: 21}
+	if s.LegacyBollBand {
+		s.boll = s.StandardIndicatorSet.LegacyBOLL(bollIW, 2.0)
+	} else {
+		s.boll = s.StandardIndicatorSet.BOLL(bollIW, 2.0)
+	}
 
 	s.orders = bbgo.NewOrderStore(s.Symbol)
 	s.orders.BindStream(session.UserDataStream)
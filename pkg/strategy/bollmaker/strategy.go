@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"sync"
+	"time"
 
 	"github.com/c9s/bbgo/pkg/indicator"
 	"github.com/c9s/bbgo/pkg/util"
@@ -47,6 +48,9 @@ type State struct {
 type BollingerSetting struct {
 	types.IntervalWindow
 	BandWidth float64 `json:"bandWidth"`
+
+	// Legacy uses the sample standard deviation of the previous versions
+	Legacy bool `json:"legacy"`
 }
 
 type Strategy struct {
@@ -153,6 +157,10 @@ type Strategy struct {
 	Position    *types.Position    `json:"position,omitempty" persistence:"position"`
 	ProfitStats *types.ProfitStats `json:"profitStats,omitempty" persistence:"profit_stats"`
 
+	// ActiveOrders is the snapshot of the maker orders, it's used for recovering the orders and the missed fills
+	// when the strategy is restarted without canceling the orders, e.g., the process was killed.
+	ActiveOrders *bbgo.ActiveOrderSnapshot `json:"activeOrders,omitempty" persistence:"active_orders"`
+
 	activeMakerOrders *bbgo.LocalActiveOrderBook
 	orderStore        *bbgo.OrderStore
 	tradeCollector    *bbgo.TradeCollector
@@ -242,7 +250,7 @@ func (s *Strategy) ClosePosition(ctx context.Context, percentage fixedpoint.Valu
 
 	s.Notify("Submitting %s %s order to close position by %v", s.Symbol, side.String(), percentage, submitOrder)
 
-	createdOrders, err := s.session.Exchange.SubmitOrders(ctx, submitOrder)
+	createdOrders, err := s.session.OrderExecutor.SubmitOrders(ctx, submitOrder)
 	if err != nil {
 		log.WithError(err).Errorf("can not place position close order")
 	}
@@ -464,6 +472,47 @@ func (s *Strategy) placeOrders(ctx context.Context, orderExecutor bbgo.OrderExec
 	}
 	s.orderStore.Add(createdOrders...)
 	s.activeMakerOrders.Add(createdOrders...)
+	s.saveActiveOrders()
+}
+
+// saveActiveOrders persists the snapshot of the maker orders with the position and the profit stats
+func (s *Strategy) saveActiveOrders() {
+	s.ActiveOrders = s.activeMakerOrders.Snapshot()
+
+	if s.Environment == nil || s.Environment.IsBackTesting() || s.Persistence == nil {
+		return
+	}
+
+	if err := s.Persistence.Sync(s); err != nil {
+		log.WithError(err).Errorf("can not save the active orders")
+	}
+}
+
+// recoverActiveOrders re-attaches the maker orders in the persisted snapshot,
+// and replays the trades since the snapshot into the position and the profit stats through the trade collector
+func (s *Strategy) recoverActiveOrders(ctx context.Context, session *bbgo.ExchangeSession) {
+	if s.ActiveOrders == nil || len(s.ActiveOrders.Orders) == 0 {
+		return
+	}
+
+	recovery := &bbgo.ActiveOrderRecovery{
+		Session:         session,
+		Symbol:          s.Symbol,
+		ActiveOrderBook: s.activeMakerOrders,
+		OrderStore:      s.orderStore,
+		TradeCollector:  s.tradeCollector,
+		StrategyID:      ID,
+		InstanceID:      s.InstanceID(),
+		Snapshot:        s.ActiveOrders,
+	}
+
+	recoveredOrders, err := recovery.Recover(ctx)
+	if err != nil {
+		log.WithError(err).Error("active orders recovery error")
+	}
+
+	log.Infof("recovered %s %d maker orders", s.Symbol, len(recoveredOrders))
+	s.ActiveOrders = s.activeMakerOrders.Snapshot()
 }
 
 type PriceTrend string
@@ -503,6 +552,14 @@ func (s *Strategy) adjustOrderQuantity(submitOrder types.SubmitOrder) types.Subm
 	return submitOrder
 }
 
+func (s *Strategy) bollinger(setting *BollingerSetting) *indicator.BOLL {
+	if setting.Legacy {
+		return s.StandardIndicatorSet.LegacyBOLL(setting.IntervalWindow, setting.BandWidth)
+	}
+
+	return s.StandardIndicatorSet.BOLL(setting.IntervalWindow, setting.BandWidth)
+}
+
 func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, session *bbgo.ExchangeSession) error {
 	// StrategyController
 	s.Status = types.StrategyStatusRunning
@@ -520,6 +577,7 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 
 		s.tradeCollector.Process()
 
+		s.ActiveOrders = s.activeMakerOrders.Snapshot()
 		_ = s.Persistence.Sync(s)
 	})
 
@@ -552,8 +610,8 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 	// initial required information
 	s.session = session
 
-	s.neutralBoll = s.StandardIndicatorSet.BOLL(s.NeutralBollinger.IntervalWindow, s.NeutralBollinger.BandWidth)
-	s.defaultBoll = s.StandardIndicatorSet.BOLL(s.DefaultBollinger.IntervalWindow, s.DefaultBollinger.BandWidth)
+	s.neutralBoll = s.bollinger(s.NeutralBollinger)
+	s.defaultBoll = s.bollinger(s.DefaultBollinger)
 
 	// calculate group id for orders
 	instanceID := s.InstanceID()
@@ -574,6 +632,8 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 		}
 	}
 
+	s.Position.SetPriceLookup(s.session.LivePriceLookup())
+
 	if s.session.MakerFeeRate.Sign() > 0 || s.session.TakerFeeRate.Sign() > 0 {
 		s.Position.SetExchangeFeeRate(s.session.ExchangeName, types.ExchangeFee{
 			MakerFeeRate: s.session.MakerFeeRate,
@@ -637,6 +697,11 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 
 	s.tradeCollector.BindStream(session.UserDataStream)
 
+	if !s.Environment.IsBackTesting() {
+		// add the funding fees and the isolated margin interests into the position and the profit stats
+		go bbgo.NewCarryCostCollector(session, s.Position, s.ProfitStats).Run(ctx, time.Hour)
+	}
+
 	s.SmartStops.RunStopControllers(ctx, session, s.tradeCollector)
 
 	if s.Environment.IsBackTesting() {
@@ -645,6 +710,18 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 	}
 
 	session.UserDataStream.OnStart(func() {
+		// the maker orders left by the previous run are re-attached and canceled before placing the new orders,
+		// their missed fills are added to the position and the profit stats
+		if s.ActiveOrders != nil && len(s.ActiveOrders.Orders) > 0 {
+			s.recoverActiveOrders(ctx, session)
+
+			if err := s.activeMakerOrders.GracefulCancel(ctx, s.session.Exchange); err != nil {
+				log.WithError(err).Errorf("graceful cancel order error")
+			}
+
+			s.tradeCollector.Process()
+		}
+
 		if s.UseTickerPrice {
 			ticker, err := s.session.Exchange.QueryTicker(ctx, s.Symbol)
 			if err != nil {
@@ -703,6 +780,9 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 		}
 
 		s.tradeCollector.Process()
+
+		// the orders that can not be canceled are kept in the snapshot for the next run
+		s.ActiveOrders = s.activeMakerOrders.Snapshot()
 	})
 
 	return nil
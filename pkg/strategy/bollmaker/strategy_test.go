@@ -1,9 +1,17 @@
 package bollmaker
 
 import (
+	"context"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
 )
 
 func Test_calculateBandPercentage(t *testing.T) {
@@ -67,3 +75,100 @@ func Test_calculateBandPercentage(t *testing.T) {
 		})
 	}
 }
+
+type recoveryTestExchange struct {
+	types.Exchange
+
+	openOrders   []types.Order
+	closedOrders map[uint64]types.Order
+	trades       []types.Trade
+}
+
+func (e *recoveryTestExchange) QueryOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return e.openOrders, nil
+}
+
+func (e *recoveryTestExchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	orderID, err := strconv.ParseUint(q.OrderID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if o, ok := e.closedOrders[orderID]; ok {
+		return &o, nil
+	}
+
+	return nil, errors.New("order not found")
+}
+
+func (e *recoveryTestExchange) QueryTrades(ctx context.Context, symbol string, options *types.TradeQueryOptions) ([]types.Trade, error) {
+	return e.trades, nil
+}
+
+func (e *recoveryTestExchange) QueryClosedOrders(ctx context.Context, symbol string, since, until time.Time, lastOrderID uint64) ([]types.Order, error) {
+	return nil, nil
+}
+
+func TestStrategy_recoverActiveOrders(t *testing.T) {
+	newOrder := func(orderID uint64, side types.SideType, price int64, status types.OrderStatus) types.Order {
+		return types.Order{
+			SubmitOrder: types.SubmitOrder{
+				Symbol:   "BTCUSDT",
+				Side:     side,
+				Type:     types.OrderTypeLimitMaker,
+				Quantity: fixedpoint.One,
+				Price:    fixedpoint.NewFromInt(price),
+			},
+			OrderID: orderID,
+			Status:  status,
+		}
+	}
+
+	ex := &recoveryTestExchange{
+		openOrders: []types.Order{
+			newOrder(1, types.SideTypeSell, 1100, types.OrderStatusNew),
+		},
+		closedOrders: map[uint64]types.Order{
+			2: newOrder(2, types.SideTypeBuy, 1000, types.OrderStatusFilled),
+		},
+		trades: []types.Trade{
+			{ID: 1, OrderID: 2, Symbol: "BTCUSDT", Side: types.SideTypeBuy, Price: fixedpoint.NewFromInt(1000), Quantity: fixedpoint.One, QuoteQuantity: fixedpoint.NewFromInt(1000)},
+		},
+	}
+
+	market := types.Market{Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"}
+	s := &Strategy{
+		Symbol:      "BTCUSDT",
+		Market:      market,
+		Position:    types.NewPositionFromMarket(market),
+		ProfitStats: types.NewProfitStats(market),
+		ActiveOrders: &bbgo.ActiveOrderSnapshot{
+			Orders: []types.Order{
+				newOrder(1, types.SideTypeSell, 1100, types.OrderStatusNew),
+				newOrder(2, types.SideTypeBuy, 1000, types.OrderStatusNew),
+			},
+			Time: time.Now().Add(-time.Hour),
+		},
+	}
+
+	s.activeMakerOrders = bbgo.NewLocalActiveOrderBook(s.Symbol)
+	s.orderStore = bbgo.NewOrderStore(s.Symbol)
+	s.tradeCollector = bbgo.NewTradeCollector(s.Symbol, s.Position, s.orderStore)
+	s.tradeCollector.OnTrade(func(trade types.Trade, profit, netProfit fixedpoint.Value) {
+		s.ProfitStats.AddTrade(trade)
+	})
+
+	s.recoverActiveOrders(context.Background(), &bbgo.ExchangeSession{Name: "binance", Exchange: ex})
+
+	// the open sell order is re-attached, and the missed buy fill is added to the position and the profit stats
+	assert.Equal(t, 1, s.activeMakerOrders.NumOfOrders())
+	assert.True(t, s.activeMakerOrders.Exists(ex.openOrders[0]))
+	assert.Equal(t, "1", s.Position.Base.String())
+	assert.Equal(t, "1000", s.Position.AverageCost.String())
+	assert.Equal(t, "1", s.ProfitStats.AccumulatedVolume.String())
+
+	// the snapshot is updated with the recovered orders
+	if assert.Len(t, s.ActiveOrders.Orders, 1) {
+		assert.Equal(t, uint64(1), s.ActiveOrders.Orders[0].OrderID)
+	}
+}
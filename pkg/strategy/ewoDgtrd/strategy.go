@@ -3,7 +3,6 @@ package ewoDgtrd
 import (
 	"context"
 	"fmt"
-	"math"
 	"sync"
 
 	"github.com/sirupsen/logrus"
@@ -59,6 +58,9 @@ func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {
 	log.Infof("subscribe %s", s.Symbol)
 	session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: types.Interval1m.String()})
 	session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: s.Interval.String()})
+	if s.UseHeikinAshi {
+		session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: s.heikinAshiInterval().String()})
+	}
 	s.SmartStops.Subscribe(session)
 }
 
@@ -170,20 +172,20 @@ func (s *HeikinAshi) Print() string {
 		s.Volume.Last())
 }
 
+// Update pushes the heikin-ashi kline transformed by the market data store
 func (inc *HeikinAshi) Update(kline types.KLine) {
-	open := kline.Open.Float64()
-	cloze := kline.Close.Float64()
-	high := kline.High.Float64()
-	low := kline.Low.Float64()
-	newClose := (open + high + low + cloze) / 4.
-	newOpen := (inc.Open.Last() + inc.Close.Last()) / 2.
-	inc.Close.Update(newClose)
-	inc.Open.Update(newOpen)
-	inc.High.Update(math.Max(math.Max(high, newOpen), newClose))
-	inc.Low.Update(math.Min(math.Min(low, newOpen), newClose))
+	inc.Close.Update(kline.Close.Float64())
+	inc.Open.Update(kline.Open.Float64())
+	inc.High.Update(kline.High.Float64())
+	inc.Low.Update(kline.Low.Float64())
 	inc.Volume.Update(kline.Volume.Float64())
 }
 
+// heikinAshiInterval is the derived interval of the heikin-ashi klines of s.Interval
+func (s *Strategy) heikinAshiInterval() types.Interval {
+	return types.DerivedInterval{Type: types.DerivedKLineHeikinAshi, Source: s.Interval}.Interval()
+}
+
 func (s *Strategy) SetupIndicators() {
 	store, ok := s.Session.MarketDataStore(s.Symbol)
 	if !ok {
@@ -193,28 +195,16 @@ func (s *Strategy) SetupIndicators() {
 
 	s.atr = &indicator.ATR{IntervalWindow: types.IntervalWindow{s.Interval, 34}}
 
+	// the indicators of the heikin-ashi klines are updated with the derived heikin-ashi interval,
+	// which is updated right after the klines of s.Interval
+	indicatorInterval := s.Interval
+
 	if s.UseHeikinAshi {
+		indicatorInterval = s.heikinAshiInterval()
+		s.atr.Bind(store)
 		s.heikinAshi = NewHeikinAshi(50)
 		store.OnKLineWindowUpdate(func(interval types.Interval, window types.KLineWindow) {
-			if interval == s.atr.Interval {
-				if s.atr.RMA == nil {
-					for _, kline := range window {
-						s.atr.Update(
-							kline.High.Float64(),
-							kline.Low.Float64(),
-							kline.Close.Float64(),
-						)
-					}
-				} else {
-					kline := window[len(window)-1]
-					s.atr.Update(
-						kline.High.Float64(),
-						kline.Low.Float64(),
-						kline.Close.Float64(),
-					)
-				}
-			}
-			if s.Interval != interval {
+			if indicatorInterval != interval {
 				return
 			}
 			if s.heikinAshi.Close.Length() == 0 {
@@ -229,7 +219,7 @@ func (s *Strategy) SetupIndicators() {
 			ema5 := &indicator.EWMA{IntervalWindow: types.IntervalWindow{s.Interval, 5}}
 			ema34 := &indicator.EWMA{IntervalWindow: types.IntervalWindow{s.Interval, 34}}
 			store.OnKLineWindowUpdate(func(interval types.Interval, _ types.KLineWindow) {
-				if s.Interval != interval {
+				if indicatorInterval != interval {
 					return
 				}
 				if ema5.Length() == 0 {
@@ -247,10 +237,10 @@ func (s *Strategy) SetupIndicators() {
 			s.ma5 = ema5
 			s.ma34 = ema34
 		} else if s.UseSma {
-			sma5 := &indicator.SMA{IntervalWindow: types.IntervalWindow{s.Interval, 5}}
-			sma34 := &indicator.SMA{IntervalWindow: types.IntervalWindow{s.Interval, 34}}
+			sma5 := &indicator.SMA{IntervalWindow: types.IntervalWindow{s.Interval, 5}, Legacy: true}
+			sma34 := &indicator.SMA{IntervalWindow: types.IntervalWindow{s.Interval, 34}, Legacy: true}
 			store.OnKLineWindowUpdate(func(interval types.Interval, _ types.KLineWindow) {
-				if s.Interval != interval {
+				if indicatorInterval != interval {
 					return
 				}
 				if sma5.Length() == 0 {
@@ -277,7 +267,7 @@ func (s *Strategy) SetupIndicators() {
 				V:  &indicator.EWMA{IntervalWindow: types.IntervalWindow{s.Interval, 34}},
 			}
 			store.OnKLineWindowUpdate(func(interval types.Interval, _ types.KLineWindow) {
-				if s.Interval != interval {
+				if indicatorInterval != interval {
 					return
 				}
 				if evwma5.PV.Length() == 0 {
@@ -343,7 +333,7 @@ func (s *Strategy) SetupIndicators() {
 	if s.UseEma {
 		sig := &indicator.EWMA{IntervalWindow: types.IntervalWindow{s.Interval, s.SignalWindow}}
 		store.OnKLineWindowUpdate(func(interval types.Interval, _ types.KLineWindow) {
-			if interval != s.Interval {
+			if interval != indicatorInterval {
 				return
 			}
 
@@ -359,9 +349,9 @@ func (s *Strategy) SetupIndicators() {
 		})
 		s.ewoSignal = sig
 	} else if s.UseSma {
-		sig := &indicator.SMA{IntervalWindow: types.IntervalWindow{s.Interval, s.SignalWindow}}
+		sig := &indicator.SMA{IntervalWindow: types.IntervalWindow{s.Interval, s.SignalWindow}, Legacy: true}
 		store.OnKLineWindowUpdate(func(interval types.Interval, _ types.KLineWindow) {
-			if interval != s.Interval {
+			if interval != indicatorInterval {
 				return
 			}
 
@@ -382,7 +372,7 @@ func (s *Strategy) SetupIndicators() {
 			V:  &indicator.EWMA{IntervalWindow: types.IntervalWindow{s.Interval, s.SignalWindow}},
 		}
 		store.OnKLineWindowUpdate(func(interval types.Interval, window types.KLineWindow) {
-			if interval != s.Interval {
+			if interval != indicatorInterval {
 				return
 			}
 			var vol float64
@@ -0,0 +1,157 @@
+package exprsignal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/expr"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const ID = "exprsignal"
+
+var log = logrus.WithField("strategy", ID)
+
+func init() {
+	bbgo.RegisterStrategy(ID, &Strategy{})
+}
+
+// Strategy opens the long position when the entry expression is true, and closes the position when the exit expression is true.
+// The expressions are evaluated when the kline of the interval is closed, see pkg/expr for the expression language.
+type Strategy struct {
+	Symbol   string           `json:"symbol"`
+	Interval types.Interval   `json:"interval"`
+	Quantity fixedpoint.Value `json:"quantity"`
+
+	// Entry is the signal to open the position, for example, "crossover(ema(close, 9), sma(close, 21)) and rsi(close, 14) < 70"
+	Entry string `json:"entry"`
+
+	// Exit is the signal to close the position, for example, "crossunder(ema(close, 9), sma(close, 21))"
+	Exit string `json:"exit"`
+
+	Market types.Market
+
+	Position *types.Position `json:"position,omitempty"`
+
+	session        *bbgo.ExchangeSession
+	orderStore     *bbgo.OrderStore
+	tradeCollector *bbgo.TradeCollector
+
+	entry, exit *expr.Expression
+}
+
+func (s *Strategy) ID() string {
+	return ID
+}
+
+func (s *Strategy) Validate() error {
+	if len(s.Symbol) == 0 {
+		return errors.New("symbol is required")
+	}
+
+	if len(s.Interval) == 0 {
+		return errors.New("interval is required")
+	}
+
+	if s.Quantity.Sign() <= 0 {
+		return errors.New("quantity must be positive")
+	}
+
+	if len(s.Entry) == 0 || len(s.Exit) == 0 {
+		return errors.New("entry and exit expressions are required")
+	}
+
+	// check the syntax before the session is started
+	env := expr.NewEnvironment(s.Interval)
+	for _, source := range []string{s.Entry, s.Exit} {
+		if _, err := env.Compile(source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {
+	session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: s.Interval.String()})
+}
+
+func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, session *bbgo.ExchangeSession) error {
+	s.session = session
+
+	store, ok := session.MarketDataStore(s.Symbol)
+	if !ok {
+		return fmt.Errorf("market data store of %s not found", s.Symbol)
+	}
+
+	env := expr.NewEnvironment(s.Interval)
+
+	var err error
+	if s.entry, err = env.Compile(s.Entry); err != nil {
+		return err
+	}
+	if s.exit, err = env.Compile(s.Exit); err != nil {
+		return err
+	}
+
+	// warm up the indicators with the loaded klines
+	if window, ok := store.KLinesOfInterval(s.Interval); ok {
+		env.Update(*window...)
+	}
+	env.Bind(store)
+
+	s.orderStore = bbgo.NewOrderStore(s.Symbol)
+	s.orderStore.BindStream(session.UserDataStream)
+
+	if s.Position == nil {
+		s.Position = types.NewPositionFromMarket(s.Market)
+	}
+
+	s.tradeCollector = bbgo.NewTradeCollector(s.Symbol, s.Position, s.orderStore)
+	s.tradeCollector.OnPositionUpdate(func(position *types.Position) {
+		log.Infof("position changed: %s", position)
+	})
+	s.tradeCollector.BindStream(session.UserDataStream)
+
+	session.MarketDataStream.OnKLineClosed(func(kline types.KLine) {
+		if kline.Symbol != s.Symbol || kline.Interval != s.Interval {
+			return
+		}
+
+		base := s.Position.GetBase()
+		if base.Compare(s.Market.MinQuantity) >= 0 {
+			if s.exit.BoolSeries().Last() {
+				log.Infof("exit signal %q at %v", s.Exit, kline.Close)
+				s.submitOrder(ctx, orderExecutor, types.SideTypeSell, base)
+			}
+		} else if s.entry.BoolSeries().Last() {
+			log.Infof("entry signal %q at %v", s.Entry, kline.Close)
+			s.submitOrder(ctx, orderExecutor, types.SideTypeBuy, s.Quantity)
+		}
+
+		s.tradeCollector.Process()
+	})
+
+	return nil
+}
+
+func (s *Strategy) submitOrder(ctx context.Context, orderExecutor bbgo.OrderExecutor, side types.SideType, quantity fixedpoint.Value) {
+	createdOrders, err := orderExecutor.SubmitOrders(ctx, types.SubmitOrder{
+		Symbol:   s.Symbol,
+		Side:     side,
+		Type:     types.OrderTypeMarket,
+		Quantity: quantity,
+		Market:   s.Market,
+	})
+	if err != nil {
+		log.WithError(err).Errorf("can not submit %s order", side)
+		return
+	}
+
+	s.orderStore.Add(createdOrders...)
+}
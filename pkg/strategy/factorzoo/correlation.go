@@ -2,7 +2,6 @@ package factorzoo
 
 import (
 	"fmt"
-	"math"
 	"time"
 
 	"github.com/c9s/bbgo/pkg/indicator"
@@ -77,25 +76,13 @@ func calculateCORRELATION(klines []types.KLine, window int, valA KLineValueMappe
 		return 0.0, fmt.Errorf("insufficient elements for calculating VOL with window = %d", window)
 	}
 
-	sumA, sumB, sumAB, squareSumA, squareSumB := 0., 0., 0., 0., 0.
+	var a, b types.Float64Slice
 	for _, k := range klines {
-		// sum of elements of array A
-		sumA += valA(k)
-		// sum of elements of array B
-		sumB += valB(k)
-
-		// sum of A[i] * B[i].
-		sumAB = sumAB + valA(k)*valB(k)
-
-		// sum of square of array elements.
-		squareSumA = squareSumA + valA(k)*valA(k)
-		squareSumB = squareSumB + valB(k)*valB(k)
+		a.Push(valA(k))
+		b.Push(valB(k))
 	}
-	// use formula for calculating correlation coefficient.
-	corr := (float64(window)*sumAB - sumA*sumB) /
-		math.Sqrt((float64(window)*squareSumA-sumA*sumA)*(float64(window)*squareSumB-sumB*sumB))
 
-	return corr, nil
+	return types.Correlation(&a, &b, window), nil
 }
 
 func KLineAmplitudeMapper(k types.KLine) float64 {
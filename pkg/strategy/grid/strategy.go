@@ -42,6 +42,9 @@ type State struct {
 	ArbitrageOrders map[uint64]types.Order `json:"arbitrageOrders"`
 
 	ProfitStats types.ProfitStats `json:"profitStats,omitempty"`
+
+	// ActiveOrders is the snapshot of the resting grid orders, it's saved when KeepOrders is enabled
+	ActiveOrders *bbgo.ActiveOrderSnapshot `json:"activeOrders,omitempty"`
 }
 
 type Strategy struct {
@@ -94,6 +97,10 @@ type Strategy struct {
 	// Long means you want to hold more base asset than the quote asset.
 	Long bool `json:"long,omitempty" yaml:"long,omitempty"`
 
+	// KeepOrders keeps the grid orders on the exchange when the strategy is shut down,
+	// the orders are recovered and the missed fills are replayed when the strategy restarts.
+	KeepOrders bool `json:"keepOrders,omitempty" yaml:"keepOrders,omitempty"`
+
 	state *State
 
 	// orderStore is used to store all the created orders, so that we can filter the trades.
@@ -560,8 +567,13 @@ func (s *Strategy) SaveState() error {
 		log.Infof("backing up grid state...")
 
 		instanceID := s.InstanceID()
-		submitOrders := s.activeOrders.Backup()
-		s.state.Orders = submitOrders
+		if s.KeepOrders {
+			s.state.Orders = nil
+			s.state.ActiveOrders = s.activeOrders.Snapshot()
+		} else {
+			s.state.Orders = s.activeOrders.Backup()
+			s.state.ActiveOrders = nil
+		}
 
 		if err := s.Persistence.Save(s.state, ID, instanceID); err != nil {
 			return err
@@ -634,6 +646,11 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 			s.Notify("%s: %s grid is saved", ID, s.Symbol)
 		}
 
+		if s.KeepOrders {
+			log.Infof("keeping %d active orders...", s.activeOrders.NumOfOrders())
+			return
+		}
+
 		// now we can cancel the open orders
 		log.Infof("canceling active orders...")
 		if err := session.Exchange.CancelOrders(context.Background(), s.activeOrders.Orders()...); err != nil {
@@ -642,6 +659,29 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 	})
 
 	session.UserDataStream.OnStart(func() {
+		// re-attach the resting orders and replay the missed fills
+		if s.KeepOrders && s.state.ActiveOrders != nil {
+			recovery := &bbgo.ActiveOrderRecovery{
+				Session:         session,
+				Symbol:          s.Symbol,
+				ActiveOrderBook: s.activeOrders,
+				OrderStore:      s.orderStore,
+				TradeCollector:  s.tradeCollector,
+				StrategyID:      ID,
+				InstanceID:      instanceID,
+				Snapshot:        s.state.ActiveOrders,
+			}
+
+			recoveredOrders, err := recovery.Recover(ctx)
+			if err != nil {
+				log.WithError(err).Error("active orders recovery error")
+			}
+
+			s.Notifiability.Notify("recovered %s %d grid orders", s.Symbol, len(recoveredOrders))
+			s.state.ActiveOrders = nil
+			return
+		}
+
 		// if we have orders in the state data, we can restore them
 		if len(s.state.Orders) > 0 {
 			s.Notifiability.Notify("restoring %s %d grid orders...", s.Symbol, len(s.state.Orders))
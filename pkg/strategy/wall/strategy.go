@@ -261,6 +261,8 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 		s.Position = types.NewPositionFromMarket(s.Market)
 	}
 
+	s.Position.SetPriceLookup(session.LivePriceLookup())
+
 	if s.ProfitStats == nil {
 		s.ProfitStats = types.NewProfitStats(s.Market)
 	}
@@ -309,6 +311,11 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 
 	s.tradeCollector.BindStream(session.UserDataStream)
 
+	if !s.Environment.IsBackTesting() {
+		// add the funding fees and the isolated margin interests into the position and the profit stats
+		go bbgo.NewCarryCostCollector(session, s.Position, s.ProfitStats).Run(ctx, time.Hour)
+	}
+
 	session.UserDataStream.OnStart(func() {
 		if err := s.placeWallOrders(ctx, orderExecutor); err != nil {
 			log.WithError(err).Errorf("can not place order")
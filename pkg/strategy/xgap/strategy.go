@@ -352,7 +352,7 @@ func (s *Strategy) CrossRun(ctx context.Context, _ bbgo.OrderExecutionRouter, se
 						s.tradingMarket.MinNotional.Mul(NotionModifier).Div(price))
 				}
 
-				createdOrders, err := tradingSession.Exchange.SubmitOrders(ctx, types.SubmitOrder{
+				createdOrders, err := tradingSession.OrderExecutor.SubmitOrders(ctx, types.SubmitOrder{
 					Symbol:   s.Symbol,
 					Side:     types.SideTypeBuy,
 					Type:     types.OrderTypeLimit,
@@ -61,6 +61,9 @@ type Strategy struct {
 	BollBandMargin       fixedpoint.Value `json:"bollBandMargin"`
 	BollBandMarginFactor fixedpoint.Value `json:"bollBandMarginFactor"`
 
+	// LegacyBollBand uses the sample standard deviation of the previous versions for the boll band margin
+	LegacyBollBand bool `json:"legacyBollBand"`
+
 	StopHedgeQuoteBalance fixedpoint.Value `json:"stopHedgeQuoteBalance"`
 	StopHedgeBaseBalance  fixedpoint.Value `json:"stopHedgeBaseBalance"`
 
@@ -681,10 +684,12 @@ func (s *Strategy) CrossRun(ctx context.Context, orderExecutionRouter bbgo.Order
 		return fmt.Errorf("%s standard indicator set not found", s.Symbol)
 	}
 
-	s.boll = standardIndicatorSet.BOLL(types.IntervalWindow{
-		Interval: s.BollBandInterval,
-		Window:   21,
-	}, 1.0)
+	bollIW := types.IntervalWindow{Interval: s.BollBandInterval, Window: 21}
+	if s.LegacyBollBand {
+		s.boll = standardIndicatorSet.LegacyBOLL(bollIW, 1.0)
+	} else {
+		s.boll = standardIndicatorSet.BOLL(bollIW, 1.0)
+	}
 
 	// restore state
 	instanceID := s.InstanceID()
@@ -708,6 +713,10 @@ func (s *Strategy) CrossRun(ctx context.Context, orderExecutionRouter bbgo.Order
 		s.Position.Market = s.makerMarket
 	}
 
+	s.Position.Strategy = ID
+	s.Position.StrategyInstanceID = instanceID
+	s.Position.SetPriceLookup(s.makerSession.LivePriceLookup())
+
 	if s.ProfitStats == nil {
 		if s.state != nil {
 			p2 := s.state.ProfitStats
@@ -81,6 +81,7 @@ func (s *Strategy) recordNetAssetValue(ctx context.Context, sessions map[string]
 	totalBalances := types.BalanceMap{}
 	allPrices := map[string]fixedpoint.Value{}
 	sessionBalances := map[string]types.BalanceMap{}
+	sessionAssets := map[string]types.AssetMap{}
 	priceTime := time.Now()
 
 	// iterate the sessions and record them
@@ -109,11 +110,31 @@ func (s *Strategy) recordNetAssetValue(ctx context.Context, sessions map[string]
 			allPrices[m] = p
 		}
 
-		s.Environment.RecordAsset(priceTime, session, assets)
+		sessionAssets[sessionName] = assets
 	}
 
 	displayAssets := types.AssetMap{}
 	totalAssets := totalBalances.Assets(allPrices, priceTime)
+
+	// convert the assets into the reporting currency, the markets are routed through all sessions
+	converted := true
+	if err := s.Environment.UpdateReportingPrices(ctx, totalBalances.Currencies()); err != nil {
+		log.WithError(err).Error("reporting price update failed")
+		converted = false
+	}
+
+	for sessionName, assets := range sessionAssets {
+		if converted {
+			assets = s.Environment.ConvertAssets(assets)
+		}
+
+		s.Environment.RecordAsset(priceTime, sessions[sessionName], assets)
+	}
+
+	if converted {
+		totalAssets = s.Environment.ConvertAssets(totalAssets)
+	}
+
 	s.Environment.RecordAsset(priceTime, &bbgo.ExchangeSession{Name: "ALL"}, totalAssets)
 
 	for currency, asset := range totalAssets {
@@ -30,6 +30,12 @@ type Asset struct {
 	Available  fixedpoint.Value `json:"available"  db:"available"`
 	Borrowed   fixedpoint.Value `json:"borrowed" db:"borrowed"`
 	PriceInUSD fixedpoint.Value `json:"priceInUSD" db:"price_in_usd"`
+
+	// ReportingCurrency is the reporting currency that the net asset is converted into
+	ReportingCurrency string `json:"reportingCurrency,omitempty" db:"reporting_currency"`
+
+	// InReportingCurrency is net asset in the reporting currency
+	InReportingCurrency fixedpoint.Value `json:"inReportingCurrency" db:"net_asset_in_reporting_currency"`
 }
 
 type AssetMap map[string]Asset
@@ -45,6 +51,53 @@ func (m AssetMap) InUSD() (total fixedpoint.Value) {
 	return total
 }
 
+// InReportingCurrency returns the total net asset value in the reporting currency
+func (m AssetMap) InReportingCurrency() (total fixedpoint.Value) {
+	for _, a := range m {
+		total = total.Add(a.InReportingCurrency)
+	}
+	return total
+}
+
+// ReportingCurrency returns the reporting currency of the converted assets
+func (m AssetMap) ReportingCurrency() string {
+	for _, a := range m {
+		if len(a.ReportingCurrency) > 0 {
+			return a.ReportingCurrency
+		}
+	}
+	return ""
+}
+
+// Convert converts the net assets into the reporting currency through the conversion graph,
+// the missing USD and BTC values are also filled if the graph can route them.
+func (m AssetMap) Convert(graph *ConversionGraph, reportingCurrency string) AssetMap {
+	assets := make(AssetMap, len(m))
+	for currency, a := range m {
+		if a.InUSD.IsZero() && !a.NetAsset.IsZero() {
+			if v, ok := graph.Convert(a.NetAsset, currency, "USD"); ok {
+				a.InUSD = v
+				a.PriceInUSD, _ = graph.Rate(currency, "USD")
+			}
+		}
+
+		if a.InBTC.IsZero() && !a.NetAsset.IsZero() {
+			if v, ok := graph.Convert(a.NetAsset, currency, "BTC"); ok {
+				a.InBTC = v
+			}
+		}
+
+		a.ReportingCurrency = reportingCurrency
+		if v, ok := graph.Convert(a.NetAsset, currency, reportingCurrency); ok {
+			a.InReportingCurrency = v
+		}
+
+		assets[currency] = a
+	}
+
+	return assets
+}
+
 func (m AssetMap) PlainText() (o string) {
 	var assets = m.Slice()
 
@@ -77,7 +130,13 @@ func (m AssetMap) PlainText() (o string) {
 	o += fmt.Sprintf(" Summary: (≈ %s) (≈ %s)",
 		USD.FormatMoney(sumUsd),
 		BTC.FormatMoney(sumBTC),
-	) + "\n"
+	)
+
+	if reportingCurrency := m.ReportingCurrency(); len(reportingCurrency) > 0 {
+		o += fmt.Sprintf(" (≈ %s)", FormatMoney(reportingCurrency, m.InReportingCurrency()))
+	}
+
+	o += "\n"
 	return o
 }
 
@@ -137,11 +196,17 @@ func (m AssetMap) SlackAttachment() slack.Attachment {
 		}
 	}
 
+	title := fmt.Sprintf("Net Asset Value %s (≈ %s)",
+		USD.FormatMoney(netAssetInUSD),
+		BTC.FormatMoney(netAssetInBTC),
+	)
+
+	if reportingCurrency := m.ReportingCurrency(); len(reportingCurrency) > 0 {
+		title += fmt.Sprintf(" (≈ %s)", FormatMoney(reportingCurrency, m.InReportingCurrency()))
+	}
+
 	return slack.Attachment{
-		Title: fmt.Sprintf("Net Asset Value %s (≈ %s)",
-			USD.FormatMoney(netAssetInUSD),
-			BTC.FormatMoney(netAssetInBTC),
-		),
+		Title:  title,
 		Fields: fields,
 	}
 }
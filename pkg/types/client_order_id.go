@@ -0,0 +1,145 @@
+package types
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ClientOrderIDLimit is the length and the charset limit of the client order ID of an exchange.
+// MaxLength is the length that is left for the caller after the exchange package adds its broker prefix.
+type ClientOrderIDLimit struct {
+	MaxLength        int
+	AlphanumericOnly bool
+}
+
+var ClientOrderIDLimits = map[ExchangeName]ClientOrderIDLimit{
+	// 36 chars, the spot and futures broker prefix "x-XXXXXXXX" takes 10 chars and the whole ID is truncated to 32 chars
+	ExchangeBinance: {MaxLength: 22},
+	// 32 chars, the broker prefix "x-bbgo-" takes 7 chars
+	ExchangeMax: {MaxLength: 25},
+	// 32 chars, case-sensitive alphanumerics only
+	ExchangeOKEx: {MaxLength: 32, AlphanumericOnly: true},
+	// 40 chars
+	ExchangeKucoin: {MaxLength: 40},
+	// the broker prefix "x-BBGO" takes 6 chars
+	ExchangeFTX: {MaxLength: 26},
+}
+
+// Allow checks if the client order ID fits the limit
+func (l ClientOrderIDLimit) Allow(clientOrderID string) bool {
+	if l.MaxLength > 0 && len(clientOrderID) > l.MaxLength {
+		return false
+	}
+
+	if l.AlphanumericOnly {
+		for _, c := range clientOrderID {
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// The client order ID generated by ClientOrderIDGenerator is composed of fixed-width base36 fields,
+// so that it can be parsed even if the exchange package adds a broker prefix:
+//
+//	"bb" + strategy instance tag (7) + generator nonce (6) + sequence (6)
+//
+// The ID contains lowercase alphanumerics only, which is accepted by all supported exchanges.
+const (
+	clientOrderIDMarker    = "bb"
+	clientOrderIDTagLen    = 7
+	clientOrderIDNonceLen  = 6
+	clientOrderIDSeqLen    = 6
+	clientOrderIDLen       = len(clientOrderIDMarker) + clientOrderIDTagLen + clientOrderIDNonceLen + clientOrderIDSeqLen
+	clientOrderIDNonceSize = 2176782336 // 36^6
+)
+
+func formatBase36(v uint64, width int) string {
+	s := strconv.FormatUint(v, 36)
+	if len(s) > width {
+		return s[len(s)-width:]
+	}
+
+	return strings.Repeat("0", width-len(s)) + s
+}
+
+// ClientOrderIDTag returns the tag that identifies the strategy instance in the client order ID
+func ClientOrderIDTag(strategyID, instanceID string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strategyID + ":" + instanceID))
+	return formatBase36(uint64(h.Sum32()), clientOrderIDTagLen)
+}
+
+// ClientOrderIDInfo is the parsed client order ID
+type ClientOrderIDInfo struct {
+	Tag   string
+	Nonce string
+	Seq   uint64
+}
+
+// ParseClientOrderID parses the client order ID generated by ClientOrderIDGenerator, the broker prefix is ignored.
+func ParseClientOrderID(clientOrderID string) (info ClientOrderIDInfo, ok bool) {
+	if len(clientOrderID) < clientOrderIDLen {
+		return info, false
+	}
+
+	id := clientOrderID[len(clientOrderID)-clientOrderIDLen:]
+	if !strings.HasPrefix(id, clientOrderIDMarker) {
+		return info, false
+	}
+
+	id = id[len(clientOrderIDMarker):]
+	info.Tag = id[:clientOrderIDTagLen]
+	info.Nonce = id[clientOrderIDTagLen : clientOrderIDTagLen+clientOrderIDNonceLen]
+
+	seq, err := strconv.ParseUint(id[clientOrderIDTagLen+clientOrderIDNonceLen:], 36, 64)
+	if err != nil {
+		return info, false
+	}
+
+	info.Seq = seq
+	return info, true
+}
+
+// ClientOrderIDGenerator generates the client order IDs of a strategy instance.
+// The nonce is derived from the creation time so that the IDs do not collide after the process restarts.
+type ClientOrderIDGenerator struct {
+	tag   string
+	nonce string
+	seq   uint64
+}
+
+func NewClientOrderIDGenerator(strategyID, instanceID string) *ClientOrderIDGenerator {
+	return &ClientOrderIDGenerator{
+		tag:   ClientOrderIDTag(strategyID, instanceID),
+		nonce: formatBase36(uint64(time.Now().Unix())%clientOrderIDNonceSize, clientOrderIDNonceLen),
+	}
+}
+
+// Tag returns the strategy instance tag of the generator
+func (g *ClientOrderIDGenerator) Tag() string {
+	return g.tag
+}
+
+// Next returns the next client order ID, it's safe to be called from multiple goroutines
+func (g *ClientOrderIDGenerator) Next() string {
+	seq := atomic.AddUint64(&g.seq, 1)
+	return clientOrderIDMarker + g.tag + g.nonce + formatBase36(seq, clientOrderIDSeqLen)
+}
+
+// NextFor returns the next client order ID if it fits the limit of the exchange,
+// otherwise an empty string is returned and the exchange package generates its own ID.
+func (g *ClientOrderIDGenerator) NextFor(exchange ExchangeName) string {
+	id := g.Next()
+	if limit, ok := ClientOrderIDLimits[exchange]; ok && !limit.Allow(id) {
+		return ""
+	}
+
+	return id
+}
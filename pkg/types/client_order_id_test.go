@@ -0,0 +1,45 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientOrderIDGenerator(t *testing.T) {
+	g := NewClientOrderIDGenerator("bollmaker", "bollmaker:BTCUSDT")
+
+	id1 := g.Next()
+	id2 := g.Next()
+	assert.NotEqual(t, id1, id2)
+	assert.Len(t, id1, clientOrderIDLen)
+
+	for exchange, limit := range ClientOrderIDLimits {
+		assert.True(t, limit.Allow(id1), "client order id should fit the limit of %s", exchange)
+	}
+
+	info, ok := ParseClientOrderID(id2)
+	if assert.True(t, ok) {
+		assert.Equal(t, ClientOrderIDTag("bollmaker", "bollmaker:BTCUSDT"), info.Tag)
+		assert.Equal(t, uint64(2), info.Seq)
+	}
+
+	// with the broker prefix
+	info, ok = ParseClientOrderID("x-NSUYEBKM" + id1)
+	if assert.True(t, ok) {
+		assert.Equal(t, g.Tag(), info.Tag)
+		assert.Equal(t, uint64(1), info.Seq)
+	}
+
+	_, ok = ParseClientOrderID("x-NSUYEBKM1b4a2f1e-5a6c-4cbe-a0e5")
+	assert.False(t, ok)
+
+	assert.NotEqual(t, g.Tag(), ClientOrderIDTag("bollmaker", "bollmaker:ETHUSDT"))
+}
+
+func TestClientOrderIDLimit_Allow(t *testing.T) {
+	limit := ClientOrderIDLimit{MaxLength: 8, AlphanumericOnly: true}
+	assert.True(t, limit.Allow("abcD1234"))
+	assert.False(t, limit.Allow("abcD12345"))
+	assert.False(t, limit.Allow("ab-1"))
+}
@@ -0,0 +1,207 @@
+package types
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// USDStableCoins are pegged to USD, they are used when there is no market to convert them into USD
+var USDStableCoins = []string{"USDT", "USDC", "BUSD"}
+
+type conversionEdge struct {
+	// Symbol is the market symbol of the edge, it's empty for the pegged currencies
+	Symbol string
+
+	To string
+
+	// Inverse is true when the edge converts the quote currency into the base currency
+	Inverse bool
+}
+
+// ConversionGraph routes the currency conversion through the markets,
+// the currencies are the nodes and the markets are the edges, so that ETH can be converted to TWD via
+// ETH/BTC -> BTC/USDT -> USDT/TWD when there is no ETH/TWD market.
+//
+// The markets can be collected from different sessions, the symbol prices are shared.
+type ConversionGraph struct {
+	edges  map[string][]conversionEdge
+	prices map[string]fixedpoint.Value
+}
+
+func NewConversionGraph() *ConversionGraph {
+	return &ConversionGraph{
+		edges:  make(map[string][]conversionEdge),
+		prices: make(map[string]fixedpoint.Value),
+	}
+}
+
+func (g *ConversionGraph) hasEdge(from, to string) bool {
+	for _, e := range g.edges[from] {
+		if e.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AddMarket adds the market as the edges between its base currency and its quote currency
+func (g *ConversionGraph) AddMarket(market Market) {
+	base, quote := market.BaseCurrency, market.QuoteCurrency
+	if len(base) == 0 || len(quote) == 0 {
+		return
+	}
+
+	for _, e := range g.edges[base] {
+		if e.Symbol == market.Symbol {
+			return
+		}
+	}
+
+	g.edges[base] = append(g.edges[base], conversionEdge{Symbol: market.Symbol, To: quote})
+	g.edges[quote] = append(g.edges[quote], conversionEdge{Symbol: market.Symbol, To: base, Inverse: true})
+}
+
+// AddMarkets adds all the markets of the market map, the markets are added in the symbol order
+// so that the routes are stable.
+func (g *ConversionGraph) AddMarkets(markets MarketMap) {
+	var symbols []string
+	for symbol := range markets {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		g.AddMarket(markets[symbol])
+	}
+}
+
+// AddPeg adds a 1:1 conversion between the two currencies if there is no market between them.
+func (g *ConversionGraph) AddPeg(a, b string) {
+	if a == b || g.hasEdge(a, b) {
+		return
+	}
+
+	g.edges[a] = append(g.edges[a], conversionEdge{To: b})
+	g.edges[b] = append(g.edges[b], conversionEdge{To: a})
+}
+
+// AddUSDPegs pegs the USD stable coins to USD
+func (g *ConversionGraph) AddUSDPegs() {
+	for _, c := range USDStableCoins {
+		g.AddPeg(c, "USD")
+	}
+}
+
+// SetPrice updates the last price of the market symbol
+func (g *ConversionGraph) SetPrice(symbol string, price fixedpoint.Value) {
+	g.prices[symbol] = price
+}
+
+// SetPrices updates the last prices of the market symbols
+func (g *ConversionGraph) SetPrices(prices map[string]fixedpoint.Value) {
+	for symbol, price := range prices {
+		g.prices[symbol] = price
+	}
+}
+
+func (g *ConversionGraph) usable(e conversionEdge) bool {
+	if len(e.Symbol) == 0 {
+		return true
+	}
+
+	price, ok := g.prices[e.Symbol]
+	return ok && price.Sign() > 0
+}
+
+// route finds the shortest path from the currency to the target currency,
+// when withPrices is true, only the edges with the price are used.
+func (g *ConversionGraph) route(from, to string, withPrices bool) ([]conversionEdge, bool) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return nil, true
+	}
+
+	type node struct {
+		currency string
+		path     []conversionEdge
+	}
+
+	visited := map[string]struct{}{from: {}}
+	queue := []node{{currency: from}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		for _, e := range g.edges[n.currency] {
+			if _, ok := visited[e.To]; ok {
+				continue
+			}
+
+			if withPrices && !g.usable(e) {
+				continue
+			}
+
+			path := make([]conversionEdge, len(n.path), len(n.path)+1)
+			copy(path, n.path)
+			path = append(path, e)
+
+			if e.To == to {
+				return path, true
+			}
+
+			visited[e.To] = struct{}{}
+			queue = append(queue, node{currency: e.To, path: path})
+		}
+	}
+
+	return nil, false
+}
+
+// Symbols returns the market symbols that are required to convert the currency into the target currency,
+// it can be used for querying the tickers before the conversion.
+func (g *ConversionGraph) Symbols(from, to string) ([]string, bool) {
+	path, ok := g.route(from, to, false)
+	if !ok {
+		return nil, false
+	}
+
+	var symbols []string
+	for _, e := range path {
+		if len(e.Symbol) > 0 {
+			symbols = append(symbols, e.Symbol)
+		}
+	}
+
+	return symbols, true
+}
+
+// Rate returns the conversion rate from the currency to the target currency
+func (g *ConversionGraph) Rate(from, to string) (fixedpoint.Value, bool) {
+	return g.Convert(fixedpoint.One, from, to)
+}
+
+// Convert converts the amount of the currency into the target currency,
+// the amount is converted hop by hop to keep the precision of the fixed-point value.
+func (g *ConversionGraph) Convert(amount fixedpoint.Value, from, to string) (fixedpoint.Value, bool) {
+	path, ok := g.route(from, to, true)
+	if !ok {
+		return fixedpoint.Zero, false
+	}
+
+	for _, e := range path {
+		if len(e.Symbol) == 0 {
+			continue
+		}
+
+		price := g.prices[e.Symbol]
+		if e.Inverse {
+			amount = amount.Div(price)
+		} else {
+			amount = amount.Mul(price)
+		}
+	}
+
+	return amount, true
+}
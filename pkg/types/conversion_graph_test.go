@@ -0,0 +1,86 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func newTestConversionGraph() *ConversionGraph {
+	graph := NewConversionGraph()
+	graph.AddMarkets(MarketMap{
+		"ETHBTC":  {Symbol: "ETHBTC", BaseCurrency: "ETH", QuoteCurrency: "BTC"},
+		"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		"USDTTWD": {Symbol: "USDTTWD", BaseCurrency: "USDT", QuoteCurrency: "TWD"},
+	})
+	graph.AddUSDPegs()
+	graph.SetPrices(map[string]fixedpoint.Value{
+		"ETHBTC":  fixedpoint.NewFromFloat(0.05),
+		"BTCUSDT": fixedpoint.NewFromInt(40000),
+		"USDTTWD": fixedpoint.NewFromInt(30),
+	})
+	return graph
+}
+
+func TestConversionGraph_Convert(t *testing.T) {
+	graph := newTestConversionGraph()
+
+	symbols, ok := graph.Symbols("ETH", "TWD")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"ETHBTC", "BTCUSDT", "USDTTWD"}, symbols)
+
+	v, ok := graph.Convert(fixedpoint.NewFromInt(2), "ETH", "TWD")
+	assert.True(t, ok)
+	assert.Equal(t, "120000", v.String())
+
+	// inverse route: TWD -> USDT -> BTC
+	v, ok = graph.Convert(fixedpoint.NewFromInt(1200000), "TWD", "BTC")
+	assert.True(t, ok)
+	assert.Equal(t, "1", v.String())
+
+	// pegged USD
+	v, ok = graph.Convert(fixedpoint.NewFromInt(1), "BTC", "USD")
+	assert.True(t, ok)
+	assert.Equal(t, "40000", v.String())
+
+	v, ok = graph.Convert(fixedpoint.NewFromInt(3), "ETH", "ETH")
+	assert.True(t, ok)
+	assert.Equal(t, "3", v.String())
+
+	_, ok = graph.Convert(fixedpoint.NewFromInt(1), "DOGE", "TWD")
+	assert.False(t, ok)
+}
+
+func TestConversionGraph_MissingPrice(t *testing.T) {
+	graph := NewConversionGraph()
+	graph.AddMarket(Market{Symbol: "ETHUSDT", BaseCurrency: "ETH", QuoteCurrency: "USDT"})
+
+	symbols, ok := graph.Symbols("ETH", "USDT")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"ETHUSDT"}, symbols)
+
+	_, ok = graph.Rate("ETH", "USDT")
+	assert.False(t, ok, "the route without the price should not be used")
+}
+
+func TestAssetMap_Convert(t *testing.T) {
+	balances := BalanceMap{
+		"ETH":  {Currency: "ETH", Available: fixedpoint.NewFromInt(2)},
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromInt(1000)},
+	}
+
+	assets := balances.Assets(map[string]fixedpoint.Value{}, time.Now())
+	assets = assets.Convert(newTestConversionGraph(), "TWD")
+
+	assert.Equal(t, "TWD", assets.ReportingCurrency())
+	assert.Equal(t, "120000", assets["ETH"].InReportingCurrency.String())
+	assert.Equal(t, "30000", assets["USDT"].InReportingCurrency.String())
+	assert.Equal(t, "150000", assets.InReportingCurrency().String())
+
+	// the missing usd value is routed through the graph
+	assert.Equal(t, "4000", assets["ETH"].InUSD.String())
+	assert.Equal(t, "0.1", assets["ETH"].InBTC.String())
+}
@@ -22,6 +22,24 @@ var USD = wrapper{accounting.Accounting{Symbol: "$ ", Precision: 2}}
 var BTC = wrapper{accounting.Accounting{Symbol: "BTC ", Precision: 8}}
 var BNB = wrapper{accounting.Accounting{Symbol: "BNB ", Precision: 4}}
 
+// FormatMoney formats the amount in the given currency, the fiat currencies are formatted with 2 digits.
+func FormatMoney(currency string, v fixedpoint.Value) string {
+	switch currency {
+	case "USD":
+		return USD.FormatMoney(v)
+	case "BTC":
+		return BTC.FormatMoney(v)
+	}
+
+	precision := 8
+	if IsFiatCurrency(currency) {
+		precision = 2
+	}
+
+	w := wrapper{accounting.Accounting{Symbol: currency + " ", Precision: precision}}
+	return w.FormatMoney(v)
+}
+
 var FiatCurrencies = []string{"USDC", "USDT", "USD", "TWD", "EUR", "GBP", "BUSD"}
 
 func IsFiatCurrency(currency string) bool {
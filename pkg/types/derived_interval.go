@@ -0,0 +1,151 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// DerivedKLineType is the type of the klines that are derived from the other klines or the market trades
+type DerivedKLineType string
+
+const (
+	// DerivedKLineHeikinAshi is the heikin-ashi klines of the source interval
+	DerivedKLineHeikinAshi DerivedKLineType = "heikinashi"
+
+	// DerivedKLineRenko is the renko bricks of the close price of the source interval
+	DerivedKLineRenko DerivedKLineType = "renko"
+
+	// DerivedKLineRange is the range bars of the market trades, a bar is closed when its price range reaches the size
+	DerivedKLineRange DerivedKLineType = "range"
+
+	// DerivedKLineVolume is the volume bars of the market trades, a bar is closed when its base volume reaches the size
+	DerivedKLineVolume DerivedKLineType = "volume"
+
+	// DerivedKLineTick is the tick bars of the market trades, a bar is closed when its number of trades reaches the size
+	DerivedKLineTick DerivedKLineType = "tick"
+)
+
+// DerivedInterval is the interval of the derived klines.
+// A derived interval is formatted as "<type>:<parameters>" and can be subscribed like a normal interval:
+//
+//   heikinashi:1m    the heikin-ashi klines of the 1m klines
+//   renko:1m:10      the renko bricks of the 1m close price with the box size 10
+//   renko:1m:atr14   the renko bricks of the 1m close price with the box size of ATR(14) of the 1m klines
+//   range:10         the range bars of the market trades with the price range 10
+//   volume:100       the volume bars of every 100 base volume
+//   tick:500         the tick bars of every 500 market trades
+type DerivedInterval struct {
+	Type DerivedKLineType
+
+	// Source is the interval of the source klines, it's empty for the bars built from the market trades
+	Source Interval
+
+	// Size is the box size of the renko bricks, or the price range, the volume and the number of trades of the bars
+	Size fixedpoint.Value
+
+	// ATRWindow is the ATR window of the renko box size, the box size follows the ATR if it's set
+	ATRWindow int
+}
+
+// IsDerived returns true if the interval is a derived interval
+func (i Interval) IsDerived() bool {
+	return strings.Contains(string(i), ":")
+}
+
+// FromTrades returns true if the klines are built from the market trades
+func (d DerivedInterval) FromTrades() bool {
+	return d.Source == ""
+}
+
+// Interval returns the formatted interval of the derived klines
+func (d DerivedInterval) Interval() Interval {
+	switch d.Type {
+	case DerivedKLineHeikinAshi:
+		return Interval(fmt.Sprintf("%s:%s", d.Type, d.Source))
+
+	case DerivedKLineRenko:
+		if d.ATRWindow > 0 {
+			return Interval(fmt.Sprintf("%s:%s:atr%d", d.Type, d.Source, d.ATRWindow))
+		}
+		return Interval(fmt.Sprintf("%s:%s:%s", d.Type, d.Source, d.Size.String()))
+	}
+
+	return Interval(fmt.Sprintf("%s:%s", d.Type, d.Size.String()))
+}
+
+// ParseDerivedInterval parses the derived interval like "heikinashi:1m" or "renko:1m:atr14"
+func ParseDerivedInterval(interval Interval) (d DerivedInterval, err error) {
+	parts := strings.Split(string(interval), ":")
+	if len(parts) < 2 {
+		return d, fmt.Errorf("%s is not a derived interval", interval)
+	}
+
+	d.Type = DerivedKLineType(parts[0])
+	switch d.Type {
+	case DerivedKLineHeikinAshi:
+		if len(parts) != 2 {
+			return d, fmt.Errorf("invalid heikin-ashi interval %s, expecting heikinashi:<interval>", interval)
+		}
+
+		d.Source = Interval(parts[1])
+
+	case DerivedKLineRenko:
+		if len(parts) != 3 {
+			return d, fmt.Errorf("invalid renko interval %s, expecting renko:<interval>:<box size>", interval)
+		}
+
+		d.Source = Interval(parts[1])
+		if strings.HasPrefix(parts[2], "atr") {
+			d.ATRWindow, err = strconv.Atoi(strings.TrimPrefix(parts[2], "atr"))
+			if err != nil || d.ATRWindow <= 0 {
+				return d, fmt.Errorf("invalid renko atr window %s", parts[2])
+			}
+			return d, nil
+		}
+
+		if d.Size, err = parseDerivedIntervalSize(parts[2]); err != nil {
+			return d, err
+		}
+
+	case DerivedKLineRange, DerivedKLineVolume, DerivedKLineTick:
+		if len(parts) != 2 {
+			return d, fmt.Errorf("invalid %s interval %s, expecting %s:<size>", d.Type, interval, d.Type)
+		}
+
+		if d.Size, err = parseDerivedIntervalSize(parts[1]); err != nil {
+			return d, err
+		}
+
+	default:
+		return d, fmt.Errorf("unsupported derived kline type %s", d.Type)
+	}
+
+	if !d.FromTrades() {
+		if _, ok := SupportedIntervals[d.Source]; !ok {
+			return d, fmt.Errorf("unsupported source interval %s of %s", d.Source, interval)
+		}
+	}
+
+	return d, nil
+}
+
+func parseDerivedIntervalSize(s string) (fixedpoint.Value, error) {
+	// the dnum implementation of fixedpoint panics with the invalid numbers, so the number is checked first
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	size, err := fixedpoint.NewFromString(s)
+	if err != nil {
+		return size, err
+	}
+
+	if size.Sign() <= 0 {
+		return size, fmt.Errorf("the size of the derived interval must be positive, got %s", s)
+	}
+
+	return size, nil
+}
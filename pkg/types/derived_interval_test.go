@@ -0,0 +1,57 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestParseDerivedInterval(t *testing.T) {
+	tests := []struct {
+		interval Interval
+		want     DerivedInterval
+	}{
+		{"heikinashi:1m", DerivedInterval{Type: DerivedKLineHeikinAshi, Source: Interval1m}},
+		{"renko:1h:10", DerivedInterval{Type: DerivedKLineRenko, Source: Interval1h, Size: fixedpoint.NewFromInt(10)}},
+		{"renko:5m:atr14", DerivedInterval{Type: DerivedKLineRenko, Source: Interval5m, ATRWindow: 14}},
+		{"range:0.5", DerivedInterval{Type: DerivedKLineRange, Size: fixedpoint.NewFromFloat(0.5)}},
+		{"volume:100", DerivedInterval{Type: DerivedKLineVolume, Size: fixedpoint.NewFromInt(100)}},
+		{"tick:500", DerivedInterval{Type: DerivedKLineTick, Size: fixedpoint.NewFromInt(500)}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.interval), func(t *testing.T) {
+			assert.True(t, test.interval.IsDerived())
+
+			derived, err := ParseDerivedInterval(test.interval)
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.want, derived)
+				assert.Equal(t, test.interval, derived.Interval())
+				assert.Equal(t, test.want.Source == "", derived.FromTrades())
+			}
+		})
+	}
+}
+
+func TestParseDerivedInterval_Invalid(t *testing.T) {
+	assert.False(t, Interval1m.IsDerived())
+
+	for _, interval := range []Interval{
+		"1m",
+		"heikinashi:2m",
+		"heikinashi:1m:10",
+		"renko:1m",
+		"renko:1m:0",
+		"renko:1m:atr",
+		"renko:1m:atr-3",
+		"range:-1",
+		"volume:abc",
+		"tick:1m:10",
+		"kagi:1m",
+	} {
+		_, err := ParseDerivedInterval(interval)
+		assert.Error(t, err, interval)
+	}
+}
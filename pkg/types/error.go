@@ -1,6 +1,13 @@
 package types
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOrderAmendNotSupported is returned by ExchangeOrderAmendService when the order can not be amended natively,
+// e.g., the account mode or the order type does not support it, callers may fall back to cancel-replace.
+var ErrOrderAmendNotSupported = errors.New("order amend is not supported")
 
 type OrderError struct {
 	error error
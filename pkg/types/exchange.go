@@ -82,6 +82,12 @@ type ExchangeOrderQueryService interface {
 	QueryOrder(ctx context.Context, q OrderQuery) (*Order, error)
 }
 
+// ExchangeOrderAmendService provides an interface for amending the price or the quantity of an existing order
+// without canceling it first, the order is identified by its order ID or client order ID
+type ExchangeOrderAmendService interface {
+	AmendOrder(ctx context.Context, amend OrderAmend) (*Order, error)
+}
+
 type ExchangeTradeService interface {
 	QueryAccount(ctx context.Context) (*Account, error)
 
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"math"
 
 	"gonum.org/v1/gonum/floats"
@@ -26,8 +27,13 @@ func (s Float64Slice) Min() float64 {
 	return floats.Min(s)
 }
 
+// Sum adds the values in order, the SIMD sum of gonum depends on the memory alignment of the slice,
+// which makes the indicators restored from a snapshot differ from the running ones in the last bits
 func (s Float64Slice) Sum() (sum float64) {
-	return floats.Sum(s)
+	for _, v := range s {
+		sum += v
+	}
+	return sum
 }
 
 func (s Float64Slice) Mean() (mean float64) {
@@ -141,4 +147,42 @@ func (a Float64Slice) Addr() *Float64Slice {
 	return &a
 }
 
+// MarshalJSON encodes the NaN and the infinite values as null, since they are not supported by JSON
+func (s Float64Slice) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+
+	values := make([]*float64, len(s))
+	for i := range s {
+		if !math.IsNaN(s[i]) && !math.IsInf(s[i], 0) {
+			values[i] = &s[i]
+		}
+	}
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON decodes the null values as NaN
+func (s *Float64Slice) UnmarshalJSON(data []byte) error {
+	var values []*float64
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	if values == nil {
+		*s = nil
+		return nil
+	}
+
+	*s = make(Float64Slice, len(values))
+	for i, v := range values {
+		if v == nil {
+			(*s)[i] = math.NaN()
+		} else {
+			(*s)[i] = *v
+		}
+	}
+	return nil
+}
+
 var _ Series = Float64Slice([]float64{}).Addr()
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloat64Slice_JSON(t *testing.T) {
+	data, err := json.Marshal(Float64Slice{1.5, math.NaN(), math.Inf(1), -2})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[1.5, null, null, -2]`, string(data))
+
+	var s Float64Slice
+	assert.NoError(t, json.Unmarshal(data, &s))
+	if assert.Len(t, s, 4) {
+		assert.Equal(t, 1.5, s[0])
+		assert.True(t, math.IsNaN(s[1]))
+		assert.True(t, math.IsNaN(s[2]))
+		assert.Equal(t, -2.0, s[3])
+	}
+
+	var empty Float64Slice
+	data, err = json.Marshal(empty)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+	assert.NoError(t, json.Unmarshal(data, &s))
+	assert.Nil(t, s)
+}
+
+func TestFloat64Slice_Sum(t *testing.T) {
+	var s Float64Slice
+	for i := 0; i < 17; i++ {
+		s.Push(0.1 * float64(i) * math.Pi)
+	}
+
+	// the sum of the shifted slice is the same as the sum of its copy
+	for i := 0; i < 4; i++ {
+		shifted := s[i:]
+		copied := append(Float64Slice{}, shifted...)
+		assert.Equal(t, copied.Sum(), shifted.Sum())
+	}
+
+	assert.Equal(t, 0.0, Float64Slice{}.Sum())
+}
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// FundingFee is a funding payment of a perpetual futures position,
+// a positive amount is received by the position holder and a negative amount is paid.
+type FundingFee struct {
+	GID      int64        `json:"gid" db:"gid"`
+	Exchange ExchangeName `json:"exchange" db:"exchange"`
+	Symbol   string       `json:"symbol" db:"symbol"`
+
+	// Asset is the settlement currency of the funding payment
+	Asset  string           `json:"asset" db:"asset"`
+	Amount fixedpoint.Value `json:"amount" db:"amount"`
+
+	TransactionID string `json:"transactionID" db:"txn_id"`
+	Time          Time   `json:"time" db:"time"`
+}
+
+func (f FundingFee) String() string {
+	return fmt.Sprintf("funding fee %s %s %s at %s", f.Symbol, f.Amount.String(), f.Asset, f.Time.Time())
+}
+
+type ExchangeFundingFeeService interface {
+	// QueryFundingFeeHistory queries the funding payments of the symbol, empty symbol means all symbols
+	QueryFundingFeeHistory(ctx context.Context, symbol string, since, until time.Time) ([]FundingFee, error)
+}
@@ -35,13 +35,10 @@ type BoolSeries interface {
 // if limit is given, will only sum first limit numbers (a.Index[0..limit])
 // otherwise will sum all elements
 func Sum(a Series, limit ...int) (sum float64) {
-	l := -1
-	if len(limit) > 0 {
+	l := a.Length()
+	if len(limit) > 0 && limit[0] < l {
 		l = limit[0]
 	}
-	if l < a.Length() {
-		l = a.Length()
-	}
 	for i := 0; i < l; i++ {
 		sum += a.Index(i)
 	}
@@ -52,13 +49,10 @@ func Sum(a Series, limit ...int) (sum float64) {
 // if limit is given, will only calculate the average of first limit numbers (a.Index[0..limit])
 // otherwise will operate on all elements
 func Mean(a Series, limit ...int) (mean float64) {
-	l := -1
-	if len(limit) > 0 {
+	l := a.Length()
+	if len(limit) > 0 && limit[0] < l {
 		l = limit[0]
 	}
-	if l < a.Length() {
-		l = a.Length()
-	}
 	return Sum(a, l) / float64(l)
 }
 
@@ -437,13 +431,10 @@ func Dot(a interface{}, b interface{}, limit ...int) float64 {
 // if limit is given, will only take the first limit numbers (a.Index[0..limit])
 // otherwise will operate on all elements
 func ToArray(a Series, limit ...int) (result []float64) {
-	l := -1
-	if len(limit) > 0 {
+	l := a.Length()
+	if len(limit) > 0 && limit[0] < l {
 		l = limit[0]
 	}
-	if l < a.Length() {
-		l = a.Length()
-	}
 	result = make([]float64, l, l)
 	for i := 0; i < l; i++ {
 		result[i] = a.Index(i)
@@ -457,13 +448,10 @@ func ToArray(a Series, limit ...int) (result []float64) {
 //
 // notice that the return type is a Float64Slice, which implements the Series interface
 func ToReverseArray(a Series, limit ...int) (result Float64Slice) {
-	l := -1
-	if len(limit) > 0 {
+	l := a.Length()
+	if len(limit) > 0 && limit[0] < l {
 		l = limit[0]
 	}
-	if l < a.Length() {
-		l = a.Length()
-	}
 	result = make([]float64, l, l)
 	for i := 0; i < l; i++ {
 		result[l-i-1] = a.Index(i)
@@ -508,15 +496,3 @@ func Change(a Series, offset ...int) Series {
 
 	return &ChangeResult{a, o}
 }
-
-func Stdev(a Series, length int) float64 {
-	avg := Mean(a, length)
-	s := .0
-	for i := 0; i < length; i++ {
-		diff := a.Index(i) - avg
-		s += diff * diff
-	}
-	return math.Sqrt(s / float64(length))
-}
-
-// TODO: ta.linreg
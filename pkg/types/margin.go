@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"time"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 )
@@ -139,3 +140,23 @@ type IsolatedUserAsset struct {
 	RepayEnabled  bool             `json:"repayEnabled"`
 	TotalAsset    fixedpoint.Value `json:"totalAsset"`
 }
+
+// MarginInterest is the interest charged for the borrowed margin asset
+type MarginInterest struct {
+	GID      int64        `json:"gid" db:"gid"`
+	Exchange ExchangeName `json:"exchange" db:"exchange"`
+	Asset    string       `json:"asset" db:"asset"`
+
+	// IsolatedSymbol is the symbol of the isolated margin account, it's empty for the cross margin account
+	IsolatedSymbol string `json:"isolatedSymbol" db:"isolated_symbol"`
+
+	Principal    fixedpoint.Value `json:"principal" db:"principal"`
+	Interest     fixedpoint.Value `json:"interest" db:"interest"`
+	InterestRate fixedpoint.Value `json:"interestRate" db:"interest_rate"`
+	Time         Time             `json:"time" db:"time"`
+}
+
+type MarginInterestHistoryService interface {
+	// QueryInterestHistory queries the interest charged for the borrowed asset, empty asset means all assets
+	QueryInterestHistory(ctx context.Context, asset string, since, until time.Time) ([]MarginInterest, error)
+}
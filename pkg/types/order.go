@@ -198,6 +198,39 @@ type OrderQuery struct {
 	ClientOrderID string
 }
 
+// OrderAmend describes the modification of an existing order.
+// Price and Quantity are optional, a zero value means the field is kept unchanged.
+// Quantity is the new total quantity of the order, including the executed quantity.
+type OrderAmend struct {
+	Order Order
+
+	Price    fixedpoint.Value
+	Quantity fixedpoint.Value
+}
+
+// NewPrice returns the price of the amended order
+func (a OrderAmend) NewPrice() fixedpoint.Value {
+	if a.Price.IsZero() {
+		return a.Order.Price
+	}
+
+	return a.Price
+}
+
+// NewQuantity returns the total quantity of the amended order
+func (a OrderAmend) NewQuantity() fixedpoint.Value {
+	if a.Quantity.IsZero() {
+		return a.Order.Quantity
+	}
+
+	return a.Quantity
+}
+
+// RemainingQuantity returns the quantity that is not executed yet after the amendment
+func (a OrderAmend) RemainingQuantity() fixedpoint.Value {
+	return a.NewQuantity().Sub(a.Order.ExecutedQuantity)
+}
+
 type Order struct {
 	SubmitOrder
 
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
@@ -50,6 +51,9 @@ type Position struct {
 	// TotalFee stores the fee currency -> total fee quantity
 	TotalFee map[string]fixedpoint.Value `json:"totalFee" db:"-"`
 
+	// priceLookup converts the fees paid in other currencies into the quote currency
+	priceLookup PriceLookup
+
 	ChangedAt time.Time `json:"changedAt,omitempty" db:"changed_at"`
 
 	Strategy           string `json:"strategy,omitempty" db:"strategy"`
@@ -57,6 +61,16 @@ type Position struct {
 
 	AccumulatedProfit fixedpoint.Value `json:"accumulatedProfit,omitempty" db:"accumulated_profit"`
 
+	// AccumulatedFundingFee is the received (positive) or paid (negative) funding payments in the quote currency
+	AccumulatedFundingFee fixedpoint.Value `json:"accumulatedFundingFee,omitempty" db:"-"`
+
+	// AccumulatedInterest is the interest paid for the borrowed margin assets in the quote currency
+	AccumulatedInterest fixedpoint.Value `json:"accumulatedInterest,omitempty" db:"-"`
+
+	// FundingFeeTime and InterestTime are the time of the latest funding fee and interest added into the position
+	FundingFeeTime Time `json:"fundingFeeTime,omitempty" db:"-"`
+	InterestTime   Time `json:"interestTime,omitempty" db:"-"`
+
 	sync.Mutex
 }
 
@@ -109,6 +123,7 @@ func (p *Position) NewProfit(trade Trade, profit, netProfit fixedpoint.Value) Pr
 		Quantity:      trade.Quantity,
 		QuoteQuantity: trade.QuoteQuantity,
 		// FeeInUSD:           0,
+		FeeInQuote:  p.feeInQuote(trade),
 		Fee:         trade.Fee,
 		FeeCurrency: trade.FeeCurrency,
 
@@ -203,6 +218,142 @@ func (p *Position) addTradeFee(trade Trade) {
 	p.TotalFee[trade.FeeCurrency] = p.TotalFee[trade.FeeCurrency].Add(trade.Fee)
 }
 
+// SetPriceLookup sets the price lookup for converting the fees paid in other currencies (e.g., BNB or MAX)
+// into the quote currency at the trade time. Without the price lookup, the fee rates are used for approximating the fee.
+// The lookup is called when the trades are processed, so it should not block on the network or the database,
+// e.g., the live price lookup of the session.
+func (p *Position) SetPriceLookup(lookup PriceLookup) {
+	p.priceLookup = lookup
+}
+
+// FeeInQuote converts the trade fee into the quote currency,
+// false is returned if the fee currency can not be converted.
+func (p *Position) FeeInQuote(trade Trade) (fixedpoint.Value, bool) {
+	switch trade.FeeCurrency {
+	case p.QuoteCurrency:
+		return trade.Fee, true
+
+	case p.BaseCurrency:
+		return trade.Fee.Mul(trade.Price), true
+	}
+
+	if trade.Fee.IsZero() {
+		return fixedpoint.Zero, true
+	}
+
+	if p.priceLookup == nil {
+		return fixedpoint.Zero, false
+	}
+
+	price, err := p.priceLookup.QueryPrice(trade.FeeCurrency, p.QuoteCurrency, trade.Time.Time())
+	if err != nil {
+		log.WithError(err).Warnf("can not convert the %s fee of trade %d into %s", trade.FeeCurrency, trade.ID, p.QuoteCurrency)
+		return fixedpoint.Zero, false
+	}
+
+	return trade.Fee.Mul(price), true
+}
+
+// feeInQuote returns the converted fee, or the fee approximated by the fee rates
+func (p *Position) feeInQuote(trade Trade) fixedpoint.Value {
+	if fee, ok := p.FeeInQuote(trade); ok {
+		return fee
+	}
+
+	return p.approximateFee(trade)
+}
+
+// approximateFee approximates the fee in the quote currency by the fee rates
+func (p *Position) approximateFee(trade Trade) fixedpoint.Value {
+	feeRate := p.FeeRate
+	if p.ExchangeFeeRates != nil {
+		feeRate = nil
+		if exchangeFee, ok := p.ExchangeFeeRates[trade.Exchange]; ok {
+			feeRate = &exchangeFee
+		}
+	}
+
+	if feeRate == nil {
+		return fixedpoint.Zero
+	}
+
+	if trade.IsMaker {
+		return feeRate.MakerFeeRate.Mul(trade.QuoteQuantity)
+	}
+
+	return feeRate.TakerFeeRate.Mul(trade.QuoteQuantity)
+}
+
+// valueInQuote converts the amount of the currency into the quote currency at the given time,
+// the average cost is used for the base currency if the price lookup is not available.
+func (p *Position) valueInQuote(currency string, amount fixedpoint.Value, t time.Time) (fixedpoint.Value, bool) {
+	if currency == p.QuoteCurrency || amount.IsZero() {
+		return amount, true
+	}
+
+	if p.priceLookup != nil {
+		price, err := p.priceLookup.QueryPrice(currency, p.QuoteCurrency, t)
+		if err == nil {
+			return amount.Mul(price), true
+		}
+
+		log.WithError(err).Warnf("can not convert %s %s into %s", amount.String(), currency, p.QuoteCurrency)
+	}
+
+	if currency == p.BaseCurrency && p.AverageCost.Sign() > 0 {
+		return amount.Mul(p.AverageCost), true
+	}
+
+	return fixedpoint.Zero, false
+}
+
+// AddFundingFee accumulates the funding payment of the position symbol,
+// the amount converted into the quote currency is returned, false is returned if the fee is not added.
+func (p *Position) AddFundingFee(fee FundingFee) (fixedpoint.Value, bool) {
+	if fee.Symbol != p.Symbol {
+		return fixedpoint.Zero, false
+	}
+
+	amount, ok := p.valueInQuote(fee.Asset, fee.Amount, fee.Time.Time())
+	if !ok {
+		return fixedpoint.Zero, false
+	}
+
+	p.Lock()
+	p.AccumulatedFundingFee = p.AccumulatedFundingFee.Add(amount)
+	if fee.Time.After(p.FundingFeeTime.Time()) {
+		p.FundingFeeTime = fee.Time
+	}
+	p.Unlock()
+	return amount, true
+}
+
+// AddInterest accumulates the interest of the isolated margin account of the position symbol.
+// The interest of the cross margin account is shared by all the positions of the account, so it's not attributed to a position.
+// The interest converted into the quote currency is returned, false is returned if the interest is not added.
+func (p *Position) AddInterest(interest MarginInterest) (fixedpoint.Value, bool) {
+	if interest.IsolatedSymbol != p.Symbol {
+		return fixedpoint.Zero, false
+	}
+
+	if interest.Asset != p.BaseCurrency && interest.Asset != p.QuoteCurrency {
+		return fixedpoint.Zero, false
+	}
+
+	amount, ok := p.valueInQuote(interest.Asset, interest.Interest, interest.Time.Time())
+	if !ok {
+		return fixedpoint.Zero, false
+	}
+
+	p.Lock()
+	p.AccumulatedInterest = p.AccumulatedInterest.Add(amount)
+	if interest.Time.After(p.InterestTime.Time()) {
+		p.InterestTime = interest.Time
+	}
+	p.Unlock()
+	return amount, true
+}
+
 func (p *Position) Reset() {
 	p.Base = fixedpoint.Zero
 	p.Quote = fixedpoint.Zero
@@ -348,21 +499,7 @@ func (p *Position) AddTrade(td Trade) (profit fixedpoint.Value, netProfit fixedp
 		quoteQuantity = quoteQuantity.Sub(fee)
 
 	default:
-		if p.ExchangeFeeRates != nil {
-			if exchangeFee, ok := p.ExchangeFeeRates[td.Exchange]; ok {
-				if td.IsMaker {
-					feeInQuote = feeInQuote.Add(exchangeFee.MakerFeeRate.Mul(quoteQuantity))
-				} else {
-					feeInQuote = feeInQuote.Add(exchangeFee.TakerFeeRate.Mul(quoteQuantity))
-				}
-			}
-		} else if p.FeeRate != nil {
-			if td.IsMaker {
-				feeInQuote = feeInQuote.Add(p.FeeRate.MakerFeeRate.Mul(quoteQuantity))
-			} else {
-				feeInQuote = feeInQuote.Add(p.FeeRate.TakerFeeRate.Mul(quoteQuantity))
-			}
-		}
+		feeInQuote = p.feeInQuote(td)
 	}
 
 	p.Lock()
@@ -1,7 +1,9 @@
 package types
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -273,3 +275,138 @@ func TestPosition(t *testing.T) {
 		})
 	}
 }
+
+type priceLookupFunc func(currency, quoteCurrency string, t time.Time) (fixedpoint.Value, error)
+
+func (f priceLookupFunc) QueryPrice(currency, quoteCurrency string, t time.Time) (fixedpoint.Value, error) {
+	return f(currency, quoteCurrency, t)
+}
+
+func TestPosition_PriceLookup(t *testing.T) {
+	t0 := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	pos := NewPosition("BTCUSDT", "BTC", "USDT")
+	pos.SetFeeRate(ExchangeFee{
+		MakerFeeRate: fixedpoint.NewFromFloat(0.001),
+		TakerFeeRate: fixedpoint.NewFromFloat(0.001),
+	})
+	pos.SetPriceLookup(priceLookupFunc(func(currency, quoteCurrency string, t time.Time) (fixedpoint.Value, error) {
+		if currency != "BNB" || quoteCurrency != "USDT" {
+			return fixedpoint.Zero, errors.New("price not found")
+		}
+
+		if t.After(t0) {
+			return fixedpoint.NewFromInt(500), nil
+		}
+
+		return fixedpoint.NewFromInt(400), nil
+	}))
+
+	pos.AddTrade(Trade{
+		Price:         fixedpoint.NewFromInt(10000),
+		Quantity:      fixedpoint.One,
+		QuoteQuantity: fixedpoint.NewFromInt(10000),
+		Symbol:        "BTCUSDT",
+		Side:          SideTypeBuy,
+		Fee:           fixedpoint.NewFromFloat(0.01),
+		FeeCurrency:   "BNB",
+		Time:          Time(t0),
+	})
+	assert.Equal(t, "10004", pos.ApproximateAverageCost.String())
+
+	sellTrade := Trade{
+		Price:         fixedpoint.NewFromInt(11000),
+		Quantity:      fixedpoint.One,
+		QuoteQuantity: fixedpoint.NewFromInt(11000),
+		Symbol:        "BTCUSDT",
+		Side:          SideTypeSell,
+		Fee:           fixedpoint.NewFromFloat(0.01),
+		FeeCurrency:   "BNB",
+		Time:          Time(t0.Add(time.Hour)),
+	}
+
+	profit, netProfit, madeProfit := pos.AddTrade(sellTrade)
+	assert.True(t, madeProfit)
+	assert.Equal(t, "1000", profit.String())
+	assert.Equal(t, "991", netProfit.String())
+
+	p := pos.NewProfit(sellTrade, profit, netProfit)
+	assert.Equal(t, "5", p.FeeInQuote.String())
+
+	// the fee rate is used if the fee currency can not be converted
+	fee, ok := pos.FeeInQuote(Trade{Fee: fixedpoint.One, FeeCurrency: "MAX", Time: Time(t0)})
+	assert.False(t, ok)
+	assert.Equal(t, "0", fee.String())
+
+	p = pos.NewProfit(Trade{QuoteQuantity: fixedpoint.NewFromInt(1000), Fee: fixedpoint.One, FeeCurrency: "MAX"}, fixedpoint.Zero, fixedpoint.Zero)
+	assert.Equal(t, "1", p.FeeInQuote.String())
+
+	// the base currency fee is converted by the trade price
+	fee, ok = pos.FeeInQuote(Trade{Price: fixedpoint.NewFromInt(10000), Fee: fixedpoint.NewFromFloat(0.001), FeeCurrency: "BTC"})
+	assert.True(t, ok)
+	assert.Equal(t, "10", fee.String())
+}
+
+func TestPosition_FundingFeeAndInterest(t *testing.T) {
+	t0 := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	pos := NewPosition("BTCUSDT", "BTC", "USDT")
+	pos.AddTrade(Trade{
+		Price:         fixedpoint.NewFromInt(10000),
+		Quantity:      fixedpoint.One,
+		QuoteQuantity: fixedpoint.NewFromInt(10000),
+		Symbol:        "BTCUSDT",
+		Side:          SideTypeBuy,
+		Time:          Time(t0),
+	})
+
+	amount, ok := pos.AddFundingFee(FundingFee{Symbol: "BTCUSDT", Asset: "USDT", Amount: fixedpoint.NewFromFloat(-1.5), Time: Time(t0)})
+	assert.True(t, ok)
+	assert.Equal(t, "-1.5", amount.String())
+
+	_, ok = pos.AddFundingFee(FundingFee{Symbol: "ETHUSDT", Asset: "USDT", Amount: fixedpoint.One, Time: Time(t0)})
+	assert.False(t, ok)
+	assert.Equal(t, "-1.5", pos.AccumulatedFundingFee.String())
+
+	// the base currency interest is converted by the average cost without the price lookup
+	amount, ok = pos.AddInterest(MarginInterest{Asset: "BTC", IsolatedSymbol: "BTCUSDT", Interest: fixedpoint.NewFromFloat(0.0001), Time: Time(t0)})
+	assert.True(t, ok)
+	assert.Equal(t, "1", amount.String())
+
+	_, ok = pos.AddInterest(MarginInterest{Asset: "USDT", IsolatedSymbol: "ETHUSDT", Interest: fixedpoint.One, Time: Time(t0)})
+	assert.False(t, ok)
+
+	_, ok = pos.AddInterest(MarginInterest{Asset: "BNB", IsolatedSymbol: "BTCUSDT", Interest: fixedpoint.One, Time: Time(t0)})
+	assert.False(t, ok)
+
+	pos.SetPriceLookup(priceLookupFunc(func(currency, quoteCurrency string, t time.Time) (fixedpoint.Value, error) {
+		return fixedpoint.NewFromInt(20000), nil
+	}))
+
+	amount, ok = pos.AddInterest(MarginInterest{Asset: "BTC", IsolatedSymbol: "BTCUSDT", Interest: fixedpoint.NewFromFloat(0.0001), Time: Time(t0)})
+	assert.True(t, ok)
+	assert.Equal(t, "2", amount.String())
+	assert.Equal(t, "3", pos.AccumulatedInterest.String())
+
+	stats := &ProfitStats{}
+	stats.AddFundingFee(fixedpoint.NewFromFloat(-1.5))
+	stats.AddInterest(fixedpoint.NewFromInt(3))
+	assert.Equal(t, "-4.5", stats.AccumulatedNetProfit.String())
+	assert.Equal(t, "-4.5", stats.TodayNetProfit.String())
+	assert.Equal(t, "3", stats.AccumulatedInterest.String())
+}
+
+func TestPosition_AddInterest_SharedQuoteCurrency(t *testing.T) {
+	t0 := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	btc := NewPosition("BTCUSDT", "BTC", "USDT")
+	eth := NewPosition("ETHUSDT", "ETH", "USDT")
+
+	// the cross margin interest is not counted once per USDT position
+	cross := MarginInterest{Asset: "USDT", Interest: fixedpoint.One, Time: Time(t0)}
+	isolated := MarginInterest{Asset: "USDT", IsolatedSymbol: "BTCUSDT", Interest: fixedpoint.NewFromInt(2), Time: Time(t0)}
+	for _, interest := range []MarginInterest{cross, isolated} {
+		btc.AddInterest(interest)
+		eth.AddInterest(interest)
+	}
+
+	assert.Equal(t, "2", btc.AccumulatedInterest.String())
+	assert.Equal(t, "0", eth.AccumulatedInterest.String())
+}
@@ -0,0 +1,13 @@
+package types
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// PriceLookup looks up the price of the currency in the quote currency at the given time,
+// it's used for converting the fees and the rewards paid in other currencies.
+type PriceLookup interface {
+	QueryPrice(currency, quoteCurrency string, t time.Time) (fixedpoint.Value, error)
+}
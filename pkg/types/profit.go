@@ -47,7 +47,11 @@ type Profit struct {
 
 	// FeeInUSD is the summed fee of this profit,
 	// you will need to convert the trade fee into USD since the fee currencies can be different.
-	FeeInUSD    fixedpoint.Value `json:"feeInUSD" db:"fee_in_usd"`
+	FeeInUSD fixedpoint.Value `json:"feeInUSD" db:"fee_in_usd"`
+
+	// FeeInQuote is the trade fee converted into the quote currency at the trade time
+	FeeInQuote fixedpoint.Value `json:"feeInQuote" db:"fee_in_quote"`
+
 	Fee         fixedpoint.Value `json:"fee" db:"fee"`
 	FeeCurrency string           `json:"feeCurrency" db:"fee_currency"`
 	Exchange    ExchangeName     `json:"exchange" db:"exchange"`
@@ -101,6 +105,14 @@ func (p *Profit) SlackAttachment() slack.Attachment {
 		})
 	}
 
+	if !p.FeeInQuote.IsZero() {
+		fields = append(fields, slack.AttachmentField{
+			Title: "Fee",
+			Value: p.FeeInQuote.String() + " " + p.QuoteCurrency,
+			Short: true,
+		})
+	}
+
 	if !p.FeeInUSD.IsZero() {
 		fields = append(fields, slack.AttachmentField{
 			Title: "Fee In USD",
@@ -211,6 +223,12 @@ type ProfitStats struct {
 	AccumulatedVolume    fixedpoint.Value `json:"accumulatedVolume,omitempty"`
 	AccumulatedSince     int64            `json:"accumulatedSince,omitempty"`
 
+	// AccumulatedFundingFee is the received (positive) or paid (negative) funding payments
+	AccumulatedFundingFee fixedpoint.Value `json:"accumulatedFundingFee,omitempty"`
+
+	// AccumulatedInterest is the interest paid for the borrowed margin assets
+	AccumulatedInterest fixedpoint.Value `json:"accumulatedInterest,omitempty"`
+
 	TodayPnL       fixedpoint.Value `json:"todayPnL,omitempty"`
 	TodayNetProfit fixedpoint.Value `json:"todayNetProfit,omitempty"`
 	TodayProfit    fixedpoint.Value `json:"todayProfit,omitempty"`
@@ -253,6 +271,21 @@ func (s *ProfitStats) AddProfit(profit Profit) {
 	}
 }
 
+// AddFundingFee adds the funding payment in the quote currency into the net profit,
+// a positive amount is received and a negative amount is paid.
+func (s *ProfitStats) AddFundingFee(amount fixedpoint.Value) {
+	s.AccumulatedFundingFee = s.AccumulatedFundingFee.Add(amount)
+	s.AccumulatedNetProfit = s.AccumulatedNetProfit.Add(amount)
+	s.TodayNetProfit = s.TodayNetProfit.Add(amount)
+}
+
+// AddInterest deducts the margin interest in the quote currency from the net profit
+func (s *ProfitStats) AddInterest(amount fixedpoint.Value) {
+	s.AccumulatedInterest = s.AccumulatedInterest.Add(amount)
+	s.AccumulatedNetProfit = s.AccumulatedNetProfit.Sub(amount)
+	s.TodayNetProfit = s.TodayNetProfit.Sub(amount)
+}
+
 func (s *ProfitStats) AddTrade(trade Trade) {
 	if s.IsOver24Hours() {
 		s.ResetToday()
@@ -285,6 +318,8 @@ func (s *ProfitStats) PlainText() string {
 		"Accumulated Profit %s %s\n"+
 		"Accumulated Net Profit %s %s\n"+
 		"Accumulated Trade Loss %s %s\n"+
+		"Accumulated Funding Fee %s %s\n"+
+		"Accumulated Interest %s %s\n"+
 		"Since %s",
 		s.Symbol,
 		s.TodayPnL.String(), s.QuoteCurrency,
@@ -293,6 +328,8 @@ func (s *ProfitStats) PlainText() string {
 		s.AccumulatedPnL.String(), s.QuoteCurrency,
 		s.AccumulatedNetProfit.String(), s.QuoteCurrency,
 		s.AccumulatedLoss.String(), s.QuoteCurrency,
+		s.AccumulatedFundingFee.String(), s.QuoteCurrency,
+		s.AccumulatedInterest.String(), s.QuoteCurrency,
 		since.Format(time.RFC822),
 	)
 }
@@ -366,6 +403,20 @@ func (s *ProfitStats) SlackAttachment() slack.Attachment {
 		})
 	}
 
+	if !s.AccumulatedFundingFee.IsZero() {
+		fields = append(fields, slack.AttachmentField{
+			Title: "Accumulated Funding Fee",
+			Value: pnlSignString(s.AccumulatedFundingFee) + " " + s.QuoteCurrency,
+		})
+	}
+
+	if !s.AccumulatedInterest.IsZero() {
+		fields = append(fields, slack.AttachmentField{
+			Title: "Accumulated Interest",
+			Value: s.AccumulatedInterest.String() + " " + s.QuoteCurrency,
+		})
+	}
+
 	return slack.Attachment{
 		Color:  color,
 		Title:  title,
@@ -0,0 +1,366 @@
+package types
+
+import (
+	"math"
+	"sort"
+)
+
+// The statistics are calculated from the latest length values of the series, a.Index(0) ... a.Index(length - 1).
+// The length is truncated to the length of the series, and 0 is returned if the statistic is undefined,
+// for example, the correlation of the constant values.
+//
+// The Rolling* functions return the lazily evaluated series of the statistics,
+// Index(i) of the result is calculated from the window a.Index(i) ... a.Index(i + window - 1) when it's accessed,
+// so no values are copied or cached.
+
+// Population variance of the latest length values
+func Variance(a Series, length int) float64 {
+	return variance(a, nil, 0, length)
+}
+
+// Population standard deviation of the latest length values
+func Stdev(a Series, length int) float64 {
+	return stdev(a, nil, 0, length)
+}
+
+// The standard score of the latest value in the latest length values
+func ZScore(a Series, length int) float64 {
+	return zScore(a, nil, 0, length)
+}
+
+// Population covariance of the latest length values of a and b
+func Covariance(a Series, b Series, length int) float64 {
+	return covariance(a, b, 0, length)
+}
+
+// Pearson correlation coefficient of the latest length values of a and b
+func Correlation(a Series, b Series, length int) float64 {
+	return correlation(a, b, 0, length)
+}
+
+// The beta of a relative to the benchmark b, cov(a, b) / var(b), of the latest length values.
+// a and b are usually the returns, for example, types.Change(price)
+func Beta(a Series, b Series, length int) float64 {
+	return beta(a, b, 0, length)
+}
+
+// The least squares linear regression of the latest length values against the time,
+// the oldest value is at x = 0 and the latest value is at x = length - 1,
+// so the regression value of the latest value is intercept + slope * (length - 1)
+func LinearRegression(a Series, length int) (slope, intercept, rSquared float64) {
+	return linearRegression(a, 0, length)
+}
+
+// Population skewness of the latest length values
+func Skew(a Series, length int) float64 {
+	return skew(a, nil, 0, length)
+}
+
+// Population excess kurtosis of the latest length values, 0 for the normal distribution
+func Kurtosis(a Series, length int) float64 {
+	return kurtosis(a, nil, 0, length)
+}
+
+// The percentile (0 ~ 100) of the latest length values, linearly interpolated between the closest ranks
+func Percentile(a Series, length int, percentile float64) float64 {
+	return newPercentileFunc(percentile)(a, nil, 0, length)
+}
+
+// The percent rank (0 ~ 100) of the latest value, the percentage of the previous length values
+// that are less than or equal to the latest value, same as ta.percentrank in pine script
+func Rank(a Series, length int) float64 {
+	return rank(a, nil, 0, length)
+}
+
+// RollingResult is the lazily evaluated statistic of the rolling window of the series
+type RollingResult struct {
+	a, b   Series
+	window int
+
+	// span is the number of values needed for one statistic
+	span int
+
+	calculate func(a, b Series, offset, length int) float64
+}
+
+func (r *RollingResult) Last() float64 {
+	return r.Index(0)
+}
+
+func (r *RollingResult) Index(i int) float64 {
+	if i < 0 || i >= r.Length() {
+		return 0
+	}
+	return r.calculate(r.a, r.b, i, r.window)
+}
+
+func (r *RollingResult) Length() int {
+	length := r.a.Length()
+	if r.b != nil && r.b.Length() < length {
+		length = r.b.Length()
+	}
+	if length < r.span {
+		return 0
+	}
+	return length - r.span + 1
+}
+
+var _ Series = &RollingResult{}
+
+func newRollingResult(a, b Series, window int, calculate func(a, b Series, offset, length int) float64) *RollingResult {
+	return &RollingResult{a: a, b: b, window: window, span: window, calculate: calculate}
+}
+
+// Rolling population variance of the series
+func RollingVariance(a Series, window int) Series {
+	return newRollingResult(a, nil, window, variance)
+}
+
+// Rolling population standard deviation of the series
+func RollingStdev(a Series, window int) Series {
+	return newRollingResult(a, nil, window, stdev)
+}
+
+// Rolling standard score of the series
+func RollingZScore(a Series, window int) Series {
+	return newRollingResult(a, nil, window, zScore)
+}
+
+// Rolling population covariance of a and b
+func RollingCovariance(a Series, b Series, window int) Series {
+	return newRollingResult(a, b, window, covariance)
+}
+
+// Rolling Pearson correlation coefficient of a and b
+func RollingCorrelation(a Series, b Series, window int) Series {
+	return newRollingResult(a, b, window, correlation)
+}
+
+// Rolling beta of a relative to the benchmark b
+func RollingBeta(a Series, b Series, window int) Series {
+	return newRollingResult(a, b, window, beta)
+}
+
+// Rolling linear regression of the series, see LinearRegression
+func RollingLinearRegression(a Series, window int) (slope, intercept, rSquared Series) {
+	slope = newRollingResult(a, nil, window, func(a, _ Series, offset, length int) float64 {
+		s, _, _ := linearRegression(a, offset, length)
+		return s
+	})
+	intercept = newRollingResult(a, nil, window, func(a, _ Series, offset, length int) float64 {
+		_, i, _ := linearRegression(a, offset, length)
+		return i
+	})
+	rSquared = newRollingResult(a, nil, window, func(a, _ Series, offset, length int) float64 {
+		_, _, r := linearRegression(a, offset, length)
+		return r
+	})
+	return slope, intercept, rSquared
+}
+
+// Rolling population skewness of the series
+func RollingSkew(a Series, window int) Series {
+	return newRollingResult(a, nil, window, skew)
+}
+
+// Rolling population excess kurtosis of the series
+func RollingKurtosis(a Series, window int) Series {
+	return newRollingResult(a, nil, window, kurtosis)
+}
+
+// Rolling percentile (0 ~ 100) of the series
+func RollingPercentile(a Series, window int, percentile float64) Series {
+	return newRollingResult(a, nil, window, newPercentileFunc(percentile))
+}
+
+// Rolling percent rank (0 ~ 100) of the series, see Rank
+func RollingRank(a Series, window int) Series {
+	r := newRollingResult(a, nil, window, rank)
+	// the rank compares the value with the previous window values
+	r.span = window + 1
+	return r
+}
+
+// available truncates the length to the number of the values after the offset
+func available(a, b Series, offset, length int) int {
+	n := a.Length()
+	if b != nil && b.Length() < n {
+		n = b.Length()
+	}
+	if n-offset < length {
+		length = n - offset
+	}
+	if length < 0 {
+		return 0
+	}
+	return length
+}
+
+func mean(a Series, offset, length int) float64 {
+	s := 0.
+	for i := offset; i < offset+length; i++ {
+		s += a.Index(i)
+	}
+	return s / float64(length)
+}
+
+// moment returns the population central moment of the given order
+func moment(a Series, offset, length int, avg float64, order int) float64 {
+	s := 0.
+	for i := offset; i < offset+length; i++ {
+		d := a.Index(i) - avg
+		p := d
+		for o := 1; o < order; o++ {
+			p *= d
+		}
+		s += p
+	}
+	return s / float64(length)
+}
+
+func variance(a, _ Series, offset, length int) float64 {
+	length = available(a, nil, offset, length)
+	if length == 0 {
+		return 0
+	}
+	return moment(a, offset, length, mean(a, offset, length), 2)
+}
+
+func stdev(a, _ Series, offset, length int) float64 {
+	return math.Sqrt(variance(a, nil, offset, length))
+}
+
+func zScore(a, _ Series, offset, length int) float64 {
+	length = available(a, nil, offset, length)
+	if length == 0 {
+		return 0
+	}
+	avg := mean(a, offset, length)
+	sd := math.Sqrt(moment(a, offset, length, avg, 2))
+	if sd == 0 {
+		return 0
+	}
+	return (a.Index(offset) - avg) / sd
+}
+
+func covariance(a, b Series, offset, length int) float64 {
+	length = available(a, b, offset, length)
+	if length == 0 {
+		return 0
+	}
+	avgA, avgB := mean(a, offset, length), mean(b, offset, length)
+	s := 0.
+	for i := offset; i < offset+length; i++ {
+		s += (a.Index(i) - avgA) * (b.Index(i) - avgB)
+	}
+	return s / float64(length)
+}
+
+func correlation(a, b Series, offset, length int) float64 {
+	length = available(a, b, offset, length)
+	d := math.Sqrt(variance(a, nil, offset, length) * variance(b, nil, offset, length))
+	if d == 0 {
+		return 0
+	}
+	return covariance(a, b, offset, length) / d
+}
+
+func beta(a, b Series, offset, length int) float64 {
+	length = available(a, b, offset, length)
+	v := variance(b, nil, offset, length)
+	if v == 0 {
+		return 0
+	}
+	return covariance(a, b, offset, length) / v
+}
+
+func linearRegression(a Series, offset, length int) (slope, intercept, rSquared float64) {
+	length = available(a, nil, offset, length)
+	if length < 2 {
+		return 0, 0, 0
+	}
+
+	// x of a.Index(offset + j) is length - 1 - j
+	avgX := float64(length-1) / 2
+	avgY := mean(a, offset, length)
+	sxx, sxy, syy := 0., 0., 0.
+	for j := 0; j < length; j++ {
+		dx := float64(length-1-j) - avgX
+		dy := a.Index(offset+j) - avgY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+
+	slope = sxy / sxx
+	intercept = avgY - slope*avgX
+	if syy != 0 {
+		rSquared = sxy * sxy / (sxx * syy)
+	}
+	return slope, intercept, rSquared
+}
+
+func skew(a, _ Series, offset, length int) float64 {
+	length = available(a, nil, offset, length)
+	if length == 0 {
+		return 0
+	}
+	avg := mean(a, offset, length)
+	m2 := moment(a, offset, length, avg, 2)
+	if m2 == 0 {
+		return 0
+	}
+	return moment(a, offset, length, avg, 3) / math.Pow(m2, 1.5)
+}
+
+func kurtosis(a, _ Series, offset, length int) float64 {
+	length = available(a, nil, offset, length)
+	if length == 0 {
+		return 0
+	}
+	avg := mean(a, offset, length)
+	m2 := moment(a, offset, length, avg, 2)
+	if m2 == 0 {
+		return 0
+	}
+	return moment(a, offset, length, avg, 4)/(m2*m2) - 3
+}
+
+// newPercentileFunc returns the percentile function that reuses the buffer for sorting the values
+func newPercentileFunc(percentile float64) func(a, b Series, offset, length int) float64 {
+	var values []float64
+	return func(a, _ Series, offset, length int) float64 {
+		length = available(a, nil, offset, length)
+		if length == 0 {
+			return 0
+		}
+
+		values = values[:0]
+		for i := offset; i < offset+length; i++ {
+			values = append(values, a.Index(i))
+		}
+		sort.Float64s(values)
+
+		pos := math.Max(0, math.Min(100, percentile)) / 100 * float64(length-1)
+		lower := int(math.Floor(pos))
+		if lower == length-1 {
+			return values[lower]
+		}
+		return values[lower] + (values[lower+1]-values[lower])*(pos-float64(lower))
+	}
+}
+
+func rank(a, _ Series, offset, length int) float64 {
+	length = available(a, nil, offset+1, length)
+	if length == 0 {
+		return 0
+	}
+	current := a.Index(offset)
+	count := 0
+	for i := offset + 1; i <= offset+length; i++ {
+		if a.Index(i) <= current {
+			count++
+		}
+	}
+	return float64(count) / float64(length) * 100
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+python:
+
+import statistics as st
+a = [0.54, 0.77, -0.12, 1.34, 0.91, -0.45, 0.23, 1.05, -0.78, 0.66, 0.12, 0.98, -0.31, 0.47, 1.21]
+b = [0.31, 0.52, -0.05, 0.88, 0.71, -0.22, 0.14, 0.63, -0.51, 0.42, 0.02, 0.77, -0.15, 0.30, 0.95]
+wa, wb = a[-10:], b[-10:]
+m = st.mean(wa)
+cov = sum((x - m) * (y - st.mean(wb)) for x, y in zip(wa, wb)) / 10
+lr = st.linear_regression(list(range(10)), wa)
+print(st.pvariance(wa), st.pstdev(wa), (wa[-1] - m) / st.pstdev(wa))
+print(cov, st.correlation(wa, wb), cov / st.pvariance(wb))
+print(lr.slope, lr.intercept, st.correlation(list(range(10)), wa) ** 2)
+m2, m3, m4 = [sum((x - m) ** k for x in wa) / 10 for k in (2, 3, 4)]
+print(m3 / m2 ** 1.5, m4 / m2 ** 2 - 3)
+q = st.quantiles(wa, n=100, method='inclusive')
+print(q[24], q[89])
+print(sum(1 for x in a[-11:-1] if x <= a[-1]) / 10 * 100, sum(1 for x in a[-12:-2] if x <= a[-2]) / 10 * 100)
+print(st.pstdev(a[-11:-1]), st.correlation(a[-11:-1], b[-11:-1]))
+*/
+var (
+	testStatisticsA = Float64Slice{0.54, 0.77, -0.12, 1.34, 0.91, -0.45, 0.23, 1.05, -0.78, 0.66, 0.12, 0.98, -0.31, 0.47, 1.21}
+	testStatisticsB = Float64Slice{0.31, 0.52, -0.05, 0.88, 0.71, -0.22, 0.14, 0.63, -0.51, 0.42, 0.02, 0.77, -0.15, 0.30, 0.95}
+)
+
+func TestStatistics(t *testing.T) {
+	const delta = 1e-9
+	a, b := &testStatisticsA, &testStatisticsB
+
+	assert.InDelta(t, 0.414656, Variance(a, 10), delta)
+	assert.InDelta(t, 0.6439378852032236, Stdev(a, 10), delta)
+	assert.InDelta(t, 1.3852267749683482, ZScore(a, 10), delta)
+	assert.InDelta(t, 0.28144, Covariance(a, b, 10), delta)
+	assert.InDelta(t, 0.9878625154650296, Correlation(a, b, 10), delta)
+	assert.InDelta(t, 1.4377889601266953, Beta(a, b, 10), delta)
+
+	slope, intercept, rSquared := LinearRegression(a, 10)
+	assert.InDelta(t, 0.08824242424242423, slope, delta)
+	assert.InDelta(t, -0.07909090909090905, intercept, delta)
+	assert.InDelta(t, 0.1549247685997184, rSquared, delta)
+
+	assert.InDelta(t, -0.22368544369470497, Skew(a, 10), delta)
+	assert.InDelta(t, -1.2057828541093645, Kurtosis(a, 10), delta)
+	assert.InDelta(t, -0.2025, Percentile(a, 10, 25), delta)
+	assert.InDelta(t, 1.066, Percentile(a, 10, 90), delta)
+	assert.InDelta(t, 1.21, Percentile(a, 10, 100), delta)
+	assert.InDelta(t, 100., Rank(a, 10), delta)
+}
+
+func TestStatistics_Insufficient(t *testing.T) {
+	a := &Float64Slice{1, 2, 3}
+
+	// the length is truncated to the length of the series
+	assert.InDelta(t, 2./3., Variance(a, 10), 1e-9)
+	assert.Equal(t, 0., Variance(&Float64Slice{}, 10))
+
+	// undefined statistics
+	constant := &Float64Slice{1, 1, 1}
+	assert.Equal(t, 0., Correlation(a, constant, 3))
+	assert.Equal(t, 0., ZScore(constant, 3))
+	assert.Equal(t, 0., Skew(constant, 3))
+	_, _, rSquared := LinearRegression(constant, 3)
+	assert.Equal(t, 0., rSquared)
+}
+
+func TestRollingStatistics(t *testing.T) {
+	const delta = 1e-9
+	a, b := &Float64Slice{}, &testStatisticsB
+	*a = append(*a, testStatisticsA...)
+
+	stdev := RollingStdev(a, 10)
+	assert.Equal(t, 6, stdev.Length())
+	assert.InDelta(t, 0.6439378852032236, stdev.Last(), delta)
+	assert.InDelta(t, 0.6076479243772663, stdev.Index(1), delta)
+	assert.Equal(t, 0., stdev.Index(6))
+
+	corr := RollingCorrelation(a, b, 10)
+	assert.InDelta(t, 0.9878625154650296, corr.Last(), delta)
+	assert.InDelta(t, 0.9880404525589281, corr.Index(1), delta)
+
+	slope, intercept, rSquared := RollingLinearRegression(a, 10)
+	assert.InDelta(t, 0.08824242424242423, slope.Last(), delta)
+	assert.InDelta(t, -0.07909090909090905, intercept.Last(), delta)
+	assert.InDelta(t, 0.1549247685997184, rSquared.Last(), delta)
+
+	rank := RollingRank(a, 10)
+	assert.Equal(t, 5, rank.Length())
+	assert.InDelta(t, 100., rank.Last(), delta)
+	assert.InDelta(t, 50., rank.Index(1), delta)
+
+	percentile := RollingPercentile(a, 10, 25)
+	assert.InDelta(t, -0.2025, percentile.Last(), delta)
+
+	// the rolling statistics are evaluated lazily
+	zScore := RollingZScore(a, 3)
+	a.Push(2.0)
+	assert.InDelta(t, ZScore(a, 3), zScore.Last(), delta)
+	assert.InDelta(t, Variance(a, 3), RollingVariance(a, 3).Last(), delta)
+	assert.InDelta(t, Skew(a, 5), RollingSkew(a, 5).Last(), delta)
+	assert.InDelta(t, Kurtosis(a, 5), RollingKurtosis(a, 5).Last(), delta)
+	assert.InDelta(t, Covariance(a, b, 5), RollingCovariance(a, b, 5).Last(), delta)
+	assert.InDelta(t, Beta(a, b, 5), RollingBeta(a, b, 5).Last(), delta)
+}
+
+func TestSumWithLimit(t *testing.T) {
+	a := &Float64Slice{1, 2, 3, 4}
+	assert.Equal(t, 7., Sum(a, 2))
+	assert.Equal(t, 10., Sum(a))
+	assert.Equal(t, 10., Sum(a, 10))
+	assert.Equal(t, 3.5, Mean(a, 2))
+	assert.Equal(t, []float64{4, 3}, ToArray(a, 2))
+	assert.Equal(t, Float64Slice{3, 4}, ToReverseArray(a, 2))
+}